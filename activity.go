@@ -0,0 +1,77 @@
+package broadcast
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// signalActivity 记录信号最近一次广播与监听器变化的时间, 以 UnixNano 保存
+type signalActivity struct {
+	broadcast atomic.Int64
+	watch     atomic.Int64
+}
+
+// activityOf 返回信号的活动记录, create 为 true 时不存在则创建
+// 已存在的记录只需一次无锁查找, 不影响广播热路径
+func (c *core[K, T]) activityOf(signal string, create bool) *signalActivity {
+	s := c.shard(signal)
+	if p := s.activity.Load(); p != nil {
+		if a, ok := (*p)[signal]; ok {
+			return a
+		}
+	}
+	if !create {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var activity map[string]*signalActivity
+	if p := s.activity.Load(); p != nil {
+		if a, ok := (*p)[signal]; ok {
+			return a
+		}
+		activity = make(map[string]*signalActivity, len(*p)+1)
+		for k, v := range *p {
+			activity[k] = v
+		}
+	} else {
+		activity = make(map[string]*signalActivity, 1)
+	}
+	a := &signalActivity{}
+	activity[signal] = a
+	s.activity.Store(&activity)
+	return a
+}
+
+func (c *core[K, T]) touchBroadcast(signal string, now time.Time) {
+	c.activityOf(signal, true).broadcast.Store(now.UnixNano())
+}
+
+func (c *core[K, T]) touchWatch(signal string) {
+	c.activityOf(signal, true).watch.Store(c.clock().Now().UnixNano())
+}
+
+// lastBroadcast 返回信号最近一次广播的时间, 从未广播时为零值
+func (c *core[K, T]) lastBroadcast(signal string) time.Time {
+	if a := c.activityOf(signal, false); a != nil {
+		return unixNano(a.broadcast.Load())
+	}
+	return time.Time{}
+}
+
+// lastWatchChange 返回信号最近一次监听器变化的时间, 从未变化时为零值
+func (c *core[K, T]) lastWatchChange(signal string) time.Time {
+	if a := c.activityOf(signal, false); a != nil {
+		return unixNano(a.watch.Load())
+	}
+	return time.Time{}
+}
+
+func unixNano(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
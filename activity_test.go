@@ -0,0 +1,62 @@
+package broadcast
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivity_Timestamps(t *testing.T) {
+	clock := &manualClock{now: time.Unix(100, 0)}
+	b := New[string]()
+	b.SetClock(clock)
+
+	if !b.LastBroadcast("test").IsZero() || !b.LastWatchChange("test").IsZero() {
+		t.Error("expected zero times for an unknown signal")
+	}
+
+	b.Watch("test", "a")
+	if got := b.LastWatchChange("test"); !got.Equal(time.Unix(100, 0)) {
+		t.Errorf("expected the watch time, got %v", got)
+	}
+
+	clock.now = time.Unix(200, 0)
+	b.Watch("test", "a")
+	if got := b.LastWatchChange("test"); !got.Equal(time.Unix(100, 0)) {
+		t.Errorf("expected a duplicate watch not to count as a change, got %v", got)
+	}
+	b.Broadcast("test", nil)
+	if got := b.LastBroadcast("test"); !got.Equal(time.Unix(200, 0)) {
+		t.Errorf("expected the broadcast time, got %v", got)
+	}
+
+	clock.now = time.Unix(300, 0)
+	b.Unwatch("test", "a")
+	if got := b.LastWatchChange("test"); !got.Equal(time.Unix(300, 0)) {
+		t.Errorf("expected the unwatch time, got %v", got)
+	}
+
+	clock.now = time.Unix(400, 0)
+	b.Watch("other", "a")
+	b.CleanAll()
+	if got := b.LastWatchChange("other"); !got.Equal(time.Unix(400, 0)) {
+		t.Errorf("expected CleanAll to count as a change, got %v", got)
+	}
+	if got := b.LastBroadcast("test"); !got.Equal(time.Unix(200, 0)) {
+		t.Errorf("expected timestamps to survive CleanAll, got %v", got)
+	}
+}
+
+func TestActivity_Namespace(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	b.Broadcast("test", nil)
+	if b.LastBroadcast("test").IsZero() {
+		t.Error("expected the broadcast to be recorded")
+	}
+
+	root := New[string]()
+	ns := root.Namespace("app.")
+	ns.Broadcast("start", nil)
+	if root.LastBroadcast("app.start").IsZero() || ns.LastBroadcast("start").IsZero() {
+		t.Error("expected namespaced broadcasts to be recorded under the full signal")
+	}
+}
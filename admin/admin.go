@@ -0,0 +1,307 @@
+// Package admin 提供检查与操作运行中广播器的 HTTP 接口
+//
+// 挂载到已有的 ServeMux 上, 运维无需附加调试器即可查看信号、监听器与处理器, 并暂停、恢复或清理信号:
+//
+//	mux.Handle("/debug/broadcast/", http.StripPrefix("/debug/broadcast", admin.NewHandler(b, admin.Config{})))
+//
+// 接口不做身份认证, 应挂载在内部端口或由调用方包装认证中间件
+package admin
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+// Target 是 admin 检查与操作的广播器, *broadcast.Broadcast 与 *broadcast.UniqueBroadcast 都实现了它
+type Target interface {
+	State() broadcast.StateDump
+	HandlerStats() []broadcast.HandlerStats
+	Paused() []string
+	Pause(signal string) bool
+	Resume(signal string) bool
+	Clean(signal string)
+	CleanAll()
+	Broadcast(signal string, metadata map[string]interface{}, opts ...broadcast.BroadcastOption) error
+	Tap(signal string, fn func(e broadcast.Event[any])) (cancel func())
+}
+
+// Config admin 接口配置
+type Config struct {
+	// ReadOnly 为 true 时不注册暂停、恢复、清理与广播等修改状态的接口
+	ReadOnly bool
+	// TailBuffer 为每个 tail 连接缓冲的事件数量, 客户端读取过慢时多余的事件被丢弃, 默认为 DefaultTailBuffer
+	TailBuffer int
+}
+
+// DefaultTailBuffer 是 Config.TailBuffer 的默认值
+const DefaultTailBuffer = 64
+
+// Signal 是 GET /signals 与 GET /signals/{signal} 返回的信号状态
+type Signal struct {
+	Signal    string   `json:"signal"`
+	Listeners int      `json:"listeners"`
+	Keys      []string `json:"keys,omitempty"`
+	Paused    bool     `json:"paused"`
+}
+
+// Stats 是 GET /stats 返回的汇总统计
+type Stats struct {
+	Signals     int      `json:"signals"`
+	Listeners   int      `json:"listeners"`
+	Handlers    int      `json:"handlers"`
+	Pending     int      `json:"pending"`
+	Buffered    int      `json:"buffered"`
+	DeadLetters int      `json:"dead_letters"`
+	Expired     uint64   `json:"expired"`
+	Paused      []string `json:"paused"`
+}
+
+// Event 是 tail 以 SSE 推送的一次广播, Payload 为广播时负载, 不能编码为 JSON 时为其 %v 文本
+type Event struct {
+	ID       string                 `json:"id"`
+	Time     time.Time              `json:"time"`
+	Signal   string                 `json:"signal"`
+	Source   string                 `json:"source,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Payload  any                    `json:"payload,omitempty"`
+}
+
+// BroadcastRequest 是 POST /signals/{signal}/broadcast 的请求体, 请求体可以为空
+type BroadcastRequest struct {
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// ID 与 Source 对应 broadcast.WithEventID 与 broadcast.WithSource
+	ID     string `json:"id,omitempty"`
+	Source string `json:"source,omitempty"`
+}
+
+// Result 是修改状态的接口的返回值, Changed 表示操作是否改变了状态
+type Result struct {
+	Signal  string `json:"signal,omitempty"`
+	Changed bool   `json:"changed"`
+}
+
+// NewHandler 返回 target 的 admin 接口:
+//
+//	GET  /signals                         所有有监听器或被暂停的信号
+//	GET  /signals/{signal}                单个信号的监听器 key
+//	GET  /handlers                        按注册顺序排列的处理器、名称与调用统计
+//	GET  /stats                           汇总统计
+//	GET  /signals/{signal}/tail           以 SSE 推送信号之后的每一次广播
+//	POST /signals/{signal}/pause          暂停信号的广播
+//	POST /signals/{signal}/resume         恢复信号的广播
+//	POST /signals/{signal}/clean          移除信号的所有监听器
+//	POST /signals/{signal}/broadcast      以 BroadcastRequest 广播一次测试事件
+//	POST /clean                           移除所有信号的监听器
+//
+// 信号名中的 / 需要转义为 %2F
+func NewHandler(target Target, config Config) http.Handler {
+	if config.TailBuffer <= 0 {
+		config.TailBuffer = DefaultTailBuffer
+	}
+	h := &handler{target: target, config: config}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /signals", h.signals)
+	mux.HandleFunc("GET /signals/{signal}", h.signal)
+	mux.HandleFunc("GET /signals/{signal}/tail", h.tail)
+	mux.HandleFunc("GET /handlers", h.handlers)
+	mux.HandleFunc("GET /stats", h.stats)
+	if !config.ReadOnly {
+		mux.HandleFunc("POST /signals/{signal}/pause", h.pause)
+		mux.HandleFunc("POST /signals/{signal}/resume", h.resume)
+		mux.HandleFunc("POST /signals/{signal}/clean", h.clean)
+		mux.HandleFunc("POST /signals/{signal}/broadcast", h.broadcast)
+		mux.HandleFunc("POST /clean", h.cleanAll)
+	}
+	return mux
+}
+
+type handler struct {
+	target Target
+	config Config
+}
+
+// ListSignals 合并有监听器的信号与暂停的信号, 按信号名排列, keys 为 true 时包含监听器 key
+// HTTP 与其他协议的管理接口共用它, 保证返回一致的结果
+func ListSignals(target Target, keys bool) []Signal {
+	state := target.State()
+	signals := make([]Signal, 0, len(state.Signals)+len(state.Paused))
+	for _, s := range state.Signals {
+		signal := Signal{Signal: s.Signal, Listeners: s.Listeners, Paused: slices.Contains(state.Paused, s.Signal)}
+		if keys {
+			signal.Keys = s.Keys
+		}
+		signals = append(signals, signal)
+	}
+	for _, paused := range state.Paused {
+		if !slices.ContainsFunc(signals, func(s Signal) bool { return s.Signal == paused }) {
+			signals = append(signals, Signal{Signal: paused, Paused: true})
+		}
+	}
+	slices.SortFunc(signals, func(a, b Signal) int { return cmp.Compare(a.Signal, b.Signal) })
+	return signals
+}
+
+// LookupSignal 返回单个信号的状态与监听器 key, 信号没有监听器且未暂停时返回 false
+func LookupSignal(target Target, name string) (Signal, bool) {
+	for _, s := range ListSignals(target, true) {
+		if s.Signal == name {
+			return s, true
+		}
+	}
+	return Signal{}, false
+}
+
+// CollectStats 汇总 target 的状态
+func CollectStats(target Target) Stats {
+	state := target.State()
+	stats := Stats{
+		Signals:     len(state.Signals),
+		Handlers:    len(state.Handlers),
+		Pending:     state.Pending,
+		Buffered:    state.Buffered,
+		DeadLetters: state.DeadLetters,
+		Expired:     state.Expired,
+		Paused:      state.Paused,
+	}
+	for _, s := range state.Signals {
+		stats.Listeners += s.Listeners
+	}
+	if stats.Paused == nil {
+		stats.Paused = []string{}
+	}
+	return stats
+}
+
+func (h *handler) signals(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, ListSignals(h.target, false))
+}
+
+func (h *handler) signal(w http.ResponseWriter, r *http.Request) {
+	if s, ok := LookupSignal(h.target, r.PathValue("signal")); ok {
+		writeJSON(w, http.StatusOK, s)
+		return
+	}
+	writeJSON(w, http.StatusNotFound, map[string]string{"error": "signal not found"})
+}
+
+func (h *handler) handlers(w http.ResponseWriter, r *http.Request) {
+	handlers := h.target.HandlerStats()
+	if handlers == nil {
+		handlers = []broadcast.HandlerStats{}
+	}
+	writeJSON(w, http.StatusOK, handlers)
+}
+
+func (h *handler) stats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, CollectStats(h.target))
+}
+
+func (h *handler) pause(w http.ResponseWriter, r *http.Request) {
+	signal := r.PathValue("signal")
+	writeJSON(w, http.StatusOK, Result{Signal: signal, Changed: h.target.Pause(signal)})
+}
+
+func (h *handler) resume(w http.ResponseWriter, r *http.Request) {
+	signal := r.PathValue("signal")
+	writeJSON(w, http.StatusOK, Result{Signal: signal, Changed: h.target.Resume(signal)})
+}
+
+func (h *handler) clean(w http.ResponseWriter, r *http.Request) {
+	signal := r.PathValue("signal")
+	before := h.target.State().Signals
+	h.target.Clean(signal)
+	changed := slices.ContainsFunc(before, func(s broadcast.SignalDump) bool { return s.Signal == signal })
+	writeJSON(w, http.StatusOK, Result{Signal: signal, Changed: changed})
+}
+
+func (h *handler) cleanAll(w http.ResponseWriter, r *http.Request) {
+	changed := len(h.target.State().Signals) > 0
+	h.target.CleanAll()
+	writeJSON(w, http.StatusOK, Result{Changed: changed})
+}
+
+func (h *handler) broadcast(w http.ResponseWriter, r *http.Request) {
+	var req BroadcastRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	var opts []broadcast.BroadcastOption
+	if req.ID != "" {
+		opts = append(opts, broadcast.WithEventID(req.ID))
+	}
+	if req.Source != "" {
+		opts = append(opts, broadcast.WithSource(req.Source))
+	}
+	signal := r.PathValue("signal")
+	if err := h.target.Broadcast(signal, req.Metadata, opts...); err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"signal": signal, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, Result{Signal: signal, Changed: true})
+}
+
+// tail 以 SSE 推送信号的广播, 直到客户端断开; 观察者不阻塞广播, 缓冲满时丢弃事件
+func (h *handler) tail(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusNotImplemented, map[string]string{"error": "streaming unsupported"})
+		return
+	}
+	signal := r.PathValue("signal")
+	events := make(chan broadcast.Event[any], h.config.TailBuffer)
+	cancel := h.target.Tap(signal, func(e broadcast.Event[any]) {
+		select {
+		case events <- e:
+		default:
+		}
+	})
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, ": tail %s\n\n", signal)
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-events:
+			data, err := json.Marshal(NewEvent(e))
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %s\ndata: %s\n\n", e.ID, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// NewEvent 将 Tap 观察到的广播转换为可以编码为 JSON 的 Event
+func NewEvent(e broadcast.Event[any]) Event {
+	event := Event{ID: e.ID, Time: e.Timestamp, Signal: e.Signal, Source: e.Source, Metadata: e.Metadata, Payload: e.Data}
+	if _, err := json.Marshal(event.Payload); err != nil {
+		event.Payload = fmt.Sprintf("%v", e.Data)
+	}
+	if _, err := json.Marshal(event.Metadata); err != nil {
+		event.Metadata = map[string]interface{}{"error": err.Error()}
+	}
+	return event
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,159 @@
+package admin
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unique"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+type worker string
+
+func (w worker) Unique() unique.Handle[string] { return unique.Make(string(w)) }
+func (w worker) Value() string                 { return string(w) }
+
+func do(t *testing.T, h http.Handler, method, path string, v any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	if v != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestHandler_Inspect(t *testing.T) {
+	b := broadcast.New[string]()
+	b.Watch("orders", "projection")
+	b.Watch("orders", "audit")
+	b.Watch("a/b", "x")
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error { return nil }, broadcast.WithName("indexer"))
+	b.Pause("idle")
+	h := NewHandler(b, Config{})
+
+	var signals []Signal
+	do(t, h, "GET", "/signals", &signals)
+	if len(signals) != 3 || signals[0].Signal != "a/b" || signals[1].Signal != "idle" || !signals[1].Paused {
+		t.Errorf("expected listened and paused signals sorted by name, got %+v", signals)
+	}
+
+	var orders Signal
+	if code := do(t, h, "GET", "/signals/orders", &orders); code != http.StatusOK || orders.Listeners != 2 || len(orders.Keys) != 2 {
+		t.Errorf("expected the listener keys of orders, got %d %+v", code, orders)
+	}
+	var escaped Signal
+	do(t, h, "GET", "/signals/a%2Fb", &escaped)
+	if escaped.Signal != "a/b" {
+		t.Errorf("expected escaped signal names to be decoded, got %+v", escaped)
+	}
+	if code := do(t, h, "GET", "/signals/missing", nil); code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown signal, got %d", code)
+	}
+
+	var handlers []broadcast.HandlerStats
+	do(t, h, "GET", "/handlers", &handlers)
+	if len(handlers) != 1 || handlers[0].Name != "indexer" {
+		t.Errorf("expected the named handler, got %+v", handlers)
+	}
+
+	var stats Stats
+	do(t, h, "GET", "/stats", &stats)
+	if stats.Signals != 2 || stats.Listeners != 3 || stats.Handlers != 1 || len(stats.Paused) != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestHandler_Operations(t *testing.T) {
+	b := broadcast.NewUnique[string, string]()
+	b.Watch("orders", worker("projection"))
+	b.Watch("metrics", worker("collector"))
+	h := NewHandler(b, Config{})
+
+	var result Result
+	do(t, h, "POST", "/signals/orders/pause", &result)
+	if !result.Changed || b.Broadcast("orders", nil) == nil {
+		t.Errorf("expected orders to be paused, got %+v", result)
+	}
+	do(t, h, "POST", "/signals/orders/resume", &result)
+	if !result.Changed || b.Broadcast("orders", nil) != nil {
+		t.Errorf("expected orders to be resumed, got %+v", result)
+	}
+
+	do(t, h, "POST", "/signals/orders/clean", &result)
+	if !result.Changed || b.WatchCount("orders") != 0 || b.WatchCount("metrics") != 1 {
+		t.Errorf("expected only orders to be cleaned, got %+v", result)
+	}
+	do(t, h, "POST", "/clean", &result)
+	if !result.Changed || b.TotalWatchCount() != 0 {
+		t.Errorf("expected all listeners to be cleaned, got %+v", result)
+	}
+
+	if code := do(t, h, "GET", "/signals/orders/pause", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected operations to require POST, got %d", code)
+	}
+	ro := NewHandler(b, Config{ReadOnly: true})
+	if code := do(t, ro, "POST", "/signals/orders/pause", nil); code == http.StatusOK {
+		t.Error("expected read-only handlers to reject operations")
+	}
+}
+
+func TestHandler_BroadcastAndTail(t *testing.T) {
+	b := broadcast.New[string]()
+	srv := httptest.NewServer(NewHandler(b, Config{}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/signals/orders/tail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	lines := bufio.NewScanner(resp.Body)
+	if !lines.Scan() || !strings.HasPrefix(lines.Text(), ": tail orders") {
+		t.Fatalf("expected the tail preamble, got %q", lines.Text())
+	}
+
+	body := strings.NewReader(`{"metadata": {"amount": 3}, "id": "evt-1", "source": "ctl"}`)
+	post, err := http.Post(srv.URL+"/signals/orders/broadcast", "application/json", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post.Body.Close()
+	if post.StatusCode != http.StatusOK {
+		t.Fatalf("expected the broadcast to succeed, got %d", post.StatusCode)
+	}
+	broadcast.BroadcastData(b, "orders", "hello", nil)
+
+	var events []Event
+	for lines.Scan() && len(events) < 2 {
+		if data, ok := strings.CutPrefix(lines.Text(), "data: "); ok {
+			var e Event
+			if err := json.Unmarshal([]byte(data), &e); err != nil {
+				t.Fatal(err)
+			}
+			events = append(events, e)
+		}
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected two tailed events, got %+v", events)
+	}
+	if e := events[0]; e.ID != "evt-1" || e.Source != "ctl" || e.Metadata["amount"] != float64(3) {
+		t.Errorf("unexpected tailed event %+v", e)
+	}
+	if events[1].Payload != "hello" {
+		t.Errorf("expected the payload to be tailed, got %+v", events[1])
+	}
+
+	b.Pause("orders")
+	post, _ = http.Post(srv.URL+"/signals/orders/broadcast", "application/json", nil)
+	post.Body.Close()
+	if post.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected a rejected broadcast to report an error, got %d", post.StatusCode)
+	}
+}
@@ -0,0 +1,211 @@
+package admin
+
+import (
+	_ "embed"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+// dashboardPage 是内嵌的单页面界面, 定期拉取 /snapshot 并在浏览器中绘制
+//
+//go:embed dashboard.html
+var dashboardPage []byte
+
+const (
+	// DefaultDashboardInterval 是 DashboardConfig.Interval 的默认值
+	DefaultDashboardInterval = time.Second
+	// DefaultDashboardSamples 是 DashboardConfig.Samples 的默认值
+	DefaultDashboardSamples = 300
+	// DefaultDashboardEvents 是 DashboardConfig.Events 的默认值
+	DefaultDashboardEvents = 100
+)
+
+// DashboardConfig 配置 Dashboard
+type DashboardConfig struct {
+	// Interval 为采样间隔, 也是页面刷新的间隔, 默认为 DefaultDashboardInterval
+	Interval time.Duration
+	// Samples 为保留的采样数量, 默认为 DefaultDashboardSamples
+	Samples int
+	// Events 为保留的最近广播数量, 默认为 DefaultDashboardEvents
+	Events int
+}
+
+// Sample 是一次采样, 记录各信号的监听器数量与采样间隔内的广播次数、各处理器在间隔内的调用与错误次数
+type Sample struct {
+	Time       time.Time         `json:"time"`
+	Listeners  map[string]int    `json:"listeners"`
+	Broadcasts map[string]uint64 `json:"broadcasts"`
+	Handlers   []HandlerSample   `json:"handlers"`
+}
+
+// HandlerSample 是处理器在一个采样间隔内的调用与错误次数
+type HandlerSample struct {
+	ID     broadcast.HandlerID `json:"id"`
+	Calls  uint64              `json:"calls"`
+	Errors uint64              `json:"errors"`
+}
+
+// Snapshot 是 GET /snapshot 返回的面板数据, Samples 按时间排列, Events 从新到旧排列
+type Snapshot struct {
+	Interval time.Duration            `json:"interval"`
+	Samples  []Sample                 `json:"samples"`
+	Totals   map[string]uint64        `json:"totals"`
+	Handlers []broadcast.HandlerStats `json:"handlers"`
+	Events   []Event                  `json:"events"`
+}
+
+// Dashboard 是内嵌的实时面板, 展示各信号的广播频率、监听器数量随时间的变化、处理器错误率与最近的广播
+// 面板通过 Tap 观察所有信号, 并在后台按 Interval 采样, 用于开发环境中快速排查; 不再使用时调用 Close
+//
+//	d := admin.NewDashboard(b, admin.DashboardConfig{})
+//	defer d.Close()
+//	mux.Handle("/debug/dashboard/", http.StripPrefix("/debug/dashboard", d))
+type Dashboard struct {
+	target Target
+	config DashboardConfig
+	mux    *http.ServeMux
+	untap  func()
+	stop   chan struct{}
+	once   sync.Once
+
+	mu       sync.Mutex
+	counts   map[string]uint64
+	totals   map[string]uint64
+	events   []Event
+	next     int
+	samples  []Sample
+	handlers map[broadcast.HandlerID]broadcast.HandlerStats
+}
+
+// NewDashboard 创建 target 的实时面板并开始采样:
+//
+//	GET /          面板页面
+//	GET /snapshot  面板数据 Snapshot
+func NewDashboard(target Target, config DashboardConfig) *Dashboard {
+	if config.Interval <= 0 {
+		config.Interval = DefaultDashboardInterval
+	}
+	if config.Samples <= 0 {
+		config.Samples = DefaultDashboardSamples
+	}
+	if config.Events <= 0 {
+		config.Events = DefaultDashboardEvents
+	}
+	d := &Dashboard{
+		target:   target,
+		config:   config,
+		mux:      http.NewServeMux(),
+		stop:     make(chan struct{}),
+		counts:   make(map[string]uint64),
+		totals:   make(map[string]uint64),
+		handlers: make(map[broadcast.HandlerID]broadcast.HandlerStats),
+	}
+	d.mux.HandleFunc("GET /{$}", d.page)
+	d.mux.HandleFunc("GET /snapshot", d.snapshot)
+	d.untap = target.Tap("", d.observe)
+	d.sample(time.Now())
+	go d.run()
+	return d
+}
+
+// ServeHTTP 实现 http.Handler
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+// Close 停止观察与采样
+func (d *Dashboard) Close() error {
+	d.once.Do(func() {
+		d.untap()
+		close(d.stop)
+	})
+	return nil
+}
+
+func (d *Dashboard) run() {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case now := <-ticker.C:
+			d.sample(now)
+		}
+	}
+}
+
+// observe 在广播所在的 goroutine 中记录一次广播, 只做计数与保存
+func (d *Dashboard) observe(e broadcast.Event[any]) {
+	event := NewEvent(e)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.counts[e.Signal]++
+	d.totals[e.Signal]++
+	if len(d.events) < d.config.Events {
+		d.events = append(d.events, event)
+		return
+	}
+	d.events[d.next] = event
+	d.next = (d.next + 1) % len(d.events)
+}
+
+// sample 记录一次采样, 处理器的次数为与上一次采样的差值
+func (d *Dashboard) sample(now time.Time) {
+	state := d.target.State()
+	stats := d.target.HandlerStats()
+
+	s := Sample{Time: now, Listeners: make(map[string]int, len(state.Signals)), Handlers: make([]HandlerSample, 0, len(stats))}
+	for _, signal := range state.Signals {
+		s.Listeners[signal.Signal] = signal.Listeners
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s.Broadcasts = d.counts
+	d.counts = make(map[string]uint64, len(s.Broadcasts))
+	handlers := make(map[broadcast.HandlerID]broadcast.HandlerStats, len(stats))
+	for _, h := range stats {
+		last := d.handlers[h.ID]
+		s.Handlers = append(s.Handlers, HandlerSample{ID: h.ID, Calls: h.Calls - last.Calls, Errors: h.Errors - last.Errors})
+		handlers[h.ID] = h
+	}
+	d.handlers = handlers
+
+	if len(d.samples) == d.config.Samples {
+		d.samples = slices.Delete(d.samples, 0, 1)
+	}
+	d.samples = append(d.samples, s)
+}
+
+func (d *Dashboard) page(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(dashboardPage)
+}
+
+func (d *Dashboard) snapshot(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	snapshot := Snapshot{
+		Interval: d.config.Interval,
+		Samples:  append([]Sample(nil), d.samples...),
+		Totals:   make(map[string]uint64, len(d.totals)),
+		Handlers: make([]broadcast.HandlerStats, 0, len(d.handlers)),
+		Events:   make([]Event, 0, len(d.events)),
+	}
+	for signal, n := range d.totals {
+		snapshot.Totals[signal] = n
+	}
+	for i := range d.events {
+		snapshot.Events = append(snapshot.Events, d.events[(d.next+len(d.events)-1-i)%len(d.events)])
+	}
+	d.mu.Unlock()
+
+	snapshot.Handlers = append(snapshot.Handlers, d.target.HandlerStats()...)
+	writeJSON(w, http.StatusOK, snapshot)
+}
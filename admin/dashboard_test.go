@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+func TestDashboard_Snapshot(t *testing.T) {
+	b := broadcast.New[string]()
+	b.Watch("orders", "projection")
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		return errors.New("boom")
+	}, broadcast.WithName("flaky"))
+
+	d := NewDashboard(b, DashboardConfig{Interval: time.Hour, Samples: 2, Events: 2})
+	defer d.Close()
+
+	for i := 0; i < 3; i++ {
+		b.Broadcast("orders", map[string]interface{}{"n": i})
+	}
+	b.Broadcast("metrics", nil)
+	b.Watch("orders", "audit")
+	d.sample(time.Now())
+
+	var s Snapshot
+	do(t, d, "GET", "/snapshot", &s)
+	if len(s.Samples) != 2 {
+		t.Fatalf("expected the initial and the manual sample, got %d", len(s.Samples))
+	}
+	last := s.Samples[1]
+	if last.Listeners["orders"] != 2 || last.Broadcasts["orders"] != 3 || last.Broadcasts["metrics"] != 1 {
+		t.Errorf("unexpected sample %+v", last)
+	}
+	if len(last.Handlers) != 1 || last.Handlers[0].Calls != 3 || last.Handlers[0].Errors != 3 {
+		t.Errorf("expected the handler calls in the interval, got %+v", last.Handlers)
+	}
+	if s.Totals["orders"] != 3 || len(s.Handlers) != 1 || s.Handlers[0].Name != "flaky" {
+		t.Errorf("unexpected totals or handlers %+v %+v", s.Totals, s.Handlers)
+	}
+	if len(s.Events) != 2 || s.Events[0].Signal != "metrics" || s.Events[1].Metadata["n"] != float64(2) {
+		t.Errorf("expected the latest events newest first, got %+v", s.Events)
+	}
+
+	// 处理器次数为采样间隔内的差值, 超过 Samples 的旧采样被丢弃
+	d.sample(time.Now())
+	s = Snapshot{}
+	do(t, d, "GET", "/snapshot", &s)
+	if len(s.Samples) != 2 || s.Samples[1].Handlers[0].Calls != 0 || len(s.Samples[1].Broadcasts) != 0 {
+		t.Errorf("expected an empty interval, got %+v", s.Samples)
+	}
+
+	rec := httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<html") {
+		t.Errorf("expected the embedded page, got %d", rec.Code)
+	}
+
+	d.Close()
+	b.Broadcast("orders", nil)
+	d.sample(time.Now())
+	s = Snapshot{}
+	do(t, d, "GET", "/snapshot", &s)
+	if s.Totals["orders"] != 3 {
+		t.Error("expected Close to stop observing broadcasts")
+	}
+}
@@ -0,0 +1,997 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: admin.proto
+
+package adminpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListSignalsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSignalsRequest) Reset() {
+	*x = ListSignalsRequest{}
+	mi := &file_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSignalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSignalsRequest) ProtoMessage() {}
+
+func (x *ListSignalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSignalsRequest.ProtoReflect.Descriptor instead.
+func (*ListSignalsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{0}
+}
+
+type ListSignalsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Signals       []*Signal              `protobuf:"bytes,1,rep,name=signals,proto3" json:"signals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSignalsResponse) Reset() {
+	*x = ListSignalsResponse{}
+	mi := &file_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSignalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSignalsResponse) ProtoMessage() {}
+
+func (x *ListSignalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSignalsResponse.ProtoReflect.Descriptor instead.
+func (*ListSignalsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListSignalsResponse) GetSignals() []*Signal {
+	if x != nil {
+		return x.Signals
+	}
+	return nil
+}
+
+type GetSignalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Signal        string                 `protobuf:"bytes,1,opt,name=signal,proto3" json:"signal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSignalRequest) Reset() {
+	*x = GetSignalRequest{}
+	mi := &file_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSignalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignalRequest) ProtoMessage() {}
+
+func (x *GetSignalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignalRequest.ProtoReflect.Descriptor instead.
+func (*GetSignalRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetSignalRequest) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+type Signal struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Signal    string                 `protobuf:"bytes,1,opt,name=signal,proto3" json:"signal,omitempty"`
+	Listeners int64                  `protobuf:"varint,2,opt,name=listeners,proto3" json:"listeners,omitempty"`
+	// keys 只在 GetSignal 中返回
+	Keys          []string `protobuf:"bytes,3,rep,name=keys,proto3" json:"keys,omitempty"`
+	Paused        bool     `protobuf:"varint,4,opt,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Signal) Reset() {
+	*x = Signal{}
+	mi := &file_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Signal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Signal) ProtoMessage() {}
+
+func (x *Signal) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Signal.ProtoReflect.Descriptor instead.
+func (*Signal) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Signal) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *Signal) GetListeners() int64 {
+	if x != nil {
+		return x.Listeners
+	}
+	return 0
+}
+
+func (x *Signal) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *Signal) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+type ListHandlersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHandlersRequest) Reset() {
+	*x = ListHandlersRequest{}
+	mi := &file_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHandlersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHandlersRequest) ProtoMessage() {}
+
+func (x *ListHandlersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHandlersRequest.ProtoReflect.Descriptor instead.
+func (*ListHandlersRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{4}
+}
+
+type ListHandlersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Handlers      []*Handler             `protobuf:"bytes,1,rep,name=handlers,proto3" json:"handlers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHandlersResponse) Reset() {
+	*x = ListHandlersResponse{}
+	mi := &file_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHandlersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHandlersResponse) ProtoMessage() {}
+
+func (x *ListHandlersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHandlersResponse.ProtoReflect.Descriptor instead.
+func (*ListHandlersResponse) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListHandlersResponse) GetHandlers() []*Handler {
+	if x != nil {
+		return x.Handlers
+	}
+	return nil
+}
+
+type Handler struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Calls         uint64                 `protobuf:"varint,3,opt,name=calls,proto3" json:"calls,omitempty"`
+	Errors        uint64                 `protobuf:"varint,4,opt,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Handler) Reset() {
+	*x = Handler{}
+	mi := &file_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Handler) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Handler) ProtoMessage() {}
+
+func (x *Handler) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Handler.ProtoReflect.Descriptor instead.
+func (*Handler) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Handler) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Handler) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Handler) GetCalls() uint64 {
+	if x != nil {
+		return x.Calls
+	}
+	return 0
+}
+
+func (x *Handler) GetErrors() uint64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{7}
+}
+
+type Stats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Signals       int64                  `protobuf:"varint,1,opt,name=signals,proto3" json:"signals,omitempty"`
+	Listeners     int64                  `protobuf:"varint,2,opt,name=listeners,proto3" json:"listeners,omitempty"`
+	Handlers      int64                  `protobuf:"varint,3,opt,name=handlers,proto3" json:"handlers,omitempty"`
+	Pending       int64                  `protobuf:"varint,4,opt,name=pending,proto3" json:"pending,omitempty"`
+	Buffered      int64                  `protobuf:"varint,5,opt,name=buffered,proto3" json:"buffered,omitempty"`
+	DeadLetters   int64                  `protobuf:"varint,6,opt,name=dead_letters,json=deadLetters,proto3" json:"dead_letters,omitempty"`
+	Expired       uint64                 `protobuf:"varint,7,opt,name=expired,proto3" json:"expired,omitempty"`
+	Paused        []string               `protobuf:"bytes,8,rep,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Stats) GetSignals() int64 {
+	if x != nil {
+		return x.Signals
+	}
+	return 0
+}
+
+func (x *Stats) GetListeners() int64 {
+	if x != nil {
+		return x.Listeners
+	}
+	return 0
+}
+
+func (x *Stats) GetHandlers() int64 {
+	if x != nil {
+		return x.Handlers
+	}
+	return 0
+}
+
+func (x *Stats) GetPending() int64 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *Stats) GetBuffered() int64 {
+	if x != nil {
+		return x.Buffered
+	}
+	return 0
+}
+
+func (x *Stats) GetDeadLetters() int64 {
+	if x != nil {
+		return x.DeadLetters
+	}
+	return 0
+}
+
+func (x *Stats) GetExpired() uint64 {
+	if x != nil {
+		return x.Expired
+	}
+	return 0
+}
+
+func (x *Stats) GetPaused() []string {
+	if x != nil {
+		return x.Paused
+	}
+	return nil
+}
+
+type SignalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Signal        string                 `protobuf:"bytes,1,opt,name=signal,proto3" json:"signal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignalRequest) Reset() {
+	*x = SignalRequest{}
+	mi := &file_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignalRequest) ProtoMessage() {}
+
+func (x *SignalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignalRequest.ProtoReflect.Descriptor instead.
+func (*SignalRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SignalRequest) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+type CleanAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CleanAllRequest) Reset() {
+	*x = CleanAllRequest{}
+	mi := &file_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CleanAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanAllRequest) ProtoMessage() {}
+
+func (x *CleanAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanAllRequest.ProtoReflect.Descriptor instead.
+func (*CleanAllRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{10}
+}
+
+type BroadcastRequest struct {
+	state    protoimpl.MessageState     `protogen:"open.v1"`
+	Signal   string                     `protobuf:"bytes,1,opt,name=signal,proto3" json:"signal,omitempty"`
+	Metadata map[string]*structpb.Value `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// id 与 source 对应 broadcast.WithEventID 与 broadcast.WithSource
+	Id            string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Source        string `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadcastRequest) Reset() {
+	*x = BroadcastRequest{}
+	mi := &file_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadcastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastRequest) ProtoMessage() {}
+
+func (x *BroadcastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastRequest.ProtoReflect.Descriptor instead.
+func (*BroadcastRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BroadcastRequest) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *BroadcastRequest) GetMetadata() map[string]*structpb.Value {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *BroadcastRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BroadcastRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type Result struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Signal string                 `protobuf:"bytes,1,opt,name=signal,proto3" json:"signal,omitempty"`
+	// changed 表示操作是否改变了状态
+	Changed       bool `protobuf:"varint,2,opt,name=changed,proto3" json:"changed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Result) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *Result) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+type TailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Signal        string                 `protobuf:"bytes,1,opt,name=signal,proto3" json:"signal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TailRequest) Reset() {
+	*x = TailRequest{}
+	mi := &file_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TailRequest) ProtoMessage() {}
+
+func (x *TailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TailRequest.ProtoReflect.Descriptor instead.
+func (*TailRequest) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TailRequest) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+type Event struct {
+	state    protoimpl.MessageState     `protogen:"open.v1"`
+	Id       string                     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Time     *timestamppb.Timestamp     `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Signal   string                     `protobuf:"bytes,3,opt,name=signal,proto3" json:"signal,omitempty"`
+	Source   string                     `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Metadata map[string]*structpb.Value `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// payload 为广播时负载的 JSON 表示, 不能编码为 JSON 时为其 %v 文本
+	Payload       *structpb.Value `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Event) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Event) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Event) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *Event) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Event) GetMetadata() map[string]*structpb.Value {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Event) GetPayload() *structpb.Value {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+var File_admin_proto protoreflect.FileDescriptor
+
+const file_admin_proto_rawDesc = "" +
+	"\n" +
+	"\vadmin.proto\x12\x12broadcast.admin.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x14\n" +
+	"\x12ListSignalsRequest\"K\n" +
+	"\x13ListSignalsResponse\x124\n" +
+	"\asignals\x18\x01 \x03(\v2\x1a.broadcast.admin.v1.SignalR\asignals\"*\n" +
+	"\x10GetSignalRequest\x12\x16\n" +
+	"\x06signal\x18\x01 \x01(\tR\x06signal\"j\n" +
+	"\x06Signal\x12\x16\n" +
+	"\x06signal\x18\x01 \x01(\tR\x06signal\x12\x1c\n" +
+	"\tlisteners\x18\x02 \x01(\x03R\tlisteners\x12\x12\n" +
+	"\x04keys\x18\x03 \x03(\tR\x04keys\x12\x16\n" +
+	"\x06paused\x18\x04 \x01(\bR\x06paused\"\x15\n" +
+	"\x13ListHandlersRequest\"O\n" +
+	"\x14ListHandlersResponse\x127\n" +
+	"\bhandlers\x18\x01 \x03(\v2\x1b.broadcast.admin.v1.HandlerR\bhandlers\"[\n" +
+	"\aHandler\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05calls\x18\x03 \x01(\x04R\x05calls\x12\x16\n" +
+	"\x06errors\x18\x04 \x01(\x04R\x06errors\"\x11\n" +
+	"\x0fGetStatsRequest\"\xe6\x01\n" +
+	"\x05Stats\x12\x18\n" +
+	"\asignals\x18\x01 \x01(\x03R\asignals\x12\x1c\n" +
+	"\tlisteners\x18\x02 \x01(\x03R\tlisteners\x12\x1a\n" +
+	"\bhandlers\x18\x03 \x01(\x03R\bhandlers\x12\x18\n" +
+	"\apending\x18\x04 \x01(\x03R\apending\x12\x1a\n" +
+	"\bbuffered\x18\x05 \x01(\x03R\bbuffered\x12!\n" +
+	"\fdead_letters\x18\x06 \x01(\x03R\vdeadLetters\x12\x18\n" +
+	"\aexpired\x18\a \x01(\x04R\aexpired\x12\x16\n" +
+	"\x06paused\x18\b \x03(\tR\x06paused\"'\n" +
+	"\rSignalRequest\x12\x16\n" +
+	"\x06signal\x18\x01 \x01(\tR\x06signal\"\x11\n" +
+	"\x0fCleanAllRequest\"\xf7\x01\n" +
+	"\x10BroadcastRequest\x12\x16\n" +
+	"\x06signal\x18\x01 \x01(\tR\x06signal\x12N\n" +
+	"\bmetadata\x18\x02 \x03(\v22.broadcast.admin.v1.BroadcastRequest.MetadataEntryR\bmetadata\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x1aS\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value:\x028\x01\":\n" +
+	"\x06Result\x12\x16\n" +
+	"\x06signal\x18\x01 \x01(\tR\x06signal\x12\x18\n" +
+	"\achanged\x18\x02 \x01(\bR\achanged\"%\n" +
+	"\vTailRequest\x12\x16\n" +
+	"\x06signal\x18\x01 \x01(\tR\x06signal\"\xc3\x02\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x16\n" +
+	"\x06signal\x18\x03 \x01(\tR\x06signal\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12C\n" +
+	"\bmetadata\x18\x05 \x03(\v2'.broadcast.admin.v1.Event.MetadataEntryR\bmetadata\x120\n" +
+	"\apayload\x18\x06 \x01(\v2\x16.google.protobuf.ValueR\apayload\x1aS\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value:\x028\x012\xb9\x06\n" +
+	"\fAdminService\x12^\n" +
+	"\vListSignals\x12&.broadcast.admin.v1.ListSignalsRequest\x1a'.broadcast.admin.v1.ListSignalsResponse\x12M\n" +
+	"\tGetSignal\x12$.broadcast.admin.v1.GetSignalRequest\x1a\x1a.broadcast.admin.v1.Signal\x12a\n" +
+	"\fListHandlers\x12'.broadcast.admin.v1.ListHandlersRequest\x1a(.broadcast.admin.v1.ListHandlersResponse\x12J\n" +
+	"\bGetStats\x12#.broadcast.admin.v1.GetStatsRequest\x1a\x19.broadcast.admin.v1.Stats\x12L\n" +
+	"\vPauseSignal\x12!.broadcast.admin.v1.SignalRequest\x1a\x1a.broadcast.admin.v1.Result\x12M\n" +
+	"\fResumeSignal\x12!.broadcast.admin.v1.SignalRequest\x1a\x1a.broadcast.admin.v1.Result\x12L\n" +
+	"\vCleanSignal\x12!.broadcast.admin.v1.SignalRequest\x1a\x1a.broadcast.admin.v1.Result\x12K\n" +
+	"\bCleanAll\x12#.broadcast.admin.v1.CleanAllRequest\x1a\x1a.broadcast.admin.v1.Result\x12M\n" +
+	"\tBroadcast\x12$.broadcast.admin.v1.BroadcastRequest\x1a\x1a.broadcast.admin.v1.Result\x12D\n" +
+	"\x04Tail\x12\x1f.broadcast.admin.v1.TailRequest\x1a\x19.broadcast.admin.v1.Event0\x01B6Z4pkg.blksails.net/x/broadcast/admin/grpcadmin/adminpbb\x06proto3"
+
+var (
+	file_admin_proto_rawDescOnce sync.Once
+	file_admin_proto_rawDescData []byte
+)
+
+func file_admin_proto_rawDescGZIP() []byte {
+	file_admin_proto_rawDescOnce.Do(func() {
+		file_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_admin_proto_rawDesc), len(file_admin_proto_rawDesc)))
+	})
+	return file_admin_proto_rawDescData
+}
+
+var file_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_admin_proto_goTypes = []any{
+	(*ListSignalsRequest)(nil),    // 0: broadcast.admin.v1.ListSignalsRequest
+	(*ListSignalsResponse)(nil),   // 1: broadcast.admin.v1.ListSignalsResponse
+	(*GetSignalRequest)(nil),      // 2: broadcast.admin.v1.GetSignalRequest
+	(*Signal)(nil),                // 3: broadcast.admin.v1.Signal
+	(*ListHandlersRequest)(nil),   // 4: broadcast.admin.v1.ListHandlersRequest
+	(*ListHandlersResponse)(nil),  // 5: broadcast.admin.v1.ListHandlersResponse
+	(*Handler)(nil),               // 6: broadcast.admin.v1.Handler
+	(*GetStatsRequest)(nil),       // 7: broadcast.admin.v1.GetStatsRequest
+	(*Stats)(nil),                 // 8: broadcast.admin.v1.Stats
+	(*SignalRequest)(nil),         // 9: broadcast.admin.v1.SignalRequest
+	(*CleanAllRequest)(nil),       // 10: broadcast.admin.v1.CleanAllRequest
+	(*BroadcastRequest)(nil),      // 11: broadcast.admin.v1.BroadcastRequest
+	(*Result)(nil),                // 12: broadcast.admin.v1.Result
+	(*TailRequest)(nil),           // 13: broadcast.admin.v1.TailRequest
+	(*Event)(nil),                 // 14: broadcast.admin.v1.Event
+	nil,                           // 15: broadcast.admin.v1.BroadcastRequest.MetadataEntry
+	nil,                           // 16: broadcast.admin.v1.Event.MetadataEntry
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
+	(*structpb.Value)(nil),        // 18: google.protobuf.Value
+}
+var file_admin_proto_depIdxs = []int32{
+	3,  // 0: broadcast.admin.v1.ListSignalsResponse.signals:type_name -> broadcast.admin.v1.Signal
+	6,  // 1: broadcast.admin.v1.ListHandlersResponse.handlers:type_name -> broadcast.admin.v1.Handler
+	15, // 2: broadcast.admin.v1.BroadcastRequest.metadata:type_name -> broadcast.admin.v1.BroadcastRequest.MetadataEntry
+	17, // 3: broadcast.admin.v1.Event.time:type_name -> google.protobuf.Timestamp
+	16, // 4: broadcast.admin.v1.Event.metadata:type_name -> broadcast.admin.v1.Event.MetadataEntry
+	18, // 5: broadcast.admin.v1.Event.payload:type_name -> google.protobuf.Value
+	18, // 6: broadcast.admin.v1.BroadcastRequest.MetadataEntry.value:type_name -> google.protobuf.Value
+	18, // 7: broadcast.admin.v1.Event.MetadataEntry.value:type_name -> google.protobuf.Value
+	0,  // 8: broadcast.admin.v1.AdminService.ListSignals:input_type -> broadcast.admin.v1.ListSignalsRequest
+	2,  // 9: broadcast.admin.v1.AdminService.GetSignal:input_type -> broadcast.admin.v1.GetSignalRequest
+	4,  // 10: broadcast.admin.v1.AdminService.ListHandlers:input_type -> broadcast.admin.v1.ListHandlersRequest
+	7,  // 11: broadcast.admin.v1.AdminService.GetStats:input_type -> broadcast.admin.v1.GetStatsRequest
+	9,  // 12: broadcast.admin.v1.AdminService.PauseSignal:input_type -> broadcast.admin.v1.SignalRequest
+	9,  // 13: broadcast.admin.v1.AdminService.ResumeSignal:input_type -> broadcast.admin.v1.SignalRequest
+	9,  // 14: broadcast.admin.v1.AdminService.CleanSignal:input_type -> broadcast.admin.v1.SignalRequest
+	10, // 15: broadcast.admin.v1.AdminService.CleanAll:input_type -> broadcast.admin.v1.CleanAllRequest
+	11, // 16: broadcast.admin.v1.AdminService.Broadcast:input_type -> broadcast.admin.v1.BroadcastRequest
+	13, // 17: broadcast.admin.v1.AdminService.Tail:input_type -> broadcast.admin.v1.TailRequest
+	1,  // 18: broadcast.admin.v1.AdminService.ListSignals:output_type -> broadcast.admin.v1.ListSignalsResponse
+	3,  // 19: broadcast.admin.v1.AdminService.GetSignal:output_type -> broadcast.admin.v1.Signal
+	5,  // 20: broadcast.admin.v1.AdminService.ListHandlers:output_type -> broadcast.admin.v1.ListHandlersResponse
+	8,  // 21: broadcast.admin.v1.AdminService.GetStats:output_type -> broadcast.admin.v1.Stats
+	12, // 22: broadcast.admin.v1.AdminService.PauseSignal:output_type -> broadcast.admin.v1.Result
+	12, // 23: broadcast.admin.v1.AdminService.ResumeSignal:output_type -> broadcast.admin.v1.Result
+	12, // 24: broadcast.admin.v1.AdminService.CleanSignal:output_type -> broadcast.admin.v1.Result
+	12, // 25: broadcast.admin.v1.AdminService.CleanAll:output_type -> broadcast.admin.v1.Result
+	12, // 26: broadcast.admin.v1.AdminService.Broadcast:output_type -> broadcast.admin.v1.Result
+	14, // 27: broadcast.admin.v1.AdminService.Tail:output_type -> broadcast.admin.v1.Event
+	18, // [18:28] is the sub-list for method output_type
+	8,  // [8:18] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_admin_proto_init() }
+func file_admin_proto_init() {
+	if File_admin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_admin_proto_rawDesc), len(file_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_admin_proto_goTypes,
+		DependencyIndexes: file_admin_proto_depIdxs,
+		MessageInfos:      file_admin_proto_msgTypes,
+	}.Build()
+	File_admin_proto = out.File
+	file_admin_proto_goTypes = nil
+	file_admin_proto_depIdxs = nil
+}
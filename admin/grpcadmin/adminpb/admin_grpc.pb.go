@@ -0,0 +1,491 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: admin.proto
+
+package adminpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdminService_ListSignals_FullMethodName  = "/broadcast.admin.v1.AdminService/ListSignals"
+	AdminService_GetSignal_FullMethodName    = "/broadcast.admin.v1.AdminService/GetSignal"
+	AdminService_ListHandlers_FullMethodName = "/broadcast.admin.v1.AdminService/ListHandlers"
+	AdminService_GetStats_FullMethodName     = "/broadcast.admin.v1.AdminService/GetStats"
+	AdminService_PauseSignal_FullMethodName  = "/broadcast.admin.v1.AdminService/PauseSignal"
+	AdminService_ResumeSignal_FullMethodName = "/broadcast.admin.v1.AdminService/ResumeSignal"
+	AdminService_CleanSignal_FullMethodName  = "/broadcast.admin.v1.AdminService/CleanSignal"
+	AdminService_CleanAll_FullMethodName     = "/broadcast.admin.v1.AdminService/CleanAll"
+	AdminService_Broadcast_FullMethodName    = "/broadcast.admin.v1.AdminService/Broadcast"
+	AdminService_Tail_FullMethodName         = "/broadcast.admin.v1.AdminService/Tail"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminService 与 admin 包的 HTTP 接口提供相同的检查与操作, 供其他语言编写的基础设施统一管理广播器.
+type AdminServiceClient interface {
+	// ListSignals 列出所有有监听器或被暂停的信号, 对应 GET /signals
+	ListSignals(ctx context.Context, in *ListSignalsRequest, opts ...grpc.CallOption) (*ListSignalsResponse, error)
+	// GetSignal 返回单个信号的监听器 key, 信号不存在时返回 NOT_FOUND, 对应 GET /signals/{signal}
+	GetSignal(ctx context.Context, in *GetSignalRequest, opts ...grpc.CallOption) (*Signal, error)
+	// ListHandlers 列出处理器、名称与调用统计, 对应 GET /handlers
+	ListHandlers(ctx context.Context, in *ListHandlersRequest, opts ...grpc.CallOption) (*ListHandlersResponse, error)
+	// GetStats 返回汇总统计, 对应 GET /stats
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*Stats, error)
+	// PauseSignal 暂停信号的广播, 对应 POST /signals/{signal}/pause
+	PauseSignal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*Result, error)
+	// ResumeSignal 恢复信号的广播, 对应 POST /signals/{signal}/resume
+	ResumeSignal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*Result, error)
+	// CleanSignal 移除信号的所有监听器, 对应 POST /signals/{signal}/clean
+	CleanSignal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*Result, error)
+	// CleanAll 移除所有信号的监听器, 对应 POST /clean
+	CleanAll(ctx context.Context, in *CleanAllRequest, opts ...grpc.CallOption) (*Result, error)
+	// Broadcast 广播一次测试事件, 对应 POST /signals/{signal}/broadcast
+	Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*Result, error)
+	// Tail 推送信号之后的每一次广播, 直到客户端取消, 对应 GET /signals/{signal}/tail
+	Tail(ctx context.Context, in *TailRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) ListSignals(ctx context.Context, in *ListSignalsRequest, opts ...grpc.CallOption) (*ListSignalsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSignalsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListSignals_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetSignal(ctx context.Context, in *GetSignalRequest, opts ...grpc.CallOption) (*Signal, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Signal)
+	err := c.cc.Invoke(ctx, AdminService_GetSignal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListHandlers(ctx context.Context, in *ListHandlersRequest, opts ...grpc.CallOption) (*ListHandlersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListHandlersResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListHandlers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*Stats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Stats)
+	err := c.cc.Invoke(ctx, AdminService_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) PauseSignal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, AdminService_PauseSignal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ResumeSignal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, AdminService_ResumeSignal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CleanSignal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, AdminService_CleanSignal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CleanAll(ctx context.Context, in *CleanAllRequest, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, AdminService_CleanAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, AdminService_Broadcast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Tail(ctx context.Context, in *TailRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[0], AdminService_Tail_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TailRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdminService_TailClient = grpc.ServerStreamingClient[Event]
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// AdminService 与 admin 包的 HTTP 接口提供相同的检查与操作, 供其他语言编写的基础设施统一管理广播器.
+type AdminServiceServer interface {
+	// ListSignals 列出所有有监听器或被暂停的信号, 对应 GET /signals
+	ListSignals(context.Context, *ListSignalsRequest) (*ListSignalsResponse, error)
+	// GetSignal 返回单个信号的监听器 key, 信号不存在时返回 NOT_FOUND, 对应 GET /signals/{signal}
+	GetSignal(context.Context, *GetSignalRequest) (*Signal, error)
+	// ListHandlers 列出处理器、名称与调用统计, 对应 GET /handlers
+	ListHandlers(context.Context, *ListHandlersRequest) (*ListHandlersResponse, error)
+	// GetStats 返回汇总统计, 对应 GET /stats
+	GetStats(context.Context, *GetStatsRequest) (*Stats, error)
+	// PauseSignal 暂停信号的广播, 对应 POST /signals/{signal}/pause
+	PauseSignal(context.Context, *SignalRequest) (*Result, error)
+	// ResumeSignal 恢复信号的广播, 对应 POST /signals/{signal}/resume
+	ResumeSignal(context.Context, *SignalRequest) (*Result, error)
+	// CleanSignal 移除信号的所有监听器, 对应 POST /signals/{signal}/clean
+	CleanSignal(context.Context, *SignalRequest) (*Result, error)
+	// CleanAll 移除所有信号的监听器, 对应 POST /clean
+	CleanAll(context.Context, *CleanAllRequest) (*Result, error)
+	// Broadcast 广播一次测试事件, 对应 POST /signals/{signal}/broadcast
+	Broadcast(context.Context, *BroadcastRequest) (*Result, error)
+	// Tail 推送信号之后的每一次广播, 直到客户端取消, 对应 GET /signals/{signal}/tail
+	Tail(*TailRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) ListSignals(context.Context, *ListSignalsRequest) (*ListSignalsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSignals not implemented")
+}
+func (UnimplementedAdminServiceServer) GetSignal(context.Context, *GetSignalRequest) (*Signal, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSignal not implemented")
+}
+func (UnimplementedAdminServiceServer) ListHandlers(context.Context, *ListHandlersRequest) (*ListHandlersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHandlers not implemented")
+}
+func (UnimplementedAdminServiceServer) GetStats(context.Context, *GetStatsRequest) (*Stats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedAdminServiceServer) PauseSignal(context.Context, *SignalRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseSignal not implemented")
+}
+func (UnimplementedAdminServiceServer) ResumeSignal(context.Context, *SignalRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeSignal not implemented")
+}
+func (UnimplementedAdminServiceServer) CleanSignal(context.Context, *SignalRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CleanSignal not implemented")
+}
+func (UnimplementedAdminServiceServer) CleanAll(context.Context, *CleanAllRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CleanAll not implemented")
+}
+func (UnimplementedAdminServiceServer) Broadcast(context.Context, *BroadcastRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Broadcast not implemented")
+}
+func (UnimplementedAdminServiceServer) Tail(*TailRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method Tail not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_ListSignals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSignalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListSignals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListSignals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListSignals(ctx, req.(*ListSignalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetSignal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSignalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetSignal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetSignal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetSignal(ctx, req.(*GetSignalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListHandlers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHandlersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListHandlers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListHandlers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListHandlers(ctx, req.(*ListHandlersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_PauseSignal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).PauseSignal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_PauseSignal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).PauseSignal(ctx, req.(*SignalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ResumeSignal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ResumeSignal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ResumeSignal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ResumeSignal(ctx, req.(*SignalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CleanSignal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CleanSignal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CleanSignal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CleanSignal(ctx, req.(*SignalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CleanAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CleanAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CleanAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CleanAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CleanAll(ctx, req.(*CleanAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Broadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Broadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Broadcast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Broadcast(ctx, req.(*BroadcastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Tail_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).Tail(m, &grpc.GenericServerStream[TailRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdminService_TailServer = grpc.ServerStreamingServer[Event]
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "broadcast.admin.v1.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSignals",
+			Handler:    _AdminService_ListSignals_Handler,
+		},
+		{
+			MethodName: "GetSignal",
+			Handler:    _AdminService_GetSignal_Handler,
+		},
+		{
+			MethodName: "ListHandlers",
+			Handler:    _AdminService_ListHandlers_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _AdminService_GetStats_Handler,
+		},
+		{
+			MethodName: "PauseSignal",
+			Handler:    _AdminService_PauseSignal_Handler,
+		},
+		{
+			MethodName: "ResumeSignal",
+			Handler:    _AdminService_ResumeSignal_Handler,
+		},
+		{
+			MethodName: "CleanSignal",
+			Handler:    _AdminService_CleanSignal_Handler,
+		},
+		{
+			MethodName: "CleanAll",
+			Handler:    _AdminService_CleanAll_Handler,
+		},
+		{
+			MethodName: "Broadcast",
+			Handler:    _AdminService_Broadcast_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Tail",
+			Handler:       _AdminService_Tail_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "admin.proto",
+}
@@ -0,0 +1,18 @@
+module pkg.blksails.net/x/broadcast/admin/grpcadmin
+
+go 1.24
+
+require (
+	google.golang.org/grpc v1.75.1
+	google.golang.org/protobuf v1.36.9
+	pkg.blksails.net/x/broadcast v0.0.0
+)
+
+require (
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+)
+
+replace pkg.blksails.net/x/broadcast => ../..
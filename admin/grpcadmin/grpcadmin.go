@@ -0,0 +1,244 @@
+// Package grpcadmin 以 gRPC 服务提供与 admin 包 HTTP 接口相同的检查与操作,
+// 供其他语言编写的基础设施统一管理广播器; 服务定义位于 adminpb/admin.proto
+//
+//	srv := grpc.NewServer()
+//	adminpb.RegisterAdminServiceServer(srv, grpcadmin.NewServer(b, grpcadmin.Config{}))
+//
+// 服务不做身份认证, 应监听内部端口或通过拦截器认证
+package grpcadmin
+
+//go:generate protoc -I adminpb --go_out=adminpb --go_opt=paths=source_relative --go-grpc_out=adminpb --go-grpc_opt=paths=source_relative admin.proto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"pkg.blksails.net/x/broadcast"
+	"pkg.blksails.net/x/broadcast/admin"
+	"pkg.blksails.net/x/broadcast/admin/grpcadmin/adminpb"
+)
+
+// Config gRPC 管理服务配置
+type Config struct {
+	// ReadOnly 为 true 时拒绝暂停、恢复、清理与广播等修改状态的调用, 返回 PERMISSION_DENIED
+	ReadOnly bool
+	// TailBuffer 为每个 Tail 调用缓冲的事件数量, 客户端读取过慢时多余的事件被丢弃, 默认为 admin.DefaultTailBuffer
+	TailBuffer int
+}
+
+// Server 实现 adminpb.AdminServiceServer
+type Server struct {
+	adminpb.UnimplementedAdminServiceServer
+
+	target admin.Target
+	config Config
+}
+
+// NewServer 创建 target 的 gRPC 管理服务
+func NewServer(target admin.Target, config Config) *Server {
+	if config.TailBuffer <= 0 {
+		config.TailBuffer = admin.DefaultTailBuffer
+	}
+	return &Server{target: target, config: config}
+}
+
+// ListSignals 列出所有有监听器或被暂停的信号
+func (s *Server) ListSignals(ctx context.Context, req *adminpb.ListSignalsRequest) (*adminpb.ListSignalsResponse, error) {
+	signals := admin.ListSignals(s.target, false)
+	resp := &adminpb.ListSignalsResponse{Signals: make([]*adminpb.Signal, len(signals))}
+	for i, signal := range signals {
+		resp.Signals[i] = toSignal(signal)
+	}
+	return resp, nil
+}
+
+// GetSignal 返回单个信号的监听器 key
+func (s *Server) GetSignal(ctx context.Context, req *adminpb.GetSignalRequest) (*adminpb.Signal, error) {
+	signal, ok := admin.LookupSignal(s.target, req.GetSignal())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "signal %q not found", req.GetSignal())
+	}
+	return toSignal(signal), nil
+}
+
+// ListHandlers 列出处理器、名称与调用统计
+func (s *Server) ListHandlers(ctx context.Context, req *adminpb.ListHandlersRequest) (*adminpb.ListHandlersResponse, error) {
+	stats := s.target.HandlerStats()
+	resp := &adminpb.ListHandlersResponse{Handlers: make([]*adminpb.Handler, len(stats))}
+	for i, h := range stats {
+		resp.Handlers[i] = &adminpb.Handler{Id: uint64(h.ID), Name: h.Name, Calls: h.Calls, Errors: h.Errors}
+	}
+	return resp, nil
+}
+
+// GetStats 返回汇总统计
+func (s *Server) GetStats(ctx context.Context, req *adminpb.GetStatsRequest) (*adminpb.Stats, error) {
+	stats := admin.CollectStats(s.target)
+	return &adminpb.Stats{
+		Signals:     int64(stats.Signals),
+		Listeners:   int64(stats.Listeners),
+		Handlers:    int64(stats.Handlers),
+		Pending:     int64(stats.Pending),
+		Buffered:    int64(stats.Buffered),
+		DeadLetters: int64(stats.DeadLetters),
+		Expired:     stats.Expired,
+		Paused:      stats.Paused,
+	}, nil
+}
+
+// PauseSignal 暂停信号的广播
+func (s *Server) PauseSignal(ctx context.Context, req *adminpb.SignalRequest) (*adminpb.Result, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	return &adminpb.Result{Signal: req.GetSignal(), Changed: s.target.Pause(req.GetSignal())}, nil
+}
+
+// ResumeSignal 恢复信号的广播
+func (s *Server) ResumeSignal(ctx context.Context, req *adminpb.SignalRequest) (*adminpb.Result, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	return &adminpb.Result{Signal: req.GetSignal(), Changed: s.target.Resume(req.GetSignal())}, nil
+}
+
+// CleanSignal 移除信号的所有监听器
+func (s *Server) CleanSignal(ctx context.Context, req *adminpb.SignalRequest) (*adminpb.Result, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	signal, _ := admin.LookupSignal(s.target, req.GetSignal())
+	s.target.Clean(req.GetSignal())
+	return &adminpb.Result{Signal: req.GetSignal(), Changed: signal.Listeners > 0}, nil
+}
+
+// CleanAll 移除所有信号的监听器
+func (s *Server) CleanAll(ctx context.Context, req *adminpb.CleanAllRequest) (*adminpb.Result, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	changed := len(s.target.State().Signals) > 0
+	s.target.CleanAll()
+	return &adminpb.Result{Changed: changed}, nil
+}
+
+// Broadcast 广播一次测试事件
+func (s *Server) Broadcast(ctx context.Context, req *adminpb.BroadcastRequest) (*adminpb.Result, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	var metadata map[string]interface{}
+	if len(req.GetMetadata()) > 0 {
+		metadata = make(map[string]interface{}, len(req.GetMetadata()))
+		for k, v := range req.GetMetadata() {
+			metadata[k] = v.AsInterface()
+		}
+	}
+	var opts []broadcast.BroadcastOption
+	if req.GetId() != "" {
+		opts = append(opts, broadcast.WithEventID(req.GetId()))
+	}
+	if req.GetSource() != "" {
+		opts = append(opts, broadcast.WithSource(req.GetSource()))
+	}
+	if err := s.target.Broadcast(req.GetSignal(), metadata, opts...); err != nil {
+		return nil, status.Error(code(err), err.Error())
+	}
+	return &adminpb.Result{Signal: req.GetSignal(), Changed: true}, nil
+}
+
+// Tail 推送信号之后的每一次广播, 直到客户端取消; 观察者不阻塞广播, 缓冲满时丢弃事件
+func (s *Server) Tail(req *adminpb.TailRequest, stream grpc.ServerStreamingServer[adminpb.Event]) error {
+	events := make(chan broadcast.Event[any], s.config.TailBuffer)
+	cancel := s.target.Tap(req.GetSignal(), func(e broadcast.Event[any]) {
+		select {
+		case events <- e:
+		default:
+		}
+	})
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e := <-events:
+			event, err := toEvent(admin.NewEvent(e))
+			if err != nil {
+				continue
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) writable() error {
+	if s.config.ReadOnly {
+		return status.Error(codes.PermissionDenied, "admin service is read-only")
+	}
+	return nil
+}
+
+// code 将广播被拒绝的原因映射为 gRPC 状态码
+func code(err error) codes.Code {
+	switch {
+	case errors.Is(err, broadcast.ErrValidation), errors.Is(err, broadcast.ErrUndeclaredSignal), errors.Is(err, broadcast.ErrPayloadType):
+		return codes.InvalidArgument
+	case errors.Is(err, broadcast.ErrRateLimited), errors.Is(err, broadcast.ErrQuotaExceeded), errors.Is(err, broadcast.ErrSampled):
+		return codes.ResourceExhausted
+	case errors.Is(err, broadcast.ErrSignalPaused), errors.Is(err, broadcast.ErrSignalUnhealthy):
+		return codes.FailedPrecondition
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, broadcast.ErrDeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+	return codes.Unknown
+}
+
+func toSignal(s admin.Signal) *adminpb.Signal {
+	return &adminpb.Signal{Signal: s.Signal, Listeners: int64(s.Listeners), Keys: s.Keys, Paused: s.Paused}
+}
+
+// toEvent 转换 admin.Event, 元数据与负载经过 JSON 转换为 structpb.Value
+func toEvent(e admin.Event) (*adminpb.Event, error) {
+	event := &adminpb.Event{Id: e.ID, Time: timestamppb.New(e.Time), Signal: e.Signal, Source: e.Source}
+	if len(e.Metadata) > 0 {
+		event.Metadata = make(map[string]*structpb.Value, len(e.Metadata))
+		for k, v := range e.Metadata {
+			value, err := toValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("metadata %q: %w", k, err)
+			}
+			event.Metadata[k] = value
+		}
+	}
+	if e.Payload != nil {
+		payload, err := toValue(e.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("payload: %w", err)
+		}
+		event.Payload = payload
+	}
+	return event, nil
+}
+
+func toValue(v any) (*structpb.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	value := new(structpb.Value)
+	if err := value.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
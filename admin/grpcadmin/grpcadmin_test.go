@@ -0,0 +1,145 @@
+package grpcadmin
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"pkg.blksails.net/x/broadcast"
+	"pkg.blksails.net/x/broadcast/admin/grpcadmin/adminpb"
+)
+
+// dial 在内存连接上启动 target 的管理服务
+func dial(t *testing.T, b *broadcast.Broadcast[string], config Config) adminpb.AdminServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	adminpb.RegisterAdminServiceServer(srv, NewServer(b, config))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return adminpb.NewAdminServiceClient(conn)
+}
+
+func TestServer_Inspect(t *testing.T) {
+	b := broadcast.New[string]()
+	b.Watch("orders", "projection")
+	b.Watch("orders", "audit")
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error { return nil }, broadcast.WithName("indexer"))
+	b.Pause("idle")
+	client := dial(t, b, Config{})
+	ctx := context.Background()
+
+	list, err := client.ListSignals(ctx, &adminpb.ListSignalsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Signals) != 2 || list.Signals[0].Signal != "idle" || !list.Signals[0].Paused || list.Signals[1].Listeners != 2 {
+		t.Errorf("unexpected signals %v", list.Signals)
+	}
+
+	orders, err := client.GetSignal(ctx, &adminpb.GetSignalRequest{Signal: "orders"})
+	if err != nil || len(orders.Keys) != 2 {
+		t.Errorf("expected the listener keys of orders, got %v (%v)", orders, err)
+	}
+	if _, err := client.GetSignal(ctx, &adminpb.GetSignalRequest{Signal: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NOT_FOUND, got %v", err)
+	}
+
+	b.Broadcast("orders", nil)
+	handlers, err := client.ListHandlers(ctx, &adminpb.ListHandlersRequest{})
+	if err != nil || len(handlers.Handlers) != 1 || handlers.Handlers[0].Name != "indexer" || handlers.Handlers[0].Calls != 2 {
+		t.Errorf("unexpected handlers %v (%v)", handlers, err)
+	}
+
+	stats, err := client.GetStats(ctx, &adminpb.GetStatsRequest{})
+	if err != nil || stats.Signals != 1 || stats.Listeners != 2 || stats.Handlers != 1 || len(stats.Paused) != 1 {
+		t.Errorf("unexpected stats %v (%v)", stats, err)
+	}
+}
+
+func TestServer_OperationsAndTail(t *testing.T) {
+	b := broadcast.New[string]()
+	b.Watch("orders", "projection")
+	b.Watch("metrics", "collector")
+	client := dial(t, b, Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if r, err := client.PauseSignal(ctx, &adminpb.SignalRequest{Signal: "orders"}); err != nil || !r.Changed {
+		t.Fatalf("expected orders to be paused, got %v (%v)", r, err)
+	}
+	if _, err := client.Broadcast(ctx, &adminpb.BroadcastRequest{Signal: "orders"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected a paused broadcast to fail with FAILED_PRECONDITION, got %v", err)
+	}
+	if r, err := client.ResumeSignal(ctx, &adminpb.SignalRequest{Signal: "orders"}); err != nil || !r.Changed {
+		t.Fatalf("expected orders to be resumed, got %v (%v)", r, err)
+	}
+
+	tail, err := client.Tail(ctx, &adminpb.TailRequest{Signal: "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tail 在服务端收到请求后才开始观察, 重复广播直到收到第一个事件
+	received := make(chan *adminpb.Event)
+	go func() {
+		for {
+			e, err := tail.Recv()
+			if err != nil {
+				close(received)
+				return
+			}
+			received <- e
+		}
+	}()
+	amount, _ := structpb.NewValue(3)
+	req := &adminpb.BroadcastRequest{Signal: "orders", Id: "evt-1", Source: "ops", Metadata: map[string]*structpb.Value{"amount": amount}}
+	var e *adminpb.Event
+	for e == nil {
+		if _, err := client.Broadcast(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case e = <-received:
+		default:
+		}
+	}
+	if e.Id != "evt-1" || e.Source != "ops" || e.Metadata["amount"].GetNumberValue() != 3 || e.Time == nil {
+		t.Errorf("unexpected tailed event %v", e)
+	}
+	broadcast.BroadcastData(b, "orders", map[string]int{"x": 1}, nil)
+	for e = range received {
+		if e.Payload != nil {
+			break
+		}
+	}
+	if e.GetPayload().GetStructValue().GetFields()["x"].GetNumberValue() != 1 {
+		t.Errorf("expected the payload to be tailed, got %v", e)
+	}
+
+	if r, err := client.CleanSignal(ctx, &adminpb.SignalRequest{Signal: "orders"}); err != nil || !r.Changed || b.WatchCount("metrics") != 1 {
+		t.Errorf("expected only orders to be cleaned, got %v (%v)", r, err)
+	}
+	if r, err := client.CleanAll(ctx, &adminpb.CleanAllRequest{}); err != nil || !r.Changed || b.TotalWatchCount() != 0 {
+		t.Errorf("expected all listeners to be cleaned, got %v (%v)", r, err)
+	}
+
+	ro := dial(t, b, Config{ReadOnly: true})
+	if _, err := ro.PauseSignal(ctx, &adminpb.SignalRequest{Signal: "orders"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PERMISSION_DENIED on a read-only server, got %v", err)
+	}
+}
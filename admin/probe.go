@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+// NewProbeHandler 返回 Kubernetes 存活与就绪探针使用的接口, checks 通常为 *broadcast.HealthChecks:
+//
+//	GET /healthz  checks.Healthy() 返回 nil 时为 200, 否则为 503 并输出错误
+//	GET /readyz   checks.Ready() 返回 nil 时为 200, 否则为 503 并输出错误
+//
+// 与 NewHandler 不同, 探针接口不暴露广播器的内部状态, 可以挂载在探针访问的端口上
+func NewProbeHandler(checks broadcast.HealthChecker) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		probe(w, checks.Healthy())
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		probe(w, checks.Ready())
+	})
+	return mux
+}
+
+func probe(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, err)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
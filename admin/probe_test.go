@@ -0,0 +1,35 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+func TestProbeHandler(t *testing.T) {
+	var ready error
+	checks := broadcast.NewHealthChecks()
+	checks.Add("nats", broadcast.HealthCheckFunc(func() error { return ready }))
+	h := NewProbeHandler(checks)
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec.Code, rec.Body.String()
+	}
+	if code, body := get("/readyz"); code != http.StatusOK || body != "ok\n" {
+		t.Errorf("expected ready, got %d %q", code, body)
+	}
+
+	ready = errors.New("connection lost")
+	if code, body := get("/readyz"); code != http.StatusServiceUnavailable || !strings.Contains(body, "nats: connection lost") {
+		t.Errorf("expected 503 naming the failing check, got %d %q", code, body)
+	}
+	if code, _ := get("/healthz"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected the function check to fail liveness too, got %d", code)
+	}
+}
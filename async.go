@@ -0,0 +1,285 @@
+package broadcast
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OverflowPolicy 定义异步队列已满时的处理策略
+type OverflowPolicy int
+
+const (
+	// Block 阻塞发布者直到队列有空位
+	Block OverflowPolicy = iota
+	// DropOldest 丢弃队列中最早的事件, 为新事件腾出位置
+	DropOldest
+	// DropNewest 丢弃新事件
+	DropNewest
+	// Callback 不入队, 将新事件交给 AsyncConfig.OnOverflow 处理
+	Callback
+)
+
+// AsyncConfig 异步投递配置
+type AsyncConfig struct {
+	// Workers 执行投递的 goroutine 数量, 默认为 1
+	// 大于 1 时不同广播之间的投递顺序不再保证
+	Workers int
+	// QueueSize 队列容量, 默认为 1024
+	QueueSize int
+	// Overflow 队列已满时的策略
+	Overflow OverflowPolicy
+	// OnOverflow 在事件因队列已满被丢弃或被 Callback 策略拒绝时调用
+	OnOverflow func(signal string, metadata map[string]interface{})
+	// KeyOrdered 开启按 key 分区的投递: 广播按监听器 key 拆分到 Workers 个分区,
+	// 相同 key 的投递在同一分区内严格按顺序执行, 不同 key 之间并发执行. 每个分区的队列容量为 QueueSize
+	KeyOrdered bool
+	// PriorityWorkers 大于 0 时为 PriorityHigh 信号创建独立通道及其工作 goroutine,
+	// 通道容量与溢出策略与主队列相同
+	PriorityWorkers int
+}
+
+// delivery 是一次待执行的广播, 在发布时捕获监听器和处理器快照
+type delivery[K comparable, T any] struct {
+	seq      uint64
+	signal   string
+	metadata map[string]interface{}
+	payload  any
+	// ttl 大于 0 时, deadline 为发布时间加 ttl
+	ttl       time.Duration
+	deadline  time.Time
+	listeners []listener[K, T]
+	handlers  []handlerEntry[T]
+	// journaled 表示广播已写入日志, 持久处理器需要确认
+	journaled bool
+	// id 为 WithEventID 设置的事件 ID
+	id string
+	// source 为 WithSource 设置的事件来源
+	source string
+	// time 为广播时间
+	time time.Time
+	// parts 非 nil 时本投递是按 key 拆分后的一部分
+	parts *deliveryParts
+	// low 为 true 时队列已满直接丢弃
+	low bool
+	// cutoff 为 WithDeadline 设置的扇出截止时间, 之后不再开始新的处理器调用
+	cutoff time.Time
+	// ctx 为 BroadcastCtx 传入的 ctx, 交给 HandleCtx 注册的处理器
+	ctx context.Context
+}
+
+// dispatcher 是有界环形队列及其工作 goroutine
+type dispatcher[K comparable, T any] struct {
+	mu       sync.Mutex
+	notEmpty sync.Cond
+	notFull  sync.Cond
+	items    []delivery[K, T]
+	head     int
+	size     int
+	closed   bool
+
+	config AsyncConfig
+	run    func(d delivery[K, T])
+	// drop 在投递因队列已满被丢弃时调用
+	drop func(d delivery[K, T])
+	wg   sync.WaitGroup
+
+	// partitions 非 nil 时本队列不执行投递, 只按 key 转发到各分区
+	partitions []*dispatcher[K, T]
+	// lane 非 nil 时为高优先级信号的独立通道
+	lane *dispatcher[K, T]
+	// shared 非 nil 时投递进入 Hub 的共享工作池, 本队列不持有 goroutine
+	shared *workerPool
+}
+
+func newDispatcher[K comparable, T any](config AsyncConfig, run, drop func(d delivery[K, T])) *dispatcher[K, T] {
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1024
+	}
+
+	if config.KeyOrdered {
+		return newPartitioned(config, run, drop)
+	}
+
+	d := &dispatcher[K, T]{
+		items:  make([]delivery[K, T], config.QueueSize),
+		config: config,
+		run:    run,
+		drop:   drop,
+	}
+	d.notEmpty.L = &d.mu
+	d.notFull.L = &d.mu
+
+	d.wg.Add(config.Workers)
+	for i := 0; i < config.Workers; i++ {
+		go d.work()
+	}
+	return d
+}
+
+// push 将投递放入队列, 队列已关闭时返回 false, 由调用方同步执行
+func (d *dispatcher[K, T]) push(item delivery[K, T]) bool {
+	if d.partitions != nil {
+		return d.route(item)
+	}
+	if d.shared != nil {
+		return d.submit(item)
+	}
+
+	d.mu.Lock()
+	for d.size == len(d.items) && !d.closed {
+		policy := d.config.Overflow
+		if item.low {
+			policy = DropNewest
+		}
+		switch policy {
+		case DropOldest:
+			dropped := d.items[d.head]
+			d.items[d.head] = delivery[K, T]{}
+			d.head = (d.head + 1) % len(d.items)
+			d.size--
+			d.mu.Unlock()
+			d.overflow(dropped)
+			d.mu.Lock()
+		case DropNewest, Callback:
+			d.mu.Unlock()
+			d.overflow(item)
+			return true
+		default:
+			d.notFull.Wait()
+		}
+	}
+	if d.closed {
+		d.mu.Unlock()
+		return false
+	}
+
+	d.items[(d.head+d.size)%len(d.items)] = item
+	d.size++
+	d.mu.Unlock()
+	d.notEmpty.Signal()
+	return true
+}
+
+func (d *dispatcher[K, T]) overflow(item delivery[K, T]) {
+	if d.drop != nil {
+		d.drop(item)
+	}
+	if d.config.OnOverflow != nil {
+		d.config.OnOverflow(item.signal, item.metadata)
+	}
+}
+
+func (d *dispatcher[K, T]) pop() (delivery[K, T], bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for d.size == 0 {
+		if d.closed {
+			return delivery[K, T]{}, false
+		}
+		d.notEmpty.Wait()
+	}
+
+	item := d.items[d.head]
+	d.items[d.head] = delivery[K, T]{}
+	d.head = (d.head + 1) % len(d.items)
+	d.size--
+	d.notFull.Signal()
+	return item, true
+}
+
+func (d *dispatcher[K, T]) work() {
+	defer d.wg.Done()
+
+	for {
+		item, ok := d.pop()
+		if !ok {
+			return
+		}
+		d.run(item)
+	}
+}
+
+// len 返回队列中等待投递的事件数量
+func (d *dispatcher[K, T]) len() int {
+	if d.shared != nil {
+		return d.shared.len()
+	}
+	n := 0
+	if d.lane != nil {
+		n += d.lane.len()
+	}
+	if d.partitions != nil {
+		for _, p := range d.partitions {
+			n += p.len()
+		}
+		return n
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return n + d.size
+}
+
+// close 停止接收新事件, 等待队列中已有的事件投递完成
+func (d *dispatcher[K, T]) close() {
+	if d.shared != nil {
+		// 共享工作池由 Hub 关闭
+		return
+	}
+	if d.lane != nil {
+		d.lane.close()
+	}
+	for _, p := range d.partitions {
+		p.close()
+	}
+
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+	d.notEmpty.Broadcast()
+	d.notFull.Broadcast()
+
+	d.wg.Wait()
+}
+
+func (c *core[K, T]) enableAsync(config AsyncConfig) {
+	d := newDispatcher(config, c.run, c.overflowed)
+	if config.PriorityWorkers > 0 {
+		lane := config
+		lane.Workers = config.PriorityWorkers
+		d.lane = newDispatcher(lane, c.run, c.overflowed)
+	}
+
+	var previous *dispatcher[K, T]
+	c.updateSettings(func(s *settings[K, T]) {
+		previous = s.async
+		s.async = d
+	})
+	if previous != nil {
+		previous.close()
+	}
+}
+
+func (c *core[K, T]) close() {
+	var previous *dispatcher[K, T]
+	c.updateSettings(func(s *settings[K, T]) {
+		previous = s.async
+		s.async = nil
+	})
+	if previous != nil {
+		previous.close()
+	}
+}
+
+func (c *core[K, T]) pending() int {
+	if d := c.loadSettings().async; d != nil {
+		return d.len()
+	}
+	return 0
+}
@@ -0,0 +1,166 @@
+package broadcast
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gatedBroadcast 返回一个处理器被 gate 阻塞的异步广播器, 以及收到的 metadata["seq"] 列表
+func gatedBroadcast(t *testing.T, config AsyncConfig) (*Broadcast[string], chan struct{}, func() []int) {
+	t.Helper()
+
+	b := New[string]()
+	gate := make(chan struct{})
+	var (
+		mu       sync.Mutex
+		received []int
+	)
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		<-gate
+		mu.Lock()
+		received = append(received, metadata["seq"].(int))
+		mu.Unlock()
+		return nil
+	})
+	b.Watch("test", "data")
+	b.EnableAsync(config)
+
+	// 第一个事件被工作 goroutine 取走并阻塞在 gate 上
+	b.Broadcast("test", map[string]interface{}{"seq": 0})
+	waitFor(t, func() bool { return b.Pending() == 0 })
+
+	return b, gate, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), received...)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAsync_DropOldest(t *testing.T) {
+	var dropped []int
+	b, gate, received := gatedBroadcast(t, AsyncConfig{
+		QueueSize: 2,
+		Overflow:  DropOldest,
+		OnOverflow: func(signal string, metadata map[string]interface{}) {
+			dropped = append(dropped, metadata["seq"].(int))
+		},
+	})
+
+	for i := 1; i <= 4; i++ {
+		b.Broadcast("test", map[string]interface{}{"seq": i})
+	}
+	close(gate)
+	b.Close()
+
+	if got := received(); len(got) != 3 || got[0] != 0 || got[1] != 3 || got[2] != 4 {
+		t.Errorf("expected [0 3 4] delivered, got %v", got)
+	}
+	if len(dropped) != 2 || dropped[0] != 1 || dropped[1] != 2 {
+		t.Errorf("expected [1 2] dropped, got %v", dropped)
+	}
+}
+
+func TestAsync_DropNewest(t *testing.T) {
+	var dropped atomic.Int64
+	b, gate, received := gatedBroadcast(t, AsyncConfig{
+		QueueSize: 2,
+		Overflow:  DropNewest,
+		OnOverflow: func(signal string, metadata map[string]interface{}) {
+			dropped.Add(1)
+		},
+	})
+
+	for i := 1; i <= 4; i++ {
+		b.Broadcast("test", map[string]interface{}{"seq": i})
+	}
+	close(gate)
+	b.Close()
+
+	if got := received(); len(got) != 3 || got[1] != 1 || got[2] != 2 {
+		t.Errorf("expected [0 1 2] delivered, got %v", got)
+	}
+	if dropped.Load() != 2 {
+		t.Errorf("expected 2 dropped, got %d", dropped.Load())
+	}
+}
+
+func TestAsync_Callback(t *testing.T) {
+	var rejected []int
+	b, gate, received := gatedBroadcast(t, AsyncConfig{
+		QueueSize: 1,
+		Overflow:  Callback,
+		OnOverflow: func(signal string, metadata map[string]interface{}) {
+			rejected = append(rejected, metadata["seq"].(int))
+		},
+	})
+
+	b.Broadcast("test", map[string]interface{}{"seq": 1})
+	b.Broadcast("test", map[string]interface{}{"seq": 2})
+	close(gate)
+	b.Close()
+
+	if got := received(); len(got) != 2 {
+		t.Errorf("expected 2 delivered, got %v", got)
+	}
+	if len(rejected) != 1 || rejected[0] != 2 {
+		t.Errorf("expected [2] passed to callback, got %v", rejected)
+	}
+}
+
+func TestAsync_Block(t *testing.T) {
+	b, gate, received := gatedBroadcast(t, AsyncConfig{QueueSize: 1, Overflow: Block})
+
+	b.Broadcast("test", map[string]interface{}{"seq": 1})
+
+	published := make(chan struct{})
+	go func() {
+		b.Broadcast("test", map[string]interface{}{"seq": 2})
+		close(published)
+	}()
+
+	select {
+	case <-published:
+		t.Fatal("publisher should block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(gate)
+	<-published
+	b.Close()
+
+	if got := received(); len(got) != 3 {
+		t.Errorf("expected all 3 events delivered, got %v", got)
+	}
+}
+
+func TestAsync_CloseFallsBackToSync(t *testing.T) {
+	b := New[string]()
+	calls := 0
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		calls++
+		return nil
+	})
+	b.Watch("test", "data")
+
+	b.EnableAsync(AsyncConfig{Workers: 4})
+	b.Close()
+
+	b.Broadcast("test", nil)
+	if calls != 1 {
+		t.Errorf("broadcast after Close should be synchronous, got %d calls", calls)
+	}
+}
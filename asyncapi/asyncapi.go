@@ -0,0 +1,102 @@
+// Package asyncapi 将广播器的信号目录导出为 AsyncAPI 文档
+// 信号、负载结构与传输方式在 Catalog 中声明, 负载的 JSON Schema 通过反射生成或直接注册,
+// 其他团队可以据此发现事件契约并生成代码
+package asyncapi
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Version 生成文档使用的 AsyncAPI 版本
+const Version = "2.6.0"
+
+// Info 文档的基本信息
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server 是承载信号的传输方式, 例如桥接使用的 NATS 或 WebSocket
+type Server struct {
+	Name        string `json:"-"`
+	URL         string `json:"url"`
+	Protocol    string `json:"protocol"`
+	Description string `json:"description,omitempty"`
+}
+
+// Channel 是一个已声明的信号
+type Channel struct {
+	Signal      string
+	Description string
+	// Payload 是负载类型的示例值, 用于反射生成 Schema, 例如 PriceTick{}
+	Payload any
+	// Schema 非 nil 时直接使用, 忽略 Payload
+	Schema map[string]any
+}
+
+// Catalog 信号目录
+type Catalog struct {
+	Info     Info
+	Servers  []Server
+	Channels []Channel
+}
+
+// Add 声明一个信号
+func (c *Catalog) Add(signal string, description string, payload any) {
+	c.Channels = append(c.Channels, Channel{Signal: signal, Description: description, Payload: payload})
+}
+
+// Document 生成 AsyncAPI 文档
+func (c *Catalog) Document() map[string]any {
+	doc := map[string]any{
+		"asyncapi": Version,
+		"info":     c.Info,
+	}
+
+	if len(c.Servers) > 0 {
+		servers := make(map[string]Server, len(c.Servers))
+		for _, s := range c.Servers {
+			servers[s.Name] = s
+		}
+		doc["servers"] = servers
+	}
+
+	channels := make(map[string]any, len(c.Channels))
+	for _, ch := range c.Channels {
+		schema := ch.Schema
+		if schema == nil {
+			schema = Schema(ch.Payload)
+		}
+		channel := map[string]any{
+			"subscribe": map[string]any{
+				"message": map[string]any{
+					"name":    ch.Signal,
+					"payload": schema,
+				},
+			},
+		}
+		if ch.Description != "" {
+			channel["description"] = ch.Description
+		}
+		channels[ch.Signal] = channel
+	}
+	doc["channels"] = channels
+	return doc
+}
+
+// MarshalJSON 将目录编码为 AsyncAPI JSON 文档
+func (c *Catalog) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Document())
+}
+
+// Signals 返回已声明的信号名, 按字典序排列
+func (c *Catalog) Signals() []string {
+	signals := make([]string, 0, len(c.Channels))
+	for _, ch := range c.Channels {
+		signals = append(signals, ch.Signal)
+	}
+	sort.Strings(signals)
+	return signals
+}
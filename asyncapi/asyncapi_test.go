@@ -0,0 +1,101 @@
+package asyncapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Meta struct {
+	Source string `json:"source"`
+}
+
+type PriceTick struct {
+	Meta
+	Symbol  string            `json:"symbol"`
+	Price   float64           `json:"price"`
+	Volume  int64             `json:"volume,omitempty"`
+	At      time.Time         `json:"at"`
+	Tags    []string          `json:"tags,omitempty"`
+	Extra   map[string]string `json:"extra,omitempty"`
+	Next    *PriceTick        `json:"next"`
+	Ignored string            `json:"-"`
+	private int
+}
+
+func TestSchema(t *testing.T) {
+	s := Schema(PriceTick{})
+	props := s["properties"].(map[string]any)
+
+	for name, want := range map[string]string{
+		"source": "string",
+		"symbol": "string",
+		"price":  "number",
+		"volume": "integer",
+		"tags":   "array",
+		"extra":  "object",
+		"next":   "object",
+	} {
+		if got := props[name].(map[string]any)["type"]; got != want {
+			t.Errorf("%s: expected type %s, got %v", name, want, got)
+		}
+	}
+	if props["at"].(map[string]any)["format"] != "date-time" {
+		t.Errorf("expected time.Time as date-time, got %v", props["at"])
+	}
+	if _, ok := props["Ignored"]; ok {
+		t.Error("expected json:\"-\" fields to be skipped")
+	}
+	if _, ok := props["private"]; ok {
+		t.Error("expected unexported fields to be skipped")
+	}
+	if want := []string{"source", "symbol", "price", "at"}; !reflect.DeepEqual(s["required"], want) {
+		t.Errorf("expected required %v, got %v", want, s["required"])
+	}
+}
+
+func TestCatalog_MarshalJSON(t *testing.T) {
+	c := &Catalog{
+		Info:    Info{Title: "market", Version: "1.0.0"},
+		Servers: []Server{{Name: "production", URL: "nats://bus:4222", Protocol: "nats"}},
+	}
+	c.Add("price.tick", "Latest trade price", PriceTick{})
+	c.Channels = append(c.Channels, Channel{Signal: "heartbeat", Schema: map[string]any{"type": "null"}})
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		AsyncAPI string `json:"asyncapi"`
+		Servers  map[string]struct {
+			Protocol string `json:"protocol"`
+		} `json:"servers"`
+		Channels map[string]struct {
+			Description string `json:"description"`
+			Subscribe   struct {
+				Message struct {
+					Payload map[string]any `json:"payload"`
+				} `json:"message"`
+			} `json:"subscribe"`
+		} `json:"channels"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.AsyncAPI != Version || doc.Servers["production"].Protocol != "nats" {
+		t.Errorf("unexpected document header: %s", data)
+	}
+	if doc.Channels["price.tick"].Description != "Latest trade price" {
+		t.Errorf("unexpected channel: %s", data)
+	}
+	if doc.Channels["heartbeat"].Subscribe.Message.Payload["type"] != "null" {
+		t.Errorf("expected registered schema to be used: %s", data)
+	}
+	if got := c.Signals(); !reflect.DeepEqual(got, []string{"heartbeat", "price.tick"}) {
+		t.Errorf("unexpected signals %v", got)
+	}
+}
@@ -0,0 +1,129 @@
+package broadcast
+
+import (
+	"context"
+	"fmt"
+	"unique"
+)
+
+// Operation 是需要授权的操作
+type Operation int
+
+const (
+	// OpWatch 添加监听器
+	OpWatch Operation = iota
+	// OpUnwatch 移除监听器
+	OpUnwatch
+	// OpBroadcast 广播信号
+	OpBroadcast
+)
+
+func (op Operation) String() string {
+	switch op {
+	case OpWatch:
+		return "watch"
+	case OpUnwatch:
+		return "unwatch"
+	case OpBroadcast:
+		return "broadcast"
+	default:
+		return fmt.Sprintf("Operation(%d)", int(op))
+	}
+}
+
+// Authorizer 在添加或移除监听器 (Watch、UpdateWatch、WatchGroup、WatchLease、Clean、Batch.Commit 等)
+// 与 Broadcast 之前调用, 返回错误时拒绝该操作; 不返回错误的方法在被拒绝时不做任何修改
+// signal 为加上命名空间前缀后的完整信号名; principal 来自 ContextWithPrincipal,
+// 没有通过 ctx 传入身份的调用 (例如 Watch 与 Broadcast) 为 nil
+type Authorizer func(op Operation, signal string, principal any) error
+
+type principalCtxKey struct{}
+
+// ContextWithPrincipal 返回携带调用方身份的 ctx, 交给 WatchCtx、UnwatchCtx 与 BroadcastCtx 用于授权
+func ContextWithPrincipal(ctx context.Context, principal any) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, principal)
+}
+
+// Principal 返回 ctx 中的调用方身份, ctx 为 nil 或不存在时为 nil
+func Principal(ctx context.Context) any {
+	if ctx == nil {
+		return nil
+	}
+	return ctx.Value(principalCtxKey{})
+}
+
+func (c *core[K, T]) setAuthorizer(a Authorizer) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.authorizer = a
+	})
+}
+
+// authorize 检查 ctx 中的身份是否允许对信号执行 op, 没有设置 Authorizer 时总是允许
+func (c *core[K, T]) authorize(ctx context.Context, op Operation, signal string) error {
+	a := c.loadSettings().authorizer
+	if a == nil {
+		return nil
+	}
+	return a(op, signal, Principal(ctx))
+}
+
+// permit 以没有身份的调用授权 op, 供不接收 ctx 的修改操作使用
+// 租约到期、ctx 取消等由广播器自身发起的移除不经过授权
+func (c *core[K, T]) permit(op Operation, signal string) bool {
+	return c.authorize(nil, op, signal) == nil
+}
+
+// watchCtx 授权后添加监听器
+func (c *core[K, T]) watchCtx(ctx context.Context, signal string, l listener[K, T]) (bool, error) {
+	if err := c.authorize(ctx, OpWatch, signal); err != nil {
+		return false, err
+	}
+	return c.watchRated(signal, l)
+}
+
+// unwatchCtx 授权后移除监听器
+func (c *core[K, T]) unwatchCtx(ctx context.Context, signal string, key unique.Handle[K]) (bool, error) {
+	if err := c.authorize(ctx, OpUnwatch, signal); err != nil {
+		return false, err
+	}
+	return c.unwatch(signal, key), nil
+}
+
+func (b *Broadcast[T]) authorizeBroadcast(ctx context.Context, signal string) error {
+	return b.c().authorize(ctx, OpBroadcast, b.sig(signal))
+}
+
+func (b *UniqueBroadcast[K, T]) authorizeBroadcast(ctx context.Context, signal string) error {
+	return b.core.authorize(ctx, OpBroadcast, signal)
+}
+
+// SetAuthorizer 设置授权钩子, 传入 nil 关闭授权, 也可通过 WithAuthorizer 设置
+func (b *Broadcast[T]) SetAuthorizer(a Authorizer) {
+	b.c().setAuthorizer(a)
+}
+
+// WatchCtx 以 ctx 中的身份授权后监听信号, 返回监听器是否被添加; 授权失败时返回 Authorizer 的错误
+// 与 WatchContext 不同, 监听器不随 ctx 取消而移除
+func (b *Broadcast[T]) WatchCtx(ctx context.Context, signal string, data T, opts ...WatchOption) (bool, error) {
+	return b.c().watchCtx(ctx, b.sig(signal), newListener[T, T](&uniqueWrapper[T]{data: data}).withOptions(opts))
+}
+
+// UnwatchCtx 以 ctx 中的身份授权后取消监听, 返回是否有监听器被移除; 授权失败时返回 Authorizer 的错误
+func (b *Broadcast[T]) UnwatchCtx(ctx context.Context, signal string, data T) (bool, error) {
+	return b.c().unwatchCtx(ctx, b.sig(signal), unique.Make(data))
+}
+
+// SetAuthorizer 设置授权钩子, 传入 nil 关闭授权, 也可通过 WithAuthorizer 设置
+func (b *UniqueBroadcast[K, T]) SetAuthorizer(a Authorizer) {
+	b.core.setAuthorizer(a)
+}
+
+// WatchCtx 以 ctx 中的身份授权后监听信号, 与 Broadcast.WatchCtx 相同
+func (b *UniqueBroadcast[K, T]) WatchCtx(ctx context.Context, signal string, data Uniquer[K, T], opts ...WatchOption) (bool, error) {
+	return b.core.watchCtx(ctx, signal, newListener(data).withOptions(opts))
+}
+
+// UnwatchCtx 以 ctx 中的身份授权后按 key 取消监听, 与 Broadcast.UnwatchCtx 相同
+func (b *UniqueBroadcast[K, T]) UnwatchCtx(ctx context.Context, signal string, key K) (bool, error) {
+	return b.core.unwatchCtx(ctx, signal, unique.Make(key))
+}
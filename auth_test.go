@@ -0,0 +1,189 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errForbidden = errors.New("forbidden")
+
+// tenantAuthorizer 只允许身份操作以自己名字开头的信号, 没有身份的调用只能监听 public.
+func tenantAuthorizer(calls *[]string) Authorizer {
+	return func(op Operation, signal string, principal any) error {
+		*calls = append(*calls, op.String()+":"+signal)
+		user, _ := principal.(string)
+		if user == "" {
+			if op == OpWatch && strings.HasPrefix(signal, "public.") {
+				return nil
+			}
+			return errForbidden
+		}
+		if !strings.HasPrefix(signal, user+".") {
+			return errForbidden
+		}
+		return nil
+	}
+}
+
+func TestAuthorizer_WatchUnwatchBroadcast(t *testing.T) {
+	var calls []string
+	b := New[string](WithAuthorizer(tenantAuthorizer(&calls)))
+	alice := ContextWithPrincipal(context.Background(), "alice")
+
+	if ok, err := b.WatchCtx(alice, "alice.orders", "c1"); !ok || err != nil {
+		t.Fatalf("expected alice to watch her signal, got %v %v", ok, err)
+	}
+	if _, err := b.WatchCtx(alice, "bob.orders", "c1"); !errors.Is(err, errForbidden) {
+		t.Errorf("expected alice to be denied bob's signal, got %v", err)
+	}
+	if b.Watch("alice.orders", "c2") {
+		t.Error("expected an anonymous Watch on a private signal to be denied")
+	}
+	if !b.Watch("public.news", "c2") {
+		t.Error("expected an anonymous Watch on a public signal to be allowed")
+	}
+
+	var delivered int
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		delivered++
+		return nil
+	})
+	if err := b.BroadcastCtx(alice, "alice.orders", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Broadcast("alice.orders", nil); !errors.Is(err, errForbidden) {
+		t.Errorf("expected an anonymous Broadcast to be denied, got %v", err)
+	}
+	if delivered != 1 {
+		t.Errorf("expected only the authorized broadcast to be delivered, got %d", delivered)
+	}
+
+	if b.Unwatch("alice.orders", "c1") || b.WatchCount("alice.orders") != 1 {
+		t.Error("expected an anonymous Unwatch to be denied")
+	}
+	if ok, err := b.UnwatchCtx(alice, "alice.orders", "c1"); !ok || err != nil {
+		t.Errorf("expected alice to unwatch, got %v %v", ok, err)
+	}
+
+	if calls[0] != "watch:alice.orders" {
+		t.Errorf("expected the authorizer to see the operation and signal, got %v", calls)
+	}
+
+	b.SetAuthorizer(nil)
+	if err := b.Broadcast("alice.orders", nil); err != nil {
+		t.Errorf("expected no authorization after SetAuthorizer(nil), got %v", err)
+	}
+}
+
+func TestAuthorizer_NamespaceAndPublishAll(t *testing.T) {
+	var calls []string
+	b := NewUnique[string, string]()
+	b.SetAuthorizer(tenantAuthorizer(&calls))
+	bob := ContextWithPrincipal(context.Background(), "bob")
+
+	if ok, err := b.WatchCtx(bob, "bob.jobs", owner{"w1", "worker"}); !ok || err != nil {
+		t.Fatalf("expected bob to watch, got %v %v", ok, err)
+	}
+	if _, err := b.UnwatchCtx(bob, "alice.jobs", "w1"); !errors.Is(err, errForbidden) {
+		t.Errorf("expected bob to be denied alice's signal, got %v", err)
+	}
+
+	err := PublishAll(bob, []PublishEntry{{B: b, Signal: "bob.jobs"}, {B: b, Signal: "alice.jobs"}})
+	if !errors.Is(err, errForbidden) {
+		t.Errorf("expected PublishAll to fail in the prepare phase, got %v", err)
+	}
+
+	ns := New[string]().Namespace("carol.")
+	ns.SetAuthorizer(tenantAuthorizer(&calls))
+	carol := ContextWithPrincipal(context.Background(), "carol")
+	if ok, err := ns.WatchCtx(carol, "inbox", "c"); !ok || err != nil {
+		t.Errorf("expected the authorizer to see the full namespaced signal, got %v %v", ok, err)
+	}
+}
+
+// denyAll 拒绝所有监听器的添加与移除
+func denyAll(op Operation, signal string, principal any) error {
+	if op == OpBroadcast {
+		return nil
+	}
+	return errForbidden
+}
+
+func TestAuthorizer_EveryWatchEntryPoint(t *testing.T) {
+	b := NewUnique[string, string](WithAuthorizer(denyAll))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b.WatchGroup("g", "locks", owner{"db", "a"})
+	b.UpdateWatch("locks", owner{"cache", "a"})
+	b.WatchContext(ctx, "locks", owner{"queue", "a"})
+	if _, ok := b.WatchLease("locks", owner{"lease", "a"}, time.Hour); ok {
+		t.Error("expected WatchLease to be denied")
+	}
+	if _, added := b.WatchIfAbsent("locks", owner{"file", "a"}); added {
+		t.Error("expected WatchIfAbsent to be denied")
+	}
+	if err := b.Batch().Watch("locks", owner{"batch", "a"}).Commit(); !errors.Is(err, errForbidden) {
+		t.Errorf("expected Batch.Commit to return the authorizer error, got %v", err)
+	}
+	if n := b.WatchCount("locks"); n != 0 {
+		t.Errorf("expected every denied entry point to leave no listeners, got %d", n)
+	}
+
+	plain := New[string](WithAuthorizer(denyAll))
+	plain.WatchGroup("g", "orders", "c1")
+	plain.WatchContext(ctx, "orders", "c2")
+	if _, ok := plain.WatchLease("orders", "c3", time.Hour); ok {
+		t.Error("expected WatchLease to be denied")
+	}
+	other := New[string]()
+	other.Watch("orders", "c4")
+	if plain.Absorb(other) != 0 || plain.WatchCount("orders") != 0 {
+		t.Errorf("expected no listeners, got %d", plain.WatchCount("orders"))
+	}
+}
+
+func TestAuthorizer_EveryUnwatchEntryPoint(t *testing.T) {
+	var deny bool
+	b := NewUnique[string, string](WithAuthorizer(func(op Operation, signal string, principal any) error {
+		if deny && op != OpBroadcast {
+			return errForbidden
+		}
+		return nil
+	}))
+	b.WatchGroup("g", "locks", owner{"db", "a"})
+	b.Watch("locks", owner{"cache", "a"})
+	b.Watch("audit", owner{"cache", "a"})
+	version := b.Version("locks")
+	b.Watch("locks", owner{"queue", "a"})
+	deny = true
+
+	b.UnwatchGroup("g")
+	b.UnwatchAll("cache")
+	b.UnwatchIf("locks", "queue", func(string) bool { return true })
+	b.CompareAndSwapWatch("locks", owner{"queue", "b"}, func(string) bool { return true })
+	b.CleanKeys(func(string) bool { return true })
+	b.Clean("locks")
+	b.CleanAll()
+	if err := b.Batch().Unwatch("locks", "db").Commit(); !errors.Is(err, errForbidden) {
+		t.Errorf("expected Batch.Commit to return the authorizer error, got %v", err)
+	}
+	if err := b.RollbackTo("locks", version); !errors.Is(err, errForbidden) {
+		t.Errorf("expected RollbackTo to return the authorizer error, got %v", err)
+	}
+	if b.WatchCount("locks") != 3 || b.WatchCount("audit") != 1 {
+		t.Errorf("expected every denied entry point to keep the listeners, got %d and %d", b.WatchCount("locks"), b.WatchCount("audit"))
+	}
+	if actual, _ := b.Get("locks", "queue"); actual != "a" {
+		t.Errorf("expected CompareAndSwapWatch to be denied, got %q", actual)
+	}
+
+	// 允许后, 被拒绝时保留在分组中的监听器仍可通过 UnwatchGroup 移除
+	deny = false
+	if n := b.UnwatchGroup("g"); n != 1 {
+		t.Errorf("expected the group to still hold its listener, removed %d", n)
+	}
+}
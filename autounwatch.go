@@ -0,0 +1,87 @@
+package broadcast
+
+import (
+	"sync"
+	"unique"
+)
+
+// AutoUnwatchConfig 自动移除持续失败的监听器的配置
+// 同一信号上某个 key 对任一处理器连续失败 After 次后, 监听器被移除, 与隔离不同, 移除后不会再恢复
+type AutoUnwatchConfig[K comparable] struct {
+	// After 连续失败达到该次数时移除监听器, 默认为 3
+	After int
+	// OnUnwatch 在监听器被自动移除后调用, err 为最后一次失败的错误
+	OnUnwatch func(signal string, key K, err error)
+}
+
+// autoUnwatch 记录每个 (信号, key) 对各处理器的连续失败次数
+type autoUnwatch[K comparable] struct {
+	mu       sync.Mutex
+	config   AutoUnwatchConfig[K]
+	failures map[signalKey[K]]map[HandlerID]int
+}
+
+func newAutoUnwatch[K comparable](config AutoUnwatchConfig[K]) *autoUnwatch[K] {
+	if config.After <= 0 {
+		config.After = 3
+	}
+	return &autoUnwatch[K]{config: config, failures: make(map[signalKey[K]]map[HandlerID]int)}
+}
+
+// record 记录一次调用的结果, 返回是否达到阈值, 达到阈值时清空该 key 的计数
+func (a *autoUnwatch[K]) record(signal string, key unique.Handle[K], id HandlerID, err error) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	k := signalKey[K]{signal, key}
+	counts, ok := a.failures[k]
+	if err == nil {
+		if ok {
+			delete(counts, id)
+			if len(counts) == 0 {
+				delete(a.failures, k)
+			}
+		}
+		return false
+	}
+	if !ok {
+		counts = make(map[HandlerID]int)
+		a.failures[k] = counts
+	}
+	counts[id]++
+	if counts[id] < a.config.After {
+		return false
+	}
+	delete(a.failures, k)
+	return true
+}
+
+// recordAutoUnwatch 记录一次调用的结果, 连续失败达到阈值时移除监听器并调用回调
+func (c *core[K, T]) recordAutoUnwatch(a *autoUnwatch[K], signal string, key unique.Handle[K], id HandlerID, err error) {
+	if !a.record(signal, key, id, err) {
+		return
+	}
+	if c.unwatch(signal, key) && a.config.OnUnwatch != nil {
+		a.config.OnUnwatch(signal, key.Value(), err)
+	}
+}
+
+func (c *core[K, T]) setAutoUnwatch(config *AutoUnwatchConfig[K]) {
+	c.updateSettings(func(s *settings[K, T]) {
+		if config != nil {
+			s.autoUnwatch = newAutoUnwatch(*config)
+		} else {
+			s.autoUnwatch = nil
+		}
+	})
+}
+
+// SetAutoUnwatch 开启对持续失败的监听器的自动移除, 传入 nil 关闭
+func (b *Broadcast[T]) SetAutoUnwatch(config *AutoUnwatchConfig[T]) {
+	b.c().setAutoUnwatch(config)
+}
+
+// SetAutoUnwatch 开启对持续失败的监听器的自动移除, 传入 nil 关闭
+func (b *UniqueBroadcast[K, T]) SetAutoUnwatch(config *AutoUnwatchConfig[K]) {
+	b.core.setAutoUnwatch(config)
+}
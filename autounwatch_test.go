@@ -0,0 +1,48 @@
+package broadcast
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAutoUnwatch(t *testing.T) {
+	b := NewUnique[string, string]()
+	var removed []string
+	b.SetAutoUnwatch(&AutoUnwatchConfig[string]{
+		After: 3,
+		OnUnwatch: func(signal string, key string, err error) {
+			removed = append(removed, signal+"/"+key+": "+err.Error())
+		},
+	})
+	b.Watch("hooks", owner{"good", "ok"})
+	b.Watch("hooks", owner{"bad", "broken"})
+
+	calls := map[string]int{}
+	b.Handle(func(signal string, data string, _ map[string]interface{}) error {
+		calls[data]++
+		if data == "broken" {
+			return errors.New("410 gone")
+		}
+		return nil
+	})
+
+	for i := 0; i < 5; i++ {
+		b.Broadcast("hooks", nil)
+	}
+	if b.Has("hooks", "bad") || !b.Has("hooks", "good") {
+		t.Error("expected only the broken listener to be removed")
+	}
+	if calls["broken"] != 3 || calls["ok"] != 5 {
+		t.Errorf("expected 3 attempts before removal, got %v", calls)
+	}
+	if len(removed) != 1 || removed[0] != "hooks/bad: 410 gone" {
+		t.Errorf("expected one removal event, got %v", removed)
+	}
+
+	// 重新添加后从零开始计数
+	b.Watch("hooks", owner{"bad", "broken"})
+	b.Broadcast("hooks", nil)
+	if !b.Has("hooks", "bad") {
+		t.Error("expected a re-added listener to start with a clean count")
+	}
+}
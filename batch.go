@@ -0,0 +1,226 @@
+package broadcast
+
+import (
+	"fmt"
+	"slices"
+	"unique"
+)
+
+type batchKind int
+
+const (
+	batchWatch batchKind = iota
+	batchUnwatch
+	batchClean
+)
+
+// batchOp 是批量操作中的一步
+type batchOp[K comparable, T any] struct {
+	kind   batchKind
+	signal string
+	l      listener[K, T]
+}
+
+// applyBatch 以全有或全无的方式应用批量操作
+// 涉及的信号按名称顺序加锁, 所有步骤在锁内基于副本校验, 全部成功后才替换监听器切片,
+// 因此任何广播看到的都是某个信号在批量操作之前或之后的完整状态
+func (c *core[K, T]) applyBatch(ops []batchOp[K, T]) error {
+	if len(ops) == 0 {
+		return nil
+	}
+
+	create := make(map[string]bool)
+	for _, op := range ops {
+		create[op.signal] = create[op.signal] || op.kind == batchWatch
+		authOp := OpUnwatch
+		if op.kind == batchWatch {
+			authOp = OpWatch
+		}
+		if err := c.authorize(nil, authOp, op.signal); err != nil {
+			return err
+		}
+		if op.kind == batchWatch {
+			if err := c.validate(op.signal, op.l.data.Value()); err != nil {
+				return err
+			}
+		}
+	}
+	signals := make([]string, 0, len(create))
+	for signal, watched := range create {
+		if watched && !c.allowWatch(signal) {
+			return fmt.Errorf("%w: watch %q rejected", ErrBatchConflict, signal)
+		}
+		signals = append(signals, signal)
+	}
+	slices.Sort(signals)
+
+	// 暂存的事件在释放信号锁之后投递
+	added, err := c.commitBatch(signals, create, ops)
+	for _, signal := range added {
+		c.flushBuffer(signal)
+	}
+	return err
+}
+
+// commitBatch 在所有信号的锁内校验并应用批量操作, 返回新增了监听器的信号
+func (c *core[K, T]) commitBatch(signals []string, create map[string]bool, ops []batchOp[K, T]) ([]string, error) {
+	entries := make([]*signalEntry[K, T], len(signals))
+	for {
+		for i, signal := range signals {
+			entries[i] = c.entry(signal, create[signal])
+		}
+		if c.lockBatch(entries) {
+			break
+		}
+	}
+	defer func() {
+		for _, e := range entries {
+			if e != nil {
+				e.mu.Unlock()
+			}
+		}
+	}()
+
+	pending := make(map[string][]listener[K, T], len(signals))
+	for i, signal := range signals {
+		if entries[i] != nil {
+			pending[signal] = entries[i].load()
+		}
+	}
+	// rewatched 记录重新添加了原有 key 的信号, 值可能已经不同, 视为变化
+	rewatched := make(map[string]bool)
+	for _, op := range ops {
+		listeners := pending[op.signal]
+		index := slices.IndexFunc(listeners, func(item listener[K, T]) bool {
+			return item.key == op.l.key
+		})
+		switch op.kind {
+		case batchWatch:
+			if index >= 0 {
+				return nil, fmt.Errorf("%w: watch %q: key %v already watching", ErrBatchConflict, op.signal, op.l.key.Value())
+			}
+			if e := entries[slices.Index(signals, op.signal)]; e != nil && slices.ContainsFunc(e.load(), op.l.sameKey) {
+				rewatched[op.signal] = true
+			}
+			pending[op.signal] = append(slices.Clip(listeners), op.l)
+		case batchUnwatch:
+			if index < 0 {
+				return nil, fmt.Errorf("%w: unwatch %q: key %v not watching", ErrBatchConflict, op.signal, op.l.key.Value())
+			}
+			pending[op.signal] = slices.Delete(slices.Clone(listeners), index, index+1)
+		case batchClean:
+			pending[op.signal] = nil
+		}
+	}
+
+	var added []string
+	for i, signal := range signals {
+		e := entries[i]
+		if e == nil {
+			continue
+		}
+		before, after := e.load(), pending[signal]
+		if !rewatched[signal] && slices.EqualFunc(before, after, listener[K, T].sameKey) {
+			// 监听器集合没有变化, 不产生新的版本
+			continue
+		}
+		grew := c.retrack(signal, before, after)
+		c.commitEntry(e, after)
+		c.touchWatch(signal)
+		if grew {
+			added = append(added, signal)
+		}
+	}
+	return added, nil
+}
+
+// lockBatch 依次锁住所有条目, 如果有条目在加锁前已被移除则全部释放并返回 false
+func (c *core[K, T]) lockBatch(entries []*signalEntry[K, T]) bool {
+	gen := c.gen.Load()
+	for i, e := range entries {
+		if e == nil {
+			continue
+		}
+		e.mu.Lock()
+		if e.removed || e.gen != gen {
+			for _, locked := range entries[:i+1] {
+				if locked != nil {
+					locked.mu.Unlock()
+				}
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// Batch 收集一组 Watch/Unwatch/Clean 操作, 调用 Commit 时一次性原子地应用
+type Batch[T comparable] struct {
+	b   *Broadcast[T]
+	ops []batchOp[T, T]
+}
+
+// Batch 创建一个批量操作
+func (b *Broadcast[T]) Batch() *Batch[T] {
+	return &Batch[T]{b: b}
+}
+
+// Watch 添加监听, 相同数据已在监听该信号时 Commit 返回 ErrBatchConflict
+func (t *Batch[T]) Watch(signal string, data T) *Batch[T] {
+	l := newListener[T, T](&uniqueWrapper[T]{data: data})
+	t.ops = append(t.ops, batchOp[T, T]{kind: batchWatch, signal: t.b.sig(signal), l: l})
+	return t
+}
+
+// Unwatch 取消监听, 数据没有在监听该信号时 Commit 返回 ErrBatchConflict
+func (t *Batch[T]) Unwatch(signal string, data T) *Batch[T] {
+	l := listener[T, T]{key: unique.Make(data)}
+	t.ops = append(t.ops, batchOp[T, T]{kind: batchUnwatch, signal: t.b.sig(signal), l: l})
+	return t
+}
+
+// Clean 清除信号上的所有监听器, 之后的步骤可以重新添加监听器
+func (t *Batch[T]) Clean(signal string) *Batch[T] {
+	t.ops = append(t.ops, batchOp[T, T]{kind: batchClean, signal: t.b.sig(signal)})
+	return t
+}
+
+// Commit 按添加顺序应用所有操作, 任何一步失败时不做任何修改并返回错误
+func (t *Batch[T]) Commit() error {
+	return t.b.c().applyBatch(t.ops)
+}
+
+// UniqueBatch 收集一组 Watch/Unwatch/Clean 操作, 调用 Commit 时一次性原子地应用
+type UniqueBatch[K comparable, T any] struct {
+	b   *UniqueBroadcast[K, T]
+	ops []batchOp[K, T]
+}
+
+// Batch 创建一个批量操作
+func (b *UniqueBroadcast[K, T]) Batch() *UniqueBatch[K, T] {
+	return &UniqueBatch[K, T]{b: b}
+}
+
+// Watch 添加监听, 相同 key 已在监听该信号时 Commit 返回 ErrBatchConflict
+func (t *UniqueBatch[K, T]) Watch(signal string, data Uniquer[K, T]) *UniqueBatch[K, T] {
+	t.ops = append(t.ops, batchOp[K, T]{kind: batchWatch, signal: signal, l: newListener(data)})
+	return t
+}
+
+// Unwatch 取消监听, key 没有在监听该信号时 Commit 返回 ErrBatchConflict
+func (t *UniqueBatch[K, T]) Unwatch(signal string, key K) *UniqueBatch[K, T] {
+	l := listener[K, T]{key: unique.Make(key)}
+	t.ops = append(t.ops, batchOp[K, T]{kind: batchUnwatch, signal: signal, l: l})
+	return t
+}
+
+// Clean 清除信号上的所有监听器, 之后的步骤可以重新添加监听器
+func (t *UniqueBatch[K, T]) Clean(signal string) *UniqueBatch[K, T] {
+	t.ops = append(t.ops, batchOp[K, T]{kind: batchClean, signal: signal})
+	return t
+}
+
+// Commit 按添加顺序应用所有操作, 任何一步失败时不做任何修改并返回错误
+func (t *UniqueBatch[K, T]) Commit() error {
+	return t.b.core.applyBatch(t.ops)
+}
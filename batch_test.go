@@ -0,0 +1,100 @@
+package broadcast
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBatch_Commit(t *testing.T) {
+	b := New[string]()
+	b.Watch("a", "x")
+	b.Watch("b", "y")
+
+	err := b.Batch().
+		Unwatch("a", "x").
+		Watch("a", "z").
+		Clean("b").
+		Watch("b", "w").
+		Commit()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.WatchCount("a") != 1 || b.WatchCount("b") != 1 {
+		t.Fatalf("unexpected counts: a=%d b=%d", b.WatchCount("a"), b.WatchCount("b"))
+	}
+
+	var got []string
+	b.Handle(func(signal string, data string, _ map[string]interface{}) error {
+		got = append(got, signal+":"+data)
+		return nil
+	})
+	b.Broadcast("a", nil)
+	b.Broadcast("b", nil)
+	if len(got) != 2 || got[0] != "a:z" || got[1] != "b:w" {
+		t.Errorf("unexpected deliveries: %v", got)
+	}
+}
+
+func TestBatch_AllOrNothing(t *testing.T) {
+	b := NewUnique[string, string]()
+	b.Watch("locks", owner{"db", "alice"})
+
+	err := b.Batch().
+		Watch("locks", owner{"cache", "bob"}).
+		Unwatch("locks", "db").
+		Unwatch("locks", "queue").
+		Commit()
+	if !errors.Is(err, ErrBatchConflict) {
+		t.Fatalf("expected ErrBatchConflict, got %v", err)
+	}
+	if !b.Has("locks", "db") || b.Has("locks", "cache") {
+		t.Error("expected a failed batch to leave listeners unchanged")
+	}
+
+	if err := b.Batch().Watch("locks", owner{"db", "bob"}).Commit(); !errors.Is(err, ErrBatchConflict) {
+		t.Errorf("expected a duplicate watch to conflict, got %v", err)
+	}
+	if err := b.Batch().Watch("other", owner{"db", "bob"}).Unwatch("other", "db").Commit(); err != nil {
+		t.Errorf("expected later steps to see earlier ones, got %v", err)
+	}
+	if b.Has("other", "db") {
+		t.Error("expected the watch to be undone within the batch")
+	}
+	if signals := b.SignalsOf("db"); len(signals) != 1 || signals[0] != "locks" {
+		t.Errorf("expected the index to track only locks, got %v", signals)
+	}
+}
+
+func TestBatch_VersionOnlyForChangedSignals(t *testing.T) {
+	b := New[string]()
+	b.Watch("a", "x")
+	b.Watch("c", "y")
+	b.Unwatch("c", "y")
+	a, c := b.Version("a"), b.Version("c")
+
+	err := b.Batch().
+		Watch("a", "z").
+		Unwatch("a", "z").
+		Clean("c").
+		Watch("d", "w").
+		Commit()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Version("a") != a || b.Version("c") != c {
+		t.Errorf("expected unchanged signals to keep their versions, a %d->%d c %d->%d", a, b.Version("a"), c, b.Version("c"))
+	}
+	if b.Version("d") == 0 {
+		t.Error("expected the changed signal to get a new version")
+	}
+
+	u := NewUnique[string, string]()
+	u.Watch("locks", owner{"db", "alice"})
+	v := u.Version("locks")
+	if err := u.Batch().Unwatch("locks", "db").Watch("locks", owner{"db", "bob"}).Commit(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := u.Get("locks", "db"); got != "bob" || u.Version("locks") == v {
+		t.Errorf("expected a replaced value to count as a change, got %q version %d", got, u.Version("locks"))
+	}
+}
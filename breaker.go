@@ -0,0 +1,157 @@
+package broadcast
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerConfig 生产者侧熔断器配置
+// 当信号的处理器持续失败或异步队列积压超过阈值时, 该信号的 Broadcast 直接返回
+// ErrSignalUnhealthy, 每隔 ProbeInterval 放行一次探测广播, 探测成功后恢复
+type BreakerConfig struct {
+	// FailureThreshold 连续失败的广播次数达到该值时断开, 0 表示不根据失败断开
+	// 任一处理器返回错误即视为该次广播失败
+	FailureThreshold int
+	// QueueThreshold 异步队列积压达到该值时断开, 0 表示不检查队列
+	QueueThreshold int
+	// ProbeInterval 断开后经过该时间允许一次探测广播, 默认为 1s
+	ProbeInterval time.Duration
+	// ProbeTimeout 探测广播在该时间内没有投递结果时允许新的探测, 默认与 ProbeInterval 相同
+	ProbeTimeout time.Duration
+}
+
+type breakerStatus int
+
+const (
+	breakerClosed breakerStatus = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type breakerState struct {
+	status   breakerStatus
+	failures int
+	openedAt time.Time
+	probedAt time.Time
+}
+
+// breaker 维护每个信号的熔断状态
+type breaker struct {
+	mu      sync.Mutex
+	config  BreakerConfig
+	signals map[string]*breakerState
+}
+
+func newBreaker(config BreakerConfig) *breaker {
+	if config.ProbeInterval <= 0 {
+		config.ProbeInterval = time.Second
+	}
+	if config.ProbeTimeout <= 0 {
+		config.ProbeTimeout = config.ProbeInterval
+	}
+	return &breaker{config: config, signals: make(map[string]*breakerState)}
+}
+
+func (b *breaker) state(signal string) *breakerState {
+	s, ok := b.signals[signal]
+	if !ok {
+		s = &breakerState{}
+		b.signals[signal] = s
+	}
+	return s
+}
+
+// allow 判断信号当前是否允许广播, pending 为异步队列积压数量
+// probe 为 true 表示放行的是一次探测广播
+func (b *breaker) allow(signal string, now time.Time, pending int) (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := b.state(signal)
+	switch s.status {
+	case breakerOpen:
+		if now.Sub(s.openedAt) < b.config.ProbeInterval {
+			return false, ErrSignalUnhealthy
+		}
+		// 放行一次探测, 结果由 record 决定
+		s.status, s.probedAt = breakerHalfOpen, now
+		return true, nil
+	case breakerHalfOpen:
+		if now.Sub(s.probedAt) < b.config.ProbeTimeout {
+			return false, ErrSignalUnhealthy
+		}
+		// 探测超时没有结果, 放行新的探测
+		s.probedAt = now
+		return true, nil
+	}
+
+	if b.config.QueueThreshold > 0 && pending >= b.config.QueueThreshold {
+		s.status, s.openedAt = breakerOpen, now
+		return false, ErrSignalUnhealthy
+	}
+	return false, nil
+}
+
+// record 记录一次广播的投递结果
+func (b *breaker) record(signal string, ok bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := b.state(signal)
+	if ok {
+		s.status, s.failures = breakerClosed, 0
+		return
+	}
+
+	s.failures++
+	if s.status == breakerHalfOpen ||
+		(b.config.FailureThreshold > 0 && s.failures >= b.config.FailureThreshold) {
+		s.status, s.openedAt = breakerOpen, now
+	}
+}
+
+// abort 在探测广播没有被投递时重新断开, 经过 ProbeInterval 后放行新的探测
+func (b *breaker) abort(signal string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s, ok := b.signals[signal]; ok && s.status == breakerHalfOpen {
+		s.status, s.openedAt = breakerOpen, now
+	}
+}
+
+// release 撤销一次放行后没有广播的探测, 熔断器回到断开状态且不重新计时, 下一次广播即可探测
+func (b *breaker) release(signal string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s, ok := b.signals[signal]; ok && s.status == breakerHalfOpen {
+		s.status = breakerOpen
+	}
+}
+
+// healthy 返回信号的熔断器是否闭合
+func (b *breaker) healthy(signal string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.signals[signal]
+	return !ok || s.status == breakerClosed
+}
+
+func (c *core[K, T]) setBreaker(config *BreakerConfig) {
+	c.updateSettings(func(s *settings[K, T]) {
+		if config != nil {
+			s.breaker = newBreaker(*config)
+		} else {
+			s.breaker = nil
+		}
+	})
+}
+
+func (c *core[K, T]) healthy(signal string) bool {
+	if b := c.loadSettings().breaker; b != nil {
+		return b.healthy(signal)
+	}
+	return true
+}
@@ -0,0 +1,211 @@
+package broadcast
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// manualClock 是可手动推进的 Clock, 仅用于包内测试
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func (c *manualClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+func TestBreaker_OpensOnFailuresAndProbes(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b := New[string]()
+	b.SetClock(clock)
+	b.SetBreaker(&BreakerConfig{FailureThreshold: 2, ProbeInterval: time.Second})
+
+	failing := true
+	calls := 0
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		calls++
+		if failing {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	})
+	b.Watch("orders", "db")
+	b.Watch("audit", "db")
+
+	for i := 0; i < 2; i++ {
+		if err := b.Broadcast("orders", nil); err == nil || errors.Is(err, ErrSignalUnhealthy) {
+			t.Fatalf("broadcast %d should return the handler error, got %v", i, err)
+		}
+	}
+	if b.Healthy("orders") {
+		t.Fatal("breaker should open after 2 consecutive failures")
+	}
+
+	if err := b.Broadcast("orders", nil); !errors.Is(err, ErrSignalUnhealthy) {
+		t.Errorf("expected ErrSignalUnhealthy while open, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("handlers should not run while open, got %d calls", calls)
+	}
+	if !b.Healthy("audit") {
+		t.Error("other signals should not be affected")
+	}
+
+	// 探测失败, 重新断开
+	clock.now = clock.now.Add(time.Second)
+	if err := b.Broadcast("orders", nil); err == nil || errors.Is(err, ErrSignalUnhealthy) {
+		t.Errorf("probe should be delivered, got %v", err)
+	}
+	if err := b.Broadcast("orders", nil); !errors.Is(err, ErrSignalUnhealthy) {
+		t.Errorf("failed probe should reopen the breaker, got %v", err)
+	}
+
+	// 探测成功, 恢复
+	failing = false
+	clock.now = clock.now.Add(time.Second)
+	if err := b.Broadcast("orders", nil); err != nil {
+		t.Errorf("successful probe should pass, got %v", err)
+	}
+	if !b.Healthy("orders") {
+		t.Error("breaker should close after a successful probe")
+	}
+}
+
+func TestBreaker_QueueThreshold(t *testing.T) {
+	b := New[string]()
+	gate := make(chan struct{})
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		<-gate
+		return nil
+	})
+	b.Watch("test", "data")
+	b.EnableAsync(AsyncConfig{QueueSize: 8})
+	b.SetBreaker(&BreakerConfig{QueueThreshold: 2})
+	defer b.Close()
+
+	b.Broadcast("test", nil)
+	waitFor(t, func() bool { return b.Pending() == 0 })
+	b.Broadcast("test", nil)
+	b.Broadcast("test", nil)
+
+	if err := b.Broadcast("test", nil); !errors.Is(err, ErrSignalUnhealthy) {
+		t.Errorf("expected ErrSignalUnhealthy when the queue is saturated, got %v", err)
+	}
+	close(gate)
+}
+
+func TestBreaker_ProbeRejectedByLimiter(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b := New[string]()
+	b.SetClock(clock)
+	b.SetBreaker(&BreakerConfig{FailureThreshold: 1, ProbeInterval: time.Second})
+
+	failing, limited := true, false
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		if failing {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	})
+	b.SetRateLimiter(RateLimiterFunc(func(string) bool { return !limited }))
+	b.Watch("orders", "db")
+
+	b.Broadcast("orders", nil)
+	if b.Healthy("orders") {
+		t.Fatal("breaker should open after a failure")
+	}
+
+	// 限流拒绝的广播不占用探测
+	failing, limited = false, true
+	clock.now = clock.now.Add(time.Second)
+	if err := b.Broadcast("orders", nil); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	limited = false
+	if err := b.Broadcast("orders", nil); err != nil {
+		t.Fatalf("expected the probe to be delivered after the limiter allows it, got %v", err)
+	}
+	if !b.Healthy("orders") {
+		t.Error("breaker should close after a successful probe")
+	}
+}
+
+func TestBreaker_UndeliveredProbe(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b := New[string]()
+	b.SetClock(clock)
+	b.SetBreaker(&BreakerConfig{FailureThreshold: 1, ProbeInterval: time.Second, ProbeTimeout: 5 * time.Second})
+
+	failing := true
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		if failing {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	})
+	b.Watch("orders", "db")
+	b.Broadcast("orders", nil)
+
+	// 暂存的探测没有被投递, 熔断器重新断开并在 ProbeInterval 后放行新的探测
+	b.SetPendingBuffer(4)
+	b.Unwatch("orders", "db")
+	clock.now = clock.now.Add(time.Second)
+	if err := b.Broadcast("orders", nil); err != nil {
+		t.Fatalf("expected the probe to be buffered, got %v", err)
+	}
+	if err := b.Broadcast("orders", nil); !errors.Is(err, ErrSignalUnhealthy) {
+		t.Fatalf("expected the breaker to reopen, got %v", err)
+	}
+	b.SetPendingBuffer(0)
+
+	// 探测停留在异步队列中, 超过 ProbeTimeout 后放行新的探测
+	gate := make(chan struct{})
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		<-gate
+		return nil
+	})
+	b.EnableAsync(AsyncConfig{QueueSize: 8})
+	defer b.Close()
+	b.Watch("orders", "db")
+	failing = false
+	clock.now = clock.now.Add(time.Second)
+	if err := b.Broadcast("orders", nil); err != nil {
+		t.Fatalf("expected the probe to be queued, got %v", err)
+	}
+	clock.now = clock.now.Add(time.Second)
+	if err := b.Broadcast("orders", nil); !errors.Is(err, ErrSignalUnhealthy) {
+		t.Errorf("expected a single probe within ProbeTimeout, got %v", err)
+	}
+	clock.now = clock.now.Add(5 * time.Second)
+	if err := b.Broadcast("orders", nil); err != nil {
+		t.Errorf("expected a new probe after ProbeTimeout, got %v", err)
+	}
+	close(gate)
+}
+
+func TestBroadcast_ReturnsHandlerErrors(t *testing.T) {
+	b := New[string]()
+	first, second := errors.New("first"), errors.New("second")
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		return first
+	})
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		return second
+	})
+	b.Watch("test", "data")
+
+	err := b.Broadcast("test", nil)
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Errorf("expected joined handler errors, got %v", err)
+	}
+
+	b.SetRateLimiter(RateLimiterFunc(func(string) bool { return false }))
+	if err := b.Broadcast("test", nil); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+}
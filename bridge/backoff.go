@@ -0,0 +1,67 @@
+package bridge
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff 定义重连的等待时间: 第 n 次重连前等待 Initial*Multiplier^(n-1), 不超过 Max,
+// 再随机减去其中至多 Jitter 的比例, 避免大量客户端同时重连
+type Backoff struct {
+	// Initial 为第一次重连前的等待时间, 默认为 DefaultBackoff.Initial
+	Initial time.Duration
+	// Max 为等待时间的上限, 默认为 DefaultBackoff.Max
+	Max time.Duration
+	// Multiplier 为每次失败后等待时间的倍数, 默认为 DefaultBackoff.Multiplier
+	Multiplier float64
+	// Jitter 为随机减少的比例, 取值 0 到 1, 为 0 时不抖动
+	Jitter float64
+	// MaxAttempts 为连续失败的最大次数, 超过后放弃重连, 为 0 时不限制
+	MaxAttempts int
+}
+
+// DefaultBackoff 是 Config.Backoff 为零值时使用的退避策略
+var DefaultBackoff = Backoff{
+	Initial:    100 * time.Millisecond,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// Delay 返回第 attempt 次重连前的等待时间, attempt 从 1 开始
+func (b Backoff) Delay(attempt int) time.Duration {
+	b = b.withDefaults()
+	d := float64(b.Initial)
+	for i := 1; i < attempt && d < float64(b.Max); i++ {
+		d *= b.Multiplier
+	}
+	d = min(d, float64(b.Max))
+	if jitter := min(max(b.Jitter, 0), 1); jitter > 0 {
+		d -= d * jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// exhausted 报告连续失败 failures 次后是否应当放弃
+func (b Backoff) exhausted(failures int) bool {
+	return b.MaxAttempts > 0 && failures >= b.MaxAttempts
+}
+
+func (b Backoff) withDefaults() Backoff {
+	if b == (Backoff{}) {
+		return DefaultBackoff
+	}
+	if b.Initial <= 0 {
+		b.Initial = DefaultBackoff.Initial
+	}
+	if b.Max <= 0 {
+		b.Max = DefaultBackoff.Max
+	}
+	if b.Max < b.Initial {
+		b.Max = b.Initial
+	}
+	if b.Multiplier < 1 {
+		b.Multiplier = DefaultBackoff.Multiplier
+	}
+	return b
+}
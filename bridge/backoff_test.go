@@ -0,0 +1,38 @@
+package bridge
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff_DelayGrowsUpToMax(t *testing.T) {
+	b := Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2}
+	want := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, w := range want {
+		if got := b.Delay(i + 1); got != w*time.Millisecond {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, w*time.Millisecond, got)
+		}
+	}
+}
+
+func TestBackoff_JitterStaysBelowDelay(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: time.Second, Jitter: 0.5}
+	for range 100 {
+		if d := b.Delay(1); d < 500*time.Millisecond || d > time.Second {
+			t.Fatalf("expected a delay within [500ms, 1s], got %v", d)
+		}
+	}
+}
+
+func TestBackoff_Defaults(t *testing.T) {
+	if got := (Backoff{}).withDefaults(); got != DefaultBackoff {
+		t.Errorf("expected the zero value to use DefaultBackoff, got %+v", got)
+	}
+	got := Backoff{MaxAttempts: 3}.withDefaults()
+	if got.Initial != DefaultBackoff.Initial || got.Multiplier != DefaultBackoff.Multiplier || got.Jitter != 0 {
+		t.Errorf("expected unset fields to be filled without jitter, got %+v", got)
+	}
+	if !got.exhausted(3) || got.exhausted(2) || DefaultBackoff.exhausted(1000) {
+		t.Error("expected MaxAttempts to bound consecutive failures only when set")
+	}
+}
@@ -0,0 +1,307 @@
+// Package bridge 提供网络桥接共用的重连框架: 连接断开后按指数退避与抖动重连,
+// 超过最大次数后放弃, 状态变化时回调; 断线期间的出站帧暂存在内存或磁盘中, 重连后按顺序补发
+//
+//	link, err := bridge.Connect(bridge.Config{
+//		Dial: func(ctx context.Context) (bridge.Conn, error) {
+//			return dialPeer(ctx, addr)
+//		},
+//	})
+//	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+//		return link.Send(encode(signal, metadata))
+//	})
+//
+// Redis、NATS、gRPC 等桥接都应通过 Link 发送, 以获得一致的重连与暂存行为
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+var (
+	// ErrClosed Link 已关闭
+	ErrClosed = errors.New("bridge: link closed")
+	// ErrGaveUp 连续重连失败的次数超过 Backoff.MaxAttempts, 需要调用 Reconnect
+	ErrGaveUp = errors.New("bridge: gave up reconnecting")
+	// ErrSpoolFull 断线期间暂存的帧超过容量
+	ErrSpoolFull = errors.New("bridge: spool full")
+	// ErrNoDial Config.Dial 为 nil
+	ErrNoDial = errors.New("bridge: Config.Dial is required")
+)
+
+// State 是 Link 的连接状态
+type State int32
+
+const (
+	// Disconnected 未连接, 等待下一次重连
+	Disconnected State = iota
+	// Connecting 正在建立连接或补发暂存的帧
+	Connecting
+	// Connected 已连接, Send 直接写入连接
+	Connected
+	// Failed 连续失败次数超过 Backoff.MaxAttempts, 不再自动重连
+	Failed
+	// Closed Link 已关闭
+	Closed
+)
+
+func (s State) String() string {
+	switch s {
+	case Disconnected:
+		return "disconnected"
+	case Connecting:
+		return "connecting"
+	case Connected:
+		return "connected"
+	case Failed:
+		return "failed"
+	case Closed:
+		return "closed"
+	}
+	return fmt.Sprintf("State(%d)", int32(s))
+}
+
+// Conn 是一条到远端的连接
+type Conn interface {
+	// Send 发送一帧, 返回错误时连接被视为断开
+	Send(frame []byte) error
+	Close() error
+}
+
+// Config 配置 Link
+type Config struct {
+	// Dial 建立连接, ctx 在 Link 关闭时取消, 必填
+	Dial func(ctx context.Context) (Conn, error)
+	// Backoff 为重连的退避策略, 零值时为 DefaultBackoff
+	Backoff Backoff
+	// Spool 暂存断线期间的出站帧, 默认为容量 DefaultBufferSize 的 MemorySpool
+	// 需要在进程重启后补发时使用 OpenFileSpool; Link 不会关闭 Spool
+	Spool Spool
+	// OnStateChange 在状态变化时调用, err 为导致变化的错误
+	// 回调在持有内部锁时同步调用, 只能调用 State, 不能调用 Link 的其他方法
+	OnStateChange func(from, to State, err error)
+	// Clock 为重连计时使用的时间源, 默认为 broadcast.SystemClock
+	Clock broadcast.Clock
+}
+
+// Link 维护到远端的连接: 连接断开后按 Backoff 重连, 断线期间 Send 的帧写入 Spool,
+// 重连成功后先按顺序补发暂存的帧, 再恢复直接发送
+// Link 实现了 broadcast.HealthChecker, 可以加入 broadcast.HealthChecks
+type Link struct {
+	config Config
+	ctx    context.Context
+	cancel context.CancelFunc
+	state  atomic.Int32
+
+	mu       sync.Mutex
+	conn     Conn
+	timer    broadcast.Timer
+	failures int
+	err      error
+}
+
+var _ broadcast.HealthChecker = (*Link)(nil)
+
+// Connect 创建 Link 并在后台开始第一次连接
+func Connect(config Config) (*Link, error) {
+	if config.Dial == nil {
+		return nil, ErrNoDial
+	}
+	config.Backoff = config.Backoff.withDefaults()
+	if config.Spool == nil {
+		config.Spool = NewMemorySpool(DefaultBufferSize)
+	}
+	if config.Clock == nil {
+		config.Clock = broadcast.SystemClock
+	}
+	l := &Link{config: config}
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.setState(Connecting, nil)
+	go l.dial()
+	return l, nil
+}
+
+// State 返回当前的连接状态
+func (l *Link) State() State {
+	return State(l.state.Load())
+}
+
+// Send 发送一帧: 已连接且没有暂存的帧时直接写入连接, 否则写入 Spool 等待补发
+// 写入连接失败时帧被暂存并开始重连. Link 已关闭时返回 ErrClosed, 放弃重连后返回 ErrGaveUp
+func (l *Link) Send(frame []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch l.State() {
+	case Closed:
+		return ErrClosed
+	case Failed:
+		return ErrGaveUp
+	case Connected:
+		if l.config.Spool.Len() == 0 {
+			err := l.conn.Send(frame)
+			if err == nil {
+				return nil
+			}
+			l.lost(err)
+		}
+	}
+	return l.config.Spool.Push(frame)
+}
+
+// Fail 报告 conn 已断开, 供桥接的读循环在读取失败时调用; conn 不是当前连接时忽略
+func (l *Link) Fail(conn Conn, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.State() == Connected && l.conn == conn {
+		l.lost(err)
+	}
+}
+
+// Reconnect 在放弃重连后重新开始, 连续失败的次数被清零; 其他状态下不做任何事
+func (l *Link) Reconnect() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.State() != Failed {
+		return
+	}
+	l.failures = 0
+	l.setState(Connecting, nil)
+	go l.dial()
+}
+
+// Pending 返回暂存等待补发的帧数
+func (l *Link) Pending() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.config.Spool.Len()
+}
+
+// Close 停止重连并关闭当前连接, 暂存的帧保留在 Spool 中
+func (l *Link) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.State() == Closed {
+		return nil
+	}
+	l.cancel()
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	var err error
+	if l.conn != nil {
+		err = l.conn.Close()
+		l.conn = nil
+	}
+	l.setState(Closed, nil)
+	return err
+}
+
+// Healthy 在 Link 已关闭或放弃重连时返回错误
+func (l *Link) Healthy() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch l.State() {
+	case Closed:
+		return ErrClosed
+	case Failed:
+		return fmt.Errorf("%w: %w", ErrGaveUp, l.err)
+	}
+	return nil
+}
+
+// Ready 在未连接时返回错误, 包含最近一次连接失败的原因
+func (l *Link) Ready() error {
+	if err := l.Healthy(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if state := l.State(); state != Connected {
+		if l.err != nil {
+			return fmt.Errorf("bridge: %s: %w", state, l.err)
+		}
+		return fmt.Errorf("bridge: %s", state)
+	}
+	return nil
+}
+
+// dial 建立连接并补发暂存的帧, 失败时按 Backoff 安排下一次重连
+func (l *Link) dial() {
+	conn, err := l.config.Dial(l.ctx)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.State() == Closed {
+		if conn != nil {
+			conn.Close()
+		}
+		return
+	}
+	if err == nil {
+		if err = l.config.Spool.Drain(conn.Send); err != nil {
+			conn.Close()
+		}
+	}
+	if err != nil {
+		l.failures++
+		l.err = err
+		if l.config.Backoff.exhausted(l.failures) {
+			l.setState(Failed, err)
+			return
+		}
+		l.retry(err)
+		return
+	}
+	l.conn = conn
+	l.failures = 0
+	l.err = nil
+	l.setState(Connected, nil)
+}
+
+// lost 在连接断开后关闭它并安排重连, 调用时持有 l.mu
+func (l *Link) lost(err error) {
+	l.conn.Close()
+	l.conn = nil
+	l.err = err
+	l.retry(err)
+}
+
+// retry 进入 Disconnected 并在退避时间后重连, 调用时持有 l.mu
+func (l *Link) retry(err error) {
+	l.setState(Disconnected, err)
+	l.timer = l.config.Clock.AfterFunc(l.config.Backoff.Delay(l.failures+1), func() {
+		l.mu.Lock()
+		if l.State() != Disconnected {
+			l.mu.Unlock()
+			return
+		}
+		l.timer = nil
+		l.setState(Connecting, nil)
+		l.mu.Unlock()
+		l.dial()
+	})
+}
+
+func (l *Link) setState(to State, err error) {
+	from := State(l.state.Swap(int32(to)))
+	if from != to && l.config.OnStateChange != nil {
+		l.config.OnStateChange(from, to, err)
+	}
+}
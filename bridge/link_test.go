@@ -0,0 +1,208 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+	"pkg.blksails.net/x/broadcast/broadcasttest"
+)
+
+// peer 模拟远端, down 为 true 时拒绝连接, 已建立的连接在 Send 时失败
+type peer struct {
+	mu     sync.Mutex
+	down   bool
+	dials  int
+	frames []string
+}
+
+type peerConn struct {
+	peer   *peer
+	closed bool
+}
+
+func (p *peer) dial(ctx context.Context) (Conn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.dials++
+	if p.down {
+		return nil, errors.New("connection refused")
+	}
+	return &peerConn{peer: p}, nil
+}
+
+func (p *peer) setDown(down bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.down = down
+}
+
+func (p *peer) received() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.frames)
+}
+
+func (c *peerConn) Send(frame []byte) error {
+	c.peer.mu.Lock()
+	defer c.peer.mu.Unlock()
+
+	if c.closed || c.peer.down {
+		return errors.New("broken pipe")
+	}
+	c.peer.frames = append(c.peer.frames, string(frame))
+	return nil
+}
+
+func (c *peerConn) Close() error {
+	c.peer.mu.Lock()
+	defer c.peer.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+// connect 创建使用 FakeClock 的 Link, 返回的通道接收每次状态变化后的状态
+func connect(t *testing.T, p *peer, backoff Backoff) (*Link, *broadcasttest.FakeClock, chan State) {
+	t.Helper()
+	clock := broadcasttest.NewFakeClock(time.Unix(0, 0))
+	states := make(chan State, 64)
+	l, err := Connect(Config{
+		Dial:          p.dial,
+		Backoff:       backoff,
+		Clock:         clock,
+		OnStateChange: func(from, to State, err error) { states <- to },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l, clock, states
+}
+
+func await(t *testing.T, states chan State, want State) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case s := <-states:
+			if s == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+}
+
+func TestLink_SpoolsWhileDisconnectedAndFlushesInOrder(t *testing.T) {
+	p := &peer{}
+	l, clock, states := connect(t, p, Backoff{Initial: time.Second, Max: time.Minute, Multiplier: 2})
+	await(t, states, Connected)
+
+	if err := l.Send([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	p.setDown(true)
+	if err := l.Send([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	if l.State() != Disconnected || l.Pending() != 1 {
+		t.Fatalf("expected the failed frame to be spooled, state=%s pending=%d", l.State(), l.Pending())
+	}
+	l.Send([]byte("c"))
+	if l.Ready() == nil || l.Healthy() != nil {
+		t.Errorf("expected reconnecting to be alive but not ready, ready=%v healthy=%v", l.Ready(), l.Healthy())
+	}
+
+	// 第一次重连在 1s 后失败, 第二次在之后的 2s
+	clock.Advance(time.Second)
+	await(t, states, Disconnected)
+	p.setDown(false)
+	clock.Advance(time.Second)
+	if l.State() != Disconnected {
+		t.Fatalf("expected the backoff to double, got %s", l.State())
+	}
+	clock.Advance(time.Second)
+	await(t, states, Connected)
+
+	l.Send([]byte("d"))
+	if got := p.received(); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("expected spooled frames before new ones, got %v", got)
+	}
+	if l.Pending() != 0 || l.Ready() != nil {
+		t.Errorf("expected a drained, ready link, pending=%d ready=%v", l.Pending(), l.Ready())
+	}
+}
+
+func TestLink_GivesUpAfterMaxAttempts(t *testing.T) {
+	p := &peer{down: true}
+	l, clock, states := connect(t, p, Backoff{Initial: time.Second, Multiplier: 1, MaxAttempts: 3})
+	await(t, states, Disconnected)
+	clock.Advance(time.Second)
+	clock.Advance(time.Second)
+	await(t, states, Failed)
+
+	if p.dials != 3 {
+		t.Errorf("expected 3 attempts, got %d", p.dials)
+	}
+	if err := l.Send([]byte("a")); !errors.Is(err, ErrGaveUp) {
+		t.Errorf("expected ErrGaveUp, got %v", err)
+	}
+	if err := l.Healthy(); !errors.Is(err, ErrGaveUp) {
+		t.Errorf("expected an unhealthy link, got %v", err)
+	}
+
+	checks := broadcast.NewHealthChecks()
+	checks.Add("peer", l)
+	p.setDown(false)
+	l.Reconnect()
+	await(t, states, Connected)
+	if err := checks.Ready(); err != nil {
+		t.Errorf("expected a reconnected link to be ready, got %v", err)
+	}
+}
+
+func TestLink_FailFromReadLoop(t *testing.T) {
+	p := &peer{}
+	l, clock, states := connect(t, p, Backoff{Initial: time.Second})
+	await(t, states, Connected)
+
+	l.mu.Lock()
+	conn := l.conn
+	l.mu.Unlock()
+	l.Fail(&peerConn{peer: p}, errors.New("stale"))
+	if l.State() != Connected {
+		t.Fatal("expected a stale connection to be ignored")
+	}
+	l.Fail(conn, errors.New("eof"))
+	if l.State() != Disconnected || !conn.(*peerConn).closed {
+		t.Fatalf("expected the connection to be closed and retried, got %s", l.State())
+	}
+	clock.Advance(time.Second)
+	await(t, states, Connected)
+	if p.dials != 2 {
+		t.Errorf("expected one redial, got %d dials", p.dials)
+	}
+}
+
+func TestLink_Close(t *testing.T) {
+	p := &peer{down: true}
+	l, clock, states := connect(t, p, Backoff{Initial: time.Second})
+	await(t, states, Disconnected)
+
+	l.Close()
+	if clock.Pending() != 0 {
+		t.Error("expected the pending retry to be stopped")
+	}
+	if err := l.Send([]byte("a")); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+	if _, err := Connect(Config{}); !errors.Is(err, ErrNoDial) {
+		t.Errorf("expected ErrNoDial, got %v", err)
+	}
+}
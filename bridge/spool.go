@@ -0,0 +1,228 @@
+package bridge
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// DefaultBufferSize 是 Config.Spool 为 nil 时内存缓冲的容量
+const DefaultBufferSize = 1024
+
+// Spool 保存断线期间的出站帧, 重连后按写入顺序补发
+// Link 在持有内部锁时串行调用这些方法, 实现不需要并发安全
+type Spool interface {
+	// Push 追加一帧, 暂存已满时返回 ErrSpoolFull
+	Push(frame []byte) error
+	// Drain 按写入顺序对每一帧调用 send, send 返回错误时停止并返回该错误,
+	// 已发送成功的帧被移除
+	Drain(send func(frame []byte) error) error
+	// Len 返回暂存的帧数
+	Len() int
+}
+
+// MemorySpool 是容量固定的内存暂存, 进程退出时暂存的帧丢失
+type MemorySpool struct {
+	frames [][]byte
+	head   int
+	size   int
+}
+
+var _ Spool = (*MemorySpool)(nil)
+
+// NewMemorySpool 创建最多保存 capacity 帧的内存暂存, capacity 不大于 0 时为 DefaultBufferSize
+func NewMemorySpool(capacity int) *MemorySpool {
+	if capacity <= 0 {
+		capacity = DefaultBufferSize
+	}
+	return &MemorySpool{frames: make([][]byte, capacity)}
+}
+
+// Push 追加一帧, frame 被复制
+func (s *MemorySpool) Push(frame []byte) error {
+	if s.size == len(s.frames) {
+		return ErrSpoolFull
+	}
+	s.frames[(s.head+s.size)%len(s.frames)] = append([]byte(nil), frame...)
+	s.size++
+	return nil
+}
+
+// Drain 按写入顺序发送暂存的帧
+func (s *MemorySpool) Drain(send func(frame []byte) error) error {
+	for s.size > 0 {
+		if err := send(s.frames[s.head]); err != nil {
+			return err
+		}
+		s.frames[s.head] = nil
+		s.head = (s.head + 1) % len(s.frames)
+		s.size--
+	}
+	return nil
+}
+
+// Len 返回暂存的帧数
+func (s *MemorySpool) Len() int {
+	return s.size
+}
+
+// FileSpool 将暂存的帧追加到目录中的文件, 进程重启后仍可补发
+// 帧以 4 字节长度前缀写入 spool 文件, 已发送的位置记录在 offset 文件中;
+// 全部发送后两个文件被截断. 崩溃时最后一次记录位置之后已发送的帧会被重发
+type FileSpool struct {
+	data   *os.File
+	offset *os.File
+	pos    int64
+	len    int
+	sync   bool
+}
+
+var _ Spool = (*FileSpool)(nil)
+
+// FileSpoolConfig 配置 FileSpool
+type FileSpoolConfig struct {
+	// Sync 为 true 时每次 Push 后调用 fsync
+	Sync bool
+}
+
+// OpenFileSpool 打开或创建 dir 中的暂存, 末尾不完整的帧被丢弃
+func OpenFileSpool(dir string, config FileSpoolConfig) (*FileSpool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	data, err := os.OpenFile(filepath.Join(dir, "spool"), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := os.OpenFile(filepath.Join(dir, "spool.offset"), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		data.Close()
+		return nil, err
+	}
+	s := &FileSpool{data: data, offset: offset, sync: config.Sync}
+	if err := s.recover(); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("bridge: open spool %s: %w", dir, err)
+	}
+	return s, nil
+}
+
+// recover 读取已发送的位置, 统计之后的完整帧并截断末尾不完整的帧
+func (s *FileSpool) recover() error {
+	var buf [8]byte
+	if _, err := s.offset.ReadAt(buf[:], 0); err == nil {
+		s.pos = int64(binary.BigEndian.Uint64(buf[:]))
+	} else if !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	info, err := s.data.Stat()
+	if err != nil {
+		return err
+	}
+	if s.pos > info.Size() {
+		s.pos = info.Size()
+	}
+	end := s.pos
+	err = s.scan(func(frame []byte, next int64) error {
+		s.len++
+		end = next
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if end < info.Size() {
+		return s.data.Truncate(end)
+	}
+	return nil
+}
+
+// scan 从已发送的位置开始依次读取完整的帧, next 为下一帧的位置
+func (s *FileSpool) scan(fn func(frame []byte, next int64) error) error {
+	r := bufio.NewReader(io.NewSectionReader(s.data, s.pos, 1<<62))
+	next := s.pos
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return nil
+		}
+		frame := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return nil
+		}
+		next += int64(len(size) + len(frame))
+		if err := fn(frame, next); err != nil {
+			return err
+		}
+	}
+}
+
+// Push 将帧追加到文件末尾
+func (s *FileSpool) Push(frame []byte) error {
+	record := make([]byte, 4+len(frame))
+	binary.BigEndian.PutUint32(record, uint32(len(frame)))
+	copy(record[4:], frame)
+
+	info, err := s.data.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := s.data.WriteAt(record, info.Size()); err != nil {
+		return err
+	}
+	if s.sync {
+		if err := s.data.Sync(); err != nil {
+			return err
+		}
+	}
+	s.len++
+	return nil
+}
+
+// Drain 按写入顺序发送暂存的帧, 每发送一帧记录一次位置
+func (s *FileSpool) Drain(send func(frame []byte) error) error {
+	err := s.scan(func(frame []byte, next int64) error {
+		if err := send(frame); err != nil {
+			return err
+		}
+		s.len--
+		return s.commit(next)
+	})
+	if err != nil {
+		return err
+	}
+	return s.reset()
+}
+
+// Len 返回暂存的帧数
+func (s *FileSpool) Len() int {
+	return s.len
+}
+
+// Close 关闭文件
+func (s *FileSpool) Close() error {
+	return errors.Join(s.data.Close(), s.offset.Close())
+}
+
+func (s *FileSpool) commit(pos int64) error {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(pos))
+	if _, err := s.offset.WriteAt(buf[:], 0); err != nil {
+		return err
+	}
+	s.pos = pos
+	return nil
+}
+
+// reset 在全部帧发送后截断文件
+func (s *FileSpool) reset() error {
+	if err := s.data.Truncate(0); err != nil {
+		return err
+	}
+	return s.commit(0)
+}
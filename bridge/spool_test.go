@@ -0,0 +1,102 @@
+package bridge
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func drain(t *testing.T, s Spool) []string {
+	t.Helper()
+	var frames []string
+	if err := s.Drain(func(frame []byte) error {
+		frames = append(frames, string(frame))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return frames
+}
+
+func TestMemorySpool_BoundedFIFO(t *testing.T) {
+	s := NewMemorySpool(2)
+	for _, f := range []string{"a", "b"} {
+		if err := s.Push([]byte(f)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Push([]byte("c")); !errors.Is(err, ErrSpoolFull) {
+		t.Errorf("expected ErrSpoolFull, got %v", err)
+	}
+
+	errSend := errors.New("send")
+	var sent []string
+	err := s.Drain(func(frame []byte) error {
+		if len(sent) == 1 {
+			return errSend
+		}
+		sent = append(sent, string(frame))
+		return nil
+	})
+	if !errors.Is(err, errSend) || s.Len() != 1 {
+		t.Fatalf("expected the drain to stop with one frame left, got %v len=%d", err, s.Len())
+	}
+	if err := s.Push([]byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if got := append(sent, drain(t, s)...); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("expected frames in push order, got %v", got)
+	}
+}
+
+func TestFileSpool_SurvivesReopen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenFileSpool(dir, FileSpoolConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"a", "b", "c"} {
+		if err := s.Push([]byte(f)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	errSend := errors.New("send")
+	err = s.Drain(func(frame []byte) error {
+		if string(frame) == "b" {
+			return errSend
+		}
+		return nil
+	})
+	if !errors.Is(err, errSend) {
+		t.Fatalf("expected the send error, got %v", err)
+	}
+	s.Close()
+
+	// 模拟写入一半时崩溃
+	f, err := os.OpenFile(filepath.Join(dir, "spool"), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0, 0, 0, 9, 'x'})
+	f.Close()
+
+	s, err = OpenFileSpool(dir, FileSpoolConfig{Sync: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if s.Len() != 2 {
+		t.Fatalf("expected the unsent frames to remain, got %d", s.Len())
+	}
+	if err := s.Push([]byte("d")); err != nil {
+		t.Fatal(err)
+	}
+	if got := drain(t, s); !slices.Equal(got, []string{"b", "c", "d"}) {
+		t.Errorf("expected the torn frame to be dropped, got %v", got)
+	}
+	if info, _ := os.Stat(filepath.Join(dir, "spool")); info.Size() != 0 || s.Len() != 0 {
+		t.Errorf("expected the spool to be truncated once drained, size=%d len=%d", info.Size(), s.Len())
+	}
+}
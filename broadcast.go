@@ -1,27 +1,53 @@
 package broadcast
 
 import (
-	"sync"
+	"context"
+	"strings"
+	"time"
 	"unique"
 )
 
 type Handler[T comparable] func(signal string, data T, metadata map[string]interface{}) error
 
 type Broadcast[T comparable] struct {
-	mu        sync.RWMutex
-	handlers  []Handler[T]
-	listeners map[string][]unique.Handle[T]
+	core core[T, T]
+	// ns 非 nil 时本实例是 Namespace 返回的视图, 所有操作转发到根广播器
+	ns *namespace[T]
 }
 
-// Handle 注册一个处理器
-func (b *Broadcast[T]) Handle(handler Handler[T]) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+// c 返回实际保存状态的 core
+func (b *Broadcast[T]) c() *core[T, T] {
+	if b.ns != nil {
+		return b.ns.root
+	}
+	return &b.core
+}
+
+// sig 为信号加上命名空间前缀
+func (b *Broadcast[T]) sig(signal string) string {
+	if b.ns != nil {
+		return b.ns.prefix + signal
+	}
+	return signal
+}
 
-	if b.handlers == nil {
-		b.handlers = make([]Handler[T], 0)
+// prefix 返回命名空间前缀, 根广播器为空
+func (b *Broadcast[T]) prefix() string {
+	if b.ns != nil {
+		return b.ns.prefix
 	}
-	b.handlers = append(b.handlers, handler)
+	return ""
+}
+
+// Handle 注册一个处理器, 返回的 HandlerID 可用于 Unhandle
+func (b *Broadcast[T]) Handle(handler Handler[T], opts ...HandleOption) HandlerID {
+	return b.c().handle(b.prefix(), handlerFunc[T](handler), opts...)
+}
+
+// Unhandle 移除一个处理器, 返回处理器是否存在
+// 正在进行的广播仍会使用移除前的处理器快照
+func (b *Broadcast[T]) Unhandle(id HandlerID) bool {
+	return b.c().unhandle(id)
 }
 
 type uniqueWrapper[T comparable] struct {
@@ -36,121 +62,290 @@ func (u *uniqueWrapper[T]) Value() T {
 	return u.data
 }
 
-// Watch 监听一个信号
-func (b *Broadcast[T]) Watch(signal string, data T) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+// HandleEvent 注册一个以 Event 信封接收广播的处理器, 返回的 HandlerID 可用于 Unhandle
+func (b *Broadcast[T]) HandleEvent(handler EventHandler[T], opts ...HandleOption) HandlerID {
+	return b.c().handleEvent(b.prefix(), eventHandlerFunc[T](handler), opts...)
+}
 
-	if b.listeners == nil {
-		b.listeners = make(map[string][]unique.Handle[T])
-	}
+// HandleAfterReplay 注册一个处理器, 该处理器在 ReplayGate.Done 之前不接收实时事件
+// 调用方先将历史事件直接交给处理器回放, 再调用 Done; 期间到达的实时事件在 Done 时按顺序补投
+func (b *Broadcast[T]) HandleAfterReplay(handler Handler[T]) *ReplayGate {
+	return b.c().handleAfterReplay(b.prefix(), handlerFunc[T](handler))
+}
 
-	var (
-		handle    = unique.Make(data)
-		listeners = b.listeners[signal]
-	)
-	for _, listener := range listeners {
-		if listener == handle {
-			return
-		}
-	}
+// Watch 监听一个信号, 返回监听器是否被添加, 可以通过 WithRate 限制监听器接收投递的速率
+// data 已在监听该信号, 或严格模式的注册表拒绝了该信号时返回 false
+func (b *Broadcast[T]) Watch(signal string, data T, opts ...WatchOption) bool {
+	ok, _ := b.c().watchCtx(nil, b.sig(signal), newListener[T, T](&uniqueWrapper[T]{data: data}).withOptions(opts))
+	return ok
+}
 
-	b.listeners[signal] = append(b.listeners[signal], handle)
+// WatchContext 监听一个信号, 并在 ctx 取消时自动取消监听
+// 如果 data 已在监听该信号, 则不做任何事
+func (b *Broadcast[T]) WatchContext(ctx context.Context, signal string, data T) {
+	b.c().watchContext(ctx, b.sig(signal), newListener[T, T](&uniqueWrapper[T]{data: data}))
 }
 
-// Unwatch 取消监听一个信号
-func (b *Broadcast[T]) Unwatch(signal string, data T) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+// WatchGroup 监听一个信号, 并将监听器记录到 group 中, 以便通过 UnwatchGroup 一次性移除
+// 如果 data 已在监听该信号, 则不做任何事
+func (b *Broadcast[T]) WatchGroup(group string, signal string, data T) {
+	b.c().watchGroup(b.sig(group), b.sig(signal), newListener[T, T](&uniqueWrapper[T]{data: data}))
+}
 
-	var (
-		handle    = unique.Make(data)
-		listeners = b.listeners[signal]
-	)
-	if listeners == nil {
-		return
-	}
+// UnwatchGroup 移除 group 中的所有监听器, 返回实际移除的数量
+func (b *Broadcast[T]) UnwatchGroup(group string) int {
+	return b.c().unwatchGroup(b.sig(group))
+}
 
-	for i, item := range listeners {
-		if item == handle {
-			b.listeners[signal] = append(listeners[:i], listeners[i+1:]...)
-			break
-		}
-	}
+// Unwatch 取消监听一个信号, 返回是否有监听器被移除
+func (b *Broadcast[T]) Unwatch(signal string, data T) bool {
+	ok, _ := b.c().unwatchCtx(nil, b.sig(signal), unique.Make(data))
+	return ok
 }
 
 // Broadcast 广播一个信号, 以触发所有监听该信号的处理器
-func (b *Broadcast[T]) Broadcast(signal string, metadata map[string]interface{}) {
-	b.mu.RLock()
-	listeners := b.listeners[signal]
-	handlers := b.handlers
-	b.mu.RUnlock()
-
-	for _, handler := range handlers {
-		for _, data := range listeners {
-			_ = handler(signal, data.Value(), metadata)
+// 同步投递时返回所有处理器错误的组合, 异步投递时入队成功即返回 nil
+func (b *Broadcast[T]) Broadcast(signal string, metadata map[string]interface{}, opts ...BroadcastOption) error {
+	return b.c().broadcast(b.sig(signal), metadata, opts...)
+}
+
+// Expired 返回因超过 TTL 而被丢弃的异步投递数量
+func (b *Broadcast[T]) Expired() uint64 {
+	return b.c().expired.Load()
+}
+
+// OnError 设置错误回调, 每个处理器错误都会调用一次, 包括异步投递中的错误, 传入 nil 取消
+// 回调在投递所在的 goroutine 中同步执行
+func (b *Broadcast[T]) OnError(fn func(signal string, data T, handlerID HandlerID, err error)) {
+	b.c().updateSettings(func(s *settings[T, T]) {
+		s.onError = fn
+	})
+}
+
+// OnExpired 设置过期回调, 在异步队列或暂存缓冲中的事件因超过 TTL 被丢弃时调用, 传入 nil 取消
+func (b *Broadcast[T]) OnExpired(fn func(signal string, metadata map[string]interface{})) {
+	b.c().updateSettings(func(s *settings[T, T]) {
+		s.onExpired = fn
+	})
+}
+
+// WaitFor 阻塞直到每个信号都至少被广播过一次, 用于启动时等待配置等必要事件
+// 在调用之前已经广播过的信号视为已满足, ctx 结束时返回 ctx.Err()
+func (b *Broadcast[T]) WaitFor(ctx context.Context, signals ...string) error {
+	if b.ns != nil {
+		prefixed := make([]string, len(signals))
+		for i, signal := range signals {
+			prefixed[i] = b.sig(signal)
 		}
+		signals = prefixed
 	}
+	return b.c().waitFor(ctx, signals...)
+}
+
+// SetPriority 设置信号的优先级, 只影响异步投递
+// PriorityHigh 的广播在 AsyncConfig.PriorityWorkers 大于 0 时进入独立通道, PriorityLow 的广播在队列已满时被丢弃
+func (b *Broadcast[T]) SetPriority(signal string, p Priority) {
+	b.c().setPriority(b.sig(signal), p)
+}
+
+// SetRegistry 设置信号注册表, 传入 nil 关闭检查
+// 严格模式下未声明信号上的 Broadcast 返回 ErrUndeclaredSignal, Watch 不添加监听器, 并调用 OnViolation.
+// 注册表中的信号名包含命名空间前缀
+func (b *Broadcast[T]) SetRegistry(config *RegistryConfig) {
+	b.c().setRegistry(config)
+}
+
+// SetRateLimiter 设置信号级限流器, 以信号名为 key
+// 被限流的广播不会投递并返回 ErrRateLimited, 传入 nil 取消限流
+func (b *Broadcast[T]) SetRateLimiter(limiter RateLimiter) {
+	b.c().updateSettings(func(s *settings[T, T]) {
+		s.limiter = limiter
+	})
+}
+
+// EnableAsync 开启异步投递, Broadcast 只将事件放入有界队列后立即返回
+// 重复调用会替换原有队列, 原队列中的事件会先投递完成
+func (b *Broadcast[T]) EnableAsync(config AsyncConfig) {
+	b.c().enableAsync(config)
+}
+
+// Close 关闭异步投递并等待队列中的事件投递完成, 之后的广播同步执行
+func (b *Broadcast[T]) Close() {
+	b.c().close()
+}
+
+// Pending 返回异步队列中等待投递的事件数量
+func (b *Broadcast[T]) Pending() int {
+	return b.c().pending()
+}
+
+// SetReceiptStore 设置投递回执存储, 之后每次投递都会记录一条回执
+// 传入 nil 停止记录
+func (b *Broadcast[T]) SetReceiptStore(store ReceiptStore[T]) {
+	b.c().updateSettings(func(s *settings[T, T]) {
+		s.receipts = store
+	})
+}
+
+// SetDeterministic 开启或关闭确定性投递模式, 用于需要断言投递顺序的测试
+// 开启后所有投递同步执行, 处理器按注册顺序执行;
+// seed 为 0 时监听器按注册顺序接收事件, 否则按种子打乱, 相同种子与相同操作序列得到相同顺序
+func (b *Broadcast[T]) SetDeterministic(enabled bool, seed uint64) {
+	b.c().setDeterministic(enabled, seed)
+}
+
+// UseTransform 追加一个在处理器之前改写数据的 Transform, 按添加顺序执行
+// 存储的监听器值不会被修改, 复制语义见 Transform
+func (b *Broadcast[T]) UseTransform(t Transform[T]) {
+	b.c().useTransform(t)
+}
+
+// SetPendingBuffer 启用暂存缓冲: 信号没有监听器或处理器时, 广播被暂存而不是丢失,
+// 在该信号出现第一个监听器或新增处理器时重新投递. 每个信号最多暂存 size 个, 超出时丢弃最早的,
+// size <= 0 时关闭缓冲并丢弃已暂存的广播
+func (b *Broadcast[T]) SetPendingBuffer(size int) {
+	b.c().setPendingBuffer(size)
+}
+
+// Buffered 返回暂存缓冲中的广播数量
+func (b *Broadcast[T]) Buffered() int {
+	return b.c().buffered()
+}
+
+// EnableStore 从 Store 恢复监听器, 然后开启写穿模式, 之后的监听器变化同步写入 Store
+// 应在启动时、其他 Watch 之前调用. 在命名空间视图上调用等同于在根广播器上调用
+func (b *Broadcast[T]) EnableStore(config StoreConfig) error {
+	return b.c().enableStore(config, func(key T, value T) Uniquer[T, T] {
+		return &uniqueWrapper[T]{data: value}
+	})
+}
+
+// EnableJournal 开启广播日志, 选定信号的广播在投递前写入日志, 写入失败时 Broadcast 返回错误且不投递
+// 广播序号从日志的最后一条记录之后继续
+func (b *Broadcast[T]) EnableJournal(config JournalConfig) error {
+	return b.c().enableJournal(config)
+}
+
+// ReplayJournal 重新投递日志中序号不小于 from 的广播, 用于崩溃后重建下游状态
+// 回放的广播不会再次写入日志, 负载以 *RawPayload 交给处理器
+func (b *Broadcast[T]) ReplayJournal(from uint64) error {
+	return b.c().replayJournal(from)
+}
+
+// HandleDurable 注册持久处理器, 提供至少一次投递: 日志中的广播在处理器对所有监听器成功后才确认,
+// 失败时按 RetryInterval 重新投递, 重启后从 config.Cursors 中的进度之后由日志重新投递. 需要先调用 EnableJournal
+func (b *Broadcast[T]) HandleDurable(config DurableConfig, handler Handler[T]) (HandlerID, error) {
+	return b.c().handleDurable(config, b.prefix(), handlerFunc[T](handler))
+}
+
+// HandleIdempotent 注册幂等处理器, 已在 config.Store 中记录的事件不再交给处理器, 处理成功后记录事件
+// 事件 ID 由 WithEventID 设置, 未设置时使用广播序号
+func (b *Broadcast[T]) HandleIdempotent(config IdempotencyConfig, handler Handler[T]) HandlerID {
+	return b.c().handleIdempotent(config, b.prefix(), handlerFunc[T](handler))
+}
+
+// SetClock 设置时间源, 默认为 SystemClock
+func (b *Broadcast[T]) SetClock(clock Clock) {
+	b.c().updateSettings(func(s *settings[T, T]) {
+		s.clock = clock
+	})
+}
+
+// EnableDeadLetter 开启死信队列, 处理器返回错误的投递会被放入有界队列
+// 队列满时最早的死信被挤出, 交给 Spill 并广播 SignalDLQOverflow 元事件
+func (b *Broadcast[T]) EnableDeadLetter(config DeadLetterConfig[T, T]) {
+	b.c().enableDeadLetter(config)
+}
+
+// DeadLetters 返回当前死信队列中的死信
+func (b *Broadcast[T]) DeadLetters() []DeadLetter[T, T] {
+	return b.c().deadLetters(false)
+}
+
+// DrainDeadLetters 返回并清空死信队列
+func (b *Broadcast[T]) DrainDeadLetters() []DeadLetter[T, T] {
+	return b.c().deadLetters(true)
+}
+
+// SetBreaker 设置生产者侧熔断器, 传入 nil 关闭熔断
+func (b *Broadcast[T]) SetBreaker(config *BreakerConfig) {
+	b.c().setBreaker(config)
+}
+
+// Healthy 返回信号的熔断器是否闭合, 未设置熔断器时始终为 true
+func (b *Broadcast[T]) Healthy(signal string) bool {
+	return b.c().healthy(b.sig(signal))
+}
+
+// SetSampling 设置过载时的自适应采样, 传入 nil 关闭采样
+func (b *Broadcast[T]) SetSampling(config *SamplingConfig) {
+	b.c().setSampling(config)
+}
+
+// SamplingRate 返回低优先级信号当前的有效采样率, 未设置采样时为 1
+func (b *Broadcast[T]) SamplingRate() float64 {
+	return b.c().samplingRate()
 }
 
 // Clean 清除指定信号的所有监听器
 func (b *Broadcast[T]) Clean(signal string) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	delete(b.listeners, signal)
+	b.c().clean(b.sig(signal))
 }
 
 // CleanAll 清除所有信号的监听器
 func (b *Broadcast[T]) CleanAll() {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	b.listeners = make(map[string][]unique.Handle[T])
+	if b.ns != nil {
+		b.ns.root.cleanPrefix(b.ns.prefix)
+		return
+	}
+	b.c().cleanAll()
 }
 
 // HasWatch 检查指定信号是否有监听器
 func (b *Broadcast[T]) HasWatch(signal string) bool {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
-
-	listeners, exists := b.listeners[signal]
-	return exists && len(listeners) > 0
+	return b.c().hasWatch(b.sig(signal))
 }
 
 // WatchCount 返回指定信号的监听器数量
 func (b *Broadcast[T]) WatchCount(signal string) int {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
+	return b.c().watchCount(b.sig(signal))
+}
+
+// LastBroadcast 返回信号最近一次广播的时间, 从未广播时为零值
+func (b *Broadcast[T]) LastBroadcast(signal string) time.Time {
+	return b.c().lastBroadcast(b.sig(signal))
+}
 
-	return len(b.listeners[signal])
+// LastWatchChange 返回信号最近一次添加或移除监听器的时间, 从未变化时为零值
+func (b *Broadcast[T]) LastWatchChange(signal string) time.Time {
+	return b.c().lastWatchChange(b.sig(signal))
 }
 
 // Range 遍历所有信号及其监听器数量
 // 如果 fn 返回 false，则停止遍历
 func (b *Broadcast[T]) Range(fn func(signal string, count int) bool) {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
-
-	for signal, listeners := range b.listeners {
-		if !fn(signal, len(listeners)) {
-			break
-		}
+	if b.ns != nil {
+		b.ns.root.rangeSignals(func(signal string, count int) bool {
+			if rest, ok := strings.CutPrefix(signal, b.ns.prefix); ok {
+				return fn(rest, count)
+			}
+			return true
+		})
+		return
 	}
+	b.c().rangeSignals(fn)
 }
 
-// New 创建一个新的广播实例
-func New[T comparable]() *Broadcast[T] {
-	return &Broadcast[T]{
-		handlers:  make([]Handler[T], 0),
-		listeners: make(map[string][]unique.Handle[T]),
-	}
+// New 创建一个新的广播实例, opts 用于在构造时配置异步投递、时间源、日志等能力
+func New[T comparable](opts ...Option) *Broadcast[T] {
+	b := &Broadcast[T]{}
+	b.core.apply(opts)
+	return b
 }
 
-// NewUnique 创建一个新的 UniqueBroadcast 实例
-func NewUnique[K comparable, T any]() *UniqueBroadcast[K, T] {
-	return &UniqueBroadcast[K, T]{
-		handlers:  make([]UniqueHandler[K, T], 0),
-		listeners: make(map[string][]Uniquer[K, T]),
-	}
+// NewUnique 创建一个新的 UniqueBroadcast 实例, opts 与 New 相同
+func NewUnique[K comparable, T any](opts ...Option) *UniqueBroadcast[K, T] {
+	b := &UniqueBroadcast[K, T]{}
+	b.core.apply(opts)
+	return b
 }
@@ -35,8 +35,8 @@ func TestBroadcast_Watch(t *testing.T) {
 	b.Watch("test", "data1") // Should not duplicate
 	b.Watch("test", "data2")
 
-	if len(b.listeners["test"]) != 2 {
-		t.Errorf("expected 2 listeners, got %d", len(b.listeners["test"]))
+	if b.WatchCount("test") != 2 {
+		t.Errorf("expected 2 listeners, got %d", b.WatchCount("test"))
 	}
 }
 
@@ -47,8 +47,8 @@ func TestBroadcast_Unwatch(t *testing.T) {
 	b.Watch("test", "data2")
 	b.Unwatch("test", "data1")
 
-	if len(b.listeners["test"]) != 1 {
-		t.Errorf("expected 1 listener after unwatch, got %d", len(b.listeners["test"]))
+	if b.WatchCount("test") != 1 {
+		t.Errorf("expected 1 listener after unwatch, got %d", b.WatchCount("test"))
 	}
 }
 
@@ -244,8 +244,8 @@ func TestBroadcast_StructData(t *testing.T) {
 				b.Watch("test", data2) // Should be considered duplicate
 			},
 			validate: func(t *testing.T, b *Broadcast[*TestData]) {
-				if len(b.listeners["test"]) != 2 {
-					t.Errorf("expected 2 listeners for struct data, got %d", len(b.listeners["test"]))
+				if b.WatchCount("test") != 2 {
+					t.Errorf("expected 2 listeners for struct data, got %d", b.WatchCount("test"))
 				}
 			},
 		},
@@ -270,8 +270,8 @@ func TestBroadcast_StructData(t *testing.T) {
 				b.Watch("test", data2)
 			},
 			validate: func(t *testing.T, b *Broadcast[*TestData]) {
-				if len(b.listeners["test"]) != 2 {
-					t.Errorf("expected 2 listeners for different struct data, got %d", len(b.listeners["test"]))
+				if b.WatchCount("test") != 2 {
+					t.Errorf("expected 2 listeners for different struct data, got %d", b.WatchCount("test"))
 				}
 			},
 		},
@@ -289,8 +289,8 @@ func TestBroadcast_StructData(t *testing.T) {
 				b.Unwatch("test", data)
 			},
 			validate: func(t *testing.T, b *Broadcast[*TestData]) {
-				if len(b.listeners["test"]) != 0 {
-					t.Errorf("expected 0 listeners after unwatch, got %d", len(b.listeners["test"]))
+				if b.WatchCount("test") != 0 {
+					t.Errorf("expected 0 listeners after unwatch, got %d", b.WatchCount("test"))
 				}
 			},
 		},
@@ -327,8 +327,8 @@ func TestBroadcast_StructDataUniquer(t *testing.T) {
 	b.Watch("test", data2)
 	b.Watch("test", data3)
 
-	if len(b.listeners["test"]) != 2 {
-		t.Errorf("expected 2 listeners, got %d", len(b.listeners["test"]))
+	if b.WatchCount("test") != 2 {
+		t.Errorf("expected 2 listeners, got %d", b.WatchCount("test"))
 	}
 
 	b.Broadcast("test", nil)
@@ -484,12 +484,18 @@ func TestBroadcast_WatchCount(t *testing.T) {
 		t.Errorf("expected 3 watchers, got %d", count)
 	}
 
-	// 测试添加重复数据
-	duplicate := TestDataUniquer{ID: 0, Name: "duplicate"}
-	b.Watch("test", duplicate)
+	// 测试添加重复数据: Broadcast 以整个值去重, 相同的值不会重复添加
+	b.Watch("test", TestDataUniquer{ID: 0, Name: "test0"})
 	if count := b.WatchCount("test"); count != 3 {
 		t.Errorf("watcher count should not increase for duplicate data, got %d", count)
 	}
+
+	// 只有部分字段相同的值是不同的监听器
+	duplicate := TestDataUniquer{ID: 0, Name: "duplicate"}
+	b.Watch("test", duplicate)
+	if count := b.WatchCount("test"); count != 4 {
+		t.Errorf("a value sharing only the ID should be a separate watcher, got %d", count)
+	}
 }
 
 // 添加性能测试
@@ -578,3 +584,77 @@ func BenchmarkBroadcast_Range(b *testing.B) {
 		})
 	}
 }
+
+func TestBroadcast_Unhandle(t *testing.T) {
+	b := New[string]()
+	var first, second int
+
+	id := b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		first++
+		return nil
+	})
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		second++
+		return nil
+	})
+	b.Watch("test", "data")
+
+	if !b.Unhandle(id) {
+		t.Fatal("Unhandle should report the handler as removed")
+	}
+	if b.Unhandle(id) {
+		t.Error("second Unhandle should report nothing removed")
+	}
+
+	b.Broadcast("test", nil)
+	if first != 0 || second != 1 {
+		t.Errorf("expected only the remaining handler to run, got first=%d second=%d", first, second)
+	}
+}
+
+func TestBroadcast_OnError(t *testing.T) {
+	b := New[string]()
+	b.Watch("test", "a")
+	b.Watch("test", "b")
+
+	failing := b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		if data == "b" {
+			return fmt.Errorf("bad %s", data)
+		}
+		return nil
+	})
+
+	type failure struct {
+		signal string
+		data   string
+		id     HandlerID
+		err    string
+	}
+	var got []failure
+	b.OnError(func(signal string, data string, handlerID HandlerID, err error) {
+		got = append(got, failure{signal, data, handlerID, err.Error()})
+	})
+
+	if err := b.Broadcast("test", nil); err == nil {
+		t.Error("expected the aggregated error to still be returned")
+	}
+	if len(got) != 1 || got[0] != (failure{"test", "b", failing, "bad b"}) {
+		t.Errorf("expected one OnError call for b, got %v", got)
+	}
+}
+
+func TestBroadcast_WatchUnwatchResult(t *testing.T) {
+	b := New[string]()
+	if !b.Watch("test", "a") {
+		t.Error("expected the first Watch to add the listener")
+	}
+	if b.Watch("test", "a") {
+		t.Error("expected a duplicate Watch to report false")
+	}
+	if !b.Unwatch("test", "a") {
+		t.Error("expected Unwatch to report the removal")
+	}
+	if b.Unwatch("test", "a") || b.Unwatch("missing", "a") {
+		t.Error("expected Unwatch of an absent listener to report false")
+	}
+}
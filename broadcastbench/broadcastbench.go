@@ -0,0 +1,150 @@
+// Package broadcastbench 是 broadcast 包的负载生成与基准测试工具
+// 按配置创建信号、监听器、处理器和发布 goroutine, 报告吞吐量与延迟分位数,
+// 用于部署容量评估和性能回归跟踪
+package broadcastbench
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+// Config 负载配置
+type Config struct {
+	// Signals 信号数量
+	Signals int
+	// Listeners 每个信号上的监听器数量
+	Listeners int
+	// Handlers 处理器数量
+	Handlers int
+	// Publishers 并发发布的 goroutine 数量
+	Publishers int
+	// Duration 压测时长
+	Duration time.Duration
+	// HandlerWork 每次处理器调用模拟的工作耗时
+	HandlerWork time.Duration
+	// Async 非 nil 时开启异步投递, 此时延迟为入队耗时
+	Async *broadcast.AsyncConfig
+}
+
+// Result 压测结果, 延迟为单次 Broadcast 调用的耗时
+type Result struct {
+	Broadcasts int64
+	Deliveries int64
+	Elapsed    time.Duration
+	P50        time.Duration
+	P90        time.Duration
+	P99        time.Duration
+	Max        time.Duration
+}
+
+// Throughput 返回每秒广播次数
+func (r Result) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Broadcasts) / r.Elapsed.Seconds()
+}
+
+// String 返回单行可读报告
+func (r Result) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "broadcasts=%d deliveries=%d elapsed=%v throughput=%.0f/s",
+		r.Broadcasts, r.Deliveries, r.Elapsed.Round(time.Millisecond), r.Throughput())
+	fmt.Fprintf(&sb, " p50=%v p90=%v p99=%v max=%v", r.P50, r.P90, r.P99, r.Max)
+	return sb.String()
+}
+
+func (c *Config) setDefaults() {
+	if c.Signals <= 0 {
+		c.Signals = 1
+	}
+	if c.Listeners <= 0 {
+		c.Listeners = 1
+	}
+	if c.Handlers <= 0 {
+		c.Handlers = 1
+	}
+	if c.Publishers <= 0 {
+		c.Publishers = 1
+	}
+	if c.Duration <= 0 {
+		c.Duration = time.Second
+	}
+}
+
+// Run 按配置执行一次压测
+func Run(cfg Config) Result {
+	cfg.setDefaults()
+
+	b := broadcast.New[int]()
+	var deliveries atomic.Int64
+	for i := 0; i < cfg.Handlers; i++ {
+		b.Handle(func(signal string, data int, metadata map[string]interface{}) error {
+			if cfg.HandlerWork > 0 {
+				time.Sleep(cfg.HandlerWork)
+			}
+			deliveries.Add(1)
+			return nil
+		})
+	}
+
+	signals := make([]string, cfg.Signals)
+	for i := range signals {
+		signals[i] = fmt.Sprintf("signal-%d", i)
+		for j := 0; j < cfg.Listeners; j++ {
+			b.Watch(signals[i], j)
+		}
+	}
+
+	if cfg.Async != nil {
+		b.EnableAsync(*cfg.Async)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		latencies = make([][]time.Duration, cfg.Publishers)
+		deadline  = time.Now().Add(cfg.Duration)
+		start     = time.Now()
+	)
+	wg.Add(cfg.Publishers)
+	for p := 0; p < cfg.Publishers; p++ {
+		go func(p int) {
+			defer wg.Done()
+			for i := p; time.Now().Before(deadline); i++ {
+				begin := time.Now()
+				b.Broadcast(signals[i%len(signals)], nil)
+				latencies[p] = append(latencies[p], time.Since(begin))
+			}
+		}(p)
+	}
+	wg.Wait()
+	b.Close()
+	elapsed := time.Since(start)
+
+	all := slices.Concat(latencies...)
+	slices.Sort(all)
+	return Result{
+		Broadcasts: int64(len(all)),
+		Deliveries: deliveries.Load(),
+		Elapsed:    elapsed,
+		P50:        percentile(all, 0.50),
+		P90:        percentile(all, 0.90),
+		P99:        percentile(all, 0.99),
+		Max:        percentile(all, 1),
+	}
+}
+
+// percentile 返回已排序样本的 q 分位数
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(q*float64(len(sorted))+0.5) - 1
+	return sorted[max(0, min(i, len(sorted)-1))]
+}
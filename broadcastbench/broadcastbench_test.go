@@ -0,0 +1,57 @@
+package broadcastbench
+
+import (
+	"testing"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+func TestRun(t *testing.T) {
+	r := Run(Config{
+		Signals:    4,
+		Listeners:  10,
+		Handlers:   2,
+		Publishers: 4,
+		Duration:   50 * time.Millisecond,
+	})
+
+	if r.Broadcasts == 0 {
+		t.Fatal("expected some broadcasts")
+	}
+	if r.Deliveries != r.Broadcasts*10*2 {
+		t.Errorf("expected %d deliveries, got %d", r.Broadcasts*20, r.Deliveries)
+	}
+	if !(r.P50 <= r.P90 && r.P90 <= r.P99 && r.P99 <= r.Max) {
+		t.Errorf("percentiles not monotonic: %s", r)
+	}
+	if r.Throughput() <= 0 {
+		t.Errorf("expected positive throughput: %s", r)
+	}
+}
+
+func TestRun_Async(t *testing.T) {
+	r := Run(Config{
+		Listeners: 5,
+		Duration:  20 * time.Millisecond,
+		Async:     &broadcast.AsyncConfig{Workers: 2, QueueSize: 64, Overflow: broadcast.Block},
+	})
+
+	// Close 会等待队列投递完成, 因此所有广播都已被处理
+	if r.Deliveries != r.Broadcasts*5 {
+		t.Errorf("expected %d deliveries, got %d", r.Broadcasts*5, r.Deliveries)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	samples := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if p := percentile(samples, 0.5); p != 5 {
+		t.Errorf("expected p50=5, got %v", p)
+	}
+	if p := percentile(samples, 0.99); p != 10 {
+		t.Errorf("expected p99=10, got %v", p)
+	}
+	if p := percentile(nil, 0.5); p != 0 {
+		t.Errorf("expected 0 for empty samples, got %v", p)
+	}
+}
@@ -0,0 +1,115 @@
+// Package broadcasttest 提供测试 broadcast 包使用者代码的辅助工具
+package broadcasttest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+// FakeClock 是手动推进的 broadcast.Clock
+// 定时器只在 Advance/Set 时触发, 触发的回调在调用 Advance 的 goroutine 中同步执行
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+var _ broadcast.Clock = (*FakeClock)(nil)
+
+type fakeTimer struct {
+	clock   *FakeClock
+	when    time.Time
+	f       func()
+	stopped bool
+}
+
+// NewFakeClock 创建一个从 now 开始的 FakeClock
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now 返回当前的模拟时间
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// AfterFunc 注册一个在模拟时间推进 d 之后触发的回调
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) broadcast.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, when: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance 将模拟时间推进 d, 并按到期顺序触发到期的定时器
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set 将模拟时间设置为 now, 并按到期顺序触发到期的定时器
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	sort.SliceStable(c.timers, func(i, j int) bool {
+		return c.timers[i].when.Before(c.timers[j].when)
+	})
+
+	var due []*fakeTimer
+	remaining := c.timers[:0]
+	for _, t := range c.timers {
+		if !t.when.After(now) {
+			due = append(due, t)
+		} else {
+			remaining = append(remaining, t)
+		}
+	}
+	c.timers = remaining
+	c.mu.Unlock()
+
+	for _, t := range due {
+		c.mu.Lock()
+		c.now = t.when
+		stopped := t.stopped
+		t.stopped = true
+		c.mu.Unlock()
+		if !stopped {
+			t.f()
+		}
+	}
+
+	c.mu.Lock()
+	c.now = now
+	c.mu.Unlock()
+}
+
+// Pending 返回尚未触发的定时器数量
+func (c *FakeClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, t := range c.timers {
+		if !t.stopped {
+			n++
+		}
+	}
+	return n
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	if t.stopped {
+		return false
+	}
+	t.stopped = true
+	return true
+}
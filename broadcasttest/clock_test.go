@@ -0,0 +1,41 @@
+package broadcasttest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewFakeClock(start)
+
+	var fired []int
+	c.AfterFunc(2*time.Second, func() { fired = append(fired, 2) })
+	c.AfterFunc(time.Second, func() {
+		if !c.Now().Equal(start.Add(time.Second)) {
+			t.Errorf("timer should observe its own deadline, got %v", c.Now())
+		}
+		fired = append(fired, 1)
+	})
+	stopped := c.AfterFunc(1500*time.Millisecond, func() { fired = append(fired, 3) })
+
+	if !stopped.Stop() || stopped.Stop() {
+		t.Error("Stop should succeed exactly once")
+	}
+
+	c.Advance(500 * time.Millisecond)
+	if len(fired) != 0 {
+		t.Fatalf("no timer should fire yet, got %v", fired)
+	}
+
+	c.Advance(2 * time.Second)
+	if len(fired) != 2 || fired[0] != 1 || fired[1] != 2 {
+		t.Errorf("expected timers to fire in deadline order [1 2], got %v", fired)
+	}
+	if !c.Now().Equal(start.Add(2500 * time.Millisecond)) {
+		t.Errorf("unexpected time after Advance: %v", c.Now())
+	}
+	if c.Pending() != 0 {
+		t.Errorf("expected no pending timers, got %d", c.Pending())
+	}
+}
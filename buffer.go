@@ -0,0 +1,98 @@
+package broadcast
+
+import (
+	"sync"
+)
+
+// pendingBuffer 暂存没有监听器或处理器的信号上的广播, 每个信号最多保存 size 个
+type pendingBuffer[K comparable, T any] struct {
+	mu      sync.Mutex
+	size    int
+	signals map[string][]delivery[K, T]
+}
+
+// add 暂存一次投递, 超出容量时丢弃该信号最早的投递
+func (b *pendingBuffer[K, T]) add(d delivery[K, T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	d.listeners, d.handlers = nil, nil
+	queue := b.signals[d.signal]
+	if len(queue) >= b.size {
+		queue = queue[1:]
+	}
+	b.signals[d.signal] = append(queue, d)
+}
+
+// take 取出并移除指定信号的暂存投递
+func (b *pendingBuffer[K, T]) take(signal string) []delivery[K, T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	queue := b.signals[signal]
+	delete(b.signals, signal)
+	return queue
+}
+
+// keys 返回有暂存投递的信号
+func (b *pendingBuffer[K, T]) keys() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	keys := make([]string, 0, len(b.signals))
+	for signal := range b.signals {
+		keys = append(keys, signal)
+	}
+	return keys
+}
+
+func (b *pendingBuffer[K, T]) len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := 0
+	for _, queue := range b.signals {
+		n += len(queue)
+	}
+	return n
+}
+
+// setPendingBuffer 启用每个信号最多 size 个的暂存缓冲, size <= 0 时关闭并丢弃已暂存的广播
+func (c *core[K, T]) setPendingBuffer(size int) {
+	c.updateSettings(func(s *settings[K, T]) {
+		if size <= 0 {
+			s.buffer = nil
+			return
+		}
+		s.buffer = &pendingBuffer[K, T]{size: size, signals: make(map[string][]delivery[K, T])}
+	})
+}
+
+// flushBuffer 重新投递指定信号的暂存广播, 仍然没有监听器或处理器的广播会再次被暂存
+func (c *core[K, T]) flushBuffer(signals ...string) {
+	buffer := c.loadSettings().buffer
+	if buffer == nil {
+		return
+	}
+	if len(signals) == 0 {
+		signals = buffer.keys()
+	}
+
+	now := c.clock().Now()
+	for _, signal := range signals {
+		for _, d := range buffer.take(signal) {
+			if d.expired(now) {
+				c.expire(d)
+				continue
+			}
+			_ = c.dispatch(d)
+		}
+	}
+}
+
+func (c *core[K, T]) buffered() int {
+	if buffer := c.loadSettings().buffer; buffer != nil {
+		return buffer.len()
+	}
+	return 0
+}
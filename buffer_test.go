@@ -0,0 +1,73 @@
+package broadcast
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPendingBuffer_FlushOnFirstListener(t *testing.T) {
+	b := New[string]()
+	b.SetPendingBuffer(2)
+
+	var received []int
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		received = append(received, metadata["seq"].(int))
+		return nil
+	})
+
+	for i := 1; i <= 3; i++ {
+		b.Broadcast("config", map[string]interface{}{"seq": i})
+	}
+	if b.Buffered() != 2 {
+		t.Fatalf("expected 2 buffered broadcasts, got %d", b.Buffered())
+	}
+
+	b.Watch("config", "service")
+	if !slices.Equal(received, []int{2, 3}) {
+		t.Errorf("expected [2 3] after flush, got %v", received)
+	}
+	if b.Buffered() != 0 {
+		t.Errorf("expected empty buffer, got %d", b.Buffered())
+	}
+
+	b.Broadcast("config", map[string]interface{}{"seq": 4})
+	if !slices.Equal(received, []int{2, 3, 4}) {
+		t.Errorf("expected direct delivery once listeners exist, got %v", received)
+	}
+}
+
+func TestPendingBuffer_FlushOnHandler(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b := NewUnique[int, TestUniqueData]()
+	b.SetClock(clock)
+	b.SetPendingBuffer(4)
+	b.Watch("schema", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	b.Broadcast("schema", map[string]interface{}{"seq": 1}, WithEventTTL(time.Second))
+	b.Broadcast("schema", map[string]interface{}{"seq": 2})
+	clock.now = clock.now.Add(time.Minute)
+
+	var received []int
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		received = append(received, metadata["seq"].(int))
+		return nil
+	})
+	if !slices.Equal(received, []int{2}) {
+		t.Errorf("expected only the unexpired broadcast, got %v", received)
+	}
+	if b.Expired() != 1 {
+		t.Errorf("expected 1 expired broadcast, got %d", b.Expired())
+	}
+}
+
+func TestPendingBuffer_Disabled(t *testing.T) {
+	b := New[string]()
+	b.SetPendingBuffer(4)
+	b.Broadcast("test", nil)
+	b.SetPendingBuffer(0)
+
+	if b.Buffered() != 0 {
+		t.Errorf("expected disabling the buffer to drop broadcasts, got %d", b.Buffered())
+	}
+}
@@ -0,0 +1,107 @@
+package broadcast
+
+import (
+	"unique"
+)
+
+// watchIfAbsent 在 key 不存在时添加监听器, 已存在时返回现有的监听器
+func (c *core[K, T]) watchIfAbsent(signal string, l listener[K, T]) (Uniquer[K, T], bool) {
+	var existing Uniquer[K, T]
+	if !c.permit(OpWatch, signal) || c.validate(signal, l.data.Value()) != nil {
+		return nil, false
+	}
+	added := c.mutateEntry(signal, true, func(e *signalEntry[K, T]) ([]listener[K, T], bool) {
+		listeners := e.load()
+		for _, item := range listeners {
+			if item.key == l.key {
+				existing = item.data
+				return nil, false
+			}
+		}
+
+		newListeners := e.append(listeners, l)
+		c.track(signal, l)
+		return newListeners, true
+	})
+	if added {
+		c.flushBuffer(signal)
+	}
+	return existing, added
+}
+
+// unwatchIf 在 key 存在且当前值满足 pred 时移除监听器, 检查与移除在同一把锁内完成
+func (c *core[K, T]) unwatchIf(signal string, key unique.Handle[K], pred func(current T) bool) bool {
+	return c.mutate(signal, false, func(listeners []listener[K, T]) ([]listener[K, T], bool) {
+		for i, item := range listeners {
+			if item.key != key {
+				continue
+			}
+			if !pred(item.data.Value()) {
+				return nil, false
+			}
+			newListeners := make([]listener[K, T], 0, len(listeners)-1)
+			newListeners = append(newListeners, listeners[:i]...)
+			newListeners = append(newListeners, listeners[i+1:]...)
+			c.untrack(signal, key)
+			return newListeners, true
+		}
+		return nil, false
+	})
+}
+
+// swapIf 在 key 存在且当前值满足 pred 时替换监听器, 检查与替换在同一把锁内完成
+func (c *core[K, T]) swapIf(signal string, l listener[K, T], pred func(current T) bool) bool {
+	if !c.permit(OpWatch, signal) || c.validate(signal, l.data.Value()) != nil {
+		return false
+	}
+	return c.mutate(signal, false, func(listeners []listener[K, T]) ([]listener[K, T], bool) {
+		for i, item := range listeners {
+			if item.key != l.key {
+				continue
+			}
+			if !pred(item.data.Value()) {
+				return nil, false
+			}
+			newListeners := make([]listener[K, T], len(listeners))
+			copy(newListeners, listeners)
+			newListeners[i] = l
+			c.track(signal, l)
+			return newListeners, true
+		}
+		return nil, false
+	})
+}
+
+// WatchIfAbsent 在信号上没有相同 key 的监听器时添加 data, 返回 data 的值与 true;
+// 否则不做修改, 返回现有监听器的值与 false. 授权或校验失败时同样不添加, added 为 false.
+// 可用于实现每个 key 只有一个所有者的协调
+func (b *UniqueBroadcast[K, T]) WatchIfAbsent(signal string, data Uniquer[K, T]) (actual T, added bool) {
+	existing, added := b.core.watchIfAbsent(signal, newListener(data))
+	if !added && existing != nil {
+		return existing.Value(), false
+	}
+	return data.Value(), added
+}
+
+// UnwatchIf 在 key 的当前值满足 pred 时取消监听, 返回是否被移除
+// pred 在该信号的锁内执行, 不得在其中修改同一信号的监听器
+func (b *UniqueBroadcast[K, T]) UnwatchIf(signal string, key K, pred func(current T) bool) bool {
+	if !b.core.permit(OpUnwatch, signal) {
+		return false
+	}
+	return b.core.unwatchIf(signal, unique.Make(key), pred)
+}
+
+// CompareAndSwapWatch 在相同 key 的当前值满足 pred 时替换为 data, 返回是否被替换
+// key 不存在、授权或校验失败时不替换. pred 在该信号的锁内执行, 不得在其中修改同一信号的监听器
+func (b *UniqueBroadcast[K, T]) CompareAndSwapWatch(signal string, data Uniquer[K, T], pred func(current T) bool) bool {
+	return b.core.swapIf(signal, newListener(data), pred)
+}
+
+// UnwatchIfValue 在 key 的当前值等于 expected 时取消监听, 返回是否被移除
+// 例如只有当前所有者才能释放 key 的所有权
+func UnwatchIfValue[K comparable, T comparable](b *UniqueBroadcast[K, T], signal string, key K, expected T) bool {
+	return b.UnwatchIf(signal, key, func(current T) bool {
+		return current == expected
+	})
+}
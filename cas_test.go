@@ -0,0 +1,80 @@
+package broadcast
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"unique"
+)
+
+// owner 以资源名为 key, 值为持有者
+type owner struct {
+	resource string
+	holder   string
+}
+
+func (o owner) Unique() unique.Handle[string] { return unique.Make(o.resource) }
+func (o owner) Value() string                 { return o.holder }
+
+func TestWatchIfAbsent_SingleOwner(t *testing.T) {
+	b := NewUnique[string, string]()
+
+	var winners atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			holder := string(rune('a' + i))
+			if actual, added := b.WatchIfAbsent("locks", owner{"db", holder}); added {
+				winners.Add(1)
+				if actual != holder {
+					t.Errorf("expected the winner to see its own value, got %q", actual)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if winners.Load() != 1 {
+		t.Errorf("expected exactly one owner, got %d", winners.Load())
+	}
+	current, _ := b.Get("locks", "db")
+	if actual, added := b.WatchIfAbsent("locks", owner{"db", "late"}); added || actual != current {
+		t.Errorf("expected the existing owner %q, got %q %v", current, actual, added)
+	}
+}
+
+func TestUnwatchIfValue(t *testing.T) {
+	b := NewUnique[string, string]()
+	b.Watch("locks", owner{"db", "alice"})
+
+	if UnwatchIfValue(b, "locks", "db", "bob") {
+		t.Error("expected a non-owner release to fail")
+	}
+	if !UnwatchIfValue(b, "locks", "db", "alice") {
+		t.Error("expected the owner release to succeed")
+	}
+	if b.Has("locks", "db") || UnwatchIfValue(b, "locks", "db", "alice") {
+		t.Error("expected the key to be released once")
+	}
+}
+
+func TestCompareAndSwapWatch(t *testing.T) {
+	b := NewUnique[string, string]()
+	isAlice := func(current string) bool { return current == "alice" }
+
+	if b.CompareAndSwapWatch("locks", owner{"db", "bob"}, isAlice) {
+		t.Error("expected no swap for a missing key")
+	}
+	b.Watch("locks", owner{"db", "alice"})
+	if !b.CompareAndSwapWatch("locks", owner{"db", "bob"}, isAlice) {
+		t.Error("expected the swap to succeed")
+	}
+	if b.CompareAndSwapWatch("locks", owner{"db", "carol"}, isAlice) {
+		t.Error("expected the second swap to fail")
+	}
+	if v, _ := b.Get("locks", "db"); v != "bob" {
+		t.Errorf("expected bob to own db, got %q", v)
+	}
+}
@@ -0,0 +1,103 @@
+package broadcast
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// HealthChecker 报告桥接连接 (Redis、NATS、gRPC 等) 或日志等基础设施组件的状态
+type HealthChecker interface {
+	// Healthy 在组件无法自行恢复、需要重启进程时返回错误, 用于存活探针
+	Healthy() error
+	// Ready 在组件暂时不能承接流量时返回错误, 例如连接正在重连, 用于就绪探针
+	Ready() error
+}
+
+// HealthCheckFunc 将一个函数同时用作 Healthy 与 Ready
+type HealthCheckFunc func() error
+
+// Healthy 调用 f
+func (f HealthCheckFunc) Healthy() error { return f() }
+
+// Ready 调用 f
+func (f HealthCheckFunc) Ready() error { return f() }
+
+// HealthChecks 汇总多个组件的状态, 供 Kubernetes 存活与就绪探针使用, 可被多个广播器共享
+type HealthChecks struct {
+	mu     sync.RWMutex
+	names  []string
+	checks map[string]HealthChecker
+}
+
+// NewHealthChecks 创建空的检查集合
+func NewHealthChecks() *HealthChecks {
+	return &HealthChecks{checks: make(map[string]HealthChecker)}
+}
+
+// Add 以 name 注册一个组件, 同名组件被替换
+func (h *HealthChecks) Add(name string, check HealthChecker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.checks[name]; !ok {
+		h.names = append(h.names, name)
+	}
+	h.checks[name] = check
+}
+
+// Remove 移除一个组件, 返回组件是否存在
+func (h *HealthChecks) Remove(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.checks[name]; !ok {
+		return false
+	}
+	delete(h.checks, name)
+	h.names = slices.DeleteFunc(h.names, func(n string) bool { return n == name })
+	return true
+}
+
+// Names 返回按注册顺序排列的组件名
+func (h *HealthChecks) Names() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return slices.Clone(h.names)
+}
+
+// run 按注册顺序检查每个组件, 返回以组件名标注的错误的组合
+func (h *HealthChecks) run(check func(c HealthChecker) error) error {
+	h.mu.RLock()
+	names := slices.Clone(h.names)
+	checks := make([]HealthChecker, len(names))
+	for i, name := range names {
+		checks[i] = h.checks[name]
+	}
+	h.mu.RUnlock()
+
+	var errs []error
+	for i, c := range checks {
+		if err := check(c); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", names[i], err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Healthy 返回所有组件 Healthy 错误的组合, 所有组件都健康时返回 nil
+func (h *HealthChecks) Healthy() error {
+	return h.run(HealthChecker.Healthy)
+}
+
+// Ready 返回所有组件 Ready 错误的组合, 不健康的组件同样视为未就绪
+func (h *HealthChecks) Ready() error {
+	return h.run(func(c HealthChecker) error {
+		if err := c.Healthy(); err != nil {
+			return err
+		}
+		return c.Ready()
+	})
+}
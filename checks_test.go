@@ -0,0 +1,99 @@
+package broadcast
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type fakeBridge struct {
+	healthy, ready error
+}
+
+func (f *fakeBridge) Healthy() error { return f.healthy }
+func (f *fakeBridge) Ready() error   { return f.ready }
+
+func TestHealthChecks_Aggregate(t *testing.T) {
+	checks := NewHealthChecks()
+	if checks.Healthy() != nil || checks.Ready() != nil {
+		t.Error("expected an empty set to be healthy and ready")
+	}
+
+	redis := &fakeBridge{}
+	nats := &fakeBridge{}
+	checks.Add("redis", redis)
+	checks.Add("nats", nats)
+	checks.Add("static", HealthCheckFunc(func() error { return nil }))
+	if !slices.Equal(checks.Names(), []string{"redis", "nats", "static"}) {
+		t.Errorf("expected registration order, got %v", checks.Names())
+	}
+
+	errReconnecting := errors.New("reconnecting")
+	nats.ready = errReconnecting
+	if err := checks.Healthy(); err != nil {
+		t.Errorf("expected a reconnecting bridge to stay alive, got %v", err)
+	}
+	err := checks.Ready()
+	if !errors.Is(err, errReconnecting) || !strings.Contains(err.Error(), "nats: reconnecting") {
+		t.Errorf("expected the not-ready bridge to be named, got %v", err)
+	}
+
+	errBroken := errors.New("broken")
+	redis.healthy = errBroken
+	if err := checks.Ready(); !errors.Is(err, errBroken) || !errors.Is(err, errReconnecting) {
+		t.Errorf("expected an unhealthy bridge to be not ready as well, got %v", err)
+	}
+
+	if !checks.Remove("redis") || checks.Remove("redis") {
+		t.Error("expected Remove to report whether the check existed")
+	}
+	checks.Add("nats", &fakeBridge{})
+	if checks.Healthy() != nil || checks.Ready() != nil || len(checks.Names()) != 2 {
+		t.Errorf("expected Add to replace the check in place, got %v", checks.Names())
+	}
+}
+
+func TestFileJournal_HealthChecks(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := OpenFileJournal(dir, FileJournalConfig{SegmentSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	checks := NewHealthChecks()
+	checks.Add("journal", journal)
+	b := New[string]()
+	b.EnableJournal(JournalConfig{Journal: journal})
+	if err := b.Broadcast("orders", nil); err != nil {
+		t.Fatal(err)
+	}
+	if checks.Healthy() != nil || checks.Ready() != nil {
+		t.Errorf("expected a writable journal to be ready, got %v", checks.Ready())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected the probe file to be removed, got %d entries", len(entries))
+	}
+
+	// 每条记录都开始新的分段, 目录被删除后追加失败
+	os.RemoveAll(dir)
+	if err := b.Broadcast("orders", nil); err == nil {
+		t.Fatal("expected the append to fail")
+	}
+	if err := checks.Healthy(); err == nil || !strings.Contains(err.Error(), "journal: journal append") {
+		t.Errorf("expected the failed append to be reported, got %v", err)
+	}
+	if err := checks.Ready(); err == nil {
+		t.Error("expected a missing directory to be not ready")
+	}
+
+	os.MkdirAll(dir, 0o755)
+	if err := b.Broadcast("orders", nil); err != nil {
+		t.Fatal(err)
+	}
+	if checks.Healthy() != nil || checks.Ready() != nil {
+		t.Error("expected the journal to recover after a successful append")
+	}
+}
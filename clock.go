@@ -0,0 +1,39 @@
+package broadcast
+
+import (
+	"time"
+)
+
+// Clock 是时间源, 所有与时间相关的功能 (限流、回执、TTL、定时任务等) 都通过它获取时间,
+// 测试中可替换为 broadcasttest.FakeClock 以获得即时且确定的行为
+type Clock interface {
+	Now() time.Time
+	// AfterFunc 在 d 之后于独立 goroutine 中调用 f
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer 是 Clock.AfterFunc 返回的定时器
+type Timer interface {
+	// Stop 停止定时器, 如果定时器已触发或已停止则返回 false
+	Stop() bool
+}
+
+// SystemClock 使用系统时间的 Clock
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+func (c *core[K, T]) clock() Clock {
+	if clock := c.loadSettings().clock; clock != nil {
+		return clock
+	}
+	return SystemClock
+}
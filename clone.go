@@ -0,0 +1,147 @@
+package broadcast
+
+import (
+	"slices"
+	"strings"
+)
+
+// cloneInto 将监听器 (以及可选的处理器) 复制到新的 dst 中
+// 只复制 prefix 下的信号并去掉前缀; 处理器保留原 HandlerID, 只复制通过 prefix 或其子命名空间注册的处理器
+// 速率限制、租约、并发限制与统计在副本中重新创建, 不与原广播器共享
+func (c *core[K, T]) cloneInto(dst *core[K, T], prefix string, withHandlers bool) {
+	gen := c.gen.Load()
+	for i := range c.shards {
+		for signal, e := range c.shards[i].load() {
+			if e.gen != gen {
+				continue
+			}
+			rest, ok := strings.CutPrefix(signal, prefix)
+			if !ok {
+				continue
+			}
+			listeners := slices.Clone(e.load())
+			if len(listeners) == 0 {
+				continue
+			}
+			for j, l := range listeners {
+				listeners[j] = dst.detach(l)
+				if l.lease != nil {
+					listeners[j] = dst.withLease(rest, listeners[j], l.lease.ttl)
+				}
+			}
+			dst.mutate(rest, true, func([]listener[K, T]) ([]listener[K, T], bool) {
+				for _, l := range listeners {
+					dst.track(rest, l)
+				}
+				return listeners, true
+			})
+			for _, l := range listeners {
+				if l.lease != nil {
+					dst.startLease(rest, l)
+				}
+			}
+		}
+	}
+
+	if !withHandlers {
+		return
+	}
+	var handlers []handlerEntry[T]
+	for _, h := range c.loadHandlers() {
+		if rest, ok := strings.CutPrefix(h.prefix, prefix); ok {
+			h.prefix = rest
+			handlers = append(handlers, h.clone())
+		}
+	}
+	dst.handlers.Store(&handlers)
+	dst.nextID.Store(c.nextID.Load())
+}
+
+// Clone 返回监听器状态的深拷贝, 两者之后的 Watch/Unwatch 互不影响
+// withHandlers 为 true 时同时复制处理器, HandlerID 保持不变; HandleState 的状态在两者之间共享,
+// 但只在原广播器移除处理器时关闭; 持久处理器在副本中为普通处理器.
+// 监听器的速率限制与租约在副本中重新开始, 租约可通过 RenewLease 续约.
+// 限流、异步等配置不会被复制. 在命名空间视图上调用时, 返回只包含该命名空间信号的独立广播器
+func (b *Broadcast[T]) Clone(withHandlers bool) *Broadcast[T] {
+	clone := New[T]()
+	b.c().cloneInto(&clone.core, b.prefix(), withHandlers)
+	return clone
+}
+
+// Clone 返回监听器状态的深拷贝, 两者之后的 Watch/Unwatch 互不影响
+// Uniquer 值本身按引用共享. 处理器、速率限制与租约的复制方式与 Broadcast.Clone 相同.
+// 限流、异步等配置不会被复制
+func (b *UniqueBroadcast[K, T]) Clone(withHandlers bool) *UniqueBroadcast[K, T] {
+	clone := NewUnique[K, T]()
+	b.core.cloneInto(&clone.core, "", withHandlers)
+	return clone
+}
+
+// absorbFrom 将 src 中 prefix 下的监听器合并到 c 中, 信号去掉 prefix 后加上 dstPrefix
+// 相同 key 已存在时保留原有监听器, 返回新增的监听器数量
+func (c *core[K, T]) absorbFrom(src *core[K, T], prefix string, dstPrefix string) int {
+	added := 0
+	gen := src.gen.Load()
+	for i := range src.shards {
+		for signal, e := range src.shards[i].load() {
+			if e.gen != gen {
+				continue
+			}
+			rest, ok := strings.CutPrefix(signal, prefix)
+			if !ok {
+				continue
+			}
+			for _, l := range e.load() {
+				signal := dstPrefix + rest
+				copied := c.detach(l)
+				if l.lease != nil {
+					copied = c.withLease(signal, copied, l.lease.ttl)
+				}
+				if c.watch(signal, copied) {
+					if copied.lease != nil {
+						c.startLease(signal, copied)
+					}
+					added++
+				}
+			}
+		}
+	}
+	return added
+}
+
+// Absorb 将 other 的信号与监听器合并到当前广播器, 相同监听器只保留一份, other 不受影响
+// 合并的监听器的速率限制与租约重新开始; 处理器与配置不会被合并, 返回新增的监听器数量
+func (b *Broadcast[T]) Absorb(other *Broadcast[T]) int {
+	return b.c().absorbFrom(other.c(), other.prefix(), b.prefix())
+}
+
+// Absorb 将 other 的信号与监听器合并到当前广播器, 相同 key 已存在时保留当前的值, other 不受影响
+// 处理器与配置不会被合并, 返回新增的监听器数量
+func (b *UniqueBroadcast[K, T]) Absorb(other *UniqueBroadcast[K, T]) int {
+	return b.core.absorbFrom(&other.core, "", "")
+}
+
+// detach 返回可以加入 c 的监听器副本: 速率限制以满的令牌桶重新开始, 租约由调用方重新创建
+func (c *core[K, T]) detach(l listener[K, T]) listener[K, T] {
+	if r := l.rate; r != nil {
+		l.rate = &listenerRate[K, T]{rate: r.rate, burst: r.burst, policy: r.policy, tokens: r.burst}
+		c.rated.Store(true)
+	}
+	l.lease = nil
+	return l
+}
+
+// clone 返回处理器的副本, 并发限制与统计独立, 不继承移除时的关闭回调与持久进度
+func (h handlerEntry[T]) clone() handlerEntry[T] {
+	h.close, h.durable = nil, nil
+	if h.sem != nil {
+		h.sem = make(chan struct{}, cap(h.sem))
+	}
+	if h.counters != nil {
+		counters := &handlerCounters{}
+		counters.calls.Store(h.counters.calls.Load())
+		counters.errors.Store(h.counters.errors.Load())
+		h.counters = counters
+	}
+	return h
+}
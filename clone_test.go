@@ -0,0 +1,177 @@
+package broadcast
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	b := New[string]()
+	b.Watch("a", "1")
+	b.Watch("a", "2")
+	calls := 0
+	id := b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		calls++
+		return nil
+	})
+
+	clone := b.Clone(true)
+	clone.Watch("a", "3")
+	b.Unwatch("a", "1")
+
+	if b.WatchCount("a") != 1 || clone.WatchCount("a") != 3 {
+		t.Errorf("expected independent listener state, got %d and %d", b.WatchCount("a"), clone.WatchCount("a"))
+	}
+
+	clone.Broadcast("a", nil)
+	if calls != 3 {
+		t.Errorf("expected cloned handler to run for 3 listeners, got %d", calls)
+	}
+	if !clone.Unhandle(id) || clone.HandlerCount() != 0 || b.HandlerCount() != 1 {
+		t.Error("expected HandlerID to be preserved and handlers to be independent")
+	}
+	if next := clone.Handle(func(string, string, map[string]interface{}) error { return nil }); next == id {
+		t.Error("expected new handler IDs not to collide with cloned ones")
+	}
+
+	if got := b.Clone(false).HandlerCount(); got != 0 {
+		t.Errorf("expected no handlers without withHandlers, got %d", got)
+	}
+}
+
+func TestClone_Namespace(t *testing.T) {
+	b := New[string]()
+	b.Watch("root", "1")
+	ns := b.Namespace("ns.")
+	ns.Watch("a", "1")
+	ns.Handle(func(string, string, map[string]interface{}) error { return nil })
+	b.Handle(func(string, string, map[string]interface{}) error { return nil })
+
+	clone := ns.Clone(true)
+	if got := clone.Signals(); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("expected only namespace signals, got %v", got)
+	}
+	if clone.HandlerCount() != 1 {
+		t.Errorf("expected only namespace handlers, got %d", clone.HandlerCount())
+	}
+}
+
+func TestUniqueClone(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	b.Watch("a", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	clone := b.Clone(false)
+	clone.UnwatchAll(1)
+	if !b.Has("a", 1) || clone.Has("a", 1) {
+		t.Error("expected the clone to have its own reverse index and listeners")
+	}
+}
+
+func TestAbsorb(t *testing.T) {
+	b := New[string]()
+	b.Watch("a", "1")
+	other := New[string]()
+	other.Watch("a", "1")
+	other.Watch("a", "2")
+	other.Watch("b", "1")
+
+	if n := b.Absorb(other); n != 2 {
+		t.Errorf("expected 2 new listeners, got %d", n)
+	}
+	if b.WatchCount("a") != 2 || b.WatchCount("b") != 1 {
+		t.Errorf("expected merged listeners, got a=%d b=%d", b.WatchCount("a"), b.WatchCount("b"))
+	}
+	if other.TotalWatchCount() != 3 {
+		t.Error("expected other to be left untouched")
+	}
+
+	ns := New[string]()
+	ns.Namespace("x.").Absorb(other.Namespace(""))
+	if got := ns.Signals(); !slices.Equal(got, []string{"x.a", "x.b"}) {
+		t.Errorf("expected absorbed signals under the namespace, got %v", got)
+	}
+}
+
+func TestUniqueAbsorb(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	b.Watch("a", &TestUniquer{data: TestUniqueData{ID: 1, Name: "mine"}})
+	other := NewUnique[int, TestUniqueData]()
+	other.Watch("a", &TestUniquer{data: TestUniqueData{ID: 1, Name: "theirs"}})
+	other.Watch("a", &TestUniquer{data: TestUniqueData{ID: 2, Name: "theirs"}})
+
+	if n := b.Absorb(other); n != 1 {
+		t.Errorf("expected 1 new listener, got %d", n)
+	}
+	if data, _ := b.Get("a", 1); data.Name != "mine" {
+		t.Errorf("expected the existing value to win, got %q", data.Name)
+	}
+	if got := b.SignalsOf(2); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("expected absorbed keys in the reverse index, got %v", got)
+	}
+}
+
+func TestClone_DoesNotShareHandlerState(t *testing.T) {
+	b := New[string]()
+	b.Watch("click", "button", WithRate(1))
+	var state *counterState
+	id := HandleState(b, func() *counterState {
+		state = &counterState{counts: make(map[string]int)}
+		return state
+	}, func(s *counterState, signal string, data string, metadata map[string]interface{}) error {
+		return nil
+	})
+	limited := b.Handle(func(string, string, map[string]interface{}) error { return nil }, WithMaxConcurrency(2))
+	b.Broadcast("click", nil)
+
+	clone := b.Clone(true)
+	original, copied := b.c().loadHandlers(), clone.c().loadHandlers()
+	if copied[1].id != limited || copied[1].sem == original[1].sem || cap(copied[1].sem) != 2 {
+		t.Error("expected the clone to get its own concurrency limit")
+	}
+	if copied[0].counters == original[0].counters {
+		t.Error("expected the clone to get its own handler stats")
+	}
+	if l := clone.c().snapshot("click")[0]; l.rate == b.c().snapshot("click")[0].rate {
+		t.Error("expected the clone to get its own rate limit")
+	}
+
+	clone.Broadcast("click", nil)
+	if !clone.Unhandle(id) || state.closed {
+		t.Error("expected Unhandle on the clone not to close the original's state")
+	}
+	if got := b.HandlerStats()[0].Calls; got != 1 {
+		t.Errorf("expected the clone's calls not to count on the original, got %d", got)
+	}
+	if !b.Unhandle(id) || !state.closed {
+		t.Error("expected the original to close its state")
+	}
+}
+
+func TestClone_FreshLeases(t *testing.T) {
+	b := NewUnique[string, string]()
+	lease, _ := b.WatchLease("presence", owner{"alice", "online"}, time.Hour)
+
+	clone := b.Clone(false)
+	other := NewUnique[string, string]()
+	if n := other.Absorb(b); n != 1 {
+		t.Fatalf("expected one absorbed listener, got %d", n)
+	}
+	for _, c := range []*UniqueBroadcast[string, string]{clone, other} {
+		if l := c.core.snapshot("presence")[0]; l.lease == nil || l.lease == lease {
+			t.Error("expected a fresh lease")
+		}
+		if !c.RenewLease("presence", "alice") {
+			t.Error("expected the copied lease to be renewable")
+		}
+	}
+
+	lease.Release()
+	if !clone.Has("presence", "alice") || !other.Has("presence", "alice") {
+		t.Error("expected releasing the original lease to leave the copies")
+	}
+	clone.UnwatchKey("presence", "alice")
+	if other.RenewLease("presence", "bob") || !other.RenewLease("presence", "alice") {
+		t.Error("expected copies to keep independent leases")
+	}
+}
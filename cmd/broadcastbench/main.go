@@ -0,0 +1,37 @@
+// broadcastbench 命令行压测工具
+//
+//	go run ./cmd/broadcastbench -signals 100 -listeners 1000 -publishers 64 -duration 5s
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"pkg.blksails.net/x/broadcast"
+	"pkg.blksails.net/x/broadcast/broadcastbench"
+)
+
+func main() {
+	var (
+		cfg     broadcastbench.Config
+		async   bool
+		workers int
+		queue   int
+	)
+	flag.IntVar(&cfg.Signals, "signals", 10, "信号数量")
+	flag.IntVar(&cfg.Listeners, "listeners", 100, "每个信号的监听器数量")
+	flag.IntVar(&cfg.Handlers, "handlers", 1, "处理器数量")
+	flag.IntVar(&cfg.Publishers, "publishers", 8, "并发发布 goroutine 数量")
+	flag.DurationVar(&cfg.Duration, "duration", 0, "压测时长 (默认 1s)")
+	flag.DurationVar(&cfg.HandlerWork, "work", 0, "每次处理器调用模拟的耗时")
+	flag.BoolVar(&async, "async", false, "开启异步投递")
+	flag.IntVar(&workers, "workers", 4, "异步投递的工作 goroutine 数量")
+	flag.IntVar(&queue, "queue", 1024, "异步队列容量")
+	flag.Parse()
+
+	if async {
+		cfg.Async = &broadcast.AsyncConfig{Workers: workers, QueueSize: queue, Overflow: broadcast.Block}
+	}
+
+	fmt.Println(broadcastbench.Run(cfg))
+}
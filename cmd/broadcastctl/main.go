@@ -0,0 +1,329 @@
+// broadcastctl 通过 admin 包提供的 HTTP 接口检查与操作运行中的广播器
+//
+//	broadcastctl -addr http://localhost:8080/debug/broadcast list
+//	broadcastctl watch-count orders
+//	broadcastctl broadcast -meta amount=3 -meta currency=USD orders
+//	broadcastctl tail orders
+//
+// -addr 默认读取环境变量 BROADCASTCTL_ADDR; -json 输出接口返回的原始 JSON, 便于在脚本中处理
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"pkg.blksails.net/x/broadcast"
+	"pkg.blksails.net/x/broadcast/admin"
+)
+
+const usage = `用法: broadcastctl [-addr URL] [-json] <命令> [参数]
+
+命令:
+  list                     列出信号与监听器数量
+  signal <signal>          列出信号的监听器 key
+  watch-count <signal>     输出信号的监听器数量
+  handlers                 列出处理器
+  stats                    输出汇总统计
+  pause <signal>           暂停信号的广播
+  resume <signal>          恢复信号的广播
+  clean <signal> | -all    移除信号或所有信号的监听器
+  broadcast [-meta k=v]... [-id ID] [-source S] <signal>
+                           广播一次测试事件, 值按 JSON 解析, 失败时作为字符串
+  tail <signal>            持续输出信号的广播, Ctrl-C 退出
+`
+
+// client 调用 admin 接口
+type client struct {
+	base string
+	raw  bool
+	out  io.Writer
+	http *http.Client
+}
+
+func main() {
+	flags := flag.NewFlagSet("broadcastctl", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage, "\n选项:\n")
+		flags.PrintDefaults()
+	}
+	addr := flags.String("addr", envOr("BROADCASTCTL_ADDR", "http://localhost:8080/debug/broadcast"), "admin 接口地址")
+	raw := flags.Bool("json", false, "输出原始 JSON")
+	flags.Parse(os.Args[1:])
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	c := &client{base: strings.TrimSuffix(*addr, "/"), raw: *raw, out: os.Stdout, http: http.DefaultClient}
+	if err := c.run(flags.Arg(0), flags.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "broadcastctl:", err)
+		os.Exit(1)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func (c *client) run(command string, args []string) error {
+	switch command {
+	case "list":
+		return c.list()
+	case "signal", "watch-count":
+		if len(args) != 1 {
+			return fmt.Errorf("%s 需要一个信号名", command)
+		}
+		return c.signal(args[0], command == "watch-count")
+	case "handlers":
+		return c.handlers()
+	case "stats":
+		return c.stats()
+	case "pause", "resume":
+		if len(args) != 1 {
+			return fmt.Errorf("%s 需要一个信号名", command)
+		}
+		return c.operate("/signals/"+url.PathEscape(args[0])+"/"+command, nil)
+	case "clean":
+		if len(args) == 1 && args[0] == "-all" {
+			return c.operate("/clean", nil)
+		}
+		if len(args) != 1 {
+			return errors.New("clean 需要一个信号名或 -all")
+		}
+		return c.operate("/signals/"+url.PathEscape(args[0])+"/clean", nil)
+	case "broadcast":
+		return c.broadcast(args)
+	case "tail":
+		if len(args) != 1 {
+			return errors.New("tail 需要一个信号名")
+		}
+		return c.tail(args[0])
+	default:
+		return fmt.Errorf("未知命令 %q, 运行 broadcastctl -h 查看用法", command)
+	}
+}
+
+// do 发送请求并将 JSON 响应解码到 v, -json 时原样输出响应
+func (c *client) do(method, path string, body io.Reader, v any) error {
+	req, err := http.NewRequest(method, c.base+path, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &e) == nil && e.Error != "" {
+			return fmt.Errorf("%s %s: %s", method, path, e.Error)
+		}
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	if c.raw {
+		_, err := c.out.Write(data)
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (c *client) list() error {
+	var signals []admin.Signal
+	if err := c.do("GET", "/signals", nil, &signals); err != nil || c.raw {
+		return err
+	}
+	tw := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SIGNAL\tLISTENERS\tPAUSED")
+	for _, s := range signals {
+		fmt.Fprintf(tw, "%s\t%d\t%v\n", s.Signal, s.Listeners, s.Paused)
+	}
+	return tw.Flush()
+}
+
+func (c *client) signal(name string, countOnly bool) error {
+	var s admin.Signal
+	if err := c.do("GET", "/signals/"+url.PathEscape(name), nil, &s); err != nil || c.raw {
+		return err
+	}
+	if countOnly {
+		_, err := fmt.Fprintln(c.out, s.Listeners)
+		return err
+	}
+	fmt.Fprintf(c.out, "%s: %d listeners, paused=%v\n", s.Signal, s.Listeners, s.Paused)
+	for _, key := range s.Keys {
+		fmt.Fprintln(c.out, " ", key)
+	}
+	return nil
+}
+
+func (c *client) handlers() error {
+	var handlers []broadcast.HandlerStats
+	if err := c.do("GET", "/handlers", nil, &handlers); err != nil || c.raw {
+		return err
+	}
+	tw := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tCALLS\tERRORS")
+	for _, h := range handlers {
+		name := h.Name
+		if name == "" {
+			name = "(unnamed)"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\n", h.ID, name, h.Calls, h.Errors)
+	}
+	return tw.Flush()
+}
+
+func (c *client) stats() error {
+	var s admin.Stats
+	if err := c.do("GET", "/stats", nil, &s); err != nil || c.raw {
+		return err
+	}
+	tw := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "signals\t%d\nlisteners\t%d\nhandlers\t%d\npending\t%d\nbuffered\t%d\ndead letters\t%d\nexpired\t%d\npaused\t%v\n",
+		s.Signals, s.Listeners, s.Handlers, s.Pending, s.Buffered, s.DeadLetters, s.Expired, s.Paused)
+	return tw.Flush()
+}
+
+// operate 调用修改状态的接口, body 为 nil 时不发送请求体
+func (c *client) operate(path string, body []byte) error {
+	var r admin.Result
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	if err := c.do("POST", path, reader, &r); err != nil || c.raw {
+		return err
+	}
+	if !r.Changed {
+		_, err := fmt.Fprintln(c.out, "unchanged")
+		return err
+	}
+	_, err := fmt.Fprintln(c.out, "ok")
+	return err
+}
+
+// metadataFlag 收集可重复的 -meta k=v
+type metadataFlag map[string]interface{}
+
+func (m metadataFlag) String() string {
+	return fmt.Sprint(map[string]interface{}(m))
+}
+
+func (m metadataFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("metadata 应为 key=value, 得到 %q", s)
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(v), &value); err != nil {
+		value = v
+	}
+	m[k] = value
+	return nil
+}
+
+func (c *client) broadcast(args []string) error {
+	req := admin.BroadcastRequest{Metadata: metadataFlag{}}
+	flags := flag.NewFlagSet("broadcast", flag.ContinueOnError)
+	flags.Var(metadataFlag(req.Metadata), "meta", "元数据 key=value, 可重复")
+	flags.StringVar(&req.ID, "id", "", "事件 ID")
+	flags.StringVar(&req.Source, "source", "broadcastctl", "事件来源")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("broadcast 需要一个信号名")
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return c.operate("/signals/"+url.PathEscape(flags.Arg(0))+"/broadcast", body)
+}
+
+// tail 输出 SSE 推送的每一次广播, -json 时每行一个事件
+func (c *client) tail(name string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	req, err := http.NewRequestWithContext(ctx, "GET", c.base+"/signals/"+url.PathEscape(name)+"/tail", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("tail %s: %s", name, resp.Status)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	for lines.Scan() {
+		data, ok := strings.CutPrefix(lines.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		if c.raw {
+			fmt.Fprintln(c.out, data)
+			continue
+		}
+		var e admin.Event
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "%s\t%s\t%s\t%s", e.Time.Format("15:04:05.000"), e.Signal, e.ID, formatMetadata(e.Metadata))
+		if e.Payload != nil {
+			payload, _ := json.Marshal(e.Payload)
+			fmt.Fprintf(c.out, "\t%s", payload)
+		}
+		fmt.Fprintln(c.out)
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return lines.Err()
+}
+
+func formatMetadata(metadata map[string]interface{}) string {
+	if len(metadata) == 0 {
+		return "-"
+	}
+	var buf bytes.Buffer
+	for i, k := range slices.Sorted(maps.Keys(metadata)) {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%s=%v", k, metadata[k])
+	}
+	return buf.String()
+}
@@ -0,0 +1,136 @@
+// dedupbench 对比不同监听器去重策略在不同负载大小和 key 基数下的性能,
+// 用于评估 broadcast 包中基于 unique.Handle 的去重方式是否适合具体场景
+//
+//	go run ./cmd/dedupbench -sizes 16,256,4096 -keys 10,100,1000
+package main
+
+import (
+	"flag"
+	"fmt"
+	"hash/maphash"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"text/tabwriter"
+	"unique"
+)
+
+// strategy 描述一种去重策略: 对给定的负载集合执行一轮 "存在则跳过, 否则插入"
+type strategy struct {
+	name string
+	run  func(payloads []string)
+}
+
+var strategies = []strategy{
+	{
+		// 与 broadcast 包一致: 驻留为 unique.Handle 后线性比较
+		name: "handle-scan",
+		run: func(payloads []string) {
+			seen := make([]unique.Handle[string], 0, len(payloads))
+		next:
+			for _, p := range payloads {
+				h := unique.Make(p)
+				for _, s := range seen {
+					if s == h {
+						continue next
+					}
+				}
+				seen = append(seen, h)
+			}
+		},
+	},
+	{
+		// 驻留为 unique.Handle 后使用 map 查找
+		name: "handle-map",
+		run: func(payloads []string) {
+			seen := make(map[unique.Handle[string]]struct{}, len(payloads))
+			for _, p := range payloads {
+				seen[unique.Make(p)] = struct{}{}
+			}
+		},
+	},
+	{
+		// 仅比较 64 位哈希, 存在极低概率的误判
+		name: "hash",
+		run: func(payloads []string) {
+			seen := make(map[uint64]struct{}, len(payloads))
+			for _, p := range payloads {
+				seen[maphash.String(seed, p)] = struct{}{}
+			}
+		},
+	},
+	{
+		// 直接以负载作为 map key
+		name: "map-key",
+		run: func(payloads []string) {
+			seen := make(map[string]struct{}, len(payloads))
+			for _, p := range payloads {
+				seen[p] = struct{}{}
+			}
+		},
+	},
+}
+
+var seed = maphash.MakeSeed()
+
+func main() {
+	sizes := flag.String("sizes", "16,256,4096", "负载大小 (字节), 逗号分隔")
+	keys := flag.String("keys", "10,100,1000", "key 基数, 逗号分隔")
+	flag.Parse()
+
+	sizeList, err := parseInts(*sizes)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid -sizes:", err)
+		os.Exit(2)
+	}
+	keyList, err := parseInts(*keys)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid -keys:", err)
+		os.Exit(2)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "size\tkeys\tstrategy\tns/op\tns/key\tB/op\tallocs/op")
+	for _, size := range sizeList {
+		for _, n := range keyList {
+			payloads := makePayloads(size, n)
+			for _, s := range strategies {
+				result := testing.Benchmark(func(b *testing.B) {
+					b.ReportAllocs()
+					for i := 0; i < b.N; i++ {
+						s.run(payloads)
+					}
+				})
+				fmt.Fprintf(w, "%d\t%d\t%s\t%d\t%.1f\t%d\t%d\n",
+					size, n, s.name, result.NsPerOp(),
+					float64(result.NsPerOp())/float64(n),
+					result.AllocedBytesPerOp(), result.AllocsPerOp())
+			}
+		}
+	}
+	w.Flush()
+}
+
+// makePayloads 生成 n 个不同的负载, 每个负载两次出现以模拟重复 Watch
+func makePayloads(size, n int) []string {
+	payloads := make([]string, 0, 2*n)
+	for i := 0; i < n; i++ {
+		prefix := strconv.Itoa(i) + ":"
+		p := prefix + strings.Repeat("x", max(size-len(prefix), 0))
+		payloads = append(payloads, p, strings.Clone(p))
+	}
+	return payloads
+}
+
+func parseInts(s string) ([]int, error) {
+	var out []int
+	for _, field := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
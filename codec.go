@@ -0,0 +1,24 @@
+package broadcast
+
+import (
+	"encoding/json"
+)
+
+// Codec 负责负载与字节之间的编解码
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec 使用 encoding/json 的 Codec
+var JSONCodec Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,111 @@
+package broadcast
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNoResponse 没有任何监听器与响应器参与 BroadcastFirst
+var ErrNoResponse = errors.New("broadcast: no response")
+
+// Responder 是返回类型化结果的处理器
+type Responder[T any, R any] func(signal string, data T, metadata map[string]interface{}) (R, error)
+
+// CollectSource 是 Collector 读取监听器的广播器, 由 Broadcast 与 UniqueBroadcast 实现
+type CollectSource[T any] interface {
+	eachValue(signal string, metadata map[string]interface{}, fn func(data T) bool)
+}
+
+// Collector 在广播器的监听器上执行一组 Responder 并收集结果, 用于查询式的扇出/扇入
+// Collector 只复用广播器的监听器注册表, 其响应器与广播器的 Handler 相互独立
+type Collector[T any, R any] struct {
+	source     CollectSource[T]
+	mu         sync.RWMutex
+	responders []Responder[T, R]
+}
+
+// NewCollector 创建一个基于 source 监听器的 Collector
+func NewCollector[T any, R any](source CollectSource[T]) *Collector[T, R] {
+	return &Collector[T, R]{source: source}
+}
+
+// Handle 注册一个响应器
+func (c *Collector[T, R]) Handle(responder Responder[T, R]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.responders = append(c.responders[:len(c.responders):len(c.responders)], responder)
+}
+
+func (c *Collector[T, R]) loadResponders() []Responder[T, R] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.responders
+}
+
+// BroadcastCollect 对每个响应器和监听器执行一次, 返回所有成功的结果以及所有错误的组合
+func (c *Collector[T, R]) BroadcastCollect(signal string, metadata map[string]interface{}) ([]R, error) {
+	var (
+		results []R
+		errs    []error
+	)
+	for _, responder := range c.loadResponders() {
+		c.source.eachValue(signal, metadata, func(data T) bool {
+			r, err := responder(signal, data, metadata)
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				results = append(results, r)
+			}
+			return true
+		})
+	}
+	return results, errors.Join(errs...)
+}
+
+// BroadcastFirst 依次执行响应器, 返回第一个成功的结果
+// 全部失败时返回所有错误的组合, 没有任何执行时返回 ErrNoResponse
+func (c *Collector[T, R]) BroadcastFirst(signal string, metadata map[string]interface{}) (R, error) {
+	var (
+		result R
+		found  bool
+		errs   []error
+	)
+	for _, responder := range c.loadResponders() {
+		c.source.eachValue(signal, metadata, func(data T) bool {
+			r, err := responder(signal, data, metadata)
+			if err != nil {
+				errs = append(errs, err)
+				return true
+			}
+			result, found = r, true
+			return false
+		})
+		if found {
+			return result, nil
+		}
+	}
+
+	if len(errs) == 0 {
+		return result, ErrNoResponse
+	}
+	return result, errors.Join(errs...)
+}
+
+func (c *core[K, T]) eachValue(signal string, metadata map[string]interface{}, fn func(data T) bool) {
+	transforms := c.loadSettings().transforms
+	for _, l := range c.snapshot(signal) {
+		if !fn(transformAll(transforms, signal, l.data.Value(), metadata)) {
+			return
+		}
+	}
+}
+
+func (b *Broadcast[T]) eachValue(signal string, metadata map[string]interface{}, fn func(data T) bool) {
+	b.c().eachValue(b.sig(signal), metadata, fn)
+}
+
+func (b *UniqueBroadcast[K, T]) eachValue(signal string, metadata map[string]interface{}, fn func(data T) bool) {
+	b.core.eachValue(signal, metadata, fn)
+}
@@ -0,0 +1,56 @@
+package broadcast
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCollector_BroadcastCollect(t *testing.T) {
+	b := New[string]()
+	b.Watch("quote", "vendor-a")
+	b.Watch("quote", "vendor-b")
+	b.Watch("quote", "vendor-c")
+
+	unavailable := errors.New("unavailable")
+	prices := map[string]int{"vendor-a": 120, "vendor-c": 95}
+
+	c := NewCollector[string, int](b)
+	c.Handle(func(signal string, vendor string, metadata map[string]interface{}) (int, error) {
+		if p, ok := prices[vendor]; ok {
+			return p, nil
+		}
+		return 0, unavailable
+	})
+
+	results, err := c.BroadcastCollect("quote", nil)
+	if !slices.Equal(results, []int{120, 95}) {
+		t.Errorf("expected [120 95], got %v", results)
+	}
+	if !errors.Is(err, unavailable) {
+		t.Errorf("expected vendor-b error, got %v", err)
+	}
+
+	first, err := c.BroadcastFirst("quote", nil)
+	if err != nil || first != 120 {
+		t.Errorf("expected first result 120, got %d, %v", first, err)
+	}
+}
+
+func TestCollector_BroadcastFirst_Errors(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	c := NewCollector[TestUniqueData, string](b)
+
+	if _, err := c.BroadcastFirst("test", nil); !errors.Is(err, ErrNoResponse) {
+		t.Errorf("expected ErrNoResponse without listeners, got %v", err)
+	}
+
+	failure := errors.New("failure")
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+	c.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) (string, error) {
+		return "", failure
+	})
+	if _, err := c.BroadcastFirst("test", nil); !errors.Is(err, failure) {
+		t.Errorf("expected the responder error, got %v", err)
+	}
+}
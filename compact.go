@@ -0,0 +1,113 @@
+package broadcast
+
+// append 在信号锁内返回追加了 l 的新监听器切片
+// 当前切片位于条目独占且仍有余量的底层数组上时原地追加, 已发布的快照长度不变, 不受影响;
+// 否则按 ReserveListeners 的容量提示分配新数组
+func (e *signalEntry[K, T]) append(listeners []listener[K, T], l listener[K, T]) []listener[K, T] {
+	n := len(listeners)
+	if n < cap(listeners) && e.owns(listeners) {
+		newListeners := listeners[:n+1]
+		newListeners[n] = l
+		return newListeners
+	}
+
+	newListeners := make([]listener[K, T], n+1, max(n+1, e.reserve))
+	copy(newListeners, listeners)
+	newListeners[n] = l
+	if cap(newListeners) > n+1 {
+		e.owned = newListeners[:cap(newListeners)]
+	} else {
+		e.owned = nil
+	}
+	return newListeners
+}
+
+// owns 报告 listeners 是否位于条目独占的底层数组上
+func (e *signalEntry[K, T]) owns(listeners []listener[K, T]) bool {
+	c := cap(listeners)
+	return c > 0 && c == cap(e.owned) && &listeners[:c][c-1] == &e.owned[c-1]
+}
+
+// reserveListeners 为信号预留至少 n 个监听器的容量, 之后的 Watch 在容量内原地追加
+// 只替换底层数组, 不改变监听器集合的版本
+func (c *core[K, T]) reserveListeners(signal string, n int) {
+	c.mutateEntry(signal, true, func(e *signalEntry[K, T]) ([]listener[K, T], bool) {
+		e.reserve = n
+		listeners := e.load()
+		if len(listeners) >= n || cap(listeners) >= n && e.owns(listeners) {
+			return nil, false
+		}
+		reserved := make([]listener[K, T], len(listeners), n)
+		copy(reserved, listeners)
+		e.owned = reserved[:n]
+		e.listeners.Store(&reserved)
+		return nil, false
+	})
+}
+
+// compact 收缩容量明显大于长度的监听器切片, 并移除没有监听器的信号条目, 返回处理的信号数量
+func (c *core[K, T]) compact() int {
+	compacted := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		gen := c.gen.Load()
+		signals := s.load()
+		newSignals := make(map[string]*signalEntry[K, T], len(signals))
+		for signal, e := range signals {
+			if e.gen != gen {
+				compacted++
+				continue
+			}
+
+			e.mu.Lock()
+			listeners := e.load()
+			if len(listeners) == 0 {
+				// 与 Clean 相同, 持有旧条目的并发写操作会重试
+				e.removed = true
+				e.mu.Unlock()
+				compacted++
+				continue
+			}
+			if cap(listeners) > len(listeners) || e.reserve > 0 {
+				shrunk := make([]listener[K, T], len(listeners))
+				copy(shrunk, listeners)
+				e.listeners.Store(&shrunk)
+				e.reserve = 0
+				e.owned = nil
+				compacted++
+			}
+			e.mu.Unlock()
+			newSignals[signal] = e
+		}
+		if len(newSignals) != len(signals) {
+			s.signals.Store(&newSignals)
+		}
+		s.mu.Unlock()
+	}
+	return compacted
+}
+
+// ReserveListeners 为信号预留 n 个监听器的容量, 适合在批量 Watch 之前调用以避免反复复制
+// 预留在 Clean 或 Compact 之后失效
+func (b *Broadcast[T]) ReserveListeners(signal string, n int) {
+	b.c().reserveListeners(b.sig(signal), n)
+}
+
+// Compact 释放大量 Unwatch 之后多余的监听器容量, 并移除没有监听器的信号及其版本历史
+// 返回被收缩或移除的信号数量
+func (b *Broadcast[T]) Compact() int {
+	return b.c().compact()
+}
+
+// ReserveListeners 为信号预留 n 个监听器的容量, 适合在批量 Watch 之前调用以避免反复复制
+// 预留在 Clean 或 Compact 之后失效
+func (b *UniqueBroadcast[K, T]) ReserveListeners(signal string, n int) {
+	b.core.reserveListeners(signal, n)
+}
+
+// Compact 释放大量 Unwatch 之后多余的监听器容量, 并移除没有监听器的信号及其版本历史
+// 返回被收缩或移除的信号数量
+func (b *UniqueBroadcast[K, T]) Compact() int {
+	return b.core.compact()
+}
@@ -0,0 +1,66 @@
+package broadcast
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestReserveListeners_AppendsInPlace(t *testing.T) {
+	b := New[string]()
+	b.ReserveListeners("bulk", 64)
+	b.Watch("bulk", "first")
+	version := b.Version("bulk")
+
+	// 原地追加后, 之前发布的快照保持不变
+	before := b.c().snapshot("bulk")
+	for i := 0; i < 10; i++ {
+		b.Watch("bulk", fmt.Sprint(i))
+	}
+	after := b.c().snapshot("bulk")
+	if len(before) != 1 || len(after) != 11 {
+		t.Fatalf("unexpected snapshot lengths %d and %d", len(before), len(after))
+	}
+	if &before[:1][0] != &after[0] || cap(after) != 64 {
+		t.Error("expected watches to reuse the reserved storage")
+	}
+
+	// 回滚到较短的前缀后, 不能在共享的数组上继续原地追加
+	if err := b.RollbackTo("bulk", version); err != nil {
+		t.Fatal(err)
+	}
+	b.Watch("bulk", "extra")
+	if after[1].data.Value() != "0" {
+		t.Error("expected the rolled back snapshot to stay intact")
+	}
+}
+
+func TestCompact(t *testing.T) {
+	b := NewUnique[string, string]()
+	for i := 0; i < 100; i++ {
+		b.Watch("churn", owner{fmt.Sprint(i), "v"})
+	}
+	b.ReserveListeners("reserved", 32)
+	b.Watch("empty", owner{"x", "v"})
+	b.Unwatch("empty", owner{"x", "v"})
+	for i := 1; i < 100; i++ {
+		b.UnwatchKey("churn", fmt.Sprint(i))
+	}
+	b.ReserveListeners("churn", 50)
+
+	if n := b.Compact(); n != 3 {
+		t.Errorf("expected 3 compacted signals, got %d", n)
+	}
+	if got := cap(b.core.snapshot("churn")); got != 1 {
+		t.Errorf("expected the churned signal to shrink, got capacity %d", got)
+	}
+	if b.core.entry("empty", false) != nil || b.core.entry("reserved", false) != nil {
+		t.Error("expected empty signals to be removed")
+	}
+	if !b.Has("churn", "0") || b.Compact() != 0 {
+		t.Error("expected a second Compact to be a no-op")
+	}
+	b.Watch("churn", owner{"1", "v"})
+	if b.WatchCount("churn") != 2 {
+		t.Errorf("expected watches to keep working after Compact, got %d", b.WatchCount("churn"))
+	}
+}
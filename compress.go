@@ -0,0 +1,180 @@
+package broadcast
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// DefaultCompressionThreshold 是 CompressionConfig.Threshold 的默认值
+const DefaultCompressionThreshold = 512
+
+// Compression 是 CompressedCodec 使用的压缩算法
+// ID 写入每个负载的首字节用于解码时识别算法, 0 表示未压缩; 本包的实现使用 1-15,
+// 自定义实现 (例如基于 snappy 或 zstd) 应使用 16 及以上的值, 并通过 RegisterCompression 登记
+type Compression interface {
+	ID() byte
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// GzipCompression 使用 compress/gzip 的默认压缩级别
+var GzipCompression Compression = gzipCompression{}
+
+// FlateCompression 使用 compress/flate 的最快压缩级别, 适合延迟敏感的网络路径
+var FlateCompression Compression = flateCompression{}
+
+var (
+	compressionsMu sync.RWMutex
+	compressions   = map[byte]Compression{
+		GzipCompression.ID():  GzipCompression,
+		FlateCompression.ID(): FlateCompression,
+	}
+)
+
+// RegisterCompression 登记解码时按 ID 识别的压缩算法, 相同 ID 已登记时替换
+// 更换 CompressionConfig.Compression 后, 之前写入日志的负载仍按其首字节解压;
+// GzipCompression 与 FlateCompression 已经登记. ID 为 0 时 panic
+func RegisterCompression(c Compression) {
+	id := c.ID()
+	if id == 0 {
+		panic("broadcast: compression ID 0 is reserved for uncompressed payloads")
+	}
+	compressionsMu.Lock()
+	defer compressionsMu.Unlock()
+
+	compressions[id] = c
+}
+
+func lookupCompression(id byte) (Compression, bool) {
+	compressionsMu.RLock()
+	defer compressionsMu.RUnlock()
+
+	c, ok := compressions[id]
+	return c, ok
+}
+
+type gzipCompression struct{}
+
+func (gzipCompression) ID() byte { return 1 }
+
+func (gzipCompression) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCompression) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+type flateCompression struct{}
+
+func (flateCompression) ID() byte { return 2 }
+
+func (flateCompression) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (flateCompression) Decompress(data []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// CompressionConfig 配置 CompressedCodec
+type CompressionConfig struct {
+	// Compression 为压缩算法, 默认为 GzipCompression
+	Compression Compression
+	// Threshold 为开启压缩的最小编码长度, 更短的负载原样保存, 默认为 DefaultCompressionThreshold;
+	// 压缩后没有变短的负载同样原样保存
+	Threshold int
+}
+
+// CompressedCodec 在另一个 Codec 之上压缩较大的负载, 用于减少日志与网络桥接的体积
+// 与 EncryptedCodec 组合时应先压缩再加密, 即 NewEncryptedCodec(NewCompressedCodec(...), ...)
+type CompressedCodec struct {
+	inner  Codec
+	config CompressionConfig
+}
+
+// NewCompressedCodec 创建压缩 Codec, inner 为 nil 时使用 JSONCodec
+func NewCompressedCodec(inner Codec, config CompressionConfig) *CompressedCodec {
+	if inner == nil {
+		inner = JSONCodec
+	}
+	if config.Compression == nil {
+		config.Compression = GzipCompression
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultCompressionThreshold
+	}
+	return &CompressedCodec{inner: inner, config: config}
+}
+
+// Marshal 使用 inner 编码 v, 长度达到阈值时压缩
+func (c *CompressedCodec) Marshal(v any) ([]byte, error) {
+	data, err := c.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) >= c.config.Threshold {
+		compressed, err := c.config.Compression.Compress(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(compressed) < len(data) {
+			return append([]byte{c.config.Compression.ID()}, compressed...), nil
+		}
+	}
+	return append([]byte{0}, data...), nil
+}
+
+// Unmarshal 按首字节解压 data 后使用 inner 解码到 v
+// 首字节不是当前算法时按 RegisterCompression 登记的算法解压, 都不匹配时返回 ErrUnknownCompression
+func (c *CompressedCodec) Unmarshal(data []byte, v any) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: empty payload", ErrUnknownCompression)
+	}
+	id := data[0]
+	if id == 0 {
+		return c.inner.Unmarshal(data[1:], v)
+	}
+	compression := c.config.Compression
+	if id != compression.ID() {
+		var ok bool
+		if compression, ok = lookupCompression(id); !ok {
+			return fmt.Errorf("%w: %d", ErrUnknownCompression, id)
+		}
+	}
+	plain, err := compression.Decompress(data[1:])
+	if err != nil {
+		return err
+	}
+	return c.inner.Unmarshal(plain, v)
+}
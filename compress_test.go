@@ -0,0 +1,108 @@
+package broadcast
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type report struct {
+	Body string `json:"body"`
+}
+
+func TestCompressedCodec_Threshold(t *testing.T) {
+	for _, compression := range []Compression{GzipCompression, FlateCompression} {
+		codec := NewCompressedCodec(nil, CompressionConfig{Compression: compression, Threshold: 64})
+
+		small, err := codec.Marshal(report{Body: "tiny"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if small[0] != 0 {
+			t.Errorf("expected payloads below the threshold to stay uncompressed, got id %d", small[0])
+		}
+
+		large := report{Body: strings.Repeat("abcdefgh", 200)}
+		data, err := codec.Marshal(large)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data[0] != compression.ID() || len(data) >= len(large.Body) {
+			t.Errorf("expected a large payload to be compressed, got id %d and %d bytes", data[0], len(data))
+		}
+
+		for encoded, want := range map[*[]byte]report{&small: {Body: "tiny"}, &data: large} {
+			var got report
+			if err := codec.Unmarshal(*encoded, &got); err != nil || got != want {
+				t.Errorf("expected the payload to round-trip, got %v", err)
+			}
+		}
+	}
+}
+
+// reversed 是测试用的自定义压缩算法: 去掉 JSON 字符串开头的引号并倒序, 使结果比输入短
+type reversed struct{}
+
+func (reversed) ID() byte { return 100 }
+
+func (reversed) Compress(data []byte) ([]byte, error) {
+	out := slices.Clone(data)
+	slices.Reverse(out)
+	return out[:len(out)-1], nil
+}
+
+func (reversed) Decompress(data []byte) ([]byte, error) {
+	out := append(slices.Clone(data), '"')
+	slices.Reverse(out)
+	return out, nil
+}
+
+func TestCompressedCodec_SwitchAlgorithm(t *testing.T) {
+	gz := NewCompressedCodec(nil, CompressionConfig{Threshold: 1})
+	custom := NewCompressedCodec(nil, CompressionConfig{Compression: reversed{}, Threshold: 1})
+	oldGzip, _ := gz.Marshal(strings.Repeat("x", 100))
+	oldCustom, _ := custom.Marshal(strings.Repeat("z", 100))
+
+	// 切换到 flate 后, 之前用 gzip 与自定义算法写入的负载仍可解码
+	fl := NewCompressedCodec(nil, CompressionConfig{Compression: FlateCompression})
+	var s string
+	if err := fl.Unmarshal(oldGzip, &s); err != nil || s != strings.Repeat("x", 100) {
+		t.Errorf("expected a gzip payload to decode after switching to flate, got %q, %v", s, err)
+	}
+	if err := fl.Unmarshal(oldCustom, &s); !errors.Is(err, ErrUnknownCompression) {
+		t.Errorf("expected an unregistered algorithm to be unknown, got %v", err)
+	}
+	RegisterCompression(reversed{})
+	if err := fl.Unmarshal(oldCustom, &s); err != nil || s != strings.Repeat("z", 100) {
+		t.Errorf("expected a registered algorithm to decode, got %q, %v", s, err)
+	}
+}
+
+func TestCompressedCodec_UnknownAndEncrypted(t *testing.T) {
+	gz := NewCompressedCodec(nil, CompressionConfig{Threshold: 1})
+	data, _ := gz.Marshal(strings.Repeat("x", 100))
+
+	fl := NewCompressedCodec(nil, CompressionConfig{Compression: FlateCompression})
+	var s string
+	if err := fl.Unmarshal(append([]byte{200}, data[1:]...), &s); !errors.Is(err, ErrUnknownCompression) {
+		t.Errorf("expected ErrUnknownCompression, got %v", err)
+	}
+	if err := fl.Unmarshal(nil, &s); !errors.Is(err, ErrUnknownCompression) {
+		t.Errorf("expected an empty payload to be rejected, got %v", err)
+	}
+
+	// 先压缩再加密
+	codec, err := NewEncryptedCodec(gz, "k", bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, _ := codec.Marshal(strings.Repeat("y", 1000))
+	if len(encoded) > 200 {
+		t.Errorf("expected compression before encryption, got %d bytes", len(encoded))
+	}
+	if err := codec.Unmarshal(encoded, &s); err != nil || s != strings.Repeat("y", 1000) {
+		t.Errorf("expected the combined codec to round-trip, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+// Package compression 提供基于 github.com/klauspost/compress 的 snappy 与 zstd 压缩算法,
+// 供 broadcast.CompressedCodec 使用. 导入本包时两种算法即通过 broadcast.RegisterCompression 登记,
+// 之后写入的负载即使更换了算法也可以解码
+//
+//	codec := broadcast.NewCompressedCodec(nil, broadcast.CompressionConfig{Compression: compression.Zstd})
+package compression
+
+import (
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+const (
+	// SnappyID 是 Snappy 写入负载首字节的算法 ID
+	SnappyID byte = 16
+	// ZstdID 是 Zstd 写入负载首字节的算法 ID
+	ZstdID byte = 17
+)
+
+var (
+	// Snappy 压缩速度最快, 适合网络桥接等延迟敏感的路径
+	Snappy broadcast.Compression = snappyCompression{}
+	// Zstd 使用默认压缩级别, 压缩率高于 gzip 且更快, 适合日志
+	Zstd broadcast.Compression = newZstd()
+)
+
+func init() {
+	broadcast.RegisterCompression(Snappy)
+	broadcast.RegisterCompression(Zstd)
+}
+
+type snappyCompression struct{}
+
+func (snappyCompression) ID() byte { return SnappyID }
+
+func (snappyCompression) Compress(data []byte) ([]byte, error) {
+	return snappy.Encode(nil, data), nil
+}
+
+func (snappyCompression) Decompress(data []byte) ([]byte, error) {
+	return snappy.Decode(nil, data)
+}
+
+// zstdCompression 共享一个编码器与解码器, EncodeAll 与 DecodeAll 可以并发调用
+type zstdCompression struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+func newZstd() zstdCompression {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		panic(err)
+	}
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		panic(err)
+	}
+	return zstdCompression{encoder: encoder, decoder: decoder}
+}
+
+func (zstdCompression) ID() byte { return ZstdID }
+
+func (z zstdCompression) Compress(data []byte) ([]byte, error) {
+	return z.encoder.EncodeAll(data, nil), nil
+}
+
+func (z zstdCompression) Decompress(data []byte) ([]byte, error) {
+	return z.decoder.DecodeAll(data, nil)
+}
@@ -0,0 +1,29 @@
+package compression
+
+import (
+	"strings"
+	"testing"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+func TestCompression_RoundTrip(t *testing.T) {
+	payload := strings.Repeat("order created ", 100)
+	for _, c := range []broadcast.Compression{Snappy, Zstd} {
+		codec := broadcast.NewCompressedCodec(nil, broadcast.CompressionConfig{Compression: c, Threshold: 64})
+		data, err := codec.Marshal(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data[0] != c.ID() || len(data) >= len(payload) {
+			t.Errorf("expected the payload to be compressed with %d, got id %d and %d bytes", c.ID(), data[0], len(data))
+		}
+
+		// 更换算法后仍可解码之前的负载
+		gz := broadcast.NewCompressedCodec(nil, broadcast.CompressionConfig{})
+		var got string
+		if err := gz.Unmarshal(data, &got); err != nil || got != payload {
+			t.Errorf("expected algorithm %d to decode through the registry, got %v", c.ID(), err)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+module pkg.blksails.net/x/broadcast/compression
+
+go 1.24
+
+require (
+	github.com/klauspost/compress v1.18.0
+	pkg.blksails.net/x/broadcast v0.0.0
+)
+
+replace pkg.blksails.net/x/broadcast => ../
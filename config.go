@@ -0,0 +1,140 @@
+package broadcast
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Config 是广播器的声明式配置, 可以从 YAML、JSON 或环境变量加载后通过 NewFromConfig 构造广播器
+// 零值表示不开启对应的能力
+type Config struct {
+	// Workers 与 QueueSize 任一大于 0 时开启异步投递
+	Workers   int `json:"workers" yaml:"workers"`
+	QueueSize int `json:"queue_size" yaml:"queue_size"`
+	// Overflow 队列已满时的策略: block (默认)、drop_oldest 或 drop_newest
+	Overflow        string `json:"overflow" yaml:"overflow"`
+	KeyOrdered      bool   `json:"key_ordered" yaml:"key_ordered"`
+	PriorityWorkers int    `json:"priority_workers" yaml:"priority_workers"`
+
+	// EventTTL 为没有通过 WithEventTTL 设置存活时间的广播提供默认值
+	EventTTL time.Duration `json:"event_ttl" yaml:"event_ttl"`
+
+	// RateLimit 大于 0 时为每个信号开启令牌桶限流, RateBurst 默认为 1
+	RateLimit float64 `json:"rate_limit" yaml:"rate_limit"`
+	RateBurst int     `json:"rate_burst" yaml:"rate_burst"`
+
+	// PendingBuffer 大于 0 时开启暂存缓冲
+	PendingBuffer int `json:"pending_buffer" yaml:"pending_buffer"`
+
+	// Parallel 大于 1 时每个处理器在最多 Parallel 个 goroutine 中并发处理各监听器
+	Parallel int `json:"parallel" yaml:"parallel"`
+
+	// Breaker 非 nil 时开启熔断
+	Breaker *BreakerConfig `json:"breaker" yaml:"breaker"`
+}
+
+var overflowPolicies = map[string]OverflowPolicy{
+	"":            Block,
+	"block":       Block,
+	"drop_oldest": DropOldest,
+	"drop_newest": DropNewest,
+}
+
+// Validate 检查配置, 返回所有问题的组合, 每个问题都包装了 ErrInvalidConfig
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidConfig}, args...)...))
+	}
+
+	if c.Workers < 0 {
+		invalid("workers must not be negative, got %d", c.Workers)
+	}
+	if c.QueueSize < 0 {
+		invalid("queue_size must not be negative, got %d", c.QueueSize)
+	}
+	if _, ok := overflowPolicies[c.Overflow]; !ok {
+		invalid("unknown overflow policy %q", c.Overflow)
+	}
+	if c.PriorityWorkers < 0 {
+		invalid("priority_workers must not be negative, got %d", c.PriorityWorkers)
+	}
+	if !c.async() && (c.KeyOrdered || c.PriorityWorkers > 0 || c.Overflow != "") {
+		invalid("key_ordered, priority_workers and overflow require workers or queue_size")
+	}
+	if c.EventTTL < 0 {
+		invalid("event_ttl must not be negative, got %s", c.EventTTL)
+	}
+	if c.RateLimit < 0 {
+		invalid("rate_limit must not be negative, got %g", c.RateLimit)
+	}
+	if c.RateBurst < 0 {
+		invalid("rate_burst must not be negative, got %d", c.RateBurst)
+	}
+	if c.RateBurst > 0 && c.RateLimit == 0 {
+		invalid("rate_burst requires rate_limit")
+	}
+	if c.PendingBuffer < 0 {
+		invalid("pending_buffer must not be negative, got %d", c.PendingBuffer)
+	}
+	if c.Parallel < 0 {
+		invalid("parallel must not be negative, got %d", c.Parallel)
+	}
+	if b := c.Breaker; b != nil {
+		if b.FailureThreshold < 0 || b.QueueThreshold < 0 || b.ProbeInterval < 0 || b.ProbeTimeout < 0 {
+			invalid("breaker thresholds, probe interval and probe timeout must not be negative")
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c Config) async() bool {
+	return c.Workers > 0 || c.QueueSize > 0
+}
+
+// Options 将配置转换为构造选项, 调用方应先调用 Validate
+func (c Config) Options() []Option {
+	var opts []Option
+	if c.async() {
+		opts = append(opts, WithAsync(AsyncConfig{
+			Workers:         c.Workers,
+			QueueSize:       c.QueueSize,
+			Overflow:        overflowPolicies[c.Overflow],
+			KeyOrdered:      c.KeyOrdered,
+			PriorityWorkers: c.PriorityWorkers,
+		}))
+	}
+	if c.EventTTL > 0 {
+		opts = append(opts, WithDefaultTTL(c.EventTTL))
+	}
+	if c.RateLimit > 0 {
+		opts = append(opts, WithRateLimiter(NewTokenBucket(c.RateLimit, max(c.RateBurst, 1))))
+	}
+	if c.PendingBuffer > 0 {
+		opts = append(opts, WithPendingBuffer(c.PendingBuffer))
+	}
+	if c.Parallel > 1 {
+		opts = append(opts, WithParallel(c.Parallel))
+	}
+	if c.Breaker != nil {
+		opts = append(opts, WithBreaker(*c.Breaker))
+	}
+	return opts
+}
+
+// NewFromConfig 校验配置并创建广播实例, opts 在配置之后应用, 可用于补充时间源、日志等无法声明的参数
+func NewFromConfig[T comparable](config Config, opts ...Option) (*Broadcast[T], error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return New[T](append(config.Options(), opts...)...), nil
+}
+
+// NewUniqueFromConfig 校验配置并创建 UniqueBroadcast 实例, opts 与 NewFromConfig 相同
+func NewUniqueFromConfig[K comparable, T any](config Config, opts ...Option) (*UniqueBroadcast[K, T], error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return NewUnique[K, T](append(config.Options(), opts...)...), nil
+}
@@ -0,0 +1,85 @@
+package broadcast
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	if err := (Config{}).Validate(); err != nil {
+		t.Errorf("expected the zero config to be valid, got %v", err)
+	}
+
+	err := Config{
+		Workers:   -1,
+		Overflow:  "drop_everything",
+		RateBurst: 5,
+		Breaker:   &BreakerConfig{ProbeInterval: -time.Second},
+	}.Validate()
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+	for _, want := range []string{"workers", "overflow", "rate_burst", "breaker"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to mention %s, got %v", want, err)
+		}
+	}
+
+	if err := (Config{KeyOrdered: true}).Validate(); err == nil {
+		t.Error("expected key_ordered without async to be rejected")
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	var config Config
+	data := `{"workers": 2, "queue_size": 16, "overflow": "drop_newest", "event_ttl": 1000000000, "rate_limit": 1}`
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := NewFromConfig[string](config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	s := b.c().loadSettings()
+	if s.async == nil || s.async.config.Workers != 2 || s.async.config.Overflow != DropNewest {
+		t.Errorf("expected async delivery from the config, got %+v", s.async)
+	}
+	if s.ttl != time.Second || s.limiter == nil {
+		t.Errorf("expected the default TTL and rate limiter, got %v %v", s.ttl, s.limiter)
+	}
+	if err := b.Broadcast("test", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Broadcast("test", nil); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected the second broadcast to be rate limited, got %v", err)
+	}
+
+	if _, err := NewUniqueFromConfig[int, TestUniqueData](Config{QueueSize: -1}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected an invalid config to be rejected, got %v", err)
+	}
+}
+
+func TestDefaultTTL(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b, gate, received := gatedBroadcast(t, AsyncConfig{QueueSize: 4})
+	b.SetClock(clock)
+	b.c().updateSettings(func(s *settings[string, string]) {
+		s.ttl = time.Second
+	})
+
+	b.Broadcast("test", map[string]interface{}{"seq": 1})
+	b.Broadcast("test", map[string]interface{}{"seq": 2}, WithEventTTL(time.Minute))
+	clock.now = clock.now.Add(2 * time.Second)
+	close(gate)
+	b.Close()
+
+	if got := received(); len(got) != 2 || got[1] != 2 {
+		t.Errorf("expected the default TTL to drop seq 1 only, got %v", got)
+	}
+}
@@ -0,0 +1,19 @@
+package broadcast
+
+import (
+	"context"
+)
+
+// watchContext 以 ctx 中的身份授权后添加监听器, 并在 ctx 取消时自动移除
+// ctx 已取消、授权失败或相同 key 已存在时不做任何事, 避免取消时移除他人添加的监听器
+func (c *core[K, T]) watchContext(ctx context.Context, signal string, l listener[K, T]) {
+	if ctx.Err() != nil || c.authorize(ctx, OpWatch, signal) != nil {
+		return
+	}
+	if added, _ := c.tryWatch(signal, l); !added {
+		return
+	}
+	context.AfterFunc(ctx, func() {
+		c.unwatch(signal, l.key)
+	})
+}
@@ -0,0 +1,43 @@
+package broadcast
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWatchContext_UnwatchOnCancel(t *testing.T) {
+	b := New[string]()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	b.WatchContext(ctx, "request", "session-1")
+	b.Watch("request", "session-2")
+	if b.WatchCount("request") != 2 {
+		t.Fatalf("expected 2 listeners, got %d", b.WatchCount("request"))
+	}
+
+	cancel()
+	waitFor(t, func() bool { return b.WatchCount("request") == 1 })
+	if !b.HasWatch("request") {
+		t.Error("expected the plain Watch to remain")
+	}
+}
+
+func TestWatchContext_CancelledOrExisting(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	data := &TestUniquer{data: TestUniqueData{ID: 1}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.WatchContext(ctx, "test", data)
+	if b.HasWatch("test") {
+		t.Error("expected no listener for a cancelled context")
+	}
+
+	b.Watch("test", data)
+	ctx, cancel = context.WithCancel(context.Background())
+	b.WatchContext(ctx, "test", data)
+	cancel()
+	if b.WatchCount("test") != 1 {
+		t.Errorf("expected the existing listener to survive, got %d", b.WatchCount("test"))
+	}
+}
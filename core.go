@@ -0,0 +1,923 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unique"
+)
+
+// shardCount 分片数量, 必须是 2 的幂
+const shardCount = 64
+
+// handlerFunc 是 Handler 与 UniqueHandler 共同的底层函数类型
+type handlerFunc[T any] func(signal string, data T, metadata map[string]interface{}) error
+
+// HandlerID 标识一个已注册的处理器, 用于 Unhandle 等操作
+type HandlerID uint64
+
+// dataHandlerFunc 是同时接收广播时负载的处理器, 由 HandleData 注册
+type dataHandlerFunc[T any] func(signal string, data T, payload any, metadata map[string]interface{}) error
+
+// handlerEntry 是已注册的处理器, fn, dataFn, eventFn 与 ctxFn 只有一个非 nil
+type handlerEntry[T any] struct {
+	id      HandlerID
+	fn      handlerFunc[T]
+	dataFn  dataHandlerFunc[T]
+	eventFn eventHandlerFunc[T]
+	ctxFn   ctxHandlerFunc[T]
+	// name 为 WithName 设置的名称
+	name string
+	// close 非 nil 时在处理器被移除后调用
+	close func()
+	// prefix 非空时只接收该命名空间下的信号
+	prefix string
+	// durable 非 nil 时为持久处理器, 跟踪其在日志上的消费进度
+	durable *durableCursor
+	// dedup 非 nil 时为幂等处理器, 跳过已处理过的事件
+	dedup *IdempotencyConfig
+	// sem 非 nil 时限制处理器同时执行的次数
+	sem chan struct{}
+	// counters 累计调用与错误次数
+	counters *handlerCounters
+}
+
+// listener 是注册在某个信号上的监听器, key 在 Watch 时计算一次并缓存
+type listener[K comparable, T any] struct {
+	key  unique.Handle[K]
+	data Uniquer[K, T]
+	// rate 非 nil 时限制监听器接收投递的速率
+	rate *listenerRate[K, T]
+	// lease 非 nil 时为 WatchLease 添加的监听器的租约
+	lease *Lease
+}
+
+func newListener[K comparable, T any](data Uniquer[K, T]) listener[K, T] {
+	return listener[K, T]{key: data.Unique(), data: data}
+}
+
+func (l listener[K, T]) sameKey(other listener[K, T]) bool {
+	return l.key == other.key
+}
+
+// signalEntry 保存单个信号的监听器切片
+// 切片本身不可变, 写操作在 mu 内构造新切片并原子替换,
+// 每个信号拥有独立的锁, 一个信号上的 Watch/Clean 不会阻塞其他信号
+type signalEntry[K comparable, T any] struct {
+	mu        sync.Mutex
+	gen       uint64
+	removed   bool
+	listeners atomic.Pointer[[]listener[K, T]]
+	// version 是监听器集合当前的版本, undo 保存最近的历史版本, 均由 mu 保护
+	version uint64
+	undo    []listenerVersion[K, T]
+	// reserve 为 ReserveListeners 设置的容量提示, owned 是条目独占的底层数组, 均由 mu 保护
+	reserve int
+	owned   []listener[K, T]
+}
+
+func (e *signalEntry[K, T]) load() []listener[K, T] {
+	if p := e.listeners.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// shard 保存一部分信号的条目
+// 信号表采用写时复制: 读取完全无锁, 只有增删信号时才需要获取 mu
+type shard[K comparable, T any] struct {
+	mu      sync.Mutex
+	signals atomic.Pointer[map[string]*signalEntry[K, T]]
+	// seen 记录至少被广播过一次的信号, 同样采用写时复制
+	seen atomic.Pointer[map[string]struct{}]
+	// activity 记录信号最近的广播与监听器变化时间, 同样采用写时复制
+	activity atomic.Pointer[map[string]*signalActivity]
+}
+
+func (s *shard[K, T]) load() map[string]*signalEntry[K, T] {
+	if p := s.signals.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// core 是 Broadcast 与 UniqueBroadcast 共用的内部实现
+// 监听器按信号哈希分布在多个分片中, 每个信号拥有独立的锁,
+// Broadcast 只读取不可变快照, 不获取任何锁
+type core[K comparable, T any] struct {
+	shards [shardCount]shard[K, T]
+
+	// gen 是 CleanAll 的代数, 代数不等于当前值的条目视为已清除
+	gen atomic.Uint64
+
+	// handlers 是不可变切片, Handle/Unhandle 复制后替换, Broadcast 无锁读取
+	handlersMu sync.Mutex
+	handlers   atomic.Pointer[[]handlerEntry[T]]
+	nextID     atomic.Uint64
+
+	settingsMu sync.Mutex
+	settings   atomic.Pointer[settings[K, T]]
+
+	// seq 为每次广播分配序号
+	seq atomic.Uint64
+	// rated 在添加过有速率限制的监听器后为 true, 投递时才需要检查监听器速率
+	rated atomic.Bool
+	// version 为监听器集合的每次变化分配版本号, 在所有信号间单调递增
+	version atomic.Uint64
+	// expired 因超过 TTL 而被丢弃的投递数量
+	expired atomic.Uint64
+
+	// seenCh 在有新信号首次被广播时关闭, 用于唤醒 WaitFor
+	seenMu sync.Mutex
+	seenCh chan struct{}
+
+	// index 记录每个 key 正在监听的信号
+	index reverseIndex[K]
+
+	// groups 记录 WatchGroup 添加的监听器
+	groupsMu sync.Mutex
+	groups   map[string]map[groupMember[K]]struct{}
+
+	// leases 记录 WatchLease 添加的监听器的租约, leased 在创建过租约后为 true
+	// groupLeases 记录 LeaseGroup 创建的分组租约
+	leased      atomic.Bool
+	leasesMu    sync.Mutex
+	leases      map[signalKey[K]]*Lease
+	groupLeases map[string]*Lease
+}
+
+// shardIndex 使用 FNV-1a 计算信号所在的分片
+func shardIndex(signal string) int {
+	h := uint32(2166136261)
+	for i := 0; i < len(signal); i++ {
+		h ^= uint32(signal[i])
+		h *= 16777619
+	}
+	return int(h & (shardCount - 1))
+}
+
+func (c *core[K, T]) shard(signal string) *shard[K, T] {
+	return &c.shards[shardIndex(signal)]
+}
+
+// handle 注册处理器, prefix 非空时处理器只接收该前缀下的信号, 且信号名去掉前缀
+func (c *core[K, T]) handle(prefix string, handler handlerFunc[T], opts ...HandleOption) HandlerID {
+	o := newHandleOptions(opts)
+	return c.addHandler(handlerEntry[T]{fn: handler, prefix: prefix, name: o.name, sem: o.semaphore()})
+}
+
+func (c *core[K, T]) addHandler(entry handlerEntry[T]) HandlerID {
+	c.handlersMu.Lock()
+	entry.id = HandlerID(c.nextID.Add(1))
+	entry.counters = new(handlerCounters)
+	handlers := c.loadHandlers()
+	newHandlers := make([]handlerEntry[T], len(handlers)+1)
+	copy(newHandlers, handlers)
+	newHandlers[len(handlers)] = entry
+	c.handlers.Store(&newHandlers)
+	c.handlersMu.Unlock()
+
+	c.flushBuffer()
+	return entry.id
+}
+
+func (c *core[K, T]) unhandle(id HandlerID) bool {
+	c.handlersMu.Lock()
+	handlers := c.loadHandlers()
+	for i, h := range handlers {
+		if h.id == id {
+			newHandlers := make([]handlerEntry[T], 0, len(handlers)-1)
+			newHandlers = append(newHandlers, handlers[:i]...)
+			newHandlers = append(newHandlers, handlers[i+1:]...)
+			c.handlers.Store(&newHandlers)
+			c.handlersMu.Unlock()
+
+			if h.close != nil {
+				h.close()
+			}
+			return true
+		}
+	}
+	c.handlersMu.Unlock()
+	return false
+}
+
+func (c *core[K, T]) loadHandlers() []handlerEntry[T] {
+	if p := c.handlers.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// entry 返回信号当前代数的条目, create 为 true 时不存在则创建
+func (c *core[K, T]) entry(signal string, create bool) *signalEntry[K, T] {
+	s := c.shard(signal)
+	if e, ok := s.load()[signal]; ok && e.gen == c.gen.Load() {
+		return e
+	}
+	if !create {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gen := c.gen.Load()
+	signals := s.load()
+	if e, ok := signals[signal]; ok && e.gen == gen {
+		return e
+	}
+
+	e := &signalEntry[K, T]{gen: gen}
+	newSignals := make(map[string]*signalEntry[K, T], len(signals)+1)
+	for k, v := range signals {
+		// 顺便丢弃 CleanAll 之前的旧条目
+		if v.gen == gen {
+			newSignals[k] = v
+		}
+	}
+	// 信号表只保留驻留后的信号名, 不持有调用方传入的字符串
+	newSignals[Intern(signal).String()] = e
+	s.signals.Store(&newSignals)
+	return e
+}
+
+// mutate 在信号锁内以写时复制方式修改监听器切片, 返回 fn 报告的是否修改
+// fn 不得修改传入的切片; create 为 false 时信号不存在则直接返回 false
+func (c *core[K, T]) mutate(signal string, create bool, fn func(listeners []listener[K, T]) ([]listener[K, T], bool)) bool {
+	return c.mutateEntry(signal, create, func(e *signalEntry[K, T]) ([]listener[K, T], bool) {
+		return fn(e.load())
+	})
+}
+
+// mutateEntry 与 mutate 相同, 但 fn 可以读取条目的版本历史
+func (c *core[K, T]) mutateEntry(signal string, create bool, fn func(e *signalEntry[K, T]) ([]listener[K, T], bool)) bool {
+	if create && !c.allowWatch(signal) {
+		return false
+	}
+	for {
+		e := c.entry(signal, create)
+		if e == nil {
+			return false
+		}
+
+		e.mu.Lock()
+		if e.removed || e.gen != c.gen.Load() {
+			// 条目在加锁前被 Clean/CleanAll 移除
+			e.mu.Unlock()
+			if !create {
+				return false
+			}
+			continue
+		}
+
+		newListeners, changed := fn(e)
+		if changed {
+			c.commitEntry(e, newListeners)
+		}
+		e.mu.Unlock()
+		if changed {
+			c.touchWatch(signal)
+		}
+		return changed
+	}
+}
+
+// watch 授权后添加监听器, 如果相同 key 已存在、授权或校验失败则返回 false
+func (c *core[K, T]) watch(signal string, l listener[K, T]) bool {
+	if !c.permit(OpWatch, signal) {
+		return false
+	}
+	added, _ := c.tryWatch(signal, l)
+	return added
+}
+
+// tryWatch 校验并添加监听器, 校验失败时返回包装了 ErrValidation 的错误; 调用方负责授权
+func (c *core[K, T]) tryWatch(signal string, l listener[K, T]) (bool, error) {
+	if err := c.validate(signal, l.data.Value()); err != nil {
+		return false, err
+	}
+	added := c.mutateEntry(signal, true, func(e *signalEntry[K, T]) ([]listener[K, T], bool) {
+		listeners := e.load()
+		for _, item := range listeners {
+			if item.key == l.key {
+				return nil, false
+			}
+		}
+
+		newListeners := e.append(listeners, l)
+		c.track(signal, l)
+		return newListeners, true
+	})
+	if added {
+		c.flushBuffer(signal)
+	}
+	return added, nil
+}
+
+// upsert 添加监听器, 相同 key 已存在时替换其值, 返回是否为替换
+// 授权、注册表或校验拒绝时返回错误, 监听器不变
+func (c *core[K, T]) upsert(signal string, l listener[K, T]) (bool, error) {
+	if err := c.authorize(nil, OpWatch, signal); err != nil {
+		return false, err
+	}
+	if err := c.validate(signal, l.data.Value()); err != nil {
+		return false, err
+	}
+	updated := false
+	changed := c.mutateEntry(signal, true, func(e *signalEntry[K, T]) ([]listener[K, T], bool) {
+		listeners := e.load()
+		for i, item := range listeners {
+			if item.key == l.key {
+				newListeners := make([]listener[K, T], len(listeners))
+				copy(newListeners, listeners)
+				newListeners[i] = l
+				c.track(signal, l)
+				updated = true
+				return newListeners, true
+			}
+		}
+
+		newListeners := e.append(listeners, l)
+		c.track(signal, l)
+		return newListeners, true
+	})
+	if !changed {
+		// fn 总是修改监听器, 没有修改说明严格模式的注册表拒绝了该信号
+		return false, undeclared(signal)
+	}
+	if !updated {
+		c.flushBuffer(signal)
+	}
+	return updated, nil
+}
+
+// unwatch 移除指定 key 的监听器, 返回是否有监听器被移除
+func (c *core[K, T]) unwatch(signal string, key unique.Handle[K]) bool {
+	return c.unwatchWhere(signal, key, nil)
+}
+
+// unwatchWhere 在 match 为 nil 或报告 true 时移除指定 key 的监听器
+func (c *core[K, T]) unwatchWhere(signal string, key unique.Handle[K], match func(l listener[K, T]) bool) bool {
+	return c.mutate(signal, false, func(listeners []listener[K, T]) ([]listener[K, T], bool) {
+		for i, item := range listeners {
+			if item.key == key {
+				if match != nil && !match(item) {
+					return nil, false
+				}
+				newListeners := make([]listener[K, T], 0, len(listeners)-1)
+				newListeners = append(newListeners, listeners[:i]...)
+				newListeners = append(newListeners, listeners[i+1:]...)
+				c.untrack(signal, key)
+				return newListeners, true
+			}
+		}
+		return nil, false
+	})
+}
+
+// snapshot 返回指定信号当前的监听器快照, 调用方不得修改返回的切片
+func (c *core[K, T]) snapshot(signal string) []listener[K, T] {
+	if e := c.entry(signal, false); e != nil {
+		return e.load()
+	}
+	return nil
+}
+
+func (c *core[K, T]) broadcast(signal string, metadata map[string]interface{}, opts ...BroadcastOption) error {
+	return c.send(nil, signal, metadata, opts)
+}
+
+// send 应用广播选项并发布, ctx 非 nil 时交给 HandleCtx 注册的处理器
+func (c *core[K, T]) send(ctx context.Context, signal string, metadata map[string]interface{}, opts []BroadcastOption) error {
+	o := newBroadcastOptions(opts)
+	if o.correlation != "" {
+		metadata = withCorrelation(metadata, o.correlation)
+	}
+	return c.publish(delivery[K, T]{ctx: ctx, signal: signal, metadata: metadata, ttl: o.ttl, id: o.id, source: o.source, cutoff: o.deadline})
+}
+
+// publish 检查授权、熔断与限流并为投递分配序号
+// 同步执行时返回所有处理器错误的组合, 异步入队成功时返回 nil
+func (c *core[K, T]) publish(d delivery[K, T]) error {
+	if err := c.authorize(d.ctx, OpBroadcast, d.signal); err != nil {
+		return err
+	}
+	if _, err := c.admit(d.signal, d.payload); err != nil {
+		return err
+	}
+	return c.commit(d)
+}
+
+// admission 记录 admit 放行一次广播时占用的资源, 供 cancel 撤销
+type admission struct {
+	limiter RateLimiter
+	quota   *quota
+	probe   bool
+}
+
+// admit 检查信号当前是否允许广播
+func (c *core[K, T]) admit(signal string, payload any) (admission, error) {
+	var a admission
+	settings := c.loadSettings()
+	if _, ok := settings.paused[signal]; ok {
+		return a, ErrSignalPaused
+	}
+	if settings.registry != nil {
+		if err := settings.registry.violation(signal, payload); err != nil {
+			return a, err
+		}
+	}
+	if settings.sampler != nil && !settings.sampler.allow(signal, c.clock().Now(), c.pending()) {
+		return a, ErrSampled
+	}
+	if settings.limiter != nil {
+		if !settings.limiter.Allow(signal) {
+			return a, ErrRateLimited
+		}
+		a.limiter = settings.limiter
+	}
+	if settings.payloadValidator != nil && payload != nil {
+		if err := validatePayload(settings.payloadValidator, signal, payload); err != nil {
+			return a, err
+		}
+	}
+	if q := settings.quotas[signal]; q != nil {
+		if err := c.checkQuota(q, signal); err != nil {
+			return a, err
+		}
+		a.quota = q
+	}
+	// 熔断器最后检查, 放行的探测广播不会再被其他检查拒绝
+	if settings.breaker != nil {
+		probe, err := settings.breaker.allow(signal, c.clock().Now(), c.pending())
+		if err != nil {
+			return a, err
+		}
+		a.probe = probe
+	}
+	return a, nil
+}
+
+// cancel 撤销一次已通过 admit 但不会提交的广播: 结束熔断器探测并退还配额与限流许可
+func (c *core[K, T]) cancel(signal string, a admission) {
+	if r, ok := a.limiter.(RateRefunder); ok {
+		r.Refund(signal)
+	}
+	if a.quota != nil {
+		a.quota.refund()
+	}
+	if a.probe {
+		if b := c.loadSettings().breaker; b != nil {
+			b.release(signal)
+		}
+	}
+}
+
+// commit 为已通过 admit 的投递分配序号并执行
+func (c *core[K, T]) commit(d delivery[K, T]) error {
+	settings := c.loadSettings()
+	d.seq = c.seq.Add(1)
+	d.time = c.clock().Now()
+	if d.ttl == 0 {
+		d.ttl = settings.ttl
+	}
+	if j := settings.journal; j != nil && j.selected(d.signal) {
+		e := JournalEntry{Seq: d.seq, ID: d.id, Signal: d.signal, Source: d.source, Time: d.time, Metadata: d.metadata, Version: j.config.Version}
+		if err := j.append(e, d.payload); err != nil {
+			c.undelivered(d, false)
+			return err
+		}
+		d.journaled = true
+		c.beginDurable(d.seq)
+	}
+	if settings.metrics != nil {
+		settings.metrics.Published(d.signal)
+	}
+	c.markSeen(d.signal)
+	c.touchBroadcast(d.signal, d.time)
+	if taps := settings.taps[d.signal]; len(taps) > 0 {
+		notifyTaps(taps, &d)
+	}
+	if len(settings.tapAll) > 0 {
+		notifyTaps(settings.tapAll, &d)
+	}
+	if d.ttl > 0 {
+		d.deadline = d.time.Add(d.ttl)
+	}
+	if !d.cutoff.IsZero() && (d.deadline.IsZero() || d.cutoff.Before(d.deadline)) {
+		d.deadline = d.cutoff
+	}
+	return c.dispatch(d)
+}
+
+// dispatch 捕获监听器与处理器快照, 然后暂存、同步或异步执行
+func (c *core[K, T]) dispatch(d delivery[K, T]) error {
+	settings := c.loadSettings()
+	d.listeners = c.snapshot(d.signal)
+	d.handlers = c.loadHandlersRecorded(settings, &d)
+	if d.ctx != nil && (settings.buffer != nil || settings.async != nil) {
+		// 暂存或异步执行时调用方可能已经返回, 保留 ctx 中的值但不继承其取消
+		d.ctx = context.WithoutCancel(d.ctx)
+	}
+	if settings.buffer != nil && (len(d.listeners) == 0 || len(d.handlers) == 0) {
+		// 没有监听器时持久处理器没有要执行的调用, 与同步投递一样直接确认
+		c.undelivered(d, false)
+		settings.buffer.add(d)
+		return nil
+	}
+	if c.rated.Load() {
+		d.listeners = c.admitListeners(&d)
+	}
+	if m := settings.deterministic; m != nil {
+		d.listeners = deterministicOrder(m.seed, d.seq, d.listeners)
+		return c.deliver(d)
+	}
+	if settings.orderOf(d.signal) == OrderKeySorted {
+		d.listeners = sortByKey(d.listeners)
+	}
+	if settings.async != nil && settings.async.enqueue(d, settings.priorityOf(d.signal)) {
+		return nil
+	}
+	return c.deliver(d)
+}
+
+// deliver 依次对每个处理器和监听器执行回调, 返回所有处理器错误的组合
+func (c *core[K, T]) deliver(d delivery[K, T]) error {
+	settings := c.loadSettings()
+	if settings.sampler != nil {
+		start := c.clock().Now()
+		defer func() {
+			settings.sampler.observe(c.clock().Now().Sub(start))
+		}()
+	}
+	// 有 Transform 时每个监听器只改写一次, 所有处理器共享改写后的值
+	var values []T
+	if len(settings.transforms) > 0 && len(d.handlers) > 0 {
+		values = make([]T, len(d.listeners))
+		for i, l := range d.listeners {
+			values[i] = transformAll(settings.transforms, d.signal, l.data.Value(), d.metadata)
+		}
+	}
+
+	var errs []error
+	var late *DeadlineError
+	for _, handler := range d.handlers {
+		signal := d.signal
+		if handler.prefix != "" {
+			rest, ok := strings.CutPrefix(d.signal, handler.prefix)
+			if !ok {
+				if handler.durable != nil && d.journaled && d.parts == nil {
+					c.settleDurable(handler, d, false)
+				}
+				continue
+			}
+			signal = rest
+		}
+		failed := false
+		if len(d.listeners) > 1 && settings.deterministic == nil && settings.orderOf(d.signal) == OrderUnordered {
+			var handlerErrs []error
+			handlerErrs, late = c.deliverParallel(settings, d, handler, signal, values, late)
+			errs = append(errs, handlerErrs...)
+			failed = len(handlerErrs) > 0
+		} else {
+			for i, l := range d.listeners {
+				if c.overdue(&d) {
+					late = skipDelivery(late, &d, handler.id, l.key.Value())
+					continue
+				}
+				var data T
+				if values != nil {
+					data = values[i]
+				} else {
+					data = l.data.Value()
+				}
+				if err := c.invoke(settings, &d, &handler, signal, l, data); err != nil {
+					errs = append(errs, err)
+					failed = true
+				}
+			}
+		}
+		// 超时未执行的调用与失败相同, 持久处理器稍后重试
+		failed = failed || late.skipped(handler.id)
+		if handler.durable != nil && d.journaled {
+			if d.parts == nil {
+				c.settleDurable(handler, d, failed)
+			} else if failed {
+				d.parts.fail(handler.id)
+			}
+		}
+	}
+	if d.parts != nil && d.journaled {
+		c.settleParts(d)
+	}
+
+	if settings.breaker != nil {
+		settings.breaker.record(d.signal, len(errs) == 0, c.clock().Now())
+	}
+	if settings.health != nil {
+		var last error
+		if len(errs) > 0 {
+			last = errs[len(errs)-1]
+		}
+		settings.health.record(d.signal, last, c.clock().Now())
+	}
+	if late != nil {
+		errs = append(errs, late)
+	}
+	return errors.Join(errs...)
+}
+
+// undelivered 在已通过 admit 的投递没有执行时调用, 如日志写入失败、暂存、
+// 异步队列溢出丢弃或过期; 作为熔断器探测的投递作废, 熔断器重新断开
+// 已写入日志的投递同时结算持久处理器: retry 为 true 时稍后重新投递, 否则确认
+func (c *core[K, T]) undelivered(d delivery[K, T], retry bool) {
+	if b := c.loadSettings().breaker; b != nil {
+		b.abort(d.signal, c.clock().Now())
+	}
+	if !d.journaled {
+		return
+	}
+	for _, handler := range d.handlers {
+		if handler.durable == nil {
+			continue
+		}
+		if d.parts == nil {
+			c.settleDurable(handler, d, retry)
+		} else if retry {
+			d.parts.fail(handler.id)
+		}
+	}
+	if d.parts != nil {
+		c.settleParts(d)
+	}
+}
+
+// overflowed 在异步队列已满丢弃投递时调用, 持久处理器稍后重新投递
+func (c *core[K, T]) overflowed(d delivery[K, T]) {
+	c.undelivered(d, true)
+	if m := c.loadSettings().metrics; m != nil {
+		m.Dropped(d.signal)
+	}
+}
+
+// call 在并发限制内调用处理器, 处理器 panic 时同样释放占用的位置
+func (c *core[K, T]) call(h *handlerEntry[T], d *delivery[K, T], signal string, data T) error {
+	if h.sem != nil {
+		h.sem <- struct{}{}
+		defer func() { <-h.sem }()
+	}
+	switch {
+	case h.eventFn != nil:
+		return h.eventFn(d.event(signal, data))
+	case h.dataFn != nil:
+		return h.dataFn(signal, data, d.payload, d.metadata)
+	case h.ctxFn != nil:
+		return invokeCtx(d, h.ctxFn, signal, data)
+	default:
+		return h.fn(signal, data, d.metadata)
+	}
+}
+
+// invoke 对单个监听器执行处理器, 并记录回执、调用错误回调与写入死信队列
+// 幂等处理器已处理过的事件与被隔离的监听器直接跳过, 返回 nil
+func (c *core[K, T]) invoke(settings *settings[K, T], d *delivery[K, T], handler *handlerEntry[T], signal string, l listener[K, T], data T) error {
+	if settings.quarantine != nil && settings.quarantine.blocked(d.signal, l.key) {
+		return nil
+	}
+	var err error
+	var key string
+	if handler.dedup != nil {
+		key = dedupKey(d, l)
+		var seen bool
+		if seen, err = handler.dedup.Store.Contains(handler.dedup.Name, key); seen {
+			return nil
+		}
+	}
+	if err == nil {
+		timed := settings.slow != nil || settings.metrics != nil
+		var start time.Time
+		if timed {
+			start = c.clock().Now()
+		}
+		err = c.call(handler, d, signal, data)
+		if handler.counters != nil {
+			handler.counters.record(err)
+		}
+		if timed {
+			elapsed := c.clock().Now().Sub(start)
+			if settings.slow != nil && elapsed > settings.slow.threshold {
+				settings.slow.fn(HandlerInfo{ID: handler.id, Name: handler.name}, d.signal, elapsed)
+			}
+			if settings.metrics != nil {
+				settings.metrics.Handled(d.signal, handler.id, elapsed, err)
+			}
+		}
+	}
+	if err == nil && handler.dedup != nil {
+		err = handler.dedup.Store.Add(handler.dedup.Name, key)
+	}
+	if settings.quarantine != nil {
+		settings.quarantine.record(d.signal, l.key, handler.id, err)
+	}
+	if settings.autoUnwatch != nil {
+		c.recordAutoUnwatch(settings.autoUnwatch, d.signal, l.key, handler.id, err)
+	}
+	if settings.receipts != nil {
+		_ = settings.receipts.Record(Receipt[K]{
+			Seq:       d.seq,
+			Signal:    d.signal,
+			Key:       l.key.Value(),
+			HandlerID: handler.id,
+			Time:      c.clock().Now(),
+			Err:       err,
+		})
+	}
+	if err == nil {
+		return nil
+	}
+
+	if settings.onError != nil {
+		settings.onError(d.signal, data, handler.id, err)
+	}
+	if settings.dlq != nil {
+		c.deadLetter(settings.dlq, DeadLetter[K, T]{
+			Seq:         d.seq,
+			Signal:      d.signal,
+			Key:         l.key.Value(),
+			Data:        data,
+			Metadata:    d.metadata,
+			HandlerID:   handler.id,
+			HandlerName: handler.name,
+			Err:         err,
+			Time:        c.clock().Now(),
+		})
+	}
+	return err
+}
+
+func (c *core[K, T]) clean(signal string) {
+	if !c.permit(OpUnwatch, signal) {
+		return
+	}
+	s := c.shard(signal)
+	s.mu.Lock()
+	signals := s.load()
+	e, ok := signals[signal]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+
+	newSignals := make(map[string]*signalEntry[K, T], len(signals))
+	for k, v := range signals {
+		if k != signal {
+			newSignals[k] = v
+		}
+	}
+	s.signals.Store(&newSignals)
+	s.mu.Unlock()
+
+	// 标记条目已移除, 持有旧条目的并发写操作会重试
+	e.mu.Lock()
+	e.removed = true
+	listeners := e.load()
+	for _, l := range listeners {
+		c.untrack(signal, l.key)
+	}
+	e.mu.Unlock()
+	if len(listeners) > 0 {
+		c.touchWatch(signal)
+	}
+}
+
+// cleanAll 递增代数使所有现有条目立即失效, 然后逐个分片回收旧条目
+// 设置了授权钩子时逐个信号授权并清除, 不允许清除的信号保留
+func (c *core[K, T]) cleanAll() {
+	if c.loadSettings().authorizer != nil {
+		c.cleanPrefix("")
+		return
+	}
+	// 先重置索引再递增代数, 期间添加的监听器最多在索引中多留一条失效记录
+	c.index.reset()
+	if s := c.loadSettings().store; s != nil {
+		s.clear()
+	}
+	c.endLeases()
+	c.gen.Add(1)
+
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		gen := c.gen.Load()
+		signals := s.load()
+		newSignals := make(map[string]*signalEntry[K, T])
+		var cleared []string
+		for k, v := range signals {
+			if v.gen == gen {
+				newSignals[k] = v
+			} else if len(v.load()) > 0 {
+				cleared = append(cleared, k)
+			}
+		}
+		if len(newSignals) != len(signals) {
+			s.signals.Store(&newSignals)
+		}
+		s.mu.Unlock()
+
+		for _, signal := range cleared {
+			c.touchWatch(signal)
+		}
+	}
+}
+
+// cleanPrefix 清除所有以 prefix 开头的信号
+func (c *core[K, T]) cleanPrefix(prefix string) {
+	var signals []string
+	c.rangeSignals(func(signal string, count int) bool {
+		if strings.HasPrefix(signal, prefix) {
+			signals = append(signals, signal)
+		}
+		return true
+	})
+	for _, signal := range signals {
+		c.clean(signal)
+	}
+}
+
+// cleanKeys 移除所有信号上 key 满足 pred 的监听器, 返回移除的数量
+// 每次只持有一个信号的锁, 其他信号上的操作不受影响
+func (c *core[K, T]) cleanKeys(pred func(key K) bool) int {
+	removed := 0
+	gen := c.gen.Load()
+	for i := range c.shards {
+		for signal, e := range c.shards[i].load() {
+			if e.gen != gen || !c.permit(OpUnwatch, signal) {
+				continue
+			}
+			c.mutate(signal, false, func(listeners []listener[K, T]) ([]listener[K, T], bool) {
+				kept := make([]listener[K, T], 0, len(listeners))
+				for _, l := range listeners {
+					if !pred(l.key.Value()) {
+						kept = append(kept, l)
+					} else {
+						c.untrack(signal, l.key)
+					}
+				}
+				if len(kept) == len(listeners) {
+					return nil, false
+				}
+				removed += len(listeners) - len(kept)
+				return kept, true
+			})
+		}
+	}
+	return removed
+}
+
+// unwatchAll 通过反向索引从所有信号中移除指定 key 的监听器, 返回被移除的信号数量
+func (c *core[K, T]) unwatchAll(key unique.Handle[K]) int {
+	removed := 0
+	for _, signal := range c.index.signals(key) {
+		if c.permit(OpUnwatch, signal) && c.unwatch(signal, key) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// lookup 返回指定 key 在信号上的监听器
+func (c *core[K, T]) lookup(signal string, key unique.Handle[K]) (Uniquer[K, T], bool) {
+	for _, l := range c.snapshot(signal) {
+		if l.key == key {
+			return l.data, true
+		}
+	}
+	return nil, false
+}
+
+func (c *core[K, T]) hasWatch(signal string) bool {
+	return len(c.snapshot(signal)) > 0
+}
+
+func (c *core[K, T]) watchCount(signal string) int {
+	return len(c.snapshot(signal))
+}
+
+// rangeSignals 依次遍历每个分片的信号表快照
+func (c *core[K, T]) rangeSignals(fn func(signal string, count int) bool) {
+	gen := c.gen.Load()
+	for i := range c.shards {
+		for signal, e := range c.shards[i].load() {
+			if e.gen != gen {
+				continue
+			}
+			if !fn(signal, len(e.load())) {
+				return
+			}
+		}
+	}
+}
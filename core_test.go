@@ -0,0 +1,185 @@
+package broadcast
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"unique"
+)
+
+func TestShardIndex_Distribution(t *testing.T) {
+	used := make(map[int]bool)
+	for i := 0; i < 1000; i++ {
+		idx := shardIndex(fmt.Sprintf("signal-%d", i))
+		if idx < 0 || idx >= shardCount {
+			t.Fatalf("shard index %d out of range", idx)
+		}
+		used[idx] = true
+	}
+
+	if len(used) != shardCount {
+		t.Errorf("expected all %d shards to be used, got %d", shardCount, len(used))
+	}
+}
+
+func TestCore_SignalsIsolatedAcrossShards(t *testing.T) {
+	b := New[int]()
+
+	for i := 0; i < 200; i++ {
+		b.Watch(fmt.Sprintf("signal-%d", i), i)
+	}
+
+	b.Clean("signal-0")
+	if b.HasWatch("signal-0") {
+		t.Error("signal-0 should be cleaned")
+	}
+
+	total := 0
+	b.Range(func(signal string, count int) bool {
+		total += count
+		return true
+	})
+	if total != 199 {
+		t.Errorf("expected 199 listeners after clean, got %d", total)
+	}
+}
+
+// benchmarkContention 在 parallelism*GOMAXPROCS 个 goroutine 上执行 Watch/Unwatch/Broadcast
+// signals 越多, 分片带来的收益越明显
+func benchmarkContention(b *testing.B, signals int) {
+	br := New[int]()
+	br.Handle(func(signal string, data int, metadata map[string]interface{}) error {
+		return nil
+	})
+
+	names := make([]string, signals)
+	for i := range names {
+		names[i] = fmt.Sprintf("signal-%d", i)
+	}
+
+	var seq atomic.Uint64
+	b.SetParallelism(100)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		id := int(seq.Add(1))
+		signal := names[id%signals]
+		for i := 0; pb.Next(); i++ {
+			br.Watch(signal, id)
+			br.Broadcast(signal, nil)
+			br.Unwatch(signal, id)
+		}
+	})
+}
+
+func BenchmarkContention_1Signal(b *testing.B) {
+	benchmarkContention(b, 1)
+}
+
+func BenchmarkContention_64Signals(b *testing.B) {
+	benchmarkContention(b, 64)
+}
+
+func BenchmarkContention_1024Signals(b *testing.B) {
+	benchmarkContention(b, 1024)
+}
+
+func TestCore_SnapshotIsImmutable(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	for i := 0; i < 3; i++ {
+		b.Watch("test", &TestUniquer{data: TestUniqueData{ID: i}})
+	}
+
+	snapshot := b.core.snapshot("test")
+	b.Unwatch("test", &TestUniquer{data: TestUniqueData{ID: 0}})
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 10}})
+	b.Clean("test")
+
+	if len(snapshot) != 3 {
+		t.Fatalf("expected snapshot to keep 3 listeners, got %d", len(snapshot))
+	}
+	for i, l := range snapshot {
+		if l.data.Value().ID != i {
+			t.Errorf("snapshot modified at index %d: got ID %d", i, l.data.Value().ID)
+		}
+	}
+}
+
+func BenchmarkCore_BroadcastAllocs(b *testing.B) {
+	br := NewUnique[int, TestUniqueData]()
+	br.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		return nil
+	})
+	for i := 0; i < 100; i++ {
+		br.Watch("test", &TestUniquer{data: TestUniqueData{ID: i}})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		br.Broadcast("test", nil)
+	}
+}
+
+func TestCore_CleanAllGeneration(t *testing.T) {
+	b := New[int]()
+	b.Watch("a", 1)
+	b.Watch("b", 2)
+
+	stale := b.core.entry("a", false)
+	b.CleanAll()
+
+	if b.core.entry("a", false) != nil {
+		t.Error("entries from before CleanAll should not be visible")
+	}
+	if b.core.unwatch("a", unique.Make(1)) {
+		t.Error("unwatch should not find listeners cleared by CleanAll")
+	}
+
+	b.Watch("a", 3)
+	if e := b.core.entry("a", false); e == stale {
+		t.Error("Watch after CleanAll should create a fresh entry")
+	}
+	if count := b.WatchCount("a"); count != 1 {
+		t.Errorf("expected 1 watcher on fresh entry, got %d", count)
+	}
+}
+
+// TestCore_BroadcastAllocationFree 确保广播热路径不分配内存:
+// 监听器与处理器均为写时复制快照, 异步投递写入预分配的环形队列
+func TestCore_BroadcastAllocationFree(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		return nil
+	})
+	for i := 0; i < 10; i++ {
+		b.Watch("test", &TestUniquer{data: TestUniqueData{ID: i}})
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { b.Broadcast("test", nil) }); allocs != 0 {
+		t.Errorf("sync broadcast allocated %.1f times per call", allocs)
+	}
+
+	b.EnableAsync(AsyncConfig{QueueSize: 1024})
+	defer b.Close()
+	if allocs := testing.AllocsPerRun(100, func() { b.Broadcast("test", nil) }); allocs != 0 {
+		t.Errorf("async broadcast allocated %.1f times per call", allocs)
+	}
+}
+
+func BenchmarkCore_AsyncBroadcastAllocs(b *testing.B) {
+	br := NewUnique[int, TestUniqueData]()
+	br.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		return nil
+	})
+	for i := 0; i < 100; i++ {
+		br.Watch("test", &TestUniquer{data: TestUniqueData{ID: i}})
+	}
+	br.EnableAsync(AsyncConfig{QueueSize: 4096, Overflow: Block})
+	defer br.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		br.Broadcast("test", nil)
+	}
+}
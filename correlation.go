@@ -0,0 +1,72 @@
+package broadcast
+
+import (
+	"context"
+)
+
+// MetadataCorrelationID 是 metadata 中保存关联 ID 的键
+const MetadataCorrelationID = "correlation_id"
+
+type correlationKey struct{}
+
+// ContextWithCorrelationID 返回携带关联 ID 的 ctx
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID 返回 ctx 中的关联 ID, 不存在时为空
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// CorrelationIDFrom 返回 metadata 中的关联 ID, 不存在时为空
+func CorrelationIDFrom(metadata map[string]interface{}) string {
+	id, _ := metadata[MetadataCorrelationID].(string)
+	return id
+}
+
+// WithCorrelationID 将关联 ID 写入本次广播的 metadata, id 为空时不做任何事
+// 传入的 metadata 不会被修改, 而是复制后再添加
+func WithCorrelationID(id string) BroadcastOption {
+	return func(o *broadcastOptions) {
+		if id != "" {
+			o.correlation = id
+		}
+	}
+}
+
+// Correlate 将处理器收到的 metadata 中的关联 ID 传递给嵌套广播, 用于串联多跳事件链:
+//
+//	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+//		return b.Broadcast("order.shipped", nil, broadcast.Correlate(metadata))
+//	})
+func Correlate(metadata map[string]interface{}) BroadcastOption {
+	return WithCorrelationID(CorrelationIDFrom(metadata))
+}
+
+// CorrelateContext 将 ctx 中的关联 ID 传递给广播
+func CorrelateContext(ctx context.Context) BroadcastOption {
+	return WithCorrelationID(CorrelationID(ctx))
+}
+
+// ContextFromMetadata 返回携带 metadata 中关联 ID 的 ctx, 用于在处理器中继续调用基于 context 的代码
+func ContextFromMetadata(ctx context.Context, metadata map[string]interface{}) context.Context {
+	if id := CorrelationIDFrom(metadata); id != "" {
+		return ContextWithCorrelationID(ctx, id)
+	}
+	return ctx
+}
+
+// withCorrelation 返回添加了关联 ID 的 metadata 副本, 已有关联 ID 时保持不变
+func withCorrelation(metadata map[string]interface{}, id string) map[string]interface{} {
+	if _, ok := metadata[MetadataCorrelationID]; ok {
+		return metadata
+	}
+	md := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		md[k] = v
+	}
+	md[MetadataCorrelationID] = id
+	return md
+}
@@ -0,0 +1,61 @@
+package broadcast
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCorrelation_PropagatesThroughNestedBroadcasts(t *testing.T) {
+	b := New[string]()
+	b.Watch("order.created", "svc")
+	b.Watch("order.paid", "svc")
+	b.Watch("order.shipped", "svc")
+
+	next := map[string]string{"order.created": "order.paid", "order.paid": "order.shipped"}
+	seen := make(map[string]string)
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		seen[signal] = CorrelationIDFrom(metadata)
+		if n, ok := next[signal]; ok {
+			return b.Broadcast(n, map[string]interface{}{"hop": signal}, Correlate(metadata))
+		}
+		return nil
+	})
+
+	ctx := ContextWithCorrelationID(context.Background(), "req-42")
+	md := map[string]interface{}{"user": "alice"}
+	if err := b.Broadcast("order.created", md, CorrelateContext(ctx)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, signal := range []string{"order.created", "order.paid", "order.shipped"} {
+		if seen[signal] != "req-42" {
+			t.Errorf("expected %s to carry req-42, got %q", signal, seen[signal])
+		}
+	}
+	if _, ok := md[MetadataCorrelationID]; ok {
+		t.Error("expected the caller's metadata not to be modified")
+	}
+}
+
+func TestCorrelation_KeepsExistingID(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	var got string
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		got = CorrelationIDFrom(metadata)
+		return nil
+	})
+	b.Broadcast("test", map[string]interface{}{MetadataCorrelationID: "original"}, WithCorrelationID("other"))
+	if got != "original" {
+		t.Errorf("expected the existing ID to be kept, got %q", got)
+	}
+
+	b.Broadcast("test", nil, CorrelateContext(context.Background()))
+	if got != "" {
+		t.Errorf("expected no ID without a correlated context, got %q", got)
+	}
+	if ctx := ContextFromMetadata(context.Background(), map[string]interface{}{MetadataCorrelationID: "x"}); CorrelationID(ctx) != "x" {
+		t.Error("expected ContextFromMetadata to carry the ID")
+	}
+}
@@ -0,0 +1,96 @@
+package broadcast
+
+import (
+	"context"
+	"time"
+)
+
+// ContextExtractor 从 ctx 中读取值并写入广播的 metadata, 由 BroadcastCtx 调用
+// metadata 是本次广播独有的副本, 可以直接修改
+type ContextExtractor func(ctx context.Context, metadata map[string]interface{})
+
+// ExtractValue 返回将 ctx.Value(key) 写入 metadata[name] 的提取器, 值为 nil 时不写入
+func ExtractValue(key any, name string) ContextExtractor {
+	return func(ctx context.Context, metadata map[string]interface{}) {
+		if v := ctx.Value(key); v != nil {
+			metadata[name] = v
+		}
+	}
+}
+
+// ExtractDeadline 返回将 ctx 的截止时间写入 metadata[name] 的提取器, 没有截止时间时不写入
+func ExtractDeadline(name string) ContextExtractor {
+	return func(ctx context.Context, metadata map[string]interface{}) {
+		if deadline, ok := ctx.Deadline(); ok {
+			metadata[name] = deadline
+		}
+	}
+}
+
+// ExtractCorrelationID 返回将 ctx 中的关联 ID 写入 metadata 的提取器, 键为 MetadataCorrelationID
+func ExtractCorrelationID() ContextExtractor {
+	return func(ctx context.Context, metadata map[string]interface{}) {
+		if id := CorrelationID(ctx); id != "" {
+			metadata[MetadataCorrelationID] = id
+		}
+	}
+}
+
+// MetadataDeadline 是 ExtractDeadline 的常用键, 值为 time.Time
+const MetadataDeadline = "deadline"
+
+// DeadlineFrom 返回 metadata 中由 ExtractDeadline(MetadataDeadline) 写入的截止时间
+func DeadlineFrom(metadata map[string]interface{}) (time.Time, bool) {
+	deadline, ok := metadata[MetadataDeadline].(time.Time)
+	return deadline, ok
+}
+
+// useContextExtractor 追加一个提取器, 按添加顺序执行
+func (c *core[K, T]) useContextExtractor(e ContextExtractor) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.extractors = append(s.extractors[:len(s.extractors):len(s.extractors)], e)
+	})
+}
+
+// broadcastCtx 在 ctx 未结束时广播, 广播前依次执行提取器
+// 调用方传入的 metadata 不会被修改, 提取器写入的是其副本, 已有的键不会被覆盖
+func (c *core[K, T]) broadcastCtx(ctx context.Context, signal string, metadata map[string]interface{}, opts ...BroadcastOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if extractors := c.loadSettings().extractors; len(extractors) > 0 {
+		extracted := make(map[string]interface{}, len(metadata)+len(extractors))
+		for _, e := range extractors {
+			e(ctx, extracted)
+		}
+		for k, v := range metadata {
+			extracted[k] = v
+		}
+		metadata = extracted
+	}
+	return c.send(ctx, signal, metadata, opts)
+}
+
+// UseContextExtractor 追加一个 BroadcastCtx 使用的提取器, 按添加顺序执行
+func (b *Broadcast[T]) UseContextExtractor(e ContextExtractor) {
+	b.c().useContextExtractor(e)
+}
+
+// BroadcastCtx 广播一个信号, 并通过 UseContextExtractor 注册的提取器将 ctx 中的请求 ID、身份、截止时间等写入 metadata
+// ctx 已结束时返回 ctx.Err() 且不广播; 显式传入的 metadata 优先于提取的值.
+// ctx 同时交给 HandleCtx 注册的处理器
+func (b *Broadcast[T]) BroadcastCtx(ctx context.Context, signal string, metadata map[string]interface{}, opts ...BroadcastOption) error {
+	return b.c().broadcastCtx(ctx, b.sig(signal), metadata, opts...)
+}
+
+// UseContextExtractor 追加一个 BroadcastCtx 使用的提取器, 按添加顺序执行
+func (b *UniqueBroadcast[K, T]) UseContextExtractor(e ContextExtractor) {
+	b.core.useContextExtractor(e)
+}
+
+// BroadcastCtx 广播一个信号, 并通过 UseContextExtractor 注册的提取器将 ctx 中的请求 ID、身份、截止时间等写入 metadata
+// ctx 已结束时返回 ctx.Err() 且不广播; 显式传入的 metadata 优先于提取的值.
+// ctx 同时交给 HandleCtx 注册的处理器
+func (b *UniqueBroadcast[K, T]) BroadcastCtx(ctx context.Context, signal string, metadata map[string]interface{}, opts ...BroadcastOption) error {
+	return b.core.broadcastCtx(ctx, signal, metadata, opts...)
+}
@@ -0,0 +1,60 @@
+package broadcast
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type principalKey struct{}
+
+func TestBroadcastCtx_ExtractsValues(t *testing.T) {
+	b := New[string](WithContextExtractor(ExtractCorrelationID()))
+	b.UseContextExtractor(ExtractValue(principalKey{}, "principal"))
+	b.UseContextExtractor(ExtractDeadline(MetadataDeadline))
+	b.Watch("test", "a")
+
+	var got map[string]interface{}
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		got = metadata
+		return nil
+	})
+
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	ctx = ContextWithCorrelationID(context.WithValue(ctx, principalKey{}, "alice"), "req-1")
+
+	md := map[string]interface{}{"principal": "explicit"}
+	if err := b.BroadcastCtx(ctx, "test", md); err != nil {
+		t.Fatal(err)
+	}
+	if CorrelationIDFrom(got) != "req-1" || got["principal"] != "explicit" {
+		t.Errorf("unexpected metadata %v", got)
+	}
+	if d, ok := DeadlineFrom(got); !ok || !d.Equal(deadline) {
+		t.Errorf("expected the deadline to be extracted, got %v", d)
+	}
+	if len(md) != 1 {
+		t.Error("expected the caller's metadata not to be modified")
+	}
+}
+
+func TestBroadcastCtx_CanceledContext(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+	calls := 0
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		calls++
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.BroadcastCtx(ctx, "test", nil); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if err := b.BroadcastCtx(context.Background(), "test", nil); err != nil || calls != 1 {
+		t.Errorf("expected a plain broadcast without extractors, got %v and %d calls", err, calls)
+	}
+}
@@ -0,0 +1,53 @@
+package broadcast
+
+// DataHandler 是同时接收监听器数据和广播时负载的处理器
+// 由普通 Broadcast 触发或负载类型不是 P 时, payload 为 P 的零值
+type DataHandler[T any, P any] func(signal string, data T, payload P, metadata map[string]interface{}) error
+
+// DataPublisher 是可以携带广播时负载的广播器, 由 Broadcast 与 UniqueBroadcast 实现
+type DataPublisher interface {
+	publishData(signal string, payload any, metadata map[string]interface{}) error
+}
+
+// DataSubscriber 是可以注册 DataHandler 的广播器, 由 Broadcast 与 UniqueBroadcast 实现
+type DataSubscriber[T any] interface {
+	handleData(handler dataHandlerFunc[T]) HandlerID
+}
+
+// BroadcastData 广播一个信号, 并将 payload 传递给通过 HandleData 注册的处理器
+// 用于传递每次广播不同的类型化数据, 而不是通过 metadata 传递无类型的值
+func BroadcastData[P any](b DataPublisher, signal string, payload P, metadata map[string]interface{}) error {
+	return b.publishData(signal, payload, metadata)
+}
+
+// HandleData 注册一个接收广播时负载的处理器
+func HandleData[T any, P any](b DataSubscriber[T], handler DataHandler[T, P]) HandlerID {
+	return b.handleData(func(signal string, data T, payload any, metadata map[string]interface{}) error {
+		p, _ := payload.(P)
+		return handler(signal, data, p, metadata)
+	})
+}
+
+func (c *core[K, T]) publishData(signal string, payload any, metadata map[string]interface{}) error {
+	return c.publish(delivery[K, T]{signal: signal, metadata: metadata, payload: payload})
+}
+
+func (c *core[K, T]) handleData(prefix string, handler dataHandlerFunc[T]) HandlerID {
+	return c.addHandler(handlerEntry[T]{dataFn: handler, prefix: prefix})
+}
+
+func (b *Broadcast[T]) publishData(signal string, payload any, metadata map[string]interface{}) error {
+	return b.c().publishData(b.sig(signal), payload, metadata)
+}
+
+func (b *Broadcast[T]) handleData(handler dataHandlerFunc[T]) HandlerID {
+	return b.c().handleData(b.prefix(), handler)
+}
+
+func (b *UniqueBroadcast[K, T]) publishData(signal string, payload any, metadata map[string]interface{}) error {
+	return b.core.publishData(signal, payload, metadata)
+}
+
+func (b *UniqueBroadcast[K, T]) handleData(handler dataHandlerFunc[T]) HandlerID {
+	return b.core.handleData("", handler)
+}
@@ -0,0 +1,59 @@
+package broadcast
+
+import (
+	"testing"
+)
+
+type priceTick struct {
+	Symbol string
+	Price  float64
+}
+
+func TestBroadcastData(t *testing.T) {
+	b := New[string]()
+	b.Watch("price", "alice")
+	b.Watch("price", "bob")
+
+	got := make(map[string]float64)
+	HandleData(b, func(signal string, user string, tick priceTick, metadata map[string]interface{}) error {
+		got[user] = tick.Price
+		return nil
+	})
+
+	plain := 0
+	b.Handle(func(signal string, user string, metadata map[string]interface{}) error {
+		plain++
+		return nil
+	})
+
+	BroadcastData(b, "price", priceTick{Symbol: "AAPL", Price: 190.5}, nil)
+	if got["alice"] != 190.5 || got["bob"] != 190.5 {
+		t.Errorf("expected both listeners to receive the payload, got %v", got)
+	}
+	if plain != 2 {
+		t.Errorf("plain handlers should still run, got %d calls", plain)
+	}
+
+	// 普通广播时负载为零值
+	b.Broadcast("price", nil)
+	if got["alice"] != 0 {
+		t.Errorf("expected zero payload for plain Broadcast, got %v", got["alice"])
+	}
+}
+
+func TestBroadcastData_Unique(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1, Name: "one"}})
+
+	var name string
+	var count int
+	HandleData(b, func(signal string, data TestUniqueData, payload int, metadata map[string]interface{}) error {
+		name, count = data.Name, payload
+		return nil
+	})
+
+	BroadcastData(b, "test", 3, nil)
+	if name != "one" || count != 3 {
+		t.Errorf("expected listener data and payload, got name=%q payload=%d", name, count)
+	}
+}
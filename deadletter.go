@@ -0,0 +1,120 @@
+package broadcast
+
+import (
+	"sync"
+	"time"
+)
+
+// SignalDLQOverflow 是死信队列溢出时广播的元事件信号
+// metadata["dead_letter"] 为被挤出队列的 DeadLetter, metadata["signal"] 为其原始信号
+const SignalDLQOverflow = "dlq.overflow"
+
+// DeadLetter 是一次失败的投递
+type DeadLetter[K comparable, T any] struct {
+	Seq       uint64
+	Signal    string
+	Key       K
+	Data      T
+	Metadata  map[string]interface{}
+	HandlerID HandlerID
+	// HandlerName 为处理器通过 WithName 设置的名称
+	HandlerName string
+	Err         error
+	Time        time.Time
+}
+
+// DeadLetterConfig 死信队列配置
+type DeadLetterConfig[K comparable, T any] struct {
+	// Size 队列容量, 默认为 1024; 队列满时最早的死信被挤出
+	Size int
+	// Spill 非 nil 时接收被挤出的死信, 例如写入持久化存储
+	Spill func(dl DeadLetter[K, T]) error
+}
+
+// deadLetterQueue 是有界的死信环形队列
+type deadLetterQueue[K comparable, T any] struct {
+	mu     sync.Mutex
+	items  []DeadLetter[K, T]
+	head   int
+	size   int
+	config DeadLetterConfig[K, T]
+}
+
+func newDeadLetterQueue[K comparable, T any](config DeadLetterConfig[K, T]) *deadLetterQueue[K, T] {
+	if config.Size <= 0 {
+		config.Size = 1024
+	}
+	return &deadLetterQueue[K, T]{
+		items:  make([]DeadLetter[K, T], config.Size),
+		config: config,
+	}
+}
+
+// push 放入一条死信, 队列已满时返回被挤出的死信
+func (q *deadLetterQueue[K, T]) push(dl DeadLetter[K, T]) (DeadLetter[K, T], bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var (
+		evicted  DeadLetter[K, T]
+		overflow bool
+	)
+	if q.size == len(q.items) {
+		evicted, overflow = q.items[q.head], true
+		q.head = (q.head + 1) % len(q.items)
+		q.size--
+	}
+	q.items[(q.head+q.size)%len(q.items)] = dl
+	q.size++
+	return evicted, overflow
+}
+
+// list 按进入队列的顺序返回死信, drain 为 true 时清空队列
+func (q *deadLetterQueue[K, T]) list(drain bool) []DeadLetter[K, T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	result := make([]DeadLetter[K, T], q.size)
+	for i := range result {
+		result[i] = q.items[(q.head+i)%len(q.items)]
+	}
+	if drain {
+		clear(q.items)
+		q.head, q.size = 0, 0
+	}
+	return result
+}
+
+// deadLetter 将失败的投递放入死信队列, 溢出时转存并广播 SignalDLQOverflow
+func (c *core[K, T]) deadLetter(q *deadLetterQueue[K, T], dl DeadLetter[K, T]) {
+	// 元事件自身的失败不进入死信队列, 避免递归
+	if dl.Signal == SignalDLQOverflow {
+		return
+	}
+
+	evicted, overflow := q.push(dl)
+	if !overflow {
+		return
+	}
+	if q.config.Spill != nil {
+		_ = q.config.Spill(evicted)
+	}
+	_ = c.broadcast(SignalDLQOverflow, map[string]interface{}{
+		"dead_letter": evicted,
+		"signal":      evicted.Signal,
+	})
+}
+
+func (c *core[K, T]) enableDeadLetter(config DeadLetterConfig[K, T]) {
+	q := newDeadLetterQueue(config)
+	c.updateSettings(func(s *settings[K, T]) {
+		s.dlq = q
+	})
+}
+
+func (c *core[K, T]) deadLetters(drain bool) []DeadLetter[K, T] {
+	if q := c.loadSettings().dlq; q != nil {
+		return q.list(drain)
+	}
+	return nil
+}
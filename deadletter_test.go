@@ -0,0 +1,74 @@
+package broadcast
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDeadLetter_BoundedWithOverflowSignal(t *testing.T) {
+	b := New[string]()
+	failure := errors.New("boom")
+
+	var spilled []uint64
+	b.EnableDeadLetter(DeadLetterConfig[string, string]{
+		Size: 2,
+		Spill: func(dl DeadLetter[string, string]) error {
+			spilled = append(spilled, dl.Seq)
+			return nil
+		},
+	})
+
+	var overflows []string
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		if signal == SignalDLQOverflow {
+			overflows = append(overflows, metadata["signal"].(string))
+			return errors.New("meta handler failures are not dead-lettered")
+		}
+		if data == "bad" {
+			return failure
+		}
+		return nil
+	})
+	b.Watch("orders", "good")
+	b.Watch("orders", "bad")
+	b.Watch(SignalDLQOverflow, "ops")
+
+	for i := 0; i < 3; i++ {
+		b.Broadcast("orders", map[string]interface{}{"n": i})
+	}
+
+	letters := b.DeadLetters()
+	if len(letters) != 2 {
+		t.Fatalf("expected DLQ bounded at 2, got %d", len(letters))
+	}
+	if letters[0].Metadata["n"] != 1 || letters[1].Metadata["n"] != 2 {
+		t.Errorf("expected the 2 most recent dead letters, got %+v", letters)
+	}
+	if letters[0].Key != "bad" || letters[0].Data != "bad" || !errors.Is(letters[0].Err, failure) {
+		t.Errorf("dead letter missing delivery details: %+v", letters[0])
+	}
+
+	if len(spilled) != 1 || len(overflows) != 1 || overflows[0] != "orders" {
+		t.Errorf("expected one spill and one overflow event, got spilled=%v overflows=%v", spilled, overflows)
+	}
+
+	if drained := b.DrainDeadLetters(); len(drained) != 2 {
+		t.Errorf("expected to drain 2 dead letters, got %d", len(drained))
+	}
+	if len(b.DeadLetters()) != 0 {
+		t.Error("DLQ should be empty after drain")
+	}
+}
+
+func TestDeadLetter_Disabled(t *testing.T) {
+	b := New[string]()
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		return errors.New("boom")
+	})
+	b.Watch("test", "data")
+	b.Broadcast("test", nil)
+
+	if letters := b.DeadLetters(); letters != nil {
+		t.Errorf("expected no dead letters without a DLQ, got %v", letters)
+	}
+}
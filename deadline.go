@@ -0,0 +1,61 @@
+package broadcast
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// WithDeadline 限制整个扇出的截止时间: 超过 t 之后不再开始新的处理器调用,
+// 已经开始的调用不会被中断. 有调用因此未执行时, 同步广播返回的错误包含 *DeadlineError.
+// 异步投递时 t 同时作为事件在队列中的存活期限, 超时信息无法返回给调用方
+func WithDeadline(t time.Time) BroadcastOption {
+	return func(o *broadcastOptions) {
+		o.deadline = t
+	}
+}
+
+// SkippedDelivery 是因超过截止时间而未执行的一次处理器调用
+type SkippedDelivery struct {
+	HandlerID HandlerID
+	Key       any
+}
+
+// DeadlineError 描述一次超过截止时间的广播中未执行的调用, 其余调用均已完成
+// errors.Is(err, ErrDeadlineExceeded) 与 errors.Is(err, context.DeadlineExceeded) 均成立
+type DeadlineError struct {
+	Signal   string
+	Seq      uint64
+	Deadline time.Time
+	Skipped  []SkippedDelivery
+}
+
+func (e *DeadlineError) Error() string {
+	return fmt.Sprintf("broadcast: deadline exceeded for signal %q, %d deliveries skipped", e.Signal, len(e.Skipped))
+}
+
+func (e *DeadlineError) Unwrap() []error {
+	return []error{ErrDeadlineExceeded, context.DeadlineExceeded}
+}
+
+// skipDelivery 记录一次未执行的调用, e 为 nil 时创建新的 DeadlineError
+func skipDelivery[K comparable, T any](e *DeadlineError, d *delivery[K, T], id HandlerID, key any) *DeadlineError {
+	if e == nil {
+		e = &DeadlineError{Signal: d.signal, Seq: d.seq, Deadline: d.cutoff}
+	}
+	e.Skipped = append(e.Skipped, SkippedDelivery{HandlerID: id, Key: key})
+	return e
+}
+
+// skipped 报告处理器是否有未执行的调用
+func (e *DeadlineError) skipped(id HandlerID) bool {
+	return e != nil && slices.ContainsFunc(e.Skipped, func(s SkippedDelivery) bool {
+		return s.HandlerID == id
+	})
+}
+
+// overdue 报告投递是否已超过 WithDeadline 设置的截止时间
+func (c *core[K, T]) overdue(d *delivery[K, T]) bool {
+	return !d.cutoff.IsZero() && c.clock().Now().After(d.cutoff)
+}
@@ -0,0 +1,81 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithDeadline_PartialDelivery(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b := NewUnique[int, string](WithClock(clock))
+	for i := 1; i <= 4; i++ {
+		b.Watch("fanout", numbered{i, "v"})
+	}
+
+	var handled []string
+	id := b.Handle(func(signal string, data string, _ map[string]interface{}) error {
+		handled = append(handled, data)
+		// 每次调用耗时 1 秒
+		clock.now = clock.now.Add(time.Second)
+		return nil
+	})
+
+	err := b.Broadcast("fanout", nil, WithDeadline(clock.now.Add(1500*time.Millisecond)))
+	if !errors.Is(err, ErrDeadlineExceeded) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline error, got %v", err)
+	}
+	var de *DeadlineError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected *DeadlineError, got %T", err)
+	}
+	if len(handled) != 2 || len(de.Skipped) != 2 {
+		t.Fatalf("expected 2 delivered and 2 skipped, got %d and %d", len(handled), len(de.Skipped))
+	}
+	if de.Signal != "fanout" || de.Skipped[0] != (SkippedDelivery{HandlerID: id, Key: 3}) || de.Skipped[1].Key != 4 {
+		t.Errorf("unexpected skipped deliveries: %+v", de)
+	}
+
+	handled = nil
+	if err := b.Broadcast("fanout", nil, WithDeadline(clock.now.Add(time.Hour))); err != nil {
+		t.Errorf("expected no error within the deadline, got %v", err)
+	}
+	if len(handled) != 4 {
+		t.Errorf("expected all listeners within the deadline, got %d", len(handled))
+	}
+}
+
+func TestWithDeadline_Unordered(t *testing.T) {
+	b := New[int]()
+	b.SetParallel(2)
+	for i := 0; i < 8; i++ {
+		b.Watch("fanout", i)
+	}
+	b.Handle(func(signal string, data int, _ map[string]interface{}) error {
+		time.Sleep(60 * time.Millisecond)
+		return nil
+	})
+
+	err := b.Broadcast("fanout", nil, WithDeadline(time.Now().Add(100*time.Millisecond)))
+	var de *DeadlineError
+	if !errors.As(err, &de) || len(de.Skipped) == 0 || len(de.Skipped) == 8 {
+		t.Fatalf("expected some deliveries to be skipped, got %v", err)
+	}
+}
+
+func TestWithDeadline_ExpiresQueuedEvents(t *testing.T) {
+	b := New[string]()
+	b.Watch("s", "a")
+	b.Handle(func(signal string, data string, _ map[string]interface{}) error { return nil })
+	b.EnableAsync(AsyncConfig{Workers: 1})
+	defer b.Close()
+
+	if err := b.Broadcast("s", nil, WithDeadline(time.Now().Add(-time.Second))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b.Close()
+	if b.Expired() != 1 {
+		t.Errorf("expected the late event to expire in the queue, got %d", b.Expired())
+	}
+}
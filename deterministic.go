@@ -0,0 +1,39 @@
+package broadcast
+
+import (
+	"math/rand/v2"
+)
+
+// deterministicMode 确定性投递模式
+// 开启后忽略异步配置, 所有投递在调用方 goroutine 中同步执行,
+// 处理器按注册顺序执行, 监听器按注册顺序或按种子确定的顺序接收事件
+type deterministicMode struct {
+	seed uint64
+}
+
+// deterministicOrder 返回本次投递的监听器顺序
+// seed 为 0 时保持注册顺序, 否则由 (seed, 事件序号) 决定一个可复现的排列
+func deterministicOrder[K comparable, T any](seed, seq uint64, listeners []listener[K, T]) []listener[K, T] {
+	if seed == 0 || len(listeners) < 2 {
+		return listeners
+	}
+
+	// 快照不可修改, 在副本上打乱
+	ordered := make([]listener[K, T], len(listeners))
+	copy(ordered, listeners)
+	r := rand.New(rand.NewPCG(seed, seq))
+	r.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	return ordered
+}
+
+func (c *core[K, T]) setDeterministic(enabled bool, seed uint64) {
+	c.updateSettings(func(s *settings[K, T]) {
+		if enabled {
+			s.deterministic = &deterministicMode{seed: seed}
+		} else {
+			s.deterministic = nil
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package broadcast
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// deliveryOrder 返回一次广播中 "处理器:监听器" 的投递顺序
+func deliveryOrder(seed uint64, async bool) []string {
+	b := New[int]()
+	var order []string
+	for h := 0; h < 2; h++ {
+		b.Handle(func(signal string, data int, metadata map[string]interface{}) error {
+			order = append(order, fmt.Sprintf("%d:%d", h, data))
+			return nil
+		})
+	}
+	for i := 0; i < 8; i++ {
+		b.Watch("test", i)
+	}
+	if async {
+		b.EnableAsync(AsyncConfig{Workers: 4})
+		defer b.Close()
+	}
+
+	b.SetDeterministic(true, seed)
+	b.Broadcast("test", nil)
+	return order
+}
+
+func TestDeterministic_RegistrationOrder(t *testing.T) {
+	order := deliveryOrder(0, true)
+
+	var want []string
+	for h := 0; h < 2; h++ {
+		for i := 0; i < 8; i++ {
+			want = append(want, fmt.Sprintf("%d:%d", h, i))
+		}
+	}
+	if !slices.Equal(order, want) {
+		t.Errorf("expected registration order %v, got %v", want, order)
+	}
+}
+
+func TestDeterministic_Seeded(t *testing.T) {
+	first := deliveryOrder(42, false)
+	second := deliveryOrder(42, false)
+	if !slices.Equal(first, second) {
+		t.Errorf("same seed should reproduce the same order:\n%v\n%v", first, second)
+	}
+
+	if slices.Equal(first, deliveryOrder(0, false)) && slices.Equal(first, deliveryOrder(7, false)) {
+		t.Error("seeds should permute listener order")
+	}
+}
+
+func TestDeterministic_DoesNotModifySnapshot(t *testing.T) {
+	b := New[int]()
+	for i := 0; i < 8; i++ {
+		b.Watch("test", i)
+	}
+	b.SetDeterministic(true, 1)
+	b.Broadcast("test", nil)
+
+	for i, l := range b.core.snapshot("test") {
+		if l.data.Value() != i {
+			t.Fatalf("listener snapshot reordered at %d", i)
+		}
+	}
+}
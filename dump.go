@@ -0,0 +1,118 @@
+package broadcast
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// StateDump 是 DumpState 输出的状态快照
+type StateDump struct {
+	Signals     []SignalDump  `json:"signals"`
+	Handlers    []HandlerInfo `json:"handlers"`
+	Pending     int           `json:"pending"`
+	Buffered    int           `json:"buffered"`
+	DeadLetters int           `json:"dead_letters"`
+	Expired     uint64        `json:"expired"`
+	Paused      []string      `json:"paused,omitempty"`
+}
+
+// SignalDump 是单个信号的状态
+type SignalDump struct {
+	Signal    string   `json:"signal"`
+	Listeners int      `json:"listeners"`
+	Keys      []string `json:"keys"`
+}
+
+// DumpOption 是 DumpState 的可选参数
+type DumpOption func(o *dumpOptions)
+
+type dumpOptions struct {
+	json bool
+}
+
+// DumpJSON 以 JSON 格式输出 StateDump, 便于交给其他工具处理
+func DumpJSON() DumpOption {
+	return func(o *dumpOptions) {
+		o.json = true
+	}
+}
+
+// dump 收集状态快照, prefix 非空时只包含该前缀下的信号与处理器, 信号名去掉前缀
+func (c *core[K, T]) dump(prefix string) StateDump {
+	state := StateDump{
+		Handlers:    c.handlerInfos(prefix),
+		Pending:     c.pending(),
+		Buffered:    c.buffered(),
+		DeadLetters: len(c.deadLetters(false)),
+		Expired:     c.expired.Load(),
+		Paused:      c.pausedSignals(prefix),
+	}
+	for _, signal := range c.signals(prefix) {
+		listeners := c.snapshot(prefix + signal)
+		keys := make([]string, len(listeners))
+		for i, l := range listeners {
+			keys[i] = fmt.Sprint(l.key.Value())
+		}
+		state.Signals = append(state.Signals, SignalDump{Signal: signal, Listeners: len(listeners), Keys: keys})
+	}
+	return state
+}
+
+// dumpState 将状态快照写入 w
+func (c *core[K, T]) dumpState(w io.Writer, prefix string, opts []DumpOption) error {
+	var o dumpOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	state := c.dump(prefix)
+	if o.json {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(state)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "signals: %d\n", len(state.Signals))
+	for _, s := range state.Signals {
+		fmt.Fprintf(tw, "  %s\t%d listeners\t%v\n", s.Signal, s.Listeners, s.Keys)
+	}
+	fmt.Fprintf(tw, "handlers: %d\n", len(state.Handlers))
+	for _, h := range state.Handlers {
+		name := h.Name
+		if name == "" {
+			name = "(unnamed)"
+		}
+		fmt.Fprintf(tw, "  #%d\t%s\n", h.ID, name)
+	}
+	fmt.Fprintf(tw, "pending: %d\nbuffered: %d\ndead letters: %d\nexpired: %d\n",
+		state.Pending, state.Buffered, state.DeadLetters, state.Expired)
+	if len(state.Paused) > 0 {
+		fmt.Fprintf(tw, "paused: %v\n", state.Paused)
+	}
+	return tw.Flush()
+}
+
+// DumpState 写出所有信号、监听器 key、处理器名称与待投递队列的可读报告, 用于排查意外的广播行为
+// 传入 DumpJSON 时以 JSON 格式输出
+func (b *Broadcast[T]) DumpState(w io.Writer, opts ...DumpOption) error {
+	return b.c().dumpState(w, b.prefix(), opts)
+}
+
+// DumpState 写出所有信号、监听器 key、处理器名称与待投递队列的可读报告, 用于排查意外的广播行为
+// 传入 DumpJSON 时以 JSON 格式输出
+func (b *UniqueBroadcast[K, T]) DumpState(w io.Writer, opts ...DumpOption) error {
+	return b.core.dumpState(w, "", opts)
+}
+
+// State 返回 DumpState 输出的状态快照, 用于在程序中检查运行时状态
+func (b *Broadcast[T]) State() StateDump {
+	return b.c().dump(b.prefix())
+}
+
+// State 返回 DumpState 输出的状态快照, 用于在程序中检查运行时状态
+func (b *UniqueBroadcast[K, T]) State() StateDump {
+	return b.core.dump("")
+}
@@ -0,0 +1,55 @@
+package broadcast
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDumpState_Text(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	b.Watch("user.login", &TestUniquer{data: TestUniqueData{ID: 1}})
+	b.Watch("user.login", &TestUniquer{data: TestUniqueData{ID: 2}})
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		return nil
+	}, WithName("audit-logger"))
+
+	var buf bytes.Buffer
+	if err := b.DumpState(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"signals: 1", "user.login", "2 listeners", "[1 2]", "audit-logger", "pending: 0"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the dump to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestDumpState_JSON(t *testing.T) {
+	b := New[string]()
+	b.Watch("other", "x")
+	ns := b.Namespace("app.")
+	ns.Watch("start", "a")
+	ns.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		return nil
+	})
+	b.SetPendingBuffer(4)
+	ns.Broadcast("idle", nil)
+
+	var buf bytes.Buffer
+	if err := ns.DumpState(&buf, DumpJSON()); err != nil {
+		t.Fatal(err)
+	}
+	var state StateDump
+	if err := json.Unmarshal(buf.Bytes(), &state); err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Signals) != 1 || state.Signals[0].Signal != "start" || state.Signals[0].Keys[0] != "a" {
+		t.Errorf("expected only the namespace's signals, got %+v", state.Signals)
+	}
+	if len(state.Handlers) != 1 || state.Buffered != 1 {
+		t.Errorf("unexpected state %+v", state)
+	}
+}
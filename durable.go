@@ -0,0 +1,267 @@
+package broadcast
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CursorStore 保存持久处理器的消费进度
+type CursorStore interface {
+	// LoadCursor 返回处理器已确认的最大连续序号, 不存在时为 0
+	LoadCursor(name string) (uint64, error)
+	// SaveCursor 保存处理器的消费进度
+	SaveCursor(name string, seq uint64) error
+}
+
+// DurableConfig 持久处理器配置
+type DurableConfig struct {
+	// Name 处理器在重启之间保持不变的名称
+	Name string
+	// Cursors 保存消费进度
+	Cursors CursorStore
+	// RetryInterval 处理器返回错误后重新投递的间隔, 默认为 1s
+	RetryInterval time.Duration
+	// OnError 在保存进度或迁移日志记录失败时调用
+	OnError func(err error)
+	// Dedup 非 nil 时跳过已处理过的事件, 在至少一次投递之上实现恰好一次处理
+	Dedup DedupStore
+}
+
+// durableCursor 跟踪持久处理器在日志上的消费进度
+// 日志中的每个广播在提交时登记为未确认, 处理器对所有监听器都成功后确认;
+// 进度为最早的未确认序号之前的位置, 重启后从进度之后重新投递
+type durableCursor struct {
+	config DurableConfig
+
+	mu       sync.Mutex
+	inflight map[uint64]struct{}
+	highest  uint64
+	saved    uint64
+	// replaying 为 true 时日志尚未回放完, 进度不能超过已回放到的位置 replayed
+	replaying bool
+	replayed  uint64
+}
+
+func (d *durableCursor) begin(seq uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.inflight[seq] = struct{}{}
+	d.highest = max(d.highest, seq)
+}
+
+// replay 登记一个回放的序号
+func (d *durableCursor) replay(seq uint64) {
+	d.begin(seq)
+
+	d.mu.Lock()
+	d.replayed = seq
+	d.mu.Unlock()
+}
+
+// finishReplay 结束回放, 之后进度只受未确认序号限制
+func (d *durableCursor) finishReplay() {
+	d.mu.Lock()
+	d.replaying = false
+	d.mu.Unlock()
+
+	d.ack(0)
+}
+
+// ack 确认一个序号, 进度前移时保存
+func (d *durableCursor) ack(seq uint64) {
+	d.mu.Lock()
+	delete(d.inflight, seq)
+	cursor := d.highest
+	for pending := range d.inflight {
+		cursor = min(cursor, pending-1)
+	}
+	if d.replaying {
+		cursor = min(cursor, d.replayed)
+	}
+	if cursor <= d.saved {
+		d.mu.Unlock()
+		return
+	}
+	d.saved = cursor
+	d.mu.Unlock()
+
+	if err := d.config.Cursors.SaveCursor(d.config.Name, cursor); err != nil && d.config.OnError != nil {
+		d.config.OnError(err)
+	}
+}
+
+// beginDurable 在日志写入后为所有持久处理器登记该序号
+func (c *core[K, T]) beginDurable(seq uint64) {
+	for _, h := range c.loadHandlers() {
+		if h.durable != nil {
+			h.durable.begin(seq)
+		}
+	}
+}
+
+// settleDurable 在一次投递之后确认或安排重新投递
+func (c *core[K, T]) settleDurable(handler handlerEntry[T], d delivery[K, T], failed bool) {
+	if !failed {
+		handler.durable.ack(d.seq)
+		return
+	}
+
+	c.clock().AfterFunc(handler.durable.config.RetryInterval, func() {
+		if !c.hasHandler(handler.id) {
+			// 处理器已移除, 序号保持未确认, 重启后重新投递
+			return
+		}
+		d.handlers = []handlerEntry[T]{handler}
+		d.parts = nil
+		d.listeners = c.snapshot(d.signal)
+		_ = c.deliver(d)
+	})
+}
+
+func (c *core[K, T]) hasHandler(id HandlerID) bool {
+	for _, h := range c.loadHandlers() {
+		if h.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// handleDurable 注册持久处理器, 并从日志中重新投递上次进度之后的广播
+func (c *core[K, T]) handleDurable(config DurableConfig, prefix string, handler handlerFunc[T]) (HandlerID, error) {
+	j := c.loadSettings().journal
+	if j == nil {
+		return 0, ErrNoJournal
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = time.Second
+	}
+
+	from, err := config.Cursors.LoadCursor(config.Name)
+	if err != nil {
+		return 0, err
+	}
+	last, err := j.config.Journal.LastSeq()
+	if err != nil {
+		return 0, err
+	}
+
+	cursor := &durableCursor{
+		config:   config,
+		inflight: make(map[uint64]struct{}),
+		highest:  from,
+		saved:    from,
+		// 回放期间到达的实时广播不能让进度越过尚未回放的记录
+		replaying: true,
+		replayed:  from,
+	}
+	entry := handlerEntry[T]{fn: handler, prefix: prefix, durable: cursor}
+	if config.Dedup != nil {
+		entry.dedup = &IdempotencyConfig{Name: config.Name, Store: config.Dedup}
+	}
+	entry.id = c.addHandler(entry)
+
+	err = j.config.Journal.Replay(from+1, func(e JournalEntry) error {
+		if e.Seq > last {
+			return nil
+		}
+		cursor.replay(e.Seq)
+		d := delivery[K, T]{
+			seq:       e.Seq,
+			id:        e.ID,
+			source:    e.Source,
+			time:      e.Time,
+			signal:    e.Signal,
+			metadata:  e.Metadata,
+			journaled: true,
+			listeners: c.snapshot(e.Signal),
+			handlers:  []handlerEntry[T]{entry},
+		}
+		payload, err := c.journalPayload(j, e)
+		if err != nil {
+			// 无法迁移的记录保持未确认, 进度停在它之前, 重启后重新投递
+			if config.OnError != nil {
+				config.OnError(err)
+			}
+			return nil
+		}
+		d.payload = payload
+		_ = c.deliver(d)
+		return nil
+	})
+	cursor.finishReplay()
+	return entry.id, err
+}
+
+// MemoryCursorStore 是基于内存的 CursorStore, 用于测试
+type MemoryCursorStore struct {
+	mu      sync.Mutex
+	cursors map[string]uint64
+}
+
+// NewMemoryCursorStore 创建内存 CursorStore
+func NewMemoryCursorStore() *MemoryCursorStore {
+	return &MemoryCursorStore{cursors: make(map[string]uint64)}
+}
+
+// LoadCursor 返回处理器的消费进度
+func (s *MemoryCursorStore) LoadCursor(name string) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cursors[name], nil
+}
+
+// SaveCursor 保存处理器的消费进度
+func (s *MemoryCursorStore) SaveCursor(name string, seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursors[name] = seq
+	return nil
+}
+
+// FileCursorStore 将每个处理器的进度保存在目录下的独立文件中, 写入时先写临时文件再重命名
+type FileCursorStore struct {
+	dir string
+}
+
+// NewFileCursorStore 创建保存在 dir 下的 CursorStore, 目录不存在时创建
+func NewFileCursorStore(dir string) (*FileCursorStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileCursorStore{dir: dir}, nil
+}
+
+func (s *FileCursorStore) path(name string) string {
+	return filepath.Join(s.dir, url.PathEscape(name)+".cursor")
+}
+
+// LoadCursor 返回处理器的消费进度
+func (s *FileCursorStore) LoadCursor(name string) (uint64, error) {
+	data, err := os.ReadFile(s.path(name))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// SaveCursor 保存处理器的消费进度
+func (s *FileCursorStore) SaveCursor(name string, seq uint64) error {
+	path := s.path(name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatUint(seq, 10)), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
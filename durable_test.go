@@ -0,0 +1,208 @@
+package broadcast
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDurable_RedeliverAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	cursors, err := NewFileCursorStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal, err := OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := New[string]()
+	if _, err := b.HandleDurable(DurableConfig{Name: "projection", Cursors: cursors}, nil); !errors.Is(err, ErrNoJournal) {
+		t.Errorf("expected ErrNoJournal, got %v", err)
+	}
+	b.EnableJournal(JournalConfig{Journal: journal})
+	b.Watch("orders", "projection")
+
+	// 处理器在第 3 个广播上失败, 模拟未确认时崩溃
+	config := DurableConfig{Name: "projection", Cursors: cursors, RetryInterval: time.Hour}
+	id, err := b.HandleDurable(config, func(signal string, data string, metadata map[string]interface{}) error {
+		if metadata["n"] == 3 {
+			return errors.New("crash")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 4; i++ {
+		b.Broadcast("orders", map[string]interface{}{"n": i})
+	}
+	b.Unhandle(id)
+	journal.Close()
+
+	if cursor, _ := cursors.LoadCursor("projection"); cursor != 2 {
+		t.Errorf("expected the cursor to stop before the unacked event, got %d", cursor)
+	}
+
+	journal, err = OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+	restarted := New[string]()
+	restarted.EnableJournal(JournalConfig{Journal: journal})
+	restarted.Watch("orders", "projection")
+
+	var got []float64
+	_, err = restarted.HandleDurable(config, func(signal string, data string, metadata map[string]interface{}) error {
+		got = append(got, metadata["n"].(float64))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, []float64{3, 4}) {
+		t.Errorf("expected events 3 and 4 to be redelivered, got %v", got)
+	}
+	if cursor, _ := cursors.LoadCursor("projection"); cursor != 4 {
+		t.Errorf("expected the cursor to advance to 4, got %d", cursor)
+	}
+}
+
+func TestDurable_RetryUntilAcked(t *testing.T) {
+	journal, err := OpenFileJournal(t.TempDir(), FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	b := NewUnique[int, TestUniqueData]()
+	b.EnableJournal(JournalConfig{Journal: journal})
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	cursors := NewMemoryCursorStore()
+	var mu sync.Mutex
+	attempts := 0
+	done := make(chan struct{})
+	_, err = b.HandleDurable(DurableConfig{Name: "h", Cursors: cursors, RetryInterval: time.Millisecond},
+		func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts++
+			if attempts < 3 {
+				return errors.New("not yet")
+			}
+			close(done)
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Broadcast("test", nil); err == nil {
+		t.Error("expected the first attempt to fail")
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the event to be redelivered until acked")
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if cursor, _ := cursors.LoadCursor("h"); cursor == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the cursor to advance after the retry succeeded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// gatedDurable 返回启用日志与异步队列的广播器, 持久处理器被 gate 阻塞, 以及收到的 metadata["n"] 列表
+func gatedDurable(t *testing.T, config AsyncConfig, retry time.Duration) (*Broadcast[string], *MemoryCursorStore, chan struct{}, func() []int) {
+	t.Helper()
+
+	journal, err := OpenFileJournal(t.TempDir(), FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { journal.Close() })
+
+	b := New[string]()
+	b.EnableJournal(JournalConfig{Journal: journal})
+	b.Watch("orders", "projection")
+
+	cursors := NewMemoryCursorStore()
+	gate := make(chan struct{})
+	var (
+		mu       sync.Mutex
+		received []int
+	)
+	_, err = b.HandleDurable(DurableConfig{Name: "h", Cursors: cursors, RetryInterval: retry},
+		func(signal string, data string, metadata map[string]interface{}) error {
+			<-gate
+			mu.Lock()
+			received = append(received, metadata["n"].(int))
+			mu.Unlock()
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.EnableAsync(config)
+
+	// 第一个事件被工作 goroutine 取走并阻塞在 gate 上
+	b.Broadcast("orders", map[string]interface{}{"n": 1})
+	waitFor(t, func() bool { return b.Pending() == 0 })
+
+	return b, cursors, gate, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), received...)
+	}
+}
+
+func TestDurable_OverflowRedelivers(t *testing.T) {
+	b, cursors, gate, received := gatedDurable(t, AsyncConfig{QueueSize: 1, Overflow: DropNewest}, time.Millisecond)
+	defer b.Close()
+
+	b.Broadcast("orders", map[string]interface{}{"n": 2})
+	b.Broadcast("orders", map[string]interface{}{"n": 3})
+	if cursor, _ := cursors.LoadCursor("h"); cursor != 0 {
+		t.Errorf("expected the dropped event to stay unacked, got cursor %d", cursor)
+	}
+	close(gate)
+
+	waitFor(t, func() bool {
+		cursor, _ := cursors.LoadCursor("h")
+		return cursor == 3
+	})
+	got := received()
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("expected the dropped event to be redelivered, got %v", got)
+	}
+}
+
+func TestDurable_ExpiredEventAcked(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b, cursors, gate, received := gatedDurable(t, AsyncConfig{QueueSize: 4}, time.Hour)
+	b.SetClock(clock)
+
+	b.Broadcast("orders", map[string]interface{}{"n": 2}, WithEventTTL(time.Second))
+	b.Broadcast("orders", map[string]interface{}{"n": 3})
+	clock.now = clock.now.Add(2 * time.Second)
+	close(gate)
+	b.Close()
+
+	if got := received(); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("expected the expired event to be skipped, got %v", got)
+	}
+	if cursor, _ := cursors.LoadCursor("h"); cursor != 3 {
+		t.Errorf("expected the expired event to be acked, got cursor %d", cursor)
+	}
+}
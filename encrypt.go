@@ -0,0 +1,149 @@
+package broadcast
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// encryptedVersion 是 EncryptedCodec 输出格式的版本号
+const encryptedVersion = 1
+
+// EncryptedCodec 在另一个 Codec 之上使用 AES-GCM 加密负载, 使写入日志或经桥接传输的负载在存储与传输中都是加密的
+// 输出格式为 版本 (1 字节) | 密钥 ID 长度 (1 字节) | 密钥 ID | nonce | 密文, 版本与密钥 ID 作为附加数据参与认证.
+// 加密总是使用当前密钥, 解密按密钥 ID 查找, 因此轮换密钥后旧数据仍可读取, 直到旧密钥被 RemoveKey 移除
+type EncryptedCodec struct {
+	inner Codec
+
+	mu      sync.RWMutex
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewEncryptedCodec 创建以 keyID 对应的 key 加密的 Codec, inner 为 nil 时使用 JSONCodec
+// key 的长度必须为 16、24 或 32 字节, 分别对应 AES-128、AES-192 与 AES-256
+func NewEncryptedCodec(inner Codec, keyID string, key []byte) (*EncryptedCodec, error) {
+	if inner == nil {
+		inner = JSONCodec
+	}
+	c := &EncryptedCodec{inner: inner, keys: make(map[string]cipher.AEAD)}
+	if err := c.Rotate(keyID, key); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func newAEAD(keyID string, key []byte) (cipher.AEAD, error) {
+	if keyID == "" || len(keyID) > 255 {
+		return nil, fmt.Errorf("broadcast: encryption key id must be 1-255 bytes, got %d", len(keyID))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// AddKey 添加一个只用于解密的密钥, 例如轮换之前的旧密钥
+func (c *EncryptedCodec) AddKey(keyID string, key []byte) error {
+	aead, err := newAEAD(keyID, key)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.keys[keyID] = aead
+	return nil
+}
+
+// Rotate 添加密钥并将其设为当前密钥, 之后的 Marshal 使用新密钥, 旧密钥继续用于解密
+func (c *EncryptedCodec) Rotate(keyID string, key []byte) error {
+	aead, err := newAEAD(keyID, key)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.keys[keyID] = aead
+	c.current = keyID
+	return nil
+}
+
+// RemoveKey 移除一个不再需要的旧密钥, 当前密钥不能被移除
+func (c *EncryptedCodec) RemoveKey(keyID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if keyID == c.current {
+		return false
+	}
+	if _, ok := c.keys[keyID]; !ok {
+		return false
+	}
+	delete(c.keys, keyID)
+	return true
+}
+
+// KeyID 返回当前用于加密的密钥 ID
+func (c *EncryptedCodec) KeyID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.current
+}
+
+// Marshal 使用 inner 编码 v 后以当前密钥加密
+func (c *EncryptedCodec) Marshal(v any) ([]byte, error) {
+	plain, err := c.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	keyID, aead := c.current, c.keys[c.current]
+	c.mu.RUnlock()
+
+	header := make([]byte, 0, 2+len(keyID)+aead.NonceSize()+len(plain)+aead.Overhead())
+	header = append(header, encryptedVersion, byte(len(keyID)))
+	header = append(header, keyID...)
+	nonce := header[len(header) : len(header)+aead.NonceSize()]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := header[:len(header)+len(nonce)]
+	return aead.Seal(out, nonce, plain, header), nil
+}
+
+// Unmarshal 按密钥 ID 解密 data 后使用 inner 解码到 v
+func (c *EncryptedCodec) Unmarshal(data []byte, v any) error {
+	if len(data) < 2 || data[0] != encryptedVersion {
+		return ErrInvalidCiphertext
+	}
+	n := int(data[1])
+	if len(data) < 2+n {
+		return ErrInvalidCiphertext
+	}
+	header, keyID := data[:2+n], string(data[2:2+n])
+
+	c.mu.RLock()
+	aead, ok := c.keys[keyID]
+	c.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+
+	rest := data[len(header):]
+	if len(rest) < aead.NonceSize() {
+		return ErrInvalidCiphertext
+	}
+	plain, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], header)
+	if err != nil {
+		return errors.Join(ErrInvalidCiphertext, err)
+	}
+	return c.inner.Unmarshal(plain, v)
+}
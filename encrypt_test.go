@@ -0,0 +1,96 @@
+package broadcast
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type secret struct {
+	Card string `json:"card"`
+}
+
+func TestEncryptedCodec_RoundTripAndRotation(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	codec, err := NewEncryptedCodec(nil, "2024", oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := codec.Marshal(secret{Card: "4242"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(before, []byte("4242")) {
+		t.Fatal("expected the payload to be encrypted")
+	}
+	again, _ := codec.Marshal(secret{Card: "4242"})
+	if bytes.Equal(before, again) {
+		t.Error("expected a fresh nonce for every Marshal")
+	}
+
+	if err := codec.Rotate("2025", bytes.Repeat([]byte{2}, 16)); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := codec.Marshal(secret{Card: "5555"})
+	if codec.KeyID() != "2025" {
+		t.Errorf("expected the rotated key to be current, got %q", codec.KeyID())
+	}
+
+	for _, tc := range []struct {
+		data []byte
+		want string
+	}{{before, "4242"}, {after, "5555"}} {
+		var s secret
+		if err := codec.Unmarshal(tc.data, &s); err != nil || s.Card != tc.want {
+			t.Errorf("expected %q after rotation, got %+v (%v)", tc.want, s, err)
+		}
+	}
+
+	if codec.RemoveKey("2025") {
+		t.Error("expected the current key not to be removable")
+	}
+	if !codec.RemoveKey("2024") {
+		t.Fatal("expected the old key to be removed")
+	}
+	var s secret
+	if err := codec.Unmarshal(before, &s); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey after removing the key, got %v", err)
+	}
+
+	// 只有旧密钥的读取方可以通过 AddKey 解密
+	reader, _ := NewEncryptedCodec(nil, "2025", bytes.Repeat([]byte{2}, 16))
+	reader.AddKey("2024", oldKey)
+	if err := reader.Unmarshal(before, &s); err != nil || s.Card != "4242" {
+		t.Errorf("expected AddKey to allow decrypting old payloads, got %+v (%v)", s, err)
+	}
+}
+
+func TestEncryptedCodec_RejectsTamperingAndBadKeys(t *testing.T) {
+	if _, err := NewEncryptedCodec(nil, "k", []byte("short")); err == nil {
+		t.Error("expected an invalid key length to be rejected")
+	}
+	if _, err := NewEncryptedCodec(nil, "", bytes.Repeat([]byte{1}, 16)); err == nil {
+		t.Error("expected an empty key id to be rejected")
+	}
+
+	codec, _ := NewEncryptedCodec(nil, "k", bytes.Repeat([]byte{3}, 16))
+	data, _ := codec.Marshal("hello")
+	data[len(data)-1] ^= 0xff
+	var s string
+	if err := codec.Unmarshal(data, &s); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Errorf("expected tampering to be detected, got %v", err)
+	}
+	for _, bad := range [][]byte{nil, {9}, {1, 5, 'k'}, {1, 1, 'k', 0}} {
+		if err := codec.Unmarshal(bad, &s); err == nil {
+			t.Errorf("expected malformed input %v to be rejected", bad)
+		}
+	}
+
+	// 日志与 RawPayload 通过同一个 Codec 解码
+	encoded, _ := codec.Marshal(secret{Card: "1234"})
+	got, err := Decode[secret](NewRawPayload(encoded, codec))
+	if err != nil || got.Card != "1234" {
+		t.Errorf("expected RawPayload to decode through the codec, got %+v (%v)", got, err)
+	}
+}
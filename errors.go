@@ -0,0 +1,44 @@
+package broadcast
+
+import (
+	"errors"
+)
+
+var (
+	// ErrRateLimited 广播被信号级限流器拒绝
+	ErrRateLimited = errors.New("broadcast: rate limited")
+	// ErrValidation Watch 的数据或广播时负载没有通过校验
+	ErrValidation = errors.New("broadcast: validation failed")
+	// ErrQuotaExceeded 广播超过信号的吞吐配额
+	ErrQuotaExceeded = errors.New("broadcast: quota exceeded")
+	// ErrSignalUnhealthy 信号的熔断器处于断开状态, 广播被快速拒绝
+	ErrSignalUnhealthy = errors.New("broadcast: signal unhealthy")
+	// ErrSignalPaused 信号被 Pause 暂停, 广播被拒绝
+	ErrSignalPaused = errors.New("broadcast: signal paused")
+	// ErrSampled 过载时低优先级信号的广播被自适应采样丢弃
+	ErrSampled = errors.New("broadcast: sampled out")
+	// ErrNoJournal 没有开启日志时调用 ReplayJournal
+	ErrNoJournal = errors.New("broadcast: journal not enabled")
+	// ErrUnknownKey EncryptedCodec 没有负载使用的密钥
+	ErrUnknownKey = errors.New("broadcast: unknown encryption key")
+	// ErrInvalidCiphertext EncryptedCodec 的输入格式错误或认证失败
+	ErrInvalidCiphertext = errors.New("broadcast: invalid ciphertext")
+	// ErrUnknownCompression CompressedCodec 无法识别负载使用的压缩算法
+	ErrUnknownCompression = errors.New("broadcast: unknown compression")
+	// ErrNoHistory 没有开启历史时调用 HandleWithReplay
+	ErrNoHistory = errors.New("broadcast: history not enabled")
+	// ErrUndeclaredSignal 严格模式下信号未在注册表中声明
+	ErrUndeclaredSignal = errors.New("broadcast: undeclared signal")
+	// ErrPayloadType 严格模式下负载类型与注册表中声明的不一致
+	ErrPayloadType = errors.New("broadcast: payload type mismatch")
+	// ErrInvalidConfig Config.Validate 发现的配置错误
+	ErrInvalidConfig = errors.New("broadcast: invalid config")
+	// ErrBatchConflict 批量操作中某一步的前置条件不满足, 整个批量操作未被应用
+	ErrBatchConflict = errors.New("broadcast: batch conflict")
+	// ErrVersionNotFound RollbackTo 的目标版本不在撤销日志中
+	ErrVersionNotFound = errors.New("broadcast: version not found")
+	// ErrDeadlineExceeded 广播超过 WithDeadline 设置的截止时间, 部分调用未执行
+	ErrDeadlineExceeded = errors.New("broadcast: deadline exceeded")
+	// ErrPublisherClosed Publisher 已关闭
+	ErrPublisherClosed = errors.New("broadcast: publisher closed")
+)
@@ -0,0 +1,47 @@
+package broadcast
+
+import (
+	"time"
+)
+
+// Event 是交给 EventHandler 的事件信封, 为所有下游提供一致的来源信息
+type Event[T any] struct {
+	// ID 为 WithEventID 设置的事件 ID, 未设置时为广播序号
+	ID        string
+	Timestamp time.Time
+	Signal    string
+	// Source 为 WithSource 设置的事件来源
+	Source   string
+	Metadata map[string]interface{}
+	Data     T
+}
+
+// EventHandler 是以事件信封接收广播的处理器
+type EventHandler[T any] func(e Event[T]) error
+
+// eventHandlerFunc 是 HandleEvent 注册的处理器
+type eventHandlerFunc[T any] func(e Event[T]) error
+
+// WithSource 设置事件来源, 通过 Event.Source 交给 EventHandler
+func WithSource(source string) BroadcastOption {
+	return func(o *broadcastOptions) {
+		o.source = source
+	}
+}
+
+// event 构造交给 EventHandler 的事件信封
+func (d *delivery[K, T]) event(signal string, data T) Event[T] {
+	return Event[T]{
+		ID:        d.eventID(),
+		Timestamp: d.time,
+		Signal:    signal,
+		Source:    d.source,
+		Metadata:  d.metadata,
+		Data:      data,
+	}
+}
+
+func (c *core[K, T]) handleEvent(prefix string, handler eventHandlerFunc[T], opts ...HandleOption) HandlerID {
+	o := newHandleOptions(opts)
+	return c.addHandler(handlerEntry[T]{eventFn: handler, prefix: prefix, name: o.name, sem: o.semaphore()})
+}
@@ -0,0 +1,62 @@
+package broadcast
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHandleEvent_Envelope(t *testing.T) {
+	clock := &manualClock{now: time.Unix(100, 0)}
+	b := New[string]()
+	b.SetClock(clock)
+	b.Watch("app.login", "alice")
+
+	var got []Event[string]
+	b.Namespace("app.").HandleEvent(func(e Event[string]) error {
+		got = append(got, e)
+		return nil
+	})
+
+	md := map[string]interface{}{"ip": "127.0.0.1"}
+	b.Broadcast("app.login", md, WithEventID("evt-1"), WithSource("auth"))
+	b.Broadcast("app.login", nil)
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+	e := got[0]
+	if e.ID != "evt-1" || e.Source != "auth" || e.Signal != "login" || e.Data != "alice" ||
+		!e.Timestamp.Equal(time.Unix(100, 0)) || e.Metadata["ip"] != "127.0.0.1" {
+		t.Errorf("unexpected envelope %+v", e)
+	}
+	if got[1].ID != "2" {
+		t.Errorf("expected the sequence number as the default ID, got %q", got[1].ID)
+	}
+}
+
+func TestHandleEvent_ReplayKeepsProvenance(t *testing.T) {
+	journal, err := OpenFileJournal(t.TempDir(), FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	clock := &manualClock{now: time.Unix(100, 0)}
+	b := NewUnique[int, TestUniqueData]()
+	b.SetClock(clock)
+	b.EnableJournal(JournalConfig{Journal: journal})
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+	b.Broadcast("test", nil, WithEventID("e1"), WithSource("svc"))
+
+	clock.now = time.Unix(500, 0)
+	var got Event[TestUniqueData]
+	b.HandleEvent(func(e Event[TestUniqueData]) error {
+		got = e
+		return nil
+	})
+	b.ReplayJournal(0)
+
+	if got.ID != "e1" || got.Source != "svc" || !got.Timestamp.Equal(time.Unix(100, 0)) || got.Data.ID != 1 {
+		t.Errorf("expected the replayed envelope to keep its provenance, got %+v", got)
+	}
+}
@@ -0,0 +1,201 @@
+// Package eventbus 在 broadcast 之上提供发布/订阅与请求/响应的事件总线
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+// requestPrefix 是请求信号的前缀, 与普通 topic 隔离
+const requestPrefix = "eventbus.request:"
+
+var (
+	// ErrNoResponders topic 上没有响应器
+	ErrNoResponders = errors.New("eventbus: no responders")
+	// ErrMessageType 请求或消息的类型与订阅者期望的不一致
+	ErrMessageType = errors.New("eventbus: message type mismatch")
+)
+
+// Config 事件总线配置
+type Config struct {
+	// RequestTimeout 为没有设置截止时间的 Request 提供超时, 默认为 5s
+	RequestTimeout time.Duration
+	// Options 应用于底层的 broadcast.Broadcast
+	Options []broadcast.Option
+}
+
+// Bus 是事件总线, 每个订阅者作为底层广播器上的一个监听器
+type Bus struct {
+	config Config
+	b      *broadcast.Broadcast[uint64]
+
+	mu     sync.RWMutex
+	subs   map[uint64]handlerFunc
+	nextID atomic.Uint64
+}
+
+type handlerFunc func(ctx context.Context, msg any) (any, error)
+
+// request 是一次 Request 在底层广播中的负载
+type request struct {
+	ctx     context.Context
+	msg     any
+	replies chan reply
+}
+
+type reply struct {
+	value any
+	err   error
+}
+
+// New 创建事件总线
+func New(config Config) *Bus {
+	if config.RequestTimeout <= 0 {
+		config.RequestTimeout = 5 * time.Second
+	}
+	bus := &Bus{
+		config: config,
+		b:      broadcast.New[uint64](config.Options...),
+		subs:   make(map[uint64]handlerFunc),
+	}
+	broadcast.HandleData(bus.b, bus.dispatch)
+	return bus
+}
+
+// Close 关闭底层广播器, 等待异步投递完成
+func (bus *Bus) Close() {
+	bus.b.Close()
+}
+
+// Broadcast 返回底层广播器, 用于开启异步投递、死信等高级能力
+func (bus *Bus) Broadcast() *broadcast.Broadcast[uint64] {
+	return bus.b
+}
+
+func (bus *Bus) dispatch(signal string, id uint64, msg any, metadata map[string]interface{}) error {
+	bus.mu.RLock()
+	fn, ok := bus.subs[id]
+	bus.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	req, ok := msg.(*request)
+	if !ok {
+		_, err := fn(context.Background(), msg)
+		return err
+	}
+	// 响应器并发执行, 使慢响应器不会阻塞其他响应器
+	go func() {
+		value, err := fn(req.ctx, req.msg)
+		select {
+		case req.replies <- reply{value: value, err: err}:
+		case <-req.ctx.Done():
+		}
+	}()
+	return nil
+}
+
+// subscribe 注册订阅者并监听 signal
+func (bus *Bus) subscribe(signal string, fn handlerFunc) *Subscription {
+	id := bus.nextID.Add(1)
+	bus.mu.Lock()
+	bus.subs[id] = fn
+	bus.mu.Unlock()
+
+	bus.b.Watch(signal, id)
+	return &Subscription{bus: bus, signal: signal, id: id}
+}
+
+// Subscription 是一个订阅, 通过 Unsubscribe 取消
+type Subscription struct {
+	bus    *Bus
+	signal string
+	id     uint64
+}
+
+// Unsubscribe 取消订阅, 重复调用不做任何事
+func (s *Subscription) Unsubscribe() {
+	s.bus.b.Unwatch(s.signal, s.id)
+	s.bus.mu.Lock()
+	delete(s.bus.subs, s.id)
+	s.bus.mu.Unlock()
+}
+
+// Subscribe 订阅 topic 上类型为 M 的消息, 其他类型的消息被忽略
+func Subscribe[M any](bus *Bus, topic string, fn func(ctx context.Context, msg M) error) *Subscription {
+	return bus.subscribe(topic, func(ctx context.Context, msg any) (any, error) {
+		m, ok := msg.(M)
+		if !ok {
+			return nil, nil
+		}
+		return nil, fn(ctx, m)
+	})
+}
+
+// Publish 向 topic 的所有订阅者发布消息, 同步投递时返回订阅者错误的组合
+func Publish[M any](bus *Bus, topic string, msg M) error {
+	return broadcast.BroadcastData(bus.b, topic, msg, nil)
+}
+
+// Respond 注册 topic 上的响应器, 处理 Request 发出的类型为 Req 的请求
+func Respond[Req any, Resp any](bus *Bus, topic string, fn func(ctx context.Context, req Req) (Resp, error)) *Subscription {
+	return bus.subscribe(requestPrefix+topic, func(ctx context.Context, msg any) (any, error) {
+		req, ok := msg.(Req)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s expects %T, got %T", ErrMessageType, topic, req, msg)
+		}
+		return fn(ctx, req)
+	})
+}
+
+// Request 向 topic 的所有响应器并发发送请求, 返回第一个成功的响应
+// 所有响应器都失败时返回错误的组合, 超时或 ctx 结束时返回 ctx.Err(); 没有截止时间时使用 Config.RequestTimeout
+func Request[Req any, Resp any](ctx context.Context, bus *Bus, topic string, req Req) (Resp, error) {
+	var zero Resp
+	signal := requestPrefix + topic
+	n := bus.b.WatchCount(signal)
+	if n == 0 {
+		return zero, fmt.Errorf("%w: %s", ErrNoResponders, topic)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, bus.config.RequestTimeout)
+		defer cancel()
+	}
+	// 返回后取消, 使仍在执行的响应器可以提前结束
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	r := &request{ctx: ctx, msg: req, replies: make(chan reply, n)}
+	if err := broadcast.BroadcastData(bus.b, signal, r, nil); err != nil {
+		return zero, err
+	}
+
+	var errs []error
+	for range n {
+		select {
+		case rep := <-r.replies:
+			if rep.err != nil {
+				errs = append(errs, rep.err)
+				continue
+			}
+			resp, ok := rep.value.(Resp)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%w: %s replied %T", ErrMessageType, topic, rep.value))
+				continue
+			}
+			return resp, nil
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+	return zero, errors.Join(errs...)
+}
@@ -0,0 +1,84 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type orderPlaced struct {
+	ID int
+}
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	bus := New(Config{})
+	defer bus.Close()
+
+	var got []int
+	sub := Subscribe(bus, "orders", func(ctx context.Context, msg orderPlaced) error {
+		got = append(got, msg.ID)
+		return nil
+	})
+	Subscribe(bus, "orders", func(ctx context.Context, msg string) error {
+		t.Error("expected messages of another type to be ignored")
+		return nil
+	})
+
+	if err := Publish(bus, "orders", orderPlaced{ID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	Publish(bus, "orders", orderPlaced{ID: 2})
+
+	if len(got) != 1 || got[0] != 1 {
+		t.Errorf("expected only the message before Unsubscribe, got %v", got)
+	}
+}
+
+func TestBus_RequestFirstResponderWins(t *testing.T) {
+	bus := New(Config{})
+	defer bus.Close()
+
+	Respond(bus, "price", func(ctx context.Context, symbol string) (float64, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	Respond(bus, "price", func(ctx context.Context, symbol string) (float64, error) {
+		return 42, nil
+	})
+
+	price, err := Request[string, float64](context.Background(), bus, "price", "BTC")
+	if err != nil || price != 42 {
+		t.Errorf("expected the fast responder to win, got %v %v", price, err)
+	}
+}
+
+func TestBus_RequestErrors(t *testing.T) {
+	bus := New(Config{RequestTimeout: 20 * time.Millisecond})
+	defer bus.Close()
+
+	if _, err := Request[string, int](context.Background(), bus, "missing", "x"); !errors.Is(err, ErrNoResponders) {
+		t.Errorf("expected ErrNoResponders, got %v", err)
+	}
+
+	boom := errors.New("boom")
+	Respond(bus, "fail", func(ctx context.Context, req string) (int, error) {
+		return 0, boom
+	})
+	if _, err := Request[string, int](context.Background(), bus, "fail", "x"); !errors.Is(err, boom) {
+		t.Errorf("expected the responder error, got %v", err)
+	}
+	if _, err := Request[int, int](context.Background(), bus, "fail", 1); !errors.Is(err, ErrMessageType) {
+		t.Errorf("expected ErrMessageType, got %v", err)
+	}
+
+	Respond(bus, "slow", func(ctx context.Context, req string) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if _, err := Request[string, int](context.Background(), bus, "slow", "x"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the request to time out, got %v", err)
+	}
+}
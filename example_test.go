@@ -0,0 +1,92 @@
+package broadcast_test
+
+import (
+	"fmt"
+	"sort"
+	"unique"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+func ExampleBroadcast() {
+	b := broadcast.New[string]()
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		fmt.Printf("%s -> %s\n", signal, data)
+		return nil
+	})
+
+	b.Watch("user.login", "audit")
+	b.Watch("user.login", "audit") // 重复的监听器会被忽略
+	b.Broadcast("user.login", nil)
+	// Output:
+	// user.login -> audit
+}
+
+type session struct {
+	UserID int
+	Device string
+}
+
+type sessionKey struct {
+	s session
+}
+
+func (s *sessionKey) Unique() unique.Handle[int] {
+	return unique.Make(s.s.UserID)
+}
+
+func (s *sessionKey) Value() session {
+	return s.s
+}
+
+func ExampleUniqueBroadcast() {
+	b := broadcast.NewUnique[int, session]()
+	b.Handle(func(signal string, s session, metadata map[string]interface{}) error {
+		fmt.Printf("notify user %d on %s\n", s.UserID, s.Device)
+		return nil
+	})
+
+	b.Watch("news", &sessionKey{s: session{UserID: 1, Device: "phone"}})
+	b.Watch("news", &sessionKey{s: session{UserID: 1, Device: "laptop"}}) // 同一用户只保留第一个
+	b.Watch("news", &sessionKey{s: session{UserID: 2, Device: "tablet"}})
+	b.Broadcast("news", nil)
+	// Output:
+	// notify user 1 on phone
+	// notify user 2 on tablet
+}
+
+func ExampleBroadcast_EnableAsync() {
+	b := broadcast.New[string]()
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		fmt.Printf("%s reloaded %v\n", data, metadata["version"])
+		return nil
+	})
+	b.Watch("config", "api")
+
+	b.EnableAsync(broadcast.AsyncConfig{QueueSize: 8, Overflow: broadcast.Block})
+	b.Broadcast("config", map[string]interface{}{"version": 1})
+	b.Broadcast("config", map[string]interface{}{"version": 2})
+	b.Close()
+	// Output:
+	// api reloaded 1
+	// api reloaded 2
+}
+
+func ExampleFanout() {
+	f := broadcast.NewFanout[int, session](2)
+
+	var notified []int
+	f.Handle(func(signal string, s session, metadata map[string]interface{}) error {
+		notified = append(notified, s.UserID)
+		return nil
+	})
+	for i := 1; i <= 5; i++ {
+		f.Watch("news", &sessionKey{s: session{UserID: i}})
+	}
+	f.Broadcast("news", nil)
+
+	sort.Ints(notified)
+	fmt.Println(notified, "via", f.RelayCount(), "relays")
+	// Output:
+	// [1 2 3 4 5] via 3 relays
+}
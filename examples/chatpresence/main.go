@@ -0,0 +1,66 @@
+// chatpresence 演示使用 UniqueBroadcast 维护聊天室在线状态:
+// 每个房间是一个信号, 每个用户是以用户 ID 去重的监听器
+package main
+
+import (
+	"fmt"
+	"unique"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+// Member 表示房间中的一个在线用户
+type Member struct {
+	UserID int
+	Name   string
+}
+
+// member 以 UserID 作为唯一标识, 同一用户在多个设备上登录只算一次
+type member struct {
+	m Member
+}
+
+func (m *member) Unique() unique.Handle[int] {
+	return unique.Make(m.m.UserID)
+}
+
+func (m *member) Value() Member {
+	return m.m
+}
+
+func main() {
+	rooms := broadcast.NewUnique[int, Member]()
+
+	// 每条消息投递给房间中的每个在线用户
+	rooms.Handle(func(room string, to Member, metadata map[string]interface{}) error {
+		fmt.Printf("[%s] -> %-5s %s: %s\n", room, to.Name, metadata["from"], metadata["text"])
+		return nil
+	})
+
+	join := func(room string, m Member) {
+		rooms.Watch(room, &member{m: m})
+		fmt.Printf("%s joined %s (%d online)\n", m.Name, room, rooms.WatchCount(room))
+	}
+	leave := func(room string, m Member) {
+		rooms.Unwatch(room, &member{m: m})
+		fmt.Printf("%s left %s (%d online)\n", m.Name, room, rooms.WatchCount(room))
+	}
+
+	alice := Member{UserID: 1, Name: "alice"}
+	bob := Member{UserID: 2, Name: "bob"}
+
+	join("#general", alice)
+	join("#general", bob)
+	join("#general", alice) // 第二个设备, 不会重复
+
+	rooms.Broadcast("#general", map[string]interface{}{"from": "alice", "text": "hi!"})
+
+	leave("#general", bob)
+	rooms.Broadcast("#general", map[string]interface{}{"from": "alice", "text": "anyone?"})
+
+	fmt.Println("\n在线统计:")
+	rooms.Range(func(room string, count int) bool {
+		fmt.Printf("%s: %d\n", room, count)
+		return true
+	})
+}
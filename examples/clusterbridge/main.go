@@ -0,0 +1,102 @@
+// clusterbridge 演示在两个进程 (这里用 net.Pipe 模拟) 的广播器之间桥接事件:
+// 本地处理器将事件编码后通过 bridge.Link 写入连接, 远端读取后在自己的广播器上重新广播.
+// 连接断开期间的事件由 Link 暂存, 重连后按顺序补发
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+	"pkg.blksails.net/x/broadcast/bridge"
+)
+
+// envelope 是在连接上传输的事件
+type envelope struct {
+	Signal   string                 `json:"signal"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+const bridgeKey = "__bridge__"
+
+// pipeConn 将 net.Conn 适配为 bridge.Conn, 每帧为一个 JSON 文档
+type pipeConn struct {
+	net.Conn
+}
+
+func (c pipeConn) Send(frame []byte) error {
+	_, err := c.Write(frame)
+	return err
+}
+
+func main() {
+	local := broadcast.New[string]()
+	remote := broadcast.New[string]()
+
+	// 远端: 每次连接启动一个读循环, 解码事件并在本地广播器上重放
+	var wg sync.WaitGroup
+	remote.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		fmt.Printf("[remote] %s -> %s: %v\n", signal, data, metadata["message"])
+		wg.Done()
+		return nil
+	})
+	remote.Watch("alerts", "pager")
+	remote.Watch("alerts", "slack")
+
+	conns := make(chan net.Conn, 1)
+	serve := func(conn net.Conn) {
+		dec := json.NewDecoder(conn)
+		for {
+			var e envelope
+			if err := dec.Decode(&e); err != nil {
+				return
+			}
+			e.Metadata["origin"] = "remote"
+			remote.Broadcast(e.Signal, e.Metadata)
+		}
+	}
+
+	link, err := bridge.Connect(bridge.Config{
+		Dial: func(ctx context.Context) (bridge.Conn, error) {
+			localConn, remoteConn := net.Pipe()
+			conns <- remoteConn
+			go serve(remoteConn)
+			return pipeConn{localConn}, nil
+		},
+		Backoff: bridge.Backoff{Initial: 10 * time.Millisecond, MaxAttempts: 5},
+		OnStateChange: func(from, to bridge.State, err error) {
+			fmt.Printf("[bridge] %s -> %s\n", from, to)
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer link.Close()
+
+	// 本地: 桥接器作为一个特殊监听器, 收到事件后交给 Link 转发给远端
+	local.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		if data != bridgeKey || metadata["origin"] == "remote" {
+			return nil
+		}
+		frame, err := json.Marshal(envelope{Signal: signal, Metadata: metadata})
+		if err != nil {
+			return err
+		}
+		return link.Send(frame)
+	})
+	local.Watch("alerts", bridgeKey)
+
+	wg.Add(3 * remote.WatchCount("alerts"))
+	first := <-conns
+	local.Broadcast("alerts", map[string]interface{}{"message": "disk almost full"})
+
+	// 远端断开: 之后的事件先写入暂存, 重连成功后补发
+	first.Close()
+	local.Broadcast("alerts", map[string]interface{}{"message": "disk full"})
+	local.Broadcast("alerts", map[string]interface{}{"message": "disk full again"})
+	wg.Wait()
+}
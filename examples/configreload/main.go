@@ -0,0 +1,54 @@
+// configreload 演示一个配置热更新总线:
+// 各组件以自身名称监听感兴趣的配置节, 配置变化时通过异步队列通知,
+// 发布方不会被慢组件阻塞
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+func main() {
+	bus := broadcast.New[string]()
+
+	var (
+		mu      sync.Mutex
+		applied = make(map[string]string)
+	)
+	bus.Handle(func(section string, component string, metadata map[string]interface{}) error {
+		time.Sleep(10 * time.Millisecond) // 模拟组件重新加载配置
+		mu.Lock()
+		applied[component+"."+section] = fmt.Sprint(metadata["value"])
+		mu.Unlock()
+		fmt.Printf("%-8s reloaded %s -> %v\n", component, section, metadata["value"])
+		return nil
+	})
+
+	bus.Watch("database", "api")
+	bus.Watch("database", "worker")
+	bus.Watch("cache", "api")
+
+	bus.EnableAsync(broadcast.AsyncConfig{
+		Workers:   2,
+		QueueSize: 16,
+		Overflow:  broadcast.DropOldest,
+		OnOverflow: func(section string, metadata map[string]interface{}) {
+			fmt.Printf("skip stale %s update %v\n", section, metadata["value"])
+		},
+	})
+
+	start := time.Now()
+	bus.Broadcast("database", map[string]interface{}{"value": "postgres://db-2"})
+	bus.Broadcast("cache", map[string]interface{}{"value": "redis://cache-1"})
+	fmt.Printf("publisher returned after %v, %d pending\n", time.Since(start).Round(time.Millisecond), bus.Pending())
+
+	bus.Close()
+
+	fmt.Println("\n最终配置:")
+	for _, key := range []string{"api.database", "api.cache", "worker.database"} {
+		fmt.Printf("%-16s %s\n", key, applied[key])
+	}
+}
@@ -0,0 +1,66 @@
+// webhookfanout 演示将事件推送给大量 webhook 订阅者:
+// Fanout 将订阅者分散到多个中继, 每个中继在独立的 goroutine 中发送请求
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"unique"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+// Subscriber 是一个 webhook 订阅
+type Subscriber struct {
+	ID  string
+	URL string
+}
+
+type subscriber struct {
+	s Subscriber
+}
+
+func (s *subscriber) Unique() unique.Handle[string] {
+	return unique.Make(s.s.ID)
+}
+
+func (s *subscriber) Value() Subscriber {
+	return s.s
+}
+
+func main() {
+	var received atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		received.Add(1)
+	}))
+	defer server.Close()
+
+	hooks := broadcast.NewFanout[string, Subscriber](25)
+	hooks.SetExecutor(func(task func()) { go task() })
+	hooks.Handle(func(event string, sub Subscriber, metadata map[string]interface{}) error {
+		body, _ := json.Marshal(map[string]interface{}{"event": event, "data": metadata})
+		resp, err := http.Post(sub.URL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	})
+
+	for i := 0; i < 100; i++ {
+		hooks.Watch("order.created", &subscriber{s: Subscriber{
+			ID:  fmt.Sprintf("sub-%03d", i),
+			URL: server.URL + fmt.Sprintf("/hook/%d", i),
+		}})
+	}
+
+	hooks.Broadcast("order.created", map[string]interface{}{"order_id": 42})
+
+	fmt.Printf("subscribers: %d, relays: %d, delivered: %d\n",
+		hooks.WatchCount("order.created"), hooks.RelayCount(), received.Load())
+}
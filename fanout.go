@@ -0,0 +1,155 @@
+package broadcast
+
+import (
+	"errors"
+	"sync"
+	"unique"
+)
+
+// Fanout 将大量监听器分散到多个中继 UniqueBroadcast 上, 形成两级广播树
+// 每个中继在同一信号上最多持有 capacity 个监听器, 容量不足时自动创建新的中继,
+// 从而使单个节点的监听器数量和分发延迟保持有界
+type Fanout[K comparable, T any] struct {
+	mu        sync.RWMutex
+	capacity  int
+	executor  func(task func())
+	handlers  []UniqueHandler[K, T]
+	relays    []*UniqueBroadcast[K, T]
+	placement map[string]map[unique.Handle[K]]int
+}
+
+// NewFanout 创建一个两级广播树, capacity 为每个中继在单个信号上的监听器上限
+func NewFanout[K comparable, T any](capacity int) *Fanout[K, T] {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &Fanout[K, T]{
+		capacity:  capacity,
+		placement: make(map[string]map[unique.Handle[K]]int),
+	}
+}
+
+// SetExecutor 设置中继的执行器, 例如提交到独立的 goroutine 池
+// 默认在当前 goroutine 中依次执行各中继
+func (f *Fanout[K, T]) SetExecutor(executor func(task func())) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.executor = executor
+}
+
+// Handle 注册一个处理器, 处理器会被注册到所有中继
+func (f *Fanout[K, T]) Handle(handler UniqueHandler[K, T]) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.handlers = append(f.handlers, handler)
+	for _, relay := range f.relays {
+		relay.Handle(handler)
+	}
+}
+
+// Watch 监听一个信号, 监听器被放置在第一个仍有容量的中继上, 返回监听器是否被添加
+func (f *Fanout[K, T]) Watch(signal string, data Uniquer[K, T]) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	placed := f.placement[signal]
+	if placed == nil {
+		placed = make(map[unique.Handle[K]]int)
+		f.placement[signal] = placed
+	}
+
+	handle := data.Unique()
+	if _, exists := placed[handle]; exists {
+		return false
+	}
+
+	index := -1
+	for i, relay := range f.relays {
+		if relay.WatchCount(signal) < f.capacity {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		index = len(f.relays)
+		f.relays = append(f.relays, f.newRelay())
+	}
+
+	f.relays[index].Watch(signal, data)
+	placed[handle] = index
+	return true
+}
+
+func (f *Fanout[K, T]) newRelay() *UniqueBroadcast[K, T] {
+	relay := NewUnique[K, T]()
+	for _, handler := range f.handlers {
+		relay.Handle(handler)
+	}
+	return relay
+}
+
+// Unwatch 取消监听一个信号, 返回是否有监听器被移除
+func (f *Fanout[K, T]) Unwatch(signal string, data Uniquer[K, T]) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	placed := f.placement[signal]
+	handle := data.Unique()
+	index, exists := placed[handle]
+	if !exists {
+		return false
+	}
+
+	f.relays[index].Unwatch(signal, data)
+	delete(placed, handle)
+	if len(placed) == 0 {
+		delete(f.placement, signal)
+	}
+	return true
+}
+
+// Broadcast 广播一个信号, 根节点将其分发给每个中继并等待所有中继完成
+// 返回所有中继错误的组合
+func (f *Fanout[K, T]) Broadcast(signal string, metadata map[string]interface{}, opts ...BroadcastOption) error {
+	f.mu.RLock()
+	relays := f.relays
+	executor := f.executor
+	f.mu.RUnlock()
+
+	errs := make([]error, len(relays))
+	if executor == nil {
+		for i, relay := range relays {
+			errs[i] = relay.Broadcast(signal, metadata, opts...)
+		}
+		return errors.Join(errs...)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(relays))
+	for i, relay := range relays {
+		executor(func() {
+			defer wg.Done()
+			errs[i] = relay.Broadcast(signal, metadata, opts...)
+		})
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// WatchCount 返回指定信号在所有中继上的监听器总数
+func (f *Fanout[K, T]) WatchCount(signal string) int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return len(f.placement[signal])
+}
+
+// RelayCount 返回当前中继节点数量
+func (f *Fanout[K, T]) RelayCount() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return len(f.relays)
+}
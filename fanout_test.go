@@ -0,0 +1,66 @@
+package broadcast
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestFanout_BoundedRelays(t *testing.T) {
+	f := NewFanout[int, TestUniqueData](10)
+
+	var calls atomic.Int64
+	f.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		calls.Add(1)
+		return nil
+	})
+
+	for i := 0; i < 95; i++ {
+		f.Watch("test", &TestUniquer{data: TestUniqueData{ID: i}})
+	}
+	f.Watch("test", &TestUniquer{data: TestUniqueData{ID: 0}}) // 重复
+
+	if count := f.WatchCount("test"); count != 95 {
+		t.Errorf("expected 95 watchers, got %d", count)
+	}
+	if relays := f.RelayCount(); relays != 10 {
+		t.Errorf("expected 10 relays, got %d", relays)
+	}
+
+	f.Broadcast("test", nil)
+	if calls.Load() != 95 {
+		t.Errorf("expected 95 handler calls, got %d", calls.Load())
+	}
+
+	// 释放的容量会被复用
+	f.Unwatch("test", &TestUniquer{data: TestUniqueData{ID: 3}})
+	f.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1000}})
+	if relays := f.RelayCount(); relays != 10 {
+		t.Errorf("expected freed capacity to be reused, got %d relays", relays)
+	}
+}
+
+func TestFanout_Executor(t *testing.T) {
+	f := NewFanout[int, TestUniqueData](2)
+
+	var tasks, calls atomic.Int64
+	f.SetExecutor(func(task func()) {
+		tasks.Add(1)
+		go task()
+	})
+	f.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		calls.Add(1)
+		return nil
+	})
+
+	for i := 0; i < 5; i++ {
+		f.Watch("test", &TestUniquer{data: TestUniqueData{ID: i}})
+	}
+
+	f.Broadcast("test", nil)
+	if tasks.Load() != 3 {
+		t.Errorf("expected 3 relay tasks, got %d", tasks.Load())
+	}
+	if calls.Load() != 5 {
+		t.Errorf("expected 5 handler calls after Broadcast returns, got %d", calls.Load())
+	}
+}
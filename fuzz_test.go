@@ -0,0 +1,127 @@
+package broadcast
+
+import (
+	"fmt"
+	"testing"
+)
+
+// fuzzSignals 限制信号数量, 使随机操作序列更容易命中同一信号
+var fuzzSignals = []string{"a", "b", "c", "d"}
+
+// FuzzBroadcast_Registry 将输入解释为操作序列, 每个操作占两个字节: 操作码和参数
+// 每一步之后都与一个简单模型比较, 检查无重复监听器且计数一致
+func FuzzBroadcast_Registry(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 1, 1, 1, 2, 0})
+	f.Add([]byte{0, 5, 0, 9, 3, 0, 0, 5, 4, 0, 2, 5})
+	f.Add([]byte{0, 0, 0, 17, 0, 33, 5, 0, 1, 17, 2, 0})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		b := New[uint8]()
+		model := make(map[string]map[uint8]bool)
+
+		delivered := 0
+		b.Handle(func(signal string, data uint8, metadata map[string]interface{}) error {
+			delivered++
+			return nil
+		})
+
+		for i := 0; i+1 < len(ops); i += 2 {
+			op, arg := ops[i]%6, ops[i+1]
+			signal := fuzzSignals[int(arg)%len(fuzzSignals)]
+			value := arg / uint8(len(fuzzSignals))
+
+			switch op {
+			case 0:
+				b.Watch(signal, value)
+				if model[signal] == nil {
+					model[signal] = make(map[uint8]bool)
+				}
+				model[signal][value] = true
+			case 1:
+				b.Unwatch(signal, value)
+				delete(model[signal], value)
+			case 2:
+				delivered = 0
+				b.Broadcast(signal, nil)
+				if delivered != len(model[signal]) {
+					t.Fatalf("op %d: broadcast on %q delivered %d, want %d", i/2, signal, delivered, len(model[signal]))
+				}
+			case 3:
+				b.Clean(signal)
+				delete(model, signal)
+			case 4:
+				b.CleanAll()
+				model = make(map[string]map[uint8]bool)
+			case 5:
+				b.Range(func(signal string, count int) bool {
+					if count != len(model[signal]) {
+						t.Fatalf("op %d: Range reported %d listeners on %q, want %d", i/2, count, signal, len(model[signal]))
+					}
+					return true
+				})
+			}
+
+			checkRegistry(t, &b.core, model)
+		}
+	})
+}
+
+// FuzzUniqueBroadcast_Registry 与 FuzzBroadcast_Registry 相同, 但同一 key 可以携带不同的值
+func FuzzUniqueBroadcast_Registry(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 1, 1, 1, 2, 0})
+	f.Add([]byte{0, 5, 0, 69, 2, 5, 1, 133, 2, 5})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		b := NewUnique[int, TestUniqueData]()
+		model := make(map[string]map[uint8]bool)
+
+		for i := 0; i+1 < len(ops); i += 2 {
+			op, arg := ops[i]%4, ops[i+1]
+			signal := fuzzSignals[int(arg)%len(fuzzSignals)]
+			// 高位只影响 Name, 不影响 key
+			key := (arg / uint8(len(fuzzSignals))) % 16
+			data := &TestUniquer{data: TestUniqueData{ID: int(key), Name: fmt.Sprint(arg)}}
+
+			switch op {
+			case 0:
+				b.Watch(signal, data)
+				if model[signal] == nil {
+					model[signal] = make(map[uint8]bool)
+				}
+				model[signal][key] = true
+			case 1:
+				b.Unwatch(signal, data)
+				delete(model[signal], key)
+			case 2:
+				b.Broadcast(signal, nil)
+			case 3:
+				b.Clean(signal)
+				delete(model, signal)
+			}
+
+			checkRegistry(t, &b.core, model)
+		}
+	})
+}
+
+func checkRegistry[K comparable, T any](t *testing.T, c *core[K, T], model map[string]map[uint8]bool) {
+	t.Helper()
+
+	for _, signal := range fuzzSignals {
+		listeners := c.snapshot(signal)
+		if len(listeners) != len(model[signal]) {
+			t.Fatalf("signal %q: got %d listeners, want %d", signal, len(listeners), len(model[signal]))
+		}
+		if c.watchCount(signal) != len(listeners) || c.hasWatch(signal) != (len(listeners) > 0) {
+			t.Fatalf("signal %q: WatchCount/HasWatch inconsistent with listeners", signal)
+		}
+
+		seen := make(map[any]bool, len(listeners))
+		for _, l := range listeners {
+			if seen[l.key] {
+				t.Fatalf("signal %q: duplicate listener %v", signal, l.key.Value())
+			}
+			seen[l.key] = true
+		}
+	}
+}
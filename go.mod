@@ -1,3 +1,3 @@
 module pkg.blksails.net/x/broadcast
 
-go 1.23.3
+go 1.24
@@ -0,0 +1,75 @@
+package broadcast
+
+import (
+	"unique"
+)
+
+// groupMember 是分组中的一个监听器
+type groupMember[K comparable] struct {
+	signal string
+	key    unique.Handle[K]
+}
+
+// watchGroup 添加监听器并记录到分组, 相同 key 已存在时不记录
+func (c *core[K, T]) watchGroup(group string, signal string, l listener[K, T]) {
+	if !c.watch(signal, l) {
+		return
+	}
+
+	c.groupsMu.Lock()
+	defer c.groupsMu.Unlock()
+
+	if c.groups == nil {
+		c.groups = make(map[string]map[groupMember[K]]struct{})
+	}
+	members, ok := c.groups[group]
+	if !ok {
+		members = make(map[groupMember[K]]struct{})
+		c.groups[group] = members
+	}
+	members[groupMember[K]{signal: signal, key: l.key}] = struct{}{}
+}
+
+// unwatchGroup 授权后移除分组中的所有监听器, 返回实际移除的数量
+// 没有通过授权的监听器保留在信号与分组中
+func (c *core[K, T]) unwatchGroup(group string) int {
+	return c.removeGroup(group, func(signal string) bool {
+		return c.permit(OpUnwatch, signal)
+	})
+}
+
+// releaseGroup 不经过授权移除分组中的所有监听器, 供分组租约到期与释放使用
+func (c *core[K, T]) releaseGroup(group string) int {
+	return c.removeGroup(group, nil)
+}
+
+// removeGroup 移除分组中 allow 为 nil 或报告 true 的信号上的监听器, 其余的保留在分组中
+func (c *core[K, T]) removeGroup(group string, allow func(signal string) bool) int {
+	c.groupsMu.Lock()
+	members := c.groups[group]
+	delete(c.groups, group)
+	c.groupsMu.Unlock()
+
+	n := 0
+	for m := range members {
+		if allow != nil && !allow(m.signal) {
+			continue
+		}
+		delete(members, m)
+		if c.unwatch(m.signal, m.key) {
+			n++
+		}
+	}
+	if len(members) > 0 {
+		c.groupsMu.Lock()
+		if c.groups == nil {
+			c.groups = make(map[string]map[groupMember[K]]struct{})
+		}
+		for m := range c.groups[group] {
+			members[m] = struct{}{}
+		}
+		c.groups[group] = members
+		c.groupsMu.Unlock()
+	}
+	return n
+}
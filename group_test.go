@@ -0,0 +1,37 @@
+package broadcast
+
+import (
+	"testing"
+)
+
+func TestWatchGroup(t *testing.T) {
+	b := New[string]()
+	b.WatchGroup("conn-1", "chat", "alice")
+	b.WatchGroup("conn-1", "presence", "alice")
+	b.WatchGroup("conn-2", "chat", "bob")
+	b.Watch("chat", "carol")
+
+	if n := b.UnwatchGroup("conn-1"); n != 2 {
+		t.Errorf("expected 2 listeners removed, got %d", n)
+	}
+	if b.WatchCount("chat") != 2 || b.HasWatch("presence") {
+		t.Errorf("expected only conn-1 listeners removed, chat=%d", b.WatchCount("chat"))
+	}
+	if n := b.UnwatchGroup("conn-1"); n != 0 {
+		t.Errorf("expected an emptied group to remove nothing, got %d", n)
+	}
+}
+
+func TestWatchGroup_ExistingListener(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	data := &TestUniquer{data: TestUniqueData{ID: 1}}
+	b.Watch("test", data)
+	b.WatchGroup("conn", "test", data)
+
+	if n := b.UnwatchGroup("conn"); n != 0 {
+		t.Errorf("expected the pre-existing listener to be left alone, got %d removed", n)
+	}
+	if !b.HasWatch("test") {
+		t.Error("expected the listener to remain")
+	}
+}
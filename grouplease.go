@@ -0,0 +1,94 @@
+package broadcast
+
+import (
+	"time"
+)
+
+// leaseGroup 为分组创建租约, 分组已有有效租约时续约并返回该租约
+// 租约到期时分组中的所有监听器被移除, 包括创建租约之后通过 WatchGroup 加入的监听器
+func (c *core[K, T]) leaseGroup(group string, ttl time.Duration) *Lease {
+	c.leasesMu.Lock()
+	defer c.leasesMu.Unlock()
+
+	if lease, ok := c.groupLeases[group]; ok && lease.Renew() {
+		return lease
+	}
+
+	lease := &Lease{ttl: ttl, clock: c.clock()}
+	lease.expire = func() {
+		c.dropGroupLease(group, lease)
+		n := c.releaseGroup(group)
+		if fn := c.loadSettings().onGroupLeaseExpired; fn != nil {
+			fn(group, n)
+		}
+	}
+	lease.release = func() {
+		c.dropGroupLease(group, lease)
+		c.releaseGroup(group)
+	}
+
+	if c.groupLeases == nil {
+		c.groupLeases = make(map[string]*Lease)
+	}
+	c.groupLeases[group] = lease
+	lease.mu.Lock()
+	lease.schedule()
+	lease.mu.Unlock()
+	return lease
+}
+
+func (c *core[K, T]) dropGroupLease(group string, lease *Lease) {
+	c.leasesMu.Lock()
+	defer c.leasesMu.Unlock()
+
+	if c.groupLeases[group] == lease {
+		delete(c.groupLeases, group)
+	}
+}
+
+// renewGroupLease 续约分组的租约, 没有有效租约时返回 false
+func (c *core[K, T]) renewGroupLease(group string) bool {
+	c.leasesMu.Lock()
+	lease, ok := c.groupLeases[group]
+	c.leasesMu.Unlock()
+	return ok && lease.Renew()
+}
+
+func (c *core[K, T]) onGroupLeaseExpired(fn func(group string, removed int)) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.onGroupLeaseExpired = fn
+	})
+}
+
+// LeaseGroup 为 group 创建需要续约的租约, 适合网络桥接 (gRPC、WebSocket) 为每个远程订阅者
+// 使用一个分组记录其所有订阅: 订阅者在 ttl 内没有续约 (例如网络分区后) 时,
+// 分组中的所有监听器被移除并调用 OnGroupLeaseExpired 注册的回调, 避免留下幽灵订阅者.
+// group 已有有效租约时续约并返回该租约
+func (b *Broadcast[T]) LeaseGroup(group string, ttl time.Duration) *Lease {
+	return b.c().leaseGroup(b.sig(group), ttl)
+}
+
+// RenewGroupLease 续约 group 的租约, 没有有效租约时返回 false
+func (b *Broadcast[T]) RenewGroupLease(group string) bool {
+	return b.c().renewGroupLease(b.sig(group))
+}
+
+// OnGroupLeaseExpired 设置分组因租约到期被释放时的回调, removed 为实际移除的监听器数量
+func (b *Broadcast[T]) OnGroupLeaseExpired(fn func(group string, removed int)) {
+	b.c().onGroupLeaseExpired(fn)
+}
+
+// LeaseGroup 为 group 创建需要续约的租约, 与 Broadcast.LeaseGroup 相同
+func (b *UniqueBroadcast[K, T]) LeaseGroup(group string, ttl time.Duration) *Lease {
+	return b.core.leaseGroup(group, ttl)
+}
+
+// RenewGroupLease 续约 group 的租约, 没有有效租约时返回 false
+func (b *UniqueBroadcast[K, T]) RenewGroupLease(group string) bool {
+	return b.core.renewGroupLease(group)
+}
+
+// OnGroupLeaseExpired 设置分组因租约到期被释放时的回调, removed 为实际移除的监听器数量
+func (b *UniqueBroadcast[K, T]) OnGroupLeaseExpired(fn func(group string, removed int)) {
+	b.core.onGroupLeaseExpired(fn)
+}
@@ -0,0 +1,112 @@
+package broadcast_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+	"pkg.blksails.net/x/broadcast/broadcasttest"
+)
+
+func TestLeaseGroup_ReleasesMembershipsOnExpiry(t *testing.T) {
+	clock := broadcasttest.NewFakeClock(time.Unix(0, 0))
+	b := broadcast.New[string](broadcast.WithClock(clock))
+	type expiry struct {
+		group   string
+		removed int
+	}
+	var expired []expiry
+	b.OnGroupLeaseExpired(func(group string, removed int) {
+		expired = append(expired, expiry{group, removed})
+	})
+
+	lease := b.LeaseGroup("conn-1", 50*time.Millisecond)
+	b.WatchGroup("conn-1", "prices", "conn-1")
+	b.WatchGroup("conn-1", "news", "conn-1")
+	b.WatchGroup("conn-2", "prices", "conn-2")
+
+	if again := b.LeaseGroup("conn-1", 50*time.Millisecond); again != lease {
+		t.Error("expected an active lease to be renewed and returned")
+	}
+	if b.RenewGroupLease("conn-2") {
+		t.Error("expected renewing a group without a lease to fail")
+	}
+
+	clock.Advance(40 * time.Millisecond)
+	if len(expired) != 0 {
+		t.Fatalf("expected the lease to be alive before its ttl, got %+v", expired)
+	}
+	clock.Advance(20 * time.Millisecond)
+	if len(expired) != 1 || expired[0] != (expiry{"conn-1", 2}) {
+		t.Fatalf("expected conn-1 to expire with 2 memberships, got %+v", expired)
+	}
+
+	if b.WatchCount("news") != 0 || b.WatchCount("prices") != 1 || !lease.Expired() {
+		t.Error("expected only the expired subscriber's memberships to be released")
+	}
+	if b.RenewGroupLease("conn-1") {
+		t.Error("expected renewing an expired lease to fail")
+	}
+	if b.LeaseGroup("conn-1", time.Minute) == lease {
+		t.Error("expected a fresh lease after expiry")
+	}
+}
+
+func TestLeaseGroup_RenewAndRelease(t *testing.T) {
+	clock := broadcasttest.NewFakeClock(time.Unix(0, 0))
+	b := broadcast.NewUnique[string, string](broadcast.WithClock(clock))
+	b.OnGroupLeaseExpired(func(group string, removed int) {
+		t.Errorf("unexpected expiry of %s", group)
+	})
+
+	lease := b.LeaseGroup("ws-7", 40*time.Millisecond)
+	b.WatchGroup("ws-7", "chat", presence{"ws-7", "alice"})
+	for range 5 {
+		clock.Advance(30 * time.Millisecond)
+		if !b.RenewGroupLease("ws-7") {
+			t.Fatal("expected the lease to be renewable")
+		}
+	}
+	if !b.Has("chat", "ws-7") {
+		t.Fatal("expected a renewed subscriber to remain")
+	}
+
+	if !lease.Release() || lease.Release() {
+		t.Error("expected Release to succeed exactly once")
+	}
+	if b.Has("chat", "ws-7") {
+		t.Error("expected Release to drop the group's listeners")
+	}
+	clock.Advance(time.Minute)
+}
+
+func TestLeaseGroup_ExpiryBypassesAuthorizer(t *testing.T) {
+	clock := broadcasttest.NewFakeClock(time.Unix(0, 0))
+	b := broadcast.New[string](broadcast.WithClock(clock))
+	lease := b.LeaseGroup("conn-1", time.Second)
+	b.WatchGroup("conn-1", "prices", "conn-1")
+	b.WatchGroup("conn-2", "prices", "conn-2")
+
+	// 授权器拒绝所有没有身份的移除, 租约到期由广播器自身发起, 不受影响
+	b.SetAuthorizer(func(op broadcast.Operation, signal string, principal any) error {
+		if op == broadcast.OpUnwatch && principal == nil {
+			return errors.New("forbidden")
+		}
+		return nil
+	})
+	if b.UnwatchGroup("conn-2") != 0 {
+		t.Error("expected UnwatchGroup to be authorized")
+	}
+
+	clock.Advance(2 * time.Second)
+	if !lease.Expired() || b.WatchCount("prices") != 1 {
+		t.Errorf("expected the expired group to be released, got %d listeners", b.WatchCount("prices"))
+	}
+
+	lease = b.LeaseGroup("conn-3", time.Second)
+	b.WatchGroup("conn-3", "prices", "conn-3")
+	if !lease.Release() || b.WatchCount("prices") != 1 {
+		t.Errorf("expected Release to drop the group's listeners, got %d", b.WatchCount("prices"))
+	}
+}
@@ -0,0 +1,208 @@
+package broadcast
+
+import (
+	"time"
+)
+
+// HandleOption 是注册处理器时的可选参数
+type HandleOption func(o *handleOptions)
+
+type handleOptions struct {
+	name           string
+	maxConcurrency int
+}
+
+// WithName 为处理器命名, 名称出现在 Handlers、死信与慢处理器告警中, 便于定位出问题的处理器
+func WithName(name string) HandleOption {
+	return func(o *handleOptions) {
+		o.name = name
+	}
+}
+
+// WithMaxConcurrency 限制处理器同时执行的次数不超过 n, 超出的调用等待空位
+// 即使开启了异步投递或并发投递, 执行数据库写入等重量级操作的处理器也不会被过度并发调用
+func WithMaxConcurrency(n int) HandleOption {
+	return func(o *handleOptions) {
+		o.maxConcurrency = n
+	}
+}
+
+// semaphore 返回限制并发的信号量, 没有设置上限时返回 nil
+func (o handleOptions) semaphore() chan struct{} {
+	if o.maxConcurrency <= 0 {
+		return nil
+	}
+	return make(chan struct{}, o.maxConcurrency)
+}
+
+func newHandleOptions(opts []HandleOption) handleOptions {
+	var o handleOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// HandlerInfo 描述一个已注册的处理器
+type HandlerInfo struct {
+	ID HandlerID
+	// Name 为 WithName 设置的名称, 未命名时为空
+	Name string
+}
+
+// handlerInfos 返回按注册顺序排列的处理器, prefix 非空时只返回通过该命名空间注册的处理器
+func (c *core[K, T]) handlerInfos(prefix string) []HandlerInfo {
+	var infos []HandlerInfo
+	for _, h := range c.loadHandlers() {
+		if prefix == "" || h.prefix == prefix {
+			infos = append(infos, HandlerInfo{ID: h.id, Name: h.name})
+		}
+	}
+	return infos
+}
+
+// handlerName 返回处理器的名称, 处理器不存在或未命名时为空
+func (c *core[K, T]) handlerName(id HandlerID) string {
+	for _, h := range c.loadHandlers() {
+		if h.id == id {
+			return h.name
+		}
+	}
+	return ""
+}
+
+// slowHandler 是慢处理器告警配置
+type slowHandler struct {
+	threshold time.Duration
+	fn        func(handler HandlerInfo, signal string, elapsed time.Duration)
+}
+
+// setSlowHandler 设置慢处理器告警, fn 为 nil 时关闭
+func (c *core[K, T]) setSlowHandler(threshold time.Duration, fn func(handler HandlerInfo, signal string, elapsed time.Duration)) {
+	c.updateSettings(func(s *settings[K, T]) {
+		if fn == nil {
+			s.slow = nil
+			return
+		}
+		s.slow = &slowHandler{threshold: threshold, fn: fn}
+	})
+}
+
+// Handlers 返回按注册顺序排列的处理器
+func (b *Broadcast[T]) Handlers() []HandlerInfo {
+	return b.c().handlerInfos(b.prefix())
+}
+
+// HandlerName 返回处理器的名称, 用于在 OnError 等只提供 HandlerID 的回调中定位处理器
+func (b *Broadcast[T]) HandlerName(id HandlerID) string {
+	return b.c().handlerName(id)
+}
+
+// SetSlowHandler 设置慢处理器告警, 单次处理器调用耗时超过 threshold 时调用 fn, fn 为 nil 时关闭
+func (b *Broadcast[T]) SetSlowHandler(threshold time.Duration, fn func(handler HandlerInfo, signal string, elapsed time.Duration)) {
+	b.c().setSlowHandler(threshold, fn)
+}
+
+// Handlers 返回按注册顺序排列的处理器
+func (b *UniqueBroadcast[K, T]) Handlers() []HandlerInfo {
+	return b.core.handlerInfos("")
+}
+
+// HandlerName 返回处理器的名称, 用于在 OnError 等只提供 HandlerID 的回调中定位处理器
+func (b *UniqueBroadcast[K, T]) HandlerName(id HandlerID) string {
+	return b.core.handlerName(id)
+}
+
+// SetSlowHandler 设置慢处理器告警, 单次处理器调用耗时超过 threshold 时调用 fn, fn 为 nil 时关闭
+func (b *UniqueBroadcast[K, T]) SetSlowHandler(threshold time.Duration, fn func(handler HandlerInfo, signal string, elapsed time.Duration)) {
+	b.core.setSlowHandler(threshold, fn)
+}
+
+// setHandlers 原子地用 fns 替换 prefix 下的所有处理器, 返回新处理器的 HandlerID
+// prefix 为空时替换所有处理器. 不存在没有处理器的中间状态, 被替换的处理器的 close 在替换后调用
+func (c *core[K, T]) setHandlers(prefix string, fns []handlerFunc[T]) []HandlerID {
+	c.handlersMu.Lock()
+	handlers := c.loadHandlers()
+	newHandlers := make([]handlerEntry[T], 0, len(handlers)+len(fns))
+	var removed []handlerEntry[T]
+	for _, h := range handlers {
+		if prefix == "" || h.prefix == prefix {
+			removed = append(removed, h)
+			continue
+		}
+		newHandlers = append(newHandlers, h)
+	}
+	ids := make([]HandlerID, len(fns))
+	for i, fn := range fns {
+		ids[i] = HandlerID(c.nextID.Add(1))
+		newHandlers = append(newHandlers, handlerEntry[T]{id: ids[i], fn: fn, prefix: prefix, counters: new(handlerCounters)})
+	}
+	c.handlers.Store(&newHandlers)
+	c.handlersMu.Unlock()
+
+	for _, h := range removed {
+		if h.close != nil {
+			h.close()
+		}
+	}
+	c.flushBuffer()
+	return ids
+}
+
+// replaceHandler 原子地替换处理器的函数, 保留 HandlerID、名称与注册顺序, 返回处理器是否存在
+func (c *core[K, T]) replaceHandler(id HandlerID, fn handlerFunc[T]) bool {
+	c.handlersMu.Lock()
+	handlers := c.loadHandlers()
+	for i, h := range handlers {
+		if h.id != id {
+			continue
+		}
+		newHandlers := make([]handlerEntry[T], len(handlers))
+		copy(newHandlers, handlers)
+		newHandlers[i].fn = fn
+		newHandlers[i].dataFn = nil
+		newHandlers[i].eventFn = nil
+		newHandlers[i].ctxFn = nil
+		newHandlers[i].close = nil
+		c.handlers.Store(&newHandlers)
+		c.handlersMu.Unlock()
+
+		if h.close != nil {
+			h.close()
+		}
+		return true
+	}
+	c.handlersMu.Unlock()
+	return false
+}
+
+// SetHandlers 原子地用 handlers 替换所有处理器, 返回新处理器的 HandlerID, 用于热加载时切换事件处理逻辑
+// 替换期间的广播要么使用旧处理器, 要么使用新处理器, 不会出现没有处理器的窗口.
+// 在命名空间视图上调用时只替换通过该视图注册的处理器
+func (b *Broadcast[T]) SetHandlers(handlers []Handler[T]) []HandlerID {
+	fns := make([]handlerFunc[T], len(handlers))
+	for i, h := range handlers {
+		fns[i] = handlerFunc[T](h)
+	}
+	return b.c().setHandlers(b.prefix(), fns)
+}
+
+// ReplaceHandler 原子地替换处理器, 保留其 HandlerID、名称与注册顺序, 返回处理器是否存在
+func (b *Broadcast[T]) ReplaceHandler(id HandlerID, handler Handler[T]) bool {
+	return b.c().replaceHandler(id, handlerFunc[T](handler))
+}
+
+// SetHandlers 原子地用 handlers 替换所有处理器, 返回新处理器的 HandlerID, 用于热加载时切换事件处理逻辑
+// 替换期间的广播要么使用旧处理器, 要么使用新处理器, 不会出现没有处理器的窗口
+func (b *UniqueBroadcast[K, T]) SetHandlers(handlers []UniqueHandler[K, T]) []HandlerID {
+	fns := make([]handlerFunc[T], len(handlers))
+	for i, h := range handlers {
+		fns[i] = handlerFunc[T](h)
+	}
+	return b.core.setHandlers("", fns)
+}
+
+// ReplaceHandler 原子地替换处理器, 保留其 HandlerID、名称与注册顺序, 返回处理器是否存在
+func (b *UniqueBroadcast[K, T]) ReplaceHandler(id HandlerID, handler UniqueHandler[K, T]) bool {
+	return b.core.replaceHandler(id, handlerFunc[T](handler))
+}
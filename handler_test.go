@@ -0,0 +1,213 @@
+package broadcast
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHandle_WithName(t *testing.T) {
+	b := New[string]()
+	b.Watch("test", "a")
+
+	anonymous := b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		return nil
+	})
+	audit := b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		return errors.New("disk full")
+	}, WithName("audit-logger"))
+
+	handlers := b.Handlers()
+	if len(handlers) != 2 || handlers[0] != (HandlerInfo{ID: anonymous}) || handlers[1] != (HandlerInfo{ID: audit, Name: "audit-logger"}) {
+		t.Errorf("unexpected handlers %+v", handlers)
+	}
+
+	var failed string
+	b.OnError(func(signal string, data string, handlerID HandlerID, err error) {
+		failed = b.HandlerName(handlerID)
+	})
+	b.EnableDeadLetter(DeadLetterConfig[string, string]{})
+	b.Broadcast("test", nil)
+
+	if failed != "audit-logger" {
+		t.Errorf("expected the error callback to resolve the name, got %q", failed)
+	}
+	if dl := b.DeadLetters(); len(dl) != 1 || dl[0].HandlerName != "audit-logger" {
+		t.Errorf("expected the dead letter to carry the handler name, got %+v", dl)
+	}
+
+	ns := b.Namespace("app.")
+	ns.HandleEvent(func(e Event[string]) error { return nil }, WithName("app-events"))
+	if infos := ns.Handlers(); len(infos) != 1 || infos[0].Name != "app-events" {
+		t.Errorf("expected the namespace to list only its own handlers, got %+v", infos)
+	}
+}
+
+func TestHandle_SlowHandlerWarning(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b := NewUnique[int, TestUniqueData]()
+	b.SetClock(clock)
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		return nil
+	}, WithName("fast"))
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		clock.now = clock.now.Add(time.Second)
+		return nil
+	}, WithName("slow"))
+
+	var warnings []string
+	b.SetSlowHandler(100*time.Millisecond, func(handler HandlerInfo, signal string, elapsed time.Duration) {
+		warnings = append(warnings, handler.Name+"@"+signal+":"+elapsed.String())
+	})
+	b.Broadcast("test", nil)
+
+	if len(warnings) != 1 || warnings[0] != "slow@test:1s" {
+		t.Errorf("expected one warning for the slow handler, got %v", warnings)
+	}
+
+	b.SetSlowHandler(0, nil)
+	b.Broadcast("test", nil)
+	if len(warnings) != 1 {
+		t.Error("expected no warnings after disabling")
+	}
+}
+
+func TestSetHandlers_AtomicSwap(t *testing.T) {
+	b := New[string]()
+	b.Watch("test", "a")
+	ns := b.Namespace("app.")
+	ns.Watch("test", "a")
+
+	var got []string
+	record := func(name string) Handler[string] {
+		return func(signal string, data string, metadata map[string]interface{}) error {
+			got = append(got, name)
+			return nil
+		}
+	}
+	old := b.Handle(record("old"))
+	ns.Handle(record("ns"))
+
+	ids := b.SetHandlers([]Handler[string]{record("new1"), record("new2")})
+	if len(ids) != 2 || b.Unhandle(old) {
+		t.Errorf("expected the old handler to be replaced, got ids %v", ids)
+	}
+	b.Broadcast("test", nil)
+	if len(got) != 2 || got[0] != "new1" || got[1] != "new2" {
+		t.Errorf("expected only the new handlers, got %v", got)
+	}
+
+	got = nil
+	ns.SetHandlers([]Handler[string]{record("ns2")})
+	ns.Broadcast("test", nil)
+	// 根处理器同样接收命名空间下的信号
+	if len(got) != 3 || got[2] != "ns2" {
+		t.Errorf("expected the namespace swap to only touch its handlers, got %v", got)
+	}
+	if len(b.Handlers()) != 3 || len(ns.Handlers()) != 1 {
+		t.Errorf("unexpected handlers %+v", b.Handlers())
+	}
+}
+
+func TestReplaceHandler_KeepsIdentity(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	var got []string
+	first := b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		got = append(got, "v1")
+		return nil
+	}, WithName("router"))
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		got = append(got, "tail")
+		return nil
+	})
+
+	ok := b.ReplaceHandler(first, func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		got = append(got, "v2")
+		return nil
+	})
+	if !ok || b.HandlerName(first) != "router" {
+		t.Error("expected the handler to keep its ID and name")
+	}
+	b.Broadcast("test", nil)
+	if len(got) != 2 || got[0] != "v2" || got[1] != "tail" {
+		t.Errorf("expected the replacement to keep its position, got %v", got)
+	}
+	if b.ReplaceHandler(999, nil) {
+		t.Error("expected false for an unknown handler")
+	}
+}
+
+func TestHandle_WithMaxConcurrency(t *testing.T) {
+	b := New[int](WithAsync(AsyncConfig{Workers: 8, QueueSize: 64}))
+	b.SetParallel(8)
+	for i := 0; i < 8; i++ {
+		b.Watch("write", i)
+	}
+
+	var running, peak, calls atomic.Int32
+	b.Handle(func(signal string, data int, _ map[string]interface{}) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		calls.Add(1)
+		return nil
+	}, WithName("db"), WithMaxConcurrency(2))
+
+	for i := 0; i < 10; i++ {
+		if err := b.Broadcast("write", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.Close()
+
+	if calls.Load() != 80 {
+		t.Errorf("expected 80 calls, got %d", calls.Load())
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("expected at most 2 concurrent calls, got %d", p)
+	}
+}
+
+func TestHandle_MaxConcurrencyReleasedOnPanic(t *testing.T) {
+	b := New[int]()
+	b.Watch("write", 1)
+	var calls atomic.Int32
+	b.Handle(func(signal string, data int, _ map[string]interface{}) error {
+		if calls.Add(1) <= 2 {
+			panic("boom")
+		}
+		return nil
+	}, WithMaxConcurrency(1))
+
+	broadcast := func() {
+		defer func() { recover() }()
+		b.Broadcast("write", nil)
+	}
+	broadcast()
+	broadcast()
+
+	done := make(chan struct{})
+	go func() {
+		b.Broadcast("write", nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected a panicking handler to release its concurrency slot")
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 calls, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,43 @@
+package broadcast
+
+import (
+	"context"
+)
+
+// CtxHandler 是接收 ctx 的处理器, ctx 携带 BroadcastCtx 传入的取消、截止时间与链路信息,
+// 可以直接传给下游调用
+type CtxHandler[T any] func(ctx context.Context, signal string, data T, metadata map[string]interface{}) error
+
+// ctxHandlerFunc 是 HandleCtx 注册的处理器
+type ctxHandlerFunc[T any] func(ctx context.Context, signal string, data T, metadata map[string]interface{}) error
+
+// invokeCtx 以投递的 ctx 调用处理器, 通过 Broadcast 广播时为 context.Background()
+// 设置了 WithDeadline 时 ctx 在截止时间到达后结束
+func invokeCtx[K comparable, T any](d *delivery[K, T], fn ctxHandlerFunc[T], signal string, data T) error {
+	ctx := d.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !d.cutoff.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, d.cutoff)
+		defer cancel()
+	}
+	return fn(ctx, signal, data, d.metadata)
+}
+
+func (c *core[K, T]) handleCtx(prefix string, handler ctxHandlerFunc[T], opts ...HandleOption) HandlerID {
+	o := newHandleOptions(opts)
+	return c.addHandler(handlerEntry[T]{ctxFn: handler, prefix: prefix, name: o.name, sem: o.semaphore()})
+}
+
+// HandleCtx 注册一个接收 ctx 的处理器, 通过 BroadcastCtx 广播时 ctx 为调用方传入的 ctx;
+// 异步或暂存执行时保留 ctx 中的值, 但不继承其取消
+func (b *Broadcast[T]) HandleCtx(handler CtxHandler[T], opts ...HandleOption) HandlerID {
+	return b.c().handleCtx(b.prefix(), ctxHandlerFunc[T](handler), opts...)
+}
+
+// HandleCtx 注册一个接收 ctx 的处理器, 与 Broadcast.HandleCtx 相同
+func (b *UniqueBroadcast[K, T]) HandleCtx(handler CtxHandler[T], opts ...HandleOption) HandlerID {
+	return b.core.handleCtx("", ctxHandlerFunc[T](handler), opts...)
+}
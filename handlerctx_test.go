@@ -0,0 +1,68 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type traceKey struct{}
+
+func TestHandleCtx(t *testing.T) {
+	b := New[string]()
+	b.Watch("order", "a")
+
+	var got context.Context
+	b.HandleCtx(func(ctx context.Context, signal string, data string, _ map[string]interface{}) error {
+		got = ctx
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "trace-1"))
+	if err := b.BroadcastCtx(ctx, "order", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Value(traceKey{}) != "trace-1" {
+		t.Error("expected the handler to receive the caller's ctx")
+	}
+	cancel()
+	if got.Err() == nil {
+		t.Error("expected synchronous handlers to share the caller's cancellation")
+	}
+
+	if err := b.Broadcast("order", nil); err != nil || got.Value(traceKey{}) != nil {
+		t.Errorf("expected a background ctx for Broadcast, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Hour)
+	err := b.Broadcast("order", nil, WithDeadline(deadline))
+	if d, ok := got.Deadline(); err != nil || !ok || !d.Equal(deadline) {
+		t.Errorf("expected WithDeadline to set the ctx deadline, got %v %v", d, err)
+	}
+}
+
+func TestHandleCtx_AsyncDetachesCancellation(t *testing.T) {
+	b := NewUnique[string, string](WithAsync(AsyncConfig{Workers: 1}))
+	b.Watch("order", owner{"a", "alice"})
+
+	result := make(chan error, 1)
+	b.HandleCtx(func(ctx context.Context, signal string, data string, _ map[string]interface{}) error {
+		if ctx.Value(traceKey{}) != "trace-2" {
+			result <- errors.New("missing trace value")
+		} else {
+			result <- ctx.Err()
+		}
+		return nil
+	}, WithName("async"))
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "trace-2"))
+	if err := b.BroadcastCtx(ctx, "order", nil); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	b.Close()
+	if err := <-result; err != nil {
+		t.Errorf("expected a live ctx with the caller's values, got %v", err)
+	}
+}
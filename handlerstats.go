@@ -0,0 +1,53 @@
+package broadcast
+
+import (
+	"sync/atomic"
+)
+
+// HandlerStats 是处理器的调用统计
+type HandlerStats struct {
+	HandlerInfo
+	// Calls 为处理器被调用的次数, 每个监听器计一次; Errors 为其中返回错误的次数
+	Calls  uint64
+	Errors uint64
+}
+
+// handlerCounters 累计处理器的调用与错误次数, 替换处理器时保留
+type handlerCounters struct {
+	calls  atomic.Uint64
+	errors atomic.Uint64
+}
+
+func (h *handlerCounters) record(err error) {
+	h.calls.Add(1)
+	if err != nil {
+		h.errors.Add(1)
+	}
+}
+
+// handlerStats 返回按注册顺序排列的处理器统计, prefix 非空时只返回通过该命名空间注册的处理器
+func (c *core[K, T]) handlerStats(prefix string) []HandlerStats {
+	var stats []HandlerStats
+	for _, h := range c.loadHandlers() {
+		if prefix != "" && h.prefix != prefix {
+			continue
+		}
+		s := HandlerStats{HandlerInfo: HandlerInfo{ID: h.id, Name: h.name}}
+		if h.counters != nil {
+			s.Calls = h.counters.calls.Load()
+			s.Errors = h.counters.errors.Load()
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// HandlerStats 返回按注册顺序排列的处理器调用次数与错误次数, 用于计算处理器的错误率
+func (b *Broadcast[T]) HandlerStats() []HandlerStats {
+	return b.c().handlerStats(b.prefix())
+}
+
+// HandlerStats 返回按注册顺序排列的处理器调用次数与错误次数, 用于计算处理器的错误率
+func (b *UniqueBroadcast[K, T]) HandlerStats() []HandlerStats {
+	return b.core.handlerStats("")
+}
@@ -0,0 +1,43 @@
+package broadcast
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHandlerStats_CountsCallsAndErrors(t *testing.T) {
+	b := New[string]()
+	b.Watch("orders", "a")
+	b.Watch("orders", "b")
+	ok := b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		return nil
+	}, WithName("ok"))
+	failing := b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		if data == "b" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	b.Broadcast("orders", nil)
+	b.Broadcast("orders", nil)
+	stats := b.HandlerStats()
+	if len(stats) != 2 {
+		t.Fatalf("expected two handlers, got %+v", stats)
+	}
+	if s := stats[0]; s.ID != ok || s.Name != "ok" || s.Calls != 4 || s.Errors != 0 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+	if s := stats[1]; s.ID != failing || s.Calls != 4 || s.Errors != 2 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+
+	// 替换处理器保留累计的统计
+	b.ReplaceHandler(failing, func(signal string, data string, metadata map[string]interface{}) error {
+		return nil
+	})
+	b.Broadcast("orders", nil)
+	if s := b.HandlerStats()[1]; s.Calls != 6 || s.Errors != 2 {
+		t.Errorf("expected the stats to survive ReplaceHandler, got %+v", s)
+	}
+}
@@ -0,0 +1,129 @@
+package broadcast
+
+import (
+	"sync"
+	"time"
+)
+
+// HealthStatus 是信号的健康状态
+type HealthStatus int
+
+const (
+	// HealthOK 最近的投递没有连续失败
+	HealthOK HealthStatus = iota
+	// HealthDegraded 连续失败的投递次数达到 HealthConfig.DegradedAfter
+	HealthDegraded
+)
+
+func (s HealthStatus) String() string {
+	if s == HealthDegraded {
+		return "degraded"
+	}
+	return "ok"
+}
+
+// SignalHealth 是根据处理器结果得出的信号健康状况
+type SignalHealth struct {
+	Status HealthStatus
+	// ConsecutiveFailures 为连续失败的投递次数, 任一处理器返回错误即视为该次投递失败
+	ConsecutiveFailures int
+	// LastError 为最近一次失败投递中最后一个处理器错误
+	LastError   error
+	LastFailure time.Time
+	LastSuccess time.Time
+}
+
+// HealthConfig 信号健康状态跟踪的配置
+type HealthConfig struct {
+	// DegradedAfter 连续失败达到该次数时进入 HealthDegraded, 默认为 1
+	DegradedAfter int
+	// OnChange 在信号的健康状态变化时调用, 可用于告警
+	OnChange func(signal string, from, to SignalHealth)
+}
+
+// healthTracker 维护每个信号的健康状况
+type healthTracker struct {
+	mu      sync.Mutex
+	config  HealthConfig
+	signals map[string]*SignalHealth
+}
+
+func newHealthTracker(config HealthConfig) *healthTracker {
+	if config.DegradedAfter <= 0 {
+		config.DegradedAfter = 1
+	}
+	return &healthTracker{config: config, signals: make(map[string]*SignalHealth)}
+}
+
+// record 记录一次投递的结果, err 为 nil 表示所有处理器都成功
+func (h *healthTracker) record(signal string, err error, now time.Time) {
+	h.mu.Lock()
+	s, ok := h.signals[signal]
+	if !ok {
+		s = &SignalHealth{}
+		h.signals[signal] = s
+	}
+	from := *s
+	if err == nil {
+		s.Status, s.ConsecutiveFailures, s.LastSuccess = HealthOK, 0, now
+	} else {
+		s.ConsecutiveFailures++
+		s.LastError, s.LastFailure = err, now
+		if s.ConsecutiveFailures >= h.config.DegradedAfter {
+			s.Status = HealthDegraded
+		}
+	}
+	to := *s
+	h.mu.Unlock()
+
+	if from.Status != to.Status && h.config.OnChange != nil {
+		h.config.OnChange(signal, from, to)
+	}
+}
+
+func (h *healthTracker) health(signal string) SignalHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if s, ok := h.signals[signal]; ok {
+		return *s
+	}
+	return SignalHealth{}
+}
+
+func (c *core[K, T]) setHealthTracking(config *HealthConfig) {
+	c.updateSettings(func(s *settings[K, T]) {
+		if config != nil {
+			s.health = newHealthTracker(*config)
+		} else {
+			s.health = nil
+		}
+	})
+}
+
+func (c *core[K, T]) signalHealth(signal string) SignalHealth {
+	if h := c.loadSettings().health; h != nil {
+		return h.health(signal)
+	}
+	return SignalHealth{}
+}
+
+// SetHealthTracking 开启信号健康状态跟踪, 传入 nil 关闭
+func (b *Broadcast[T]) SetHealthTracking(config *HealthConfig) {
+	b.c().setHealthTracking(config)
+}
+
+// Health 返回信号的健康状况, 没有开启跟踪或信号尚未投递时为 HealthOK 的零值
+func (b *Broadcast[T]) Health(signal string) SignalHealth {
+	return b.c().signalHealth(b.sig(signal))
+}
+
+// SetHealthTracking 开启信号健康状态跟踪, 传入 nil 关闭
+func (b *UniqueBroadcast[K, T]) SetHealthTracking(config *HealthConfig) {
+	b.core.setHealthTracking(config)
+}
+
+// Health 返回信号的健康状况, 没有开启跟踪或信号尚未投递时为 HealthOK 的零值
+func (b *UniqueBroadcast[K, T]) Health(signal string) SignalHealth {
+	return b.core.signalHealth(signal)
+}
@@ -0,0 +1,51 @@
+package broadcast
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealth_Transitions(t *testing.T) {
+	clock := &manualClock{now: time.Unix(100, 0)}
+	var changes []string
+	b := New[string](WithClock(clock), WithHealthTracking(HealthConfig{
+		DegradedAfter: 2,
+		OnChange: func(signal string, from, to SignalHealth) {
+			changes = append(changes, signal+":"+from.Status.String()+"->"+to.Status.String())
+		},
+	}))
+	b.Watch("payments", "a")
+
+	fail := errors.New("gateway down")
+	var result error
+	b.Handle(func(signal string, data string, _ map[string]interface{}) error {
+		return result
+	})
+
+	if h := b.Health("payments"); h.Status != HealthOK {
+		t.Fatalf("expected an untracked signal to be ok, got %v", h.Status)
+	}
+
+	result = fail
+	b.Broadcast("payments", nil)
+	if h := b.Health("payments"); h.Status != HealthOK || h.ConsecutiveFailures != 1 || h.LastError != fail {
+		t.Fatalf("expected one failure below the threshold, got %+v", h)
+	}
+	b.Broadcast("payments", nil)
+	if h := b.Health("payments"); h.Status != HealthDegraded || !h.LastFailure.Equal(clock.now) {
+		t.Fatalf("expected the signal to degrade, got %+v", h)
+	}
+
+	result = nil
+	b.Broadcast("payments", nil)
+	h := b.Health("payments")
+	if h.Status != HealthOK || h.ConsecutiveFailures != 0 || h.LastError != fail {
+		t.Errorf("expected recovery to keep the last error, got %+v", h)
+	}
+
+	want := []string{"payments:ok->degraded", "payments:degraded->ok"}
+	if len(changes) != 2 || changes[0] != want[0] || changes[1] != want[1] {
+		t.Errorf("expected transitions %v, got %v", want, changes)
+	}
+}
@@ -0,0 +1,231 @@
+package broadcast
+
+import (
+	"cmp"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"unique"
+)
+
+// DefaultHistorySize 是 HistoryConfig.Size 的默认值
+const DefaultHistorySize = 16
+
+// HistoryConfig 配置广播历史, 历史用于 HandleWithReplay 向新处理器回放最近的事件
+type HistoryConfig struct {
+	// Size 为每个信号保留的最近广播数量, 默认为 DefaultHistorySize;
+	// Compact 时为每个信号保留的 key 数量, 超出时丢弃最久没有更新的 key
+	Size int
+	// Compact 开启日志压缩: 每个信号只为每个监听器 key 保留最近的一次事件,
+	// 类似 Kafka 的日志压缩, 回放时每个 key 只收到最新的事件即可重建当前状态
+	Compact bool
+}
+
+// history 按信号保留最近的广播, 每条记录包含广播时的监听器快照
+// 记录与加载处理器列表在同一把锁内完成, 注册回放处理器时持有同一把锁,
+// 因此每次广播要么在回放的快照中, 要么投递给新处理器, 不会遗漏也不会重复
+type history[K comparable, T any] struct {
+	size    int
+	compact bool
+
+	mu        sync.Mutex
+	signals   map[string]*historyRing[K, T]
+	compacted map[string]*compactedLog[K, T]
+}
+
+// historyRing 是单个信号的环形缓冲
+type historyRing[K comparable, T any] struct {
+	events []delivery[K, T]
+	next   int
+}
+
+// compactedLog 是单个信号压缩后的历史, 每个 key 只保留最近的一次事件
+type compactedLog[K comparable, T any] struct {
+	latest map[unique.Handle[K]]compactedEvent[K, T]
+	// order 为下一个事件的顺序号, 用于按广播顺序回放与淘汰最久没有更新的 key
+	order uint64
+}
+
+type compactedEvent[K comparable, T any] struct {
+	d     delivery[K, T]
+	l     listener[K, T]
+	order uint64
+}
+
+func newHistory[K comparable, T any](config HistoryConfig) *history[K, T] {
+	if config.Size <= 0 {
+		config.Size = DefaultHistorySize
+	}
+	h := &history[K, T]{size: config.Size, compact: config.Compact}
+	if config.Compact {
+		h.compacted = make(map[string]*compactedLog[K, T])
+	} else {
+		h.signals = make(map[string]*historyRing[K, T])
+	}
+	return h
+}
+
+// add 记录一次广播, 调用方持有 h.mu
+func (h *history[K, T]) add(d delivery[K, T]) {
+	if len(d.listeners) == 0 {
+		return
+	}
+	// 只保留回放需要的字段, 不持有处理器列表与调用方的 ctx
+	d.handlers, d.ctx, d.parts, d.journaled = nil, nil, nil, false
+	if h.compact {
+		h.addCompacted(d)
+		return
+	}
+	r := h.signals[d.signal]
+	if r == nil {
+		r = &historyRing[K, T]{}
+		h.signals[d.signal] = r
+	}
+	if len(r.events) < h.size {
+		r.events = append(r.events, d)
+		return
+	}
+	r.events[r.next] = d
+	r.next = (r.next + 1) % h.size
+}
+
+// addCompacted 以本次广播替换每个监听器 key 之前的事件, 调用方持有 h.mu
+func (h *history[K, T]) addCompacted(d delivery[K, T]) {
+	log := h.compacted[d.signal]
+	if log == nil {
+		log = &compactedLog[K, T]{latest: make(map[unique.Handle[K]]compactedEvent[K, T])}
+		h.compacted[d.signal] = log
+	}
+	listeners := d.listeners
+	d.listeners = nil
+	for _, l := range listeners {
+		if _, ok := log.latest[l.key]; !ok && len(log.latest) >= h.size {
+			log.evictOldest()
+		}
+		log.latest[l.key] = compactedEvent[K, T]{d: d, l: l, order: log.order}
+		log.order++
+	}
+}
+
+// evictOldest 丢弃最久没有更新的 key
+func (log *compactedLog[K, T]) evictOldest() {
+	var oldest unique.Handle[K]
+	order := log.order
+	for key, e := range log.latest {
+		if e.order < order {
+			oldest, order = key, e.order
+		}
+	}
+	delete(log.latest, oldest)
+}
+
+// last 按广播顺序返回信号最近的 n 次广播, 调用方持有 h.mu
+// 压缩模式下返回最近更新的 n 个 key 各自最新的事件, 每个事件只包含该 key 的监听器
+func (h *history[K, T]) last(signal string, n int) []delivery[K, T] {
+	if h.compact {
+		return h.lastCompacted(signal, n)
+	}
+	r := h.signals[signal]
+	if r == nil || n <= 0 {
+		return nil
+	}
+	events := make([]delivery[K, T], 0, len(r.events))
+	events = append(events, r.events[r.next:]...)
+	events = append(events, r.events[:r.next]...)
+	return events[max(len(events)-n, 0):]
+}
+
+func (h *history[K, T]) lastCompacted(signal string, n int) []delivery[K, T] {
+	log := h.compacted[signal]
+	if log == nil || n <= 0 {
+		return nil
+	}
+	latest := slices.SortedFunc(maps.Values(log.latest), func(a, b compactedEvent[K, T]) int {
+		return cmp.Compare(a.order, b.order)
+	})
+	latest = latest[max(len(latest)-n, 0):]
+	events := make([]delivery[K, T], len(latest))
+	for i, e := range latest {
+		events[i] = e.d
+		events[i].listeners = []listener[K, T]{e.l}
+	}
+	return events
+}
+
+func (c *core[K, T]) setHistory(config *HistoryConfig) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.history = nil
+		if config != nil {
+			s.history = newHistory[K, T](*config)
+		}
+	})
+}
+
+// loadHandlersRecorded 加载处理器列表, 开启历史时同时记录本次广播
+// 开启暂存时没有监听器或处理器的广播会稍后重新投递, 不在此时记录
+func (c *core[K, T]) loadHandlersRecorded(settings *settings[K, T], d *delivery[K, T]) []handlerEntry[T] {
+	h := settings.history
+	if h == nil {
+		return c.loadHandlers()
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	handlers := c.loadHandlers()
+	if settings.buffer == nil || (len(d.listeners) > 0 && len(handlers) > 0) {
+		h.add(*d)
+	}
+	return handlers
+}
+
+// handleWithReplay 注册处理器, 并在其接收实时事件之前同步回放 signal 最近的 n 次广播
+// 回放期间到达的实时事件在回放结束后按顺序投递, 返回回放中处理器错误的组合
+func (c *core[K, T]) handleWithReplay(prefix, signal string, n int, handler handlerFunc[T]) (HandlerID, error) {
+	settings := c.loadSettings()
+	h := settings.history
+	if h == nil {
+		return 0, ErrNoHistory
+	}
+
+	g := &replayGate[T]{handler: handler}
+	h.mu.Lock()
+	events := h.last(signal, n)
+	id := c.handle(prefix, g.handle)
+	h.mu.Unlock()
+
+	var errs []error
+	name, _ := strings.CutPrefix(signal, prefix)
+	for _, d := range events {
+		for _, l := range d.listeners {
+			data := transformAll(settings.transforms, d.signal, l.data.Value(), d.metadata)
+			if err := handler(name, data, d.metadata); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	g.open()
+	return id, errors.Join(errs...)
+}
+
+// SetHistory 开启广播历史, 传入 nil 关闭; 重新设置时已有的历史被丢弃
+func (b *Broadcast[T]) SetHistory(config *HistoryConfig) {
+	b.c().setHistory(config)
+}
+
+// HandleWithReplay 注册一个处理器, 先同步回放 signal 最近的 n 次广播, 再开始接收实时事件
+// 回放与实时事件之间没有遗漏也没有重复; 没有开启历史时返回 ErrNoHistory 且不注册处理器
+func (b *Broadcast[T]) HandleWithReplay(signal string, n int, handler Handler[T]) (HandlerID, error) {
+	return b.c().handleWithReplay(b.prefix(), b.sig(signal), n, handlerFunc[T](handler))
+}
+
+// SetHistory 开启广播历史, 传入 nil 关闭; 重新设置时已有的历史被丢弃
+func (b *UniqueBroadcast[K, T]) SetHistory(config *HistoryConfig) {
+	b.core.setHistory(config)
+}
+
+// HandleWithReplay 注册一个处理器, 先同步回放 signal 最近的 n 次广播, 再开始接收实时事件
+// 回放与实时事件之间没有遗漏也没有重复; 没有开启历史时返回 ErrNoHistory 且不注册处理器
+func (b *UniqueBroadcast[K, T]) HandleWithReplay(signal string, n int, handler UniqueHandler[K, T]) (HandlerID, error) {
+	return b.core.handleWithReplay("", signal, n, handlerFunc[T](handler))
+}
@@ -0,0 +1,156 @@
+package broadcast
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestHandleWithReplay_LastN(t *testing.T) {
+	b := New[string](WithHistory(HistoryConfig{Size: 3}))
+	if _, err := New[string]().HandleWithReplay("price", 1, nil); !errors.Is(err, ErrNoHistory) {
+		t.Fatalf("expected ErrNoHistory without history, got %v", err)
+	}
+
+	b.Watch("price", "AAPL")
+	b.Broadcast("price", map[string]interface{}{"v": 0}) // 没有处理器, 但仍然记录
+	for v := 1; v <= 4; v++ {
+		b.Broadcast("price", map[string]interface{}{"v": v})
+	}
+	b.Broadcast("other", map[string]interface{}{"v": 99})
+
+	var seen []int
+	id, err := b.HandleWithReplay("price", 2, func(signal string, data string, metadata map[string]interface{}) error {
+		if signal == "price" {
+			seen = append(seen, metadata["v"].(int))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Broadcast("price", map[string]interface{}{"v": 5})
+	if want := []int{3, 4, 5}; !slices.Equal(seen, want) {
+		t.Errorf("expected the last two events then live ones %v, got %v", want, seen)
+	}
+
+	var all []int
+	b.HandleWithReplay("price", 10, func(signal string, data string, metadata map[string]interface{}) error {
+		all = append(all, metadata["v"].(int))
+		return nil
+	})
+	if want := []int{3, 4, 5}; !slices.Equal(all, want) {
+		t.Errorf("expected replay limited to the history size %v, got %v", want, all)
+	}
+	if !b.Unhandle(id) {
+		t.Error("expected the replay handler to be removable by ID")
+	}
+}
+
+func TestHandleWithReplay_ErrorsAndNamespace(t *testing.T) {
+	root := NewUnique[string, string](WithHistory(HistoryConfig{}))
+	root.Watch("lock", owner{"db", "a"})
+	root.Watch("lock", owner{"cache", "b"})
+	root.Broadcast("lock", nil)
+
+	fail := errors.New("fail")
+	var holders []string
+	_, err := root.HandleWithReplay("lock", 1, func(signal string, data string, metadata map[string]interface{}) error {
+		holders = append(holders, data)
+		return fail
+	})
+	if !errors.Is(err, fail) {
+		t.Errorf("expected replay errors returned, got %v", err)
+	}
+	if len(holders) != 2 {
+		t.Errorf("expected replay to every listener of the event, got %v", holders)
+	}
+
+	b := New[string](WithHistory(HistoryConfig{}))
+	ns := b.Namespace("tenant")
+	ns.Watch("orders", "x")
+	ns.Broadcast("orders", nil)
+	var signals []string
+	ns.HandleWithReplay("orders", 1, func(signal string, data string, metadata map[string]interface{}) error {
+		signals = append(signals, signal)
+		return nil
+	})
+	if len(signals) != 1 || signals[0] != "orders" {
+		t.Errorf("expected the namespace-relative signal, got %v", signals)
+	}
+}
+
+func TestHandleWithReplay_NoGapNoOverlap(t *testing.T) {
+	b := New[int](WithHistory(HistoryConfig{Size: 1000}))
+	b.Watch("tick", 0)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for v := 1; v <= 500; v++ {
+			b.Broadcast("tick", map[string]interface{}{"v": v})
+		}
+	}()
+
+	var seen []int
+	var seenMu sync.Mutex
+	b.HandleWithReplay("tick", 1000, func(signal string, data int, metadata map[string]interface{}) error {
+		seenMu.Lock()
+		seen = append(seen, metadata["v"].(int))
+		seenMu.Unlock()
+		return nil
+	})
+	<-done
+
+	seenMu.Lock()
+	defer seenMu.Unlock()
+	if len(seen) != 500 {
+		t.Fatalf("expected every event exactly once, got %d", len(seen))
+	}
+	for i, v := range seen {
+		if v != i+1 {
+			t.Fatalf("expected events in order without gaps, got %d at %d", v, i)
+		}
+	}
+}
+
+func TestHandleWithReplay_Compacted(t *testing.T) {
+	b := NewUnique[string, string](WithHistory(HistoryConfig{Size: 2, Compact: true}))
+	b.Watch("lock", owner{"db", "a"})
+	b.Broadcast("lock", nil)
+	b.UpdateWatch("lock", owner{"db", "b"})
+	b.Watch("lock", owner{"cache", "c"})
+	b.Broadcast("lock", nil)
+	b.Unwatch("lock", owner{"cache", "c"})
+	b.UpdateWatch("lock", owner{"db", "d"})
+	b.Broadcast("lock", nil)
+
+	var state []string
+	replay := func(signal string, data string, metadata map[string]interface{}) error {
+		state = append(state, data)
+		return nil
+	}
+	if _, err := b.HandleWithReplay("lock", 10, replay); err != nil {
+		t.Fatal(err)
+	}
+	// 每个 key 只回放最新的事件, 按最近一次更新的顺序
+	if want := []string{"c", "d"}; !slices.Equal(state, want) {
+		t.Errorf("expected only the latest event per key %v, got %v", want, state)
+	}
+
+	state = nil
+	b.HandleWithReplay("lock", 1, replay)
+	if want := []string{"d"}; !slices.Equal(state, want) {
+		t.Errorf("expected the most recently updated key, got %v", state)
+	}
+
+	// 超过 Size 个 key 时丢弃最久没有更新的 key
+	b.Watch("lock", owner{"queue", "e"})
+	b.Broadcast("lock", nil)
+	state = nil
+	b.HandleWithReplay("lock", 10, replay)
+	if want := []string{"d", "e"}; !slices.Equal(state, want) {
+		t.Errorf("expected the oldest key evicted, got %v", state)
+	}
+}
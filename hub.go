@@ -0,0 +1,213 @@
+package broadcast
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// HubConfig Hub 配置
+type HubConfig struct {
+	// Workers 大于 0 时所有广播器共享一个异步工作池
+	Workers int
+	// QueueSize 共享工作池的队列容量, 默认为 1024; 队列满时发布者阻塞
+	QueueSize int
+	// Options 应用于 Hub 创建的每个广播器
+	Options []Option
+	// Metrics 非 nil 时作为所有广播器共享的计量接收器, 覆盖 Options 中的 WithMetrics
+	// 各 topic 的计量事件以各自的信号名上报, 需要区分 topic 时应使用不同的信号名
+	Metrics Metrics
+}
+
+// Hub 在同一生命周期下管理多个类型化的广播器, 按 topic 区分
+// 适用于有大量事件类型的应用, 所有广播器共享工作池与计量接收器并通过一次 Close 关闭
+type Hub struct {
+	config HubConfig
+	pool   *workerPool
+
+	mu     sync.Mutex
+	topics map[string]hubMember
+	closed bool
+}
+
+// hubMember 是 Hub 管理的广播器
+type hubMember interface {
+	Close()
+	Pending() int
+}
+
+// NewHub 创建 Hub
+func NewHub(config HubConfig) *Hub {
+	h := &Hub{config: config, topics: make(map[string]hubMember)}
+	if config.Workers > 0 {
+		h.pool = newWorkerPool(config.Workers, config.QueueSize)
+	}
+	return h
+}
+
+// Of 返回 topic 对应的 Broadcast, 不存在时创建
+// 同一 topic 以不同类型获取时 panic, 这通常是 topic 名称冲突导致的编程错误
+func Of[T comparable](h *Hub, topic string) *Broadcast[T] {
+	return hubGet(h, topic, func() *Broadcast[T] {
+		b := New[T](h.config.Options...)
+		if h.pool != nil {
+			b.core.useWorkerPool(h.pool)
+		}
+		if h.config.Metrics != nil {
+			b.core.setMetrics(h.config.Metrics)
+		}
+		return b
+	})
+}
+
+// OfUnique 返回 topic 对应的 UniqueBroadcast, 不存在时创建, 类型冲突时 panic
+func OfUnique[K comparable, T any](h *Hub, topic string) *UniqueBroadcast[K, T] {
+	return hubGet(h, topic, func() *UniqueBroadcast[K, T] {
+		b := NewUnique[K, T](h.config.Options...)
+		if h.pool != nil {
+			b.core.useWorkerPool(h.pool)
+		}
+		if h.config.Metrics != nil {
+			b.core.setMetrics(h.config.Metrics)
+		}
+		return b
+	})
+}
+
+func hubGet[B hubMember](h *Hub, topic string, create func() B) B {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if m, ok := h.topics[topic]; ok {
+		b, ok := m.(B)
+		if !ok {
+			panic(fmt.Sprintf("broadcast: hub topic %q is %T, not %T", topic, m, b))
+		}
+		return b
+	}
+	if h.closed {
+		panic(fmt.Sprintf("broadcast: hub closed, cannot create topic %q", topic))
+	}
+	b := create()
+	h.topics[topic] = b
+	return b
+}
+
+// Topics 返回按字典序排列的 topic
+func (h *Hub) Topics() []string {
+	h.mu.Lock()
+	topics := make([]string, 0, len(h.topics))
+	for topic := range h.topics {
+		topics = append(topics, topic)
+	}
+	h.mu.Unlock()
+
+	sort.Strings(topics)
+	return topics
+}
+
+// Pending 返回所有广播器中等待异步投递的事件数量
+func (h *Hub) Pending() int {
+	if h.pool != nil {
+		return h.pool.len()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	n := 0
+	for _, m := range h.topics {
+		n += m.Pending()
+	}
+	return n
+}
+
+// Close 关闭所有广播器并等待共享工作池中的事件投递完成, 之后 Of 会 panic
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	members := make([]hubMember, 0, len(h.topics))
+	for _, m := range h.topics {
+		members = append(members, m)
+	}
+	h.mu.Unlock()
+
+	for _, m := range members {
+		m.Close()
+	}
+	if h.pool != nil {
+		h.pool.close()
+	}
+}
+
+// workerPool 是多个广播器共享的有界任务队列
+type workerPool struct {
+	mu     sync.RWMutex
+	tasks  chan func()
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newWorkerPool(workers, queueSize int) *workerPool {
+	if queueSize <= 0 {
+		queueSize = 1024
+	}
+	p := &workerPool{tasks: make(chan func(), queueSize)}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer p.wg.Done()
+			for task := range p.tasks {
+				task()
+			}
+		}()
+	}
+	return p
+}
+
+// submit 将任务放入队列, 队列满时阻塞, 已关闭时返回 false
+func (p *workerPool) submit(task func()) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return false
+	}
+	p.tasks <- task
+	return true
+}
+
+func (p *workerPool) len() int {
+	return len(p.tasks)
+}
+
+func (p *workerPool) close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// submit 将投递交给共享工作池
+// 单独成函数使闭包捕获引起的堆分配只发生在共享模式下
+func (d *dispatcher[K, T]) submit(item delivery[K, T]) bool {
+	return d.shared.submit(func() {
+		d.run(item)
+	})
+}
+
+// useWorkerPool 使异步投递进入共享工作池
+func (c *core[K, T]) useWorkerPool(p *workerPool) {
+	var previous *dispatcher[K, T]
+	c.updateSettings(func(s *settings[K, T]) {
+		previous = s.async
+		s.async = &dispatcher[K, T]{shared: p, run: c.run}
+	})
+	if previous != nil {
+		previous.close()
+	}
+}
@@ -0,0 +1,94 @@
+package broadcast
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+type orderPlaced struct {
+	ID int
+}
+
+func TestHub_TypedTopics(t *testing.T) {
+	h := NewHub(HubConfig{Workers: 2})
+
+	orders := Of[string](h, "orders")
+	if Of[string](h, "orders") != orders {
+		t.Error("expected the same broadcaster for the same topic")
+	}
+	users := OfUnique[int, TestUniqueData](h, "users")
+
+	var delivered atomic.Int32
+	orders.Watch("placed", "warehouse")
+	orders.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		delivered.Add(1)
+		return nil
+	})
+	users.Watch("login", &TestUniquer{data: TestUniqueData{ID: 1}})
+	users.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		delivered.Add(1)
+		return nil
+	})
+
+	for i := 0; i < 10; i++ {
+		orders.Broadcast("placed", nil)
+		users.Broadcast("login", nil)
+	}
+	h.Close()
+
+	if delivered.Load() != 20 {
+		t.Errorf("expected all events to be delivered on Close, got %d", delivered.Load())
+	}
+	if !slices.Equal(h.Topics(), []string{"orders", "users"}) {
+		t.Errorf("unexpected topics %v", h.Topics())
+	}
+	if h.Pending() != 0 {
+		t.Errorf("expected no pending events, got %d", h.Pending())
+	}
+}
+
+func TestHub_TypeConflictPanics(t *testing.T) {
+	h := NewHub(HubConfig{})
+	defer h.Close()
+	Of[string](h, "orders")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a conflicting topic type")
+		}
+	}()
+	Of[int](h, "orders")
+}
+
+func TestHub_SharedOptions(t *testing.T) {
+	h := NewHub(HubConfig{Options: []Option{WithPendingBuffer(8)}})
+	defer h.Close()
+
+	b := Of[orderPlaced](h, "orders")
+	b.Broadcast("placed", nil)
+	if b.Buffered() != 1 {
+		t.Errorf("expected the hub options to apply to each broadcaster, got %d buffered", b.Buffered())
+	}
+}
+
+func TestHub_SharedMetrics(t *testing.T) {
+	m := newRecordingMetrics()
+	h := NewHub(HubConfig{Metrics: m})
+	defer h.Close()
+
+	orders := Of[orderPlaced](h, "orders")
+	users := OfUnique[int, TestUniqueData](h, "users")
+	orders.Watch("placed", orderPlaced{})
+	users.Watch("joined", &TestUniquer{data: TestUniqueData{ID: 1}})
+	orders.Handle(func(signal string, data orderPlaced, metadata map[string]interface{}) error { return nil })
+	users.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error { return nil })
+
+	orders.Broadcast("placed", nil)
+	users.Broadcast("joined", nil)
+	for _, signal := range []string{"placed", "joined"} {
+		if got, want := m.counts(signal), [4]int{1, 1, 0, 0}; got != want {
+			t.Errorf("expected %s to be reported to the shared sink as %v, got %v", signal, want, got)
+		}
+	}
+}
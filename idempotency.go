@@ -0,0 +1,106 @@
+package broadcast
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// WithEventID 设置广播的事件 ID, 幂等处理器以此识别重复投递
+// 未设置时使用广播序号; 开启日志后事件 ID 随广播写入日志, 回放时保持不变
+func WithEventID(id string) BroadcastOption {
+	return func(o *broadcastOptions) {
+		o.id = id
+	}
+}
+
+// DedupStore 记录处理器已经处理过的事件
+type DedupStore interface {
+	// Contains 返回 scope 下是否已记录 id
+	Contains(scope, id string) (bool, error)
+	// Add 在处理成功后记录 id
+	Add(scope, id string) error
+}
+
+// IdempotencyConfig 幂等处理器配置
+type IdempotencyConfig struct {
+	// Name 处理器在重启之间保持不变的名称, 作为 DedupStore 的 scope
+	Name string
+	// Store 记录已处理的事件
+	Store DedupStore
+}
+
+// eventID 返回投递的事件 ID, 未设置时为序号
+func (d *delivery[K, T]) eventID() string {
+	if d.id != "" {
+		return d.id
+	}
+	return strconv.FormatUint(d.seq, 10)
+}
+
+// dedupKey 返回事件与监听器的去重键, 同一事件在各监听器上分别记录,
+// 部分监听器失败后重试时只重新处理失败的监听器
+func dedupKey[K comparable, T any](d *delivery[K, T], l listener[K, T]) string {
+	return d.eventID() + "/" + fmt.Sprint(l.key.Value())
+}
+
+// handleIdempotent 注册幂等处理器
+func (c *core[K, T]) handleIdempotent(config IdempotencyConfig, prefix string, handler handlerFunc[T]) HandlerID {
+	return c.addHandler(handlerEntry[T]{fn: handler, prefix: prefix, dedup: &config})
+}
+
+// MemoryDedupStore 是基于内存的 DedupStore, 每个 scope 最多保留 size 个最近的 ID
+type MemoryDedupStore struct {
+	size int
+
+	mu     sync.Mutex
+	scopes map[string]*dedupScope
+}
+
+type dedupScope struct {
+	ids   map[string]struct{}
+	order []string
+}
+
+// NewMemoryDedupStore 创建内存 DedupStore, size <= 0 时不限制数量
+func NewMemoryDedupStore(size int) *MemoryDedupStore {
+	return &MemoryDedupStore{size: size, scopes: make(map[string]*dedupScope)}
+}
+
+// Contains 返回 scope 下是否已记录 id
+func (s *MemoryDedupStore) Contains(scope, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sc, ok := s.scopes[scope]
+	if !ok {
+		return false, nil
+	}
+	_, ok = sc.ids[id]
+	return ok, nil
+}
+
+// Add 记录 id, 超过容量时淘汰最早的记录
+func (s *MemoryDedupStore) Add(scope, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sc, ok := s.scopes[scope]
+	if !ok {
+		sc = &dedupScope{ids: make(map[string]struct{})}
+		s.scopes[scope] = sc
+	}
+	if _, ok := sc.ids[id]; ok {
+		return nil
+	}
+	sc.ids[id] = struct{}{}
+	if s.size <= 0 {
+		return nil
+	}
+	sc.order = append(sc.order, id)
+	if len(sc.order) > s.size {
+		delete(sc.ids, sc.order[0])
+		sc.order = sc.order[1:]
+	}
+	return nil
+}
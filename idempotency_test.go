@@ -0,0 +1,108 @@
+package broadcast
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestIdempotent_SkipsSeenEventIDs(t *testing.T) {
+	b := New[string]()
+	b.Watch("orders", "a")
+	b.Watch("orders", "b")
+
+	store := NewMemoryDedupStore(0)
+	var got []string
+	b.HandleIdempotent(IdempotencyConfig{Name: "projection", Store: store}, func(signal string, data string, metadata map[string]interface{}) error {
+		if data == "b" && metadata["fail"] == true {
+			return errors.New("fail")
+		}
+		got = append(got, data)
+		return nil
+	})
+
+	b.Broadcast("orders", map[string]interface{}{"fail": true}, WithEventID("order-1"))
+	// 重复投递只重新处理上次失败的监听器
+	b.Broadcast("orders", nil, WithEventID("order-1"))
+	b.Broadcast("orders", nil, WithEventID("order-1"))
+	if !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("expected each listener to process order-1 once, got %v", got)
+	}
+
+	got = nil
+	b.Broadcast("orders", nil)
+	b.Broadcast("orders", nil)
+	if len(got) != 4 {
+		t.Errorf("expected broadcasts without an ID to be distinct by sequence, got %v", got)
+	}
+}
+
+func TestIdempotent_DurableReplay(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewUnique[int, TestUniqueData]()
+	b.EnableJournal(JournalConfig{Journal: journal})
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	// 处理成功但进度未保存, 模拟确认前崩溃
+	cursors := NewMemoryCursorStore()
+	dedup := NewMemoryDedupStore(100)
+	calls := 0
+	config := DurableConfig{Name: "h", Cursors: failingCursorStore{}, Dedup: dedup}
+	b.HandleDurable(config, func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		calls++
+		return nil
+	})
+	b.Broadcast("test", nil, WithEventID("e1"))
+	journal.Close()
+
+	journal, err = OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+	restarted := NewUnique[int, TestUniqueData]()
+	restarted.EnableJournal(JournalConfig{Journal: journal})
+	restarted.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	config.Cursors = cursors
+	restarted.HandleDurable(config, func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		calls++
+		return nil
+	})
+	if calls != 1 {
+		t.Errorf("expected the redelivered event to be deduplicated, got %d calls", calls)
+	}
+	if cursor, _ := cursors.LoadCursor("h"); cursor != 1 {
+		t.Errorf("expected the deduplicated event to be acked, got cursor %d", cursor)
+	}
+}
+
+func TestMemoryDedupStore_Evicts(t *testing.T) {
+	store := NewMemoryDedupStore(2)
+	store.Add("h", "1")
+	store.Add("h", "2")
+	store.Add("h", "3")
+	if ok, _ := store.Contains("h", "1"); ok {
+		t.Error("expected the oldest id to be evicted")
+	}
+	if ok, _ := store.Contains("h", "3"); !ok {
+		t.Error("expected the newest id to be kept")
+	}
+	if ok, _ := store.Contains("other", "3"); ok {
+		t.Error("expected scopes to be independent")
+	}
+}
+
+// failingCursorStore 不保存进度
+type failingCursorStore struct{}
+
+func (failingCursorStore) LoadCursor(name string) (uint64, error) { return 0, nil }
+
+func (failingCursorStore) SaveCursor(name string, seq uint64) error {
+	return errors.New("unavailable")
+}
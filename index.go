@@ -0,0 +1,72 @@
+package broadcast
+
+import (
+	"sort"
+	"sync"
+	"unique"
+)
+
+// reverseIndex 记录每个 key 正在监听的信号
+// 索引在信号锁内更新, 只会多记录已失效的信号 (CleanAll 期间), 不会遗漏;
+// 读取时再以监听器快照校验. 信号名经过驻留, 监听同一信号的大量 key 共享一份字符串
+type reverseIndex[K comparable] struct {
+	mu   sync.Mutex
+	keys map[unique.Handle[K]]map[Signal]struct{}
+}
+
+func (x *reverseIndex[K]) add(key unique.Handle[K], signal string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.keys == nil {
+		x.keys = make(map[unique.Handle[K]]map[Signal]struct{})
+	}
+	signals, ok := x.keys[key]
+	if !ok {
+		signals = make(map[Signal]struct{})
+		x.keys[key] = signals
+	}
+	signals[Intern(signal)] = struct{}{}
+}
+
+func (x *reverseIndex[K]) remove(key unique.Handle[K], signal string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	signals := x.keys[key]
+	delete(signals, Intern(signal))
+	if len(signals) == 0 {
+		delete(x.keys, key)
+	}
+}
+
+func (x *reverseIndex[K]) signals(key unique.Handle[K]) []string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	signals := make([]string, 0, len(x.keys[key]))
+	for signal := range x.keys[key] {
+		signals = append(signals, signal.String())
+	}
+	return signals
+}
+
+func (x *reverseIndex[K]) reset() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.keys = nil
+}
+
+// signalsOf 返回指定 key 正在监听的信号, 按字典序排列
+func (c *core[K, T]) signalsOf(key unique.Handle[K]) []string {
+	signals := c.index.signals(key)
+	watching := signals[:0]
+	for _, signal := range signals {
+		if _, ok := c.lookup(signal, key); ok {
+			watching = append(watching, signal)
+		}
+	}
+	sort.Strings(watching)
+	return watching
+}
@@ -0,0 +1,29 @@
+package broadcast
+
+import (
+	"unique"
+)
+
+// Signal 是驻留后的信号名, 相同名称的 Signal 共享同一份字符串
+// Signal 之间用 == 比较只需比较指针, 适合在热路径中代替字符串比较
+type Signal struct {
+	h unique.Handle[string]
+}
+
+// Intern 驻留信号名并返回对应的 Signal, 可以在初始化时调用一次并保存结果
+func Intern(signal string) Signal {
+	return Signal{h: unique.Make(signal)}
+}
+
+// String 返回驻留后的信号名, 零值返回空字符串
+func (s Signal) String() string {
+	if s == (Signal{}) {
+		return ""
+	}
+	return s.h.Value()
+}
+
+// Handle 返回底层的 unique.Handle
+func (s Signal) Handle() unique.Handle[string] {
+	return s.h
+}
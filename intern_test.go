@@ -0,0 +1,41 @@
+package broadcast
+
+import (
+	"fmt"
+	"testing"
+	"unsafe"
+)
+
+func TestIntern(t *testing.T) {
+	a := Intern(fmt.Sprint("room", 1))
+	b := Intern(fmt.Sprint("room", 1))
+	if a != b || a == Intern("room2") {
+		t.Error("expected signals with the same name to be equal")
+	}
+	if unsafe.StringData(a.String()) != unsafe.StringData(b.String()) {
+		t.Error("expected interned names to share storage")
+	}
+	if (Signal{}).String() != "" {
+		t.Error("expected the zero Signal to be empty")
+	}
+}
+
+func TestIntern_SharedAcrossWatches(t *testing.T) {
+	b := NewUnique[string, string]()
+	for i := 0; i < 100; i++ {
+		b.Watch(fmt.Sprint("room", 1), owner{fmt.Sprint(i), "v"})
+	}
+
+	want := unsafe.StringData(Intern("room1").String())
+	for i := 0; i < 100; i++ {
+		signals := b.SignalsOf(fmt.Sprint(i))
+		if len(signals) != 1 || unsafe.StringData(signals[0]) != want {
+			t.Fatalf("expected key %d to reference the interned signal name", i)
+		}
+	}
+	for signal := range b.core.shard("room1").load() {
+		if signal == "room1" && unsafe.StringData(signal) != want {
+			t.Error("expected the signal table to use the interned name")
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package broadcast
+
+import (
+	"errors"
+	"time"
+)
+
+// JournalEntry 是日志中的一次广播
+// Metadata 经过 JSON 编码后回放, 数字类型会变为 float64; Payload 为编码后的负载,
+// 回放时以 *RawPayload 交给处理器, 需要通过 HandleRaw 或 Decode 读取
+type JournalEntry struct {
+	Seq      uint64                 `json:"seq"`
+	ID       string                 `json:"id,omitempty"`
+	Signal   string                 `json:"signal"`
+	Source   string                 `json:"source,omitempty"`
+	Time     time.Time              `json:"time"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Payload  []byte                 `json:"payload,omitempty"`
+	// Version 为写入时 JournalConfig.Version 的值, 回放时用于选择 WithUpgrader 的迁移
+	Version int `json:"version,omitempty"`
+}
+
+// Journal 是只追加的广播日志
+type Journal interface {
+	// Append 追加一条记录, 返回错误时广播不会被投递
+	Append(e JournalEntry) error
+	// Replay 按顺序对序号不小于 from 的记录调用 fn
+	Replay(from uint64, fn func(e JournalEntry) error) error
+	// LastSeq 返回最后一条记录的序号, 日志为空时为 0
+	LastSeq() (uint64, error)
+}
+
+// JournalConfig 日志配置
+type JournalConfig struct {
+	Journal Journal
+	// Signals 需要记录的信号, 为空时记录所有信号
+	Signals []string
+	// Codec 编码广播时负载, 默认为 JSONCodec; *RawPayload 直接记录其字节
+	Codec Codec
+	// Version 为当前负载格式的版本, 写入每条记录; 回放版本更低的记录时调用 WithUpgrader 设置的迁移
+	Version int
+}
+
+// journalBinding 将选定信号的广播写入日志
+type journalBinding struct {
+	config  JournalConfig
+	signals map[string]struct{}
+}
+
+func (b *journalBinding) selected(signal string) bool {
+	if b.signals == nil {
+		return true
+	}
+	_, ok := b.signals[signal]
+	return ok
+}
+
+func (b *journalBinding) append(e JournalEntry, payload any) error {
+	switch p := payload.(type) {
+	case nil:
+	case *RawPayload:
+		e.Payload = p.Bytes()
+	default:
+		data, err := b.config.Codec.Marshal(p)
+		if err != nil {
+			return err
+		}
+		e.Payload = data
+	}
+	return b.config.Journal.Append(e)
+}
+
+// enableJournal 开启日志, 广播序号从日志的最后一条记录之后继续
+func (c *core[K, T]) enableJournal(config JournalConfig) error {
+	if config.Codec == nil {
+		config.Codec = JSONCodec
+	}
+	last, err := config.Journal.LastSeq()
+	if err != nil {
+		return err
+	}
+	for {
+		seq := c.seq.Load()
+		if seq >= last || c.seq.CompareAndSwap(seq, last) {
+			break
+		}
+	}
+
+	b := &journalBinding{config: config}
+	if len(config.Signals) > 0 {
+		b.signals = make(map[string]struct{}, len(config.Signals))
+		for _, signal := range config.Signals {
+			b.signals[signal] = struct{}{}
+		}
+	}
+	c.updateSettings(func(s *settings[K, T]) {
+		s.journal = b
+	})
+	return nil
+}
+
+// replayJournal 重新投递日志中序号不小于 from 的记录, 回放的广播不会再次写入日志
+// 回放跳过熔断与限流, 处理器错误被组合后返回
+func (c *core[K, T]) replayJournal(from uint64) error {
+	b := c.loadSettings().journal
+	if b == nil {
+		return ErrNoJournal
+	}
+
+	var errs []error
+	err := b.config.Journal.Replay(from, func(e JournalEntry) error {
+		d := delivery[K, T]{seq: e.Seq, id: e.ID, signal: e.Signal, source: e.Source, time: e.Time, metadata: e.Metadata}
+		payload, err := c.journalPayload(b, e)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		d.payload = payload
+		if err := c.dispatch(d); err != nil {
+			errs = append(errs, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
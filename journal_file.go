@@ -0,0 +1,304 @@
+package broadcast
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// segmentExt 日志分段文件的扩展名, 文件名为该分段第一条记录的序号
+const segmentExt = ".wal"
+
+// FileJournalConfig 文件日志配置
+type FileJournalConfig struct {
+	// SegmentSize 单个分段文件的最大字节数, 超过后开始新的分段, 默认为 64MB
+	SegmentSize int64
+	// Sync 为 true 时每次追加后调用 fsync
+	Sync bool
+}
+
+// FileJournal 是基于分段文件的 Journal
+// 每条记录为 4 字节长度、4 字节 CRC32 和 JSON 编码的 JournalEntry;
+// 读取时遇到不完整或校验失败的记录视为日志结尾, 以容忍崩溃时写了一半的记录
+type FileJournal struct {
+	mu     sync.Mutex
+	dir    string
+	config FileJournalConfig
+
+	file *os.File
+	w    *bufio.Writer
+	size int64
+	last uint64
+	// err 为最近一次追加失败的错误, 之后的追加成功时清除;
+	// 追加失败时写了一半的记录被截断, 分段重新打开, 因此之后的追加仍可成功
+	err error
+}
+
+// OpenFileJournal 打开 dir 下的日志, 目录不存在时创建
+func OpenFileJournal(dir string, config FileJournalConfig) (*FileJournal, error) {
+	if config.SegmentSize <= 0 {
+		config.SegmentSize = 64 << 20
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	j := &FileJournal{dir: dir, config: config}
+	segments, err := j.segments()
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) > 0 {
+		// 找到最后一条完整记录, 截断其后写了一半的数据
+		path := j.path(segments[len(segments)-1])
+		valid, err := scanSegment(path, func(e JournalEntry) error {
+			j.last = e.Seq
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Truncate(path, valid); err != nil {
+			return nil, err
+		}
+		if err := j.open(path, valid); err != nil {
+			return nil, err
+		}
+		// 崩溃时可能刚创建了新的空分段, 最后的序号在更早的分段中
+		for i := len(segments) - 2; i >= 0 && j.last == 0; i-- {
+			_, err := scanSegment(j.path(segments[i]), func(e JournalEntry) error {
+				j.last = e.Seq
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return j, nil
+}
+
+func (j *FileJournal) path(first uint64) string {
+	return filepath.Join(j.dir, fmt.Sprintf("%020d%s", first, segmentExt))
+}
+
+// segments 返回所有分段的起始序号, 按升序排列
+func (j *FileJournal) segments() ([]uint64, error) {
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []uint64
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), segmentExt)
+		if !ok {
+			continue
+		}
+		first, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, first)
+	}
+	sort.Slice(segments, func(a, b int) bool { return segments[a] < segments[b] })
+	return segments, nil
+}
+
+func (j *FileJournal) open(path string, size int64) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	j.file, j.w, j.size = f, bufio.NewWriter(f), size
+	return nil
+}
+
+// Append 追加一条记录, 当前分段超过 SegmentSize 时先开始新的分段
+func (j *FileJournal) Append(e JournalEntry) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.err = j.append(e, body)
+	return j.err
+}
+
+func (j *FileJournal) append(e JournalEntry, body []byte) error {
+	if j.file == nil || j.size >= j.config.SegmentSize {
+		if err := j.closeSegment(); err != nil {
+			return err
+		}
+		if err := j.open(j.path(e.Seq), 0); err != nil {
+			return err
+		}
+	}
+
+	var header [8]byte
+	binary.LittleEndian.PutUint32(header[:4], uint32(len(body)))
+	binary.LittleEndian.PutUint32(header[4:], crc32.ChecksumIEEE(body))
+	if err := j.write(header[:], body); err != nil {
+		// 写了一半的记录会使之后的记录在回放时不可见, 截断后重新打开分段
+		return errors.Join(err, j.reset())
+	}
+	j.size += int64(len(header) + len(body))
+	j.last = e.Seq
+	return nil
+}
+
+func (j *FileJournal) write(header, body []byte) error {
+	if _, err := j.w.Write(header); err != nil {
+		return err
+	}
+	if _, err := j.w.Write(body); err != nil {
+		return err
+	}
+	if err := j.w.Flush(); err != nil {
+		return err
+	}
+	if j.config.Sync {
+		return j.file.Sync()
+	}
+	return nil
+}
+
+// reset 丢弃当前分段中 size 之后的数据并以新的 Writer 重新打开
+// 失败时关闭分段, 下一次追加开始新的分段
+func (j *FileJournal) reset() error {
+	path := j.file.Name()
+	j.file.Close()
+	j.file, j.w = nil, nil
+	if err := os.Truncate(path, j.size); err != nil {
+		return err
+	}
+	return j.open(path, j.size)
+}
+
+// Replay 按顺序对序号不小于 from 的记录调用 fn, fn 返回错误时停止
+func (j *FileJournal) Replay(from uint64, fn func(e JournalEntry) error) error {
+	j.mu.Lock()
+	segments, err := j.segments()
+	j.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for i, first := range segments {
+		// 下一个分段从 from 或更早开始时, 当前分段中没有需要的记录
+		if i+1 < len(segments) && segments[i+1] <= from {
+			continue
+		}
+		_, err := scanSegment(j.path(first), func(e JournalEntry) error {
+			if e.Seq < from {
+				return nil
+			}
+			return fn(e)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LastSeq 返回最后一条记录的序号
+func (j *FileJournal) LastSeq() (uint64, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.last, nil
+}
+
+// Healthy 返回最近一次追加失败的错误, 实现 HealthChecker
+func (j *FileJournal) Healthy() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.err != nil {
+		return fmt.Errorf("journal append: %w", j.err)
+	}
+	return nil
+}
+
+// Ready 检查日志目录是否可写, 实现 HealthChecker
+func (j *FileJournal) Ready() error {
+	f, err := os.CreateTemp(j.dir, ".probe-*")
+	if err != nil {
+		return fmt.Errorf("journal not writable: %w", err)
+	}
+	return errors.Join(f.Close(), os.Remove(f.Name()))
+}
+
+// Close 关闭当前分段
+func (j *FileJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.closeSegment()
+}
+
+func (j *FileJournal) closeSegment() error {
+	if j.file == nil {
+		return nil
+	}
+	err := errors.Join(j.w.Flush(), j.file.Close())
+	j.file, j.w = nil, nil
+	return err
+}
+
+// scanSegment 依次读取分段中的完整记录, 返回最后一条完整记录之后的偏移量
+func scanSegment(path string, fn func(e JournalEntry) error) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return offset, nil
+		}
+		// 损坏的长度超过分段的剩余字节时视为不完整的尾部, 不按它分配内存
+		size := int64(binary.LittleEndian.Uint32(header[:4]))
+		if size > info.Size()-offset-int64(len(header)) {
+			return offset, nil
+		}
+		body := make([]byte, size)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return offset, nil
+		}
+		if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(header[4:]) {
+			return offset, nil
+		}
+
+		var e JournalEntry
+		if err := json.Unmarshal(body, &e); err != nil {
+			return offset, nil
+		}
+		if err := fn(e); err != nil {
+			return offset, err
+		}
+		offset += int64(len(header) + len(body))
+	}
+}
@@ -0,0 +1,230 @@
+package broadcast
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestJournal_ReplayAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := OpenFileJournal(dir, FileJournalConfig{SegmentSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := New[string]()
+	b.Watch("orders", "projection")
+	b.Watch("metrics", "projection")
+	if err := b.EnableJournal(JournalConfig{Journal: journal, Signals: []string{"orders"}}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 5; i++ {
+		BroadcastData(b, "orders", i*10, map[string]interface{}{"n": i})
+		b.Broadcast("metrics", nil)
+	}
+	journal.Close()
+
+	segments, _ := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if len(segments) < 2 {
+		t.Errorf("expected the journal to rotate segments, got %d", len(segments))
+	}
+
+	journal, err = OpenFileJournal(dir, FileJournalConfig{SegmentSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	restarted := New[string]()
+	restarted.Watch("orders", "projection")
+	var replayed []int
+	HandleRaw(restarted, func(signal string, data string, payload *RawPayload, metadata map[string]interface{}) error {
+		if payload == nil {
+			return nil
+		}
+		v, err := Decode[int](payload)
+		replayed = append(replayed, v)
+		return err
+	})
+	if err := restarted.EnableJournal(JournalConfig{Journal: journal, Signals: []string{"orders"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 日志中 orders 的序号为 1, 3, 5, 7, 9
+	if err := restarted.ReplayJournal(5); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(replayed, []int{30, 40, 50}) {
+		t.Errorf("expected [30 40 50], got %v", replayed)
+	}
+
+	restarted.Broadcast("orders", nil)
+	if last, _ := journal.LastSeq(); last != 10 {
+		t.Errorf("expected sequence numbers to continue after the journal, got %d", last)
+	}
+}
+
+func TestJournal_TornTail(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seq := uint64(1); seq <= 3; seq++ {
+		journal.Append(JournalEntry{Seq: seq, Signal: "test"})
+	}
+	journal.Close()
+
+	segments, _ := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	f, _ := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0o644)
+	f.Write([]byte{42, 0, 0, 0, 1, 2})
+	f.Close()
+
+	journal, err = OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+	if last, _ := journal.LastSeq(); last != 3 {
+		t.Errorf("expected last seq 3, got %d", last)
+	}
+	if err := journal.Append(JournalEntry{Seq: 4, Signal: "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var seqs []uint64
+	journal.Replay(0, func(e JournalEntry) error {
+		seqs = append(seqs, e.Seq)
+		return nil
+	})
+	if !slices.Equal(seqs, []uint64{1, 2, 3, 4}) {
+		t.Errorf("expected the torn record to be dropped, got %v", seqs)
+	}
+}
+
+func TestJournal_AppendFailureBlocksDelivery(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	if err := b.ReplayJournal(0); !errors.Is(err, ErrNoJournal) {
+		t.Errorf("expected ErrNoJournal, got %v", err)
+	}
+
+	journal, err := OpenFileJournal(t.TempDir(), FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+	b.EnableJournal(JournalConfig{Journal: journal})
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	calls := 0
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		calls++
+		return nil
+	})
+	if err := b.Broadcast("test", map[string]interface{}{"bad": func() {}}); err == nil {
+		t.Error("expected the journal error to be returned")
+	}
+	if calls != 0 {
+		t.Errorf("expected no delivery when the journal append fails, got %d", calls)
+	}
+}
+
+func TestJournal_RecoverLastFromEarlierSegment(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seq := uint64(1); seq <= 3; seq++ {
+		journal.Append(JournalEntry{Seq: seq, Signal: "test"})
+	}
+	journal.Close()
+
+	// 崩溃前刚创建的下一个分段还没有任何记录
+	os.WriteFile(journal.path(4), nil, 0o644)
+
+	journal, err = OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+	if last, _ := journal.LastSeq(); last != 3 {
+		t.Errorf("expected last seq 3 from the earlier segment, got %d", last)
+	}
+}
+
+func TestJournal_AppendFailureTruncatesPartialRecord(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+	journal.Append(JournalEntry{Seq: 1, Signal: "test"})
+
+	// 模拟写了一半后失败: 分段末尾留下不完整的记录, 当前文件不可写
+	f, _ := os.OpenFile(journal.file.Name(), os.O_WRONLY|os.O_APPEND, 0o644)
+	f.Write([]byte{42, 0, 0, 0, 1, 2})
+	f.Close()
+	journal.file.Close()
+
+	if err := journal.Append(JournalEntry{Seq: 2, Signal: "test"}); err == nil {
+		t.Fatal("expected the append to fail")
+	}
+	if err := journal.Append(JournalEntry{Seq: 3, Signal: "test"}); err != nil {
+		t.Fatalf("expected the journal to recover after a failed append, got %v", err)
+	}
+	if err := journal.Healthy(); err != nil {
+		t.Errorf("expected the journal to be healthy again, got %v", err)
+	}
+
+	var seqs []uint64
+	journal.Replay(0, func(e JournalEntry) error {
+		seqs = append(seqs, e.Seq)
+		return nil
+	})
+	if !slices.Equal(seqs, []uint64{1, 3}) {
+		t.Errorf("expected the partial record to be discarded, got %v", seqs)
+	}
+}
+
+func TestJournal_CorruptLength(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seq := uint64(1); seq <= 2; seq++ {
+		journal.Append(JournalEntry{Seq: seq, Signal: "test"})
+	}
+	journal.Close()
+
+	// 损坏的头部声明了接近 4GiB 的长度
+	segments, _ := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	f, _ := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0o644)
+	f.Write([]byte{0xfe, 0xff, 0xff, 0xff, 0, 0, 0, 0, '{'})
+	f.Close()
+
+	journal, err = OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+	if last, _ := journal.LastSeq(); last != 2 {
+		t.Errorf("expected the corrupt record to end the log at seq 2, got %d", last)
+	}
+	if err := journal.Append(JournalEntry{Seq: 3, Signal: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	var seqs []uint64
+	journal.Replay(0, func(e JournalEntry) error {
+		seqs = append(seqs, e.Seq)
+		return nil
+	})
+	if !slices.Equal(seqs, []uint64{1, 2, 3}) {
+		t.Errorf("expected the corrupt tail to be dropped, got %v", seqs)
+	}
+}
@@ -0,0 +1,18 @@
+package broadcast
+
+import (
+	"time"
+)
+
+// Latency 描述注入到处理器中的人工延迟, 用于在非生产环境中做容量规划:
+// 在慢依赖真正出现之前观察分发器、队列和背压在延迟下的表现
+//
+// 使用 -tags broadcast_production 构建时 InjectLatency 不做任何包装
+type Latency struct {
+	// Base 每次调用固定增加的延迟
+	Base time.Duration
+	// Jitter 在 Base 之上随机增加 [0, Jitter) 的延迟
+	Jitter time.Duration
+	// Probability 注入延迟的概率, 0 表示每次都注入
+	Probability float64
+}
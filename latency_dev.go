@@ -0,0 +1,31 @@
+//go:build !broadcast_production
+
+package broadcast
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// InjectLatency 包装处理器, 在每次调用前按 l 的配置休眠
+// 可用于 Handler 和 UniqueHandler
+func InjectLatency[F ~func(string, T, map[string]interface{}) error, T any](handler F, l Latency) F {
+	return func(signal string, data T, metadata map[string]interface{}) error {
+		if d := l.delay(); d > 0 {
+			time.Sleep(d)
+		}
+		return handler(signal, data, metadata)
+	}
+}
+
+func (l Latency) delay() time.Duration {
+	if l.Probability > 0 && rand.Float64() >= l.Probability {
+		return 0
+	}
+
+	d := l.Base
+	if l.Jitter > 0 {
+		d += rand.N(l.Jitter)
+	}
+	return d
+}
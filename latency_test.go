@@ -0,0 +1,55 @@
+//go:build !broadcast_production
+
+package broadcast
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInjectLatency(t *testing.T) {
+	b := New[string]()
+	b.Handle(InjectLatency(Handler[string](func(signal string, data string, metadata map[string]interface{}) error {
+		return nil
+	}), Latency{Base: 20 * time.Millisecond, Jitter: 5 * time.Millisecond}))
+	b.Watch("test", "data")
+
+	start := time.Now()
+	b.Broadcast("test", nil)
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected at least 20ms injected latency, got %v", elapsed)
+	}
+}
+
+func TestLatency_Delay(t *testing.T) {
+	l := Latency{Base: time.Millisecond, Jitter: time.Millisecond}
+	for i := 0; i < 100; i++ {
+		if d := l.delay(); d < time.Millisecond || d >= 2*time.Millisecond {
+			t.Fatalf("delay %v out of [1ms, 2ms)", d)
+		}
+	}
+
+	never := Latency{Base: time.Second, Probability: 1e-12}
+	for i := 0; i < 100; i++ {
+		if d := never.delay(); d != 0 {
+			t.Fatalf("expected no delay with near-zero probability, got %v", d)
+		}
+	}
+}
+
+func TestInjectLatency_UniqueHandler(t *testing.T) {
+	calls := 0
+	h := InjectLatency(UniqueHandler[int, TestUniqueData](func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		calls++
+		return nil
+	}), Latency{})
+
+	b := NewUnique[int, TestUniqueData]()
+	b.Handle(h)
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+	b.Broadcast("test", nil)
+
+	if calls != 1 {
+		t.Errorf("expected wrapped handler to be called once, got %d", calls)
+	}
+}
@@ -0,0 +1,223 @@
+package broadcast
+
+import (
+	"sync"
+	"time"
+	"unique"
+)
+
+// Lease 是 WatchLease 添加的监听器或 LeaseGroup 覆盖的分组的租约
+// 租约在 ttl 内没有被 Renew 时到期, 监听器被移除并调用 OnLeaseExpired 或 OnGroupLeaseExpired 注册的回调
+type Lease struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	clock Clock
+	timer Timer
+	// gen 在每次 Renew 时递增, 用于忽略已被替换的定时器
+	gen  uint64
+	done bool
+	// expire 在租约到期时移除监听器, release 在主动释放时移除监听器
+	expire  func()
+	release func()
+}
+
+// schedule 在锁内为当前代数启动定时器
+func (l *Lease) schedule() {
+	gen := l.gen
+	l.timer = l.clock.AfterFunc(l.ttl, func() {
+		l.fire(gen)
+	})
+}
+
+func (l *Lease) fire(gen uint64) {
+	l.mu.Lock()
+	if l.done || l.gen != gen {
+		l.mu.Unlock()
+		return
+	}
+	l.done = true
+	l.mu.Unlock()
+
+	l.expire()
+}
+
+// Renew 续约, 租约从现在起再保持 ttl, 租约已到期或已释放时返回 false
+func (l *Lease) Renew() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.done {
+		return false
+	}
+	l.timer.Stop()
+	l.gen++
+	l.schedule()
+	return true
+}
+
+// Release 立即释放租约并取消监听, 不调用到期回调, 租约已到期或已释放时返回 false
+func (l *Lease) Release() bool {
+	l.mu.Lock()
+	if l.done {
+		l.mu.Unlock()
+		return false
+	}
+	l.done = true
+	l.timer.Stop()
+	l.mu.Unlock()
+
+	l.release()
+	return true
+}
+
+// end 在监听器已被移除后结束租约, 不调用到期回调
+func (l *Lease) end() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.done = true
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+}
+
+// Expired 报告租约是否已到期或已释放
+func (l *Lease) Expired() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.done
+}
+
+// watchLease 添加监听器并为其创建租约, 相同 key 已存在时返回 false
+// 监听器记录自己的租约, 到期或释放时只移除仍持有该租约的监听器
+func (c *core[K, T]) watchLease(signal string, l listener[K, T], ttl time.Duration) (*Lease, bool) {
+	l = c.withLease(signal, l, ttl)
+	if !c.watch(signal, l) {
+		return nil, false
+	}
+	c.startLease(signal, l)
+	return l.lease, true
+}
+
+// withLease 为监听器创建尚未计时的租约, 监听器加入信号后调用 startLease 开始计时
+func (c *core[K, T]) withLease(signal string, l listener[K, T], ttl time.Duration) listener[K, T] {
+	k := signalKey[K]{signal, l.key}
+	lease := &Lease{ttl: ttl, clock: c.clock()}
+	lease.expire = func() {
+		c.dropLease(k, lease)
+		if c.unwatchLeased(signal, l.key, lease) {
+			if fn := c.loadSettings().onLeaseExpired; fn != nil {
+				fn(signal, l.data.Value())
+			}
+		}
+	}
+	lease.release = func() {
+		c.dropLease(k, lease)
+		c.unwatchLeased(signal, l.key, lease)
+	}
+	l.lease = lease
+	c.leased.Store(true)
+	return l
+}
+
+// startLease 登记已加入信号的监听器的租约并开始计时
+func (c *core[K, T]) startLease(signal string, l listener[K, T]) {
+	k, lease := signalKey[K]{signal, l.key}, l.lease
+	c.leasesMu.Lock()
+	if c.leases == nil {
+		c.leases = make(map[signalKey[K]]*Lease)
+	}
+	if previous, ok := c.leases[k]; ok {
+		previous.end()
+	}
+	c.leases[k] = lease
+	lease.mu.Lock()
+	lease.schedule()
+	lease.mu.Unlock()
+	c.leasesMu.Unlock()
+}
+
+// unwatchLeased 在信号上 key 的监听器仍持有 lease 时移除它
+func (c *core[K, T]) unwatchLeased(signal string, key unique.Handle[K], lease *Lease) bool {
+	return c.unwatchWhere(signal, key, func(l listener[K, T]) bool { return l.lease == lease })
+}
+
+// endLease 在监听器被 Unwatch、Clean 等移除后结束其租约, 调用时可能持有信号锁
+func (c *core[K, T]) endLease(k signalKey[K]) {
+	c.leasesMu.Lock()
+	lease, ok := c.leases[k]
+	delete(c.leases, k)
+	c.leasesMu.Unlock()
+
+	if ok {
+		lease.end()
+	}
+}
+
+// endLeases 在 CleanAll 时结束所有监听器的租约
+func (c *core[K, T]) endLeases() {
+	c.leasesMu.Lock()
+	leases := c.leases
+	c.leases = nil
+	c.leasesMu.Unlock()
+
+	for _, lease := range leases {
+		lease.end()
+	}
+}
+
+func (c *core[K, T]) dropLease(k signalKey[K], lease *Lease) {
+	c.leasesMu.Lock()
+	defer c.leasesMu.Unlock()
+
+	if c.leases[k] == lease {
+		delete(c.leases, k)
+	}
+}
+
+// renewLease 续约信号上 key 的租约, 没有有效租约时返回 false
+func (c *core[K, T]) renewLease(signal string, key unique.Handle[K]) bool {
+	c.leasesMu.Lock()
+	lease, ok := c.leases[signalKey[K]{signal, key}]
+	c.leasesMu.Unlock()
+	return ok && lease.Renew()
+}
+
+func (c *core[K, T]) onLeaseExpired(fn func(signal string, data T)) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.onLeaseExpired = fn
+	})
+}
+
+// WatchLease 以租约方式监听一个信号, 适合代表可能无声消失的远程客户端的监听器
+// 租约需要在 ttl 内通过 Lease.Renew 或 RenewLease 续约, 否则监听器被移除并调用 OnLeaseExpired 注册的回调.
+// data 已在监听该信号时返回 false
+func (b *Broadcast[T]) WatchLease(signal string, data T, ttl time.Duration) (*Lease, bool) {
+	return b.c().watchLease(b.sig(signal), newListener[T, T](&uniqueWrapper[T]{data: data}), ttl)
+}
+
+// RenewLease 续约 data 在信号上的租约, 没有有效租约时返回 false
+func (b *Broadcast[T]) RenewLease(signal string, data T) bool {
+	return b.c().renewLease(b.sig(signal), unique.Make(data))
+}
+
+// OnLeaseExpired 设置监听器因租约到期被移除时的回调
+func (b *Broadcast[T]) OnLeaseExpired(fn func(signal string, data T)) {
+	b.c().onLeaseExpired(fn)
+}
+
+// WatchLease 以租约方式监听一个信号, 与 Broadcast.WatchLease 相同
+func (b *UniqueBroadcast[K, T]) WatchLease(signal string, data Uniquer[K, T], ttl time.Duration) (*Lease, bool) {
+	return b.core.watchLease(signal, newListener(data), ttl)
+}
+
+// RenewLease 续约 key 在信号上的租约, 没有有效租约时返回 false
+func (b *UniqueBroadcast[K, T]) RenewLease(signal string, key K) bool {
+	return b.core.renewLease(signal, unique.Make(key))
+}
+
+// OnLeaseExpired 设置监听器因租约到期被移除时的回调
+func (b *UniqueBroadcast[K, T]) OnLeaseExpired(fn func(signal string, data T)) {
+	b.core.onLeaseExpired(fn)
+}
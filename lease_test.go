@@ -0,0 +1,99 @@
+package broadcast_test
+
+import (
+	"testing"
+	"time"
+	"unique"
+
+	"pkg.blksails.net/x/broadcast"
+	"pkg.blksails.net/x/broadcast/broadcasttest"
+)
+
+// presence 以用户名为 key, 值为在线状态
+type presence struct {
+	user   string
+	status string
+}
+
+func (p presence) Unique() unique.Handle[string] { return unique.Make(p.user) }
+func (p presence) Value() string                 { return p.status }
+
+func newLeased(t *testing.T) (*broadcast.UniqueBroadcast[string, string], *broadcasttest.FakeClock, *[]string) {
+	t.Helper()
+	clock := broadcasttest.NewFakeClock(time.Unix(0, 0))
+	b := broadcast.NewUnique[string, string](broadcast.WithClock(clock))
+	var expired []string
+	b.OnLeaseExpired(func(signal string, data string) {
+		expired = append(expired, signal+"/"+data)
+	})
+	return b, clock, &expired
+}
+
+func TestWatchLease_ExpiresWithoutRenew(t *testing.T) {
+	b, clock, expired := newLeased(t)
+
+	live, ok := b.WatchLease("presence", presence{"alice", "online"}, 50*time.Millisecond)
+	if !ok {
+		t.Fatal("expected the lease to be granted")
+	}
+	if _, ok := b.WatchLease("presence", presence{"bob", "online"}, 50*time.Millisecond); !ok {
+		t.Fatal("expected the lease to be granted")
+	}
+	if _, ok := b.WatchLease("presence", presence{"bob", "again"}, time.Second); ok {
+		t.Error("expected a duplicate key to be rejected")
+	}
+
+	for range 5 {
+		clock.Advance(10 * time.Millisecond)
+		live.Renew()
+	}
+	if len(*expired) != 1 || (*expired)[0] != "presence/online" {
+		t.Fatalf("expected bob's lease to expire once, got %v", *expired)
+	}
+
+	if b.Has("presence", "bob") || !b.Has("presence", "alice") || live.Expired() {
+		t.Error("expected only the renewed listener to remain")
+	}
+	if b.RenewLease("presence", "bob") {
+		t.Error("expected renewing an expired lease to fail")
+	}
+	if !b.RenewLease("presence", "alice") {
+		t.Error("expected renewing by key to succeed")
+	}
+
+	if !live.Release() || live.Release() || b.Has("presence", "alice") {
+		t.Error("expected Release to unwatch once")
+	}
+	clock.Advance(time.Second)
+	if len(*expired) != 1 {
+		t.Errorf("expected Release not to fire the expiry hook, got %v", *expired)
+	}
+}
+
+func TestWatchLease_UnwatchEndsLease(t *testing.T) {
+	b, clock, expired := newLeased(t)
+
+	lease, _ := b.WatchLease("presence", presence{"alice", "online"}, time.Second)
+	b.Unwatch("presence", presence{"alice", "online"})
+	if !lease.Expired() || b.RenewLease("presence", "alice") {
+		t.Error("expected Unwatch to end the lease")
+	}
+
+	// 之后以普通方式重新监听的同一个 key 不受旧租约影响
+	b.Watch("presence", presence{"alice", "away"})
+	clock.Advance(2 * time.Second)
+	if b.WatchCount("presence") != 1 || len(*expired) != 0 {
+		t.Errorf("expected the stale lease not to remove the new listener, count=%d expired=%v", b.WatchCount("presence"), *expired)
+	}
+
+	lease, _ = b.WatchLease("other", presence{"bob", "online"}, time.Second)
+	b.Clean("other")
+	lease2, _ := b.WatchLease("third", presence{"carol", "online"}, time.Second)
+	b.CleanAll()
+	b.Watch("other", presence{"bob", "online"})
+	b.Watch("third", presence{"carol", "online"})
+	clock.Advance(2 * time.Second)
+	if !lease.Expired() || !lease2.Expired() || b.WatchCount("other") != 1 || b.WatchCount("third") != 1 || len(*expired) != 0 {
+		t.Errorf("expected Clean and CleanAll to end leases, expired=%v", *expired)
+	}
+}
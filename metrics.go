@@ -0,0 +1,33 @@
+package broadcast
+
+import (
+	"time"
+)
+
+// Metrics 接收广播器的计量事件, 用于接入 Prometheus 等监控系统
+// 方法在广播与投递的路径上同步调用, 实现需要并发安全且尽快返回
+type Metrics interface {
+	// Published 在广播通过检查并分配序号后调用
+	Published(signal string)
+	// Handled 在处理器处理完一个监听器后调用, elapsed 为处理器耗时
+	Handled(signal string, handler HandlerID, elapsed time.Duration, err error)
+	// Dropped 在已分配序号的广播因异步队列溢出或超过存活时间被丢弃时调用
+	Dropped(signal string)
+}
+
+// setMetrics 设置计量接收器, m 为 nil 时移除
+func (c *core[K, T]) setMetrics(m Metrics) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.metrics = m
+	})
+}
+
+// SetMetrics 设置计量接收器, 传入 nil 移除
+func (b *Broadcast[T]) SetMetrics(m Metrics) {
+	b.c().setMetrics(m)
+}
+
+// SetMetrics 设置计量接收器, 传入 nil 移除
+func (b *UniqueBroadcast[K, T]) SetMetrics(m Metrics) {
+	b.core.setMetrics(m)
+}
@@ -0,0 +1,103 @@
+package broadcast
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMetrics 按信号累计计量事件
+type recordingMetrics struct {
+	mu        sync.Mutex
+	published map[string]int
+	handled   map[string]int
+	failed    map[string]int
+	dropped   map[string]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{
+		published: make(map[string]int),
+		handled:   make(map[string]int),
+		failed:    make(map[string]int),
+		dropped:   make(map[string]int),
+	}
+}
+
+func (m *recordingMetrics) Published(signal string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.published[signal]++
+}
+
+func (m *recordingMetrics) Handled(signal string, handler HandlerID, elapsed time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handled[signal]++
+	if err != nil {
+		m.failed[signal]++
+	}
+}
+
+func (m *recordingMetrics) Dropped(signal string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dropped[signal]++
+}
+
+func (m *recordingMetrics) counts(signal string) [4]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return [4]int{m.published[signal], m.handled[signal], m.failed[signal], m.dropped[signal]}
+}
+
+func TestWithMetrics(t *testing.T) {
+	m := newRecordingMetrics()
+	b := NewUnique[int, TestUniqueData](
+		WithMetrics(m),
+		WithRateLimiter(RateLimiterFunc(func(key string) bool { return key != "blocked" })),
+	)
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		if data.ID == 2 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 2}})
+
+	b.Broadcast("test", nil)
+	b.Broadcast("blocked", nil)
+	if got, want := m.counts("test"), [4]int{1, 2, 1, 0}; got != want {
+		t.Errorf("expected published, handled, failed, dropped %v, got %v", want, got)
+	}
+	if got := m.counts("blocked"); got != [4]int{} {
+		t.Errorf("expected a rejected broadcast not to be counted, got %v", got)
+	}
+
+	b.SetMetrics(nil)
+	b.Broadcast("test", nil)
+	if got := m.counts("test")[0]; got != 1 {
+		t.Errorf("expected SetMetrics(nil) to remove the sink, got %d", got)
+	}
+}
+
+func TestMetrics_Dropped(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b, gate, _ := gatedBroadcast(t, AsyncConfig{QueueSize: 4})
+	b.SetClock(clock)
+	m := newRecordingMetrics()
+	b.SetMetrics(m)
+
+	b.Broadcast("test", map[string]interface{}{"seq": 1}, WithEventTTL(time.Second))
+	b.Broadcast("test", map[string]interface{}{"seq": 2})
+	clock.now = clock.now.Add(2 * time.Second)
+	close(gate)
+	b.Close()
+
+	// 阻塞在 gate 上的首个事件在设置计量接收器之前发布, 不计入 published
+	if got := m.counts("test"); got[0] != 2 || got[3] != 1 {
+		t.Errorf("expected 2 published and 1 dropped, got %v", got)
+	}
+}
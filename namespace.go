@@ -0,0 +1,16 @@
+package broadcast
+
+// namespace 是 Broadcast.Namespace 返回的视图所指向的根广播器与前缀
+type namespace[T comparable] struct {
+	root   *core[T, T]
+	prefix string
+}
+
+// Namespace 返回一个以 prefix 为信号前缀的视图
+// 视图上的 Watch/Broadcast/Clean 等操作自动为信号加上前缀, 通过视图注册的处理器只接收该前缀下的信号,
+// 且收到的信号名不含前缀; 视图的 CleanAll 与 Range 只作用于该前缀下的信号.
+// 前缀按原样拼接, 需要分隔符时应包含在 prefix 中, 例如 "billing.".
+// 限流、异步、死信等配置与根广播器共享, 在视图上设置等同于在根广播器上设置
+func (b *Broadcast[T]) Namespace(prefix string) *Broadcast[T] {
+	return &Broadcast[T]{ns: &namespace[T]{root: b.c(), prefix: b.prefix() + prefix}}
+}
@@ -0,0 +1,74 @@
+package broadcast
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNamespace_Isolation(t *testing.T) {
+	root := New[string]()
+	billing := root.Namespace("billing.")
+	orders := root.Namespace("orders.")
+
+	var billingSignals, rootSignals []string
+	billing.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		billingSignals = append(billingSignals, signal)
+		return nil
+	})
+	root.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		rootSignals = append(rootSignals, signal)
+		return nil
+	})
+
+	billing.Watch("created", "ledger")
+	orders.Watch("created", "warehouse")
+
+	if !root.HasWatch("billing.created") || !billing.HasWatch("created") {
+		t.Fatal("expected the namespace to prefix watched signals")
+	}
+
+	billing.Broadcast("created", nil)
+	orders.Broadcast("created", nil)
+	if !slices.Equal(billingSignals, []string{"created"}) {
+		t.Errorf("expected the namespace handler to see only its unprefixed signal, got %v", billingSignals)
+	}
+	if !slices.Equal(rootSignals, []string{"billing.created", "orders.created"}) {
+		t.Errorf("expected the root handler to see every signal, got %v", rootSignals)
+	}
+
+	var ranged []string
+	billing.Range(func(signal string, count int) bool {
+		ranged = append(ranged, signal)
+		return true
+	})
+	if !slices.Equal(ranged, []string{"created"}) {
+		t.Errorf("expected Range to list only namespace signals, got %v", ranged)
+	}
+
+	billing.CleanAll()
+	if billing.HasWatch("created") || !orders.HasWatch("created") {
+		t.Error("expected CleanAll on a namespace to leave other namespaces alone")
+	}
+}
+
+func TestNamespace_Nested(t *testing.T) {
+	root := New[string]()
+	nested := root.Namespace("a.").Namespace("b.")
+	nested.Watch("c", "data")
+
+	if !root.HasWatch("a.b.c") {
+		t.Error("expected nested namespaces to combine prefixes")
+	}
+
+	var got int
+	HandleData(nested, func(signal string, data string, payload int, metadata map[string]interface{}) error {
+		if signal == "c" {
+			got = payload
+		}
+		return nil
+	})
+	BroadcastData(nested, "c", 7, nil)
+	if got != 7 {
+		t.Errorf("expected data handlers to work through the namespace, got %d", got)
+	}
+}
@@ -0,0 +1,263 @@
+package broadcast
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// BroadcastOption 是单次广播的可选参数
+type BroadcastOption func(o *broadcastOptions)
+
+// broadcastOptions 保存单次广播的可选参数
+type broadcastOptions struct {
+	ttl         time.Duration
+	id          string
+	source      string
+	correlation string
+	deadline    time.Time
+}
+
+// newBroadcastOptions 应用 opts, 没有参数时不产生堆分配
+func newBroadcastOptions(opts []BroadcastOption) broadcastOptions {
+	if len(opts) == 0 {
+		return broadcastOptions{}
+	}
+
+	o := new(broadcastOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return *o
+}
+
+// Option 是 New 与 NewUnique 的可选参数
+type Option func(o *options)
+
+// options 保存构造时的配置, 在返回广播器之前依次应用
+type options struct {
+	async      *AsyncConfig
+	clock      Clock
+	logger     *slog.Logger
+	limiter    RateLimiter
+	breaker    *BreakerConfig
+	sampling   *SamplingConfig
+	buffer     int
+	registry   *RegistryConfig
+	ttl        time.Duration
+	extractors []ContextExtractor
+	parallel   int
+	health     *HealthConfig
+	history    *HistoryConfig
+	authorizer Authorizer
+	// validator 为 WithValidator 设置的 func(signal string, data T) error
+	validator        any
+	payloadValidator func(signal string, payload any) error
+	upgrader         Upgrader
+	metrics          Metrics
+}
+
+// WithAsync 开启异步投递, 等同于构造后调用 EnableAsync
+func WithAsync(config AsyncConfig) Option {
+	return func(o *options) {
+		o.async = &config
+	}
+}
+
+// WithWorkers 开启异步投递并设置工作 goroutine 数量, 可与 WithAsync 组合使用
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		if o.async == nil {
+			o.async = &AsyncConfig{}
+		}
+		o.async.Workers = n
+	}
+}
+
+// WithClock 设置时间源, 等同于构造后调用 SetClock
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithLogger 使用 logger 记录处理器错误与因超过 TTL 被丢弃的事件
+// 会占用 OnError 与 OnExpired 回调, 之后调用它们会替换日志记录
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithRateLimiter 设置信号级限流器, 等同于构造后调用 SetRateLimiter
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// WithBreaker 设置生产者侧熔断器, 等同于构造后调用 SetBreaker
+func WithBreaker(config BreakerConfig) Option {
+	return func(o *options) {
+		o.breaker = &config
+	}
+}
+
+// WithSampling 设置过载时的自适应采样, 等同于构造后调用 SetSampling
+func WithSampling(config SamplingConfig) Option {
+	return func(o *options) {
+		o.sampling = &config
+	}
+}
+
+// WithPendingBuffer 启用暂存缓冲, 等同于构造后调用 SetPendingBuffer
+func WithPendingBuffer(size int) Option {
+	return func(o *options) {
+		o.buffer = size
+	}
+}
+
+// WithDefaultTTL 为没有通过 WithEventTTL 设置存活时间的广播设置默认存活时间
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithContextExtractor 追加 BroadcastCtx 使用的提取器, 等同于构造后调用 UseContextExtractor
+func WithContextExtractor(e ContextExtractor) Option {
+	return func(o *options) {
+		o.extractors = append(o.extractors, e)
+	}
+}
+
+// WithRegistry 设置信号注册表, 等同于构造后调用 SetRegistry
+func WithRegistry(config RegistryConfig) Option {
+	return func(o *options) {
+		o.registry = &config
+	}
+}
+
+// WithParallel 让每个处理器并发处理各监听器, 等同于构造后调用 SetParallel
+func WithParallel(n int) Option {
+	return func(o *options) {
+		o.parallel = n
+	}
+}
+
+// WithHealthTracking 开启信号健康状态跟踪, 等同于构造后调用 SetHealthTracking
+func WithHealthTracking(config HealthConfig) Option {
+	return func(o *options) {
+		o.health = &config
+	}
+}
+
+// WithHistory 开启广播历史, 等同于构造后调用 SetHistory
+func WithHistory(config HistoryConfig) Option {
+	return func(o *options) {
+		o.history = &config
+	}
+}
+
+// WithAuthorizer 设置授权钩子, 等同于构造后调用 SetAuthorizer
+func WithAuthorizer(a Authorizer) Option {
+	return func(o *options) {
+		o.authorizer = a
+	}
+}
+
+// WithMetrics 设置计量接收器, 等同于构造后调用 SetMetrics
+func WithMetrics(m Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+// apply 将构造选项应用到 core, 时间源最先设置, 异步投递最后开启
+func (c *core[K, T]) apply(opts []Option) {
+	if len(opts) == 0 {
+		return
+	}
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.clock != nil {
+		c.updateSettings(func(s *settings[K, T]) {
+			s.clock = o.clock
+		})
+	}
+	if o.logger != nil {
+		c.useLogger(o.logger)
+	}
+	if o.limiter != nil {
+		c.updateSettings(func(s *settings[K, T]) {
+			s.limiter = o.limiter
+		})
+	}
+	if o.breaker != nil {
+		c.setBreaker(o.breaker)
+	}
+	if o.sampling != nil {
+		c.setSampling(o.sampling)
+	}
+	if o.buffer > 0 {
+		c.setPendingBuffer(o.buffer)
+	}
+	if o.registry != nil {
+		c.setRegistry(o.registry)
+	}
+	for _, e := range o.extractors {
+		c.useContextExtractor(e)
+	}
+	if o.ttl > 0 {
+		c.updateSettings(func(s *settings[K, T]) {
+			s.ttl = o.ttl
+		})
+	}
+	if o.health != nil {
+		c.setHealthTracking(o.health)
+	}
+	if o.history != nil {
+		c.setHistory(o.history)
+	}
+	if o.authorizer != nil {
+		c.setAuthorizer(o.authorizer)
+	}
+	if o.validator != nil {
+		v, ok := o.validator.(func(signal string, data T) error)
+		if !ok {
+			panic(fmt.Sprintf("broadcast: WithValidator data type does not match the broadcaster, got %T", o.validator))
+		}
+		c.setValidator(v)
+	}
+	if o.payloadValidator != nil {
+		c.setPayloadValidator(o.payloadValidator)
+	}
+	if o.upgrader != nil {
+		c.setUpgrader(o.upgrader)
+	}
+	if o.metrics != nil {
+		c.setMetrics(o.metrics)
+	}
+	if o.parallel > 1 {
+		c.setParallel(o.parallel)
+	}
+	if o.async != nil {
+		c.enableAsync(*o.async)
+	}
+}
+
+// useLogger 通过 OnError 与 OnExpired 回调记录日志
+func (c *core[K, T]) useLogger(logger *slog.Logger) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.onError = func(signal string, data T, handlerID HandlerID, err error) {
+			logger.Warn("broadcast: handler failed",
+				"signal", signal, "handler", handlerID, "name", c.handlerName(handlerID), "error", err)
+		}
+		s.onExpired = func(signal string, metadata map[string]interface{}) {
+			logger.Warn("broadcast: event expired", "signal", signal)
+		}
+	})
+}
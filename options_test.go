@@ -0,0 +1,62 @@
+package broadcast
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew_Options(t *testing.T) {
+	clock := &manualClock{now: time.Unix(100, 0)}
+	var logs bytes.Buffer
+	b := New[string](
+		WithClock(clock),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		WithRateLimiter(RateLimiterFunc(func(key string) bool { return key != "blocked" })),
+		WithPendingBuffer(4),
+		WithWorkers(2),
+	)
+	defer b.Close()
+
+	if err := b.Broadcast("blocked", nil); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected the rate limiter to be installed, got %v", err)
+	}
+	b.Broadcast("later", nil)
+	if b.Buffered() != 1 {
+		t.Errorf("expected the pending buffer to be enabled, got %d", b.Buffered())
+	}
+	if got := b.LastBroadcast("later"); !got.Equal(time.Unix(100, 0)) {
+		t.Errorf("expected the clock to be installed, got %v", got)
+	}
+
+	done := make(chan struct{})
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		defer close(done)
+		return errors.New("boom")
+	}, WithName("failing"))
+	b.Watch("later", "a")
+	<-done
+	b.Close()
+
+	if out := logs.String(); !strings.Contains(out, "handler failed") || !strings.Contains(out, "name=failing") {
+		t.Errorf("expected the handler error to be logged, got %q", out)
+	}
+}
+
+func TestNewUnique_Options(t *testing.T) {
+	b := NewUnique[int, TestUniqueData](
+		WithAsync(AsyncConfig{QueueSize: 8}),
+		WithRegistry(RegistryConfig{Registry: NewRegistry(), Strict: true}),
+	)
+	defer b.Close()
+
+	if err := b.Broadcast("test", nil); !errors.Is(err, ErrUndeclaredSignal) {
+		t.Errorf("expected the registry to be installed, got %v", err)
+	}
+	if b.core.loadSettings().async == nil {
+		t.Error("expected async delivery to be enabled")
+	}
+}
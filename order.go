@@ -0,0 +1,111 @@
+package broadcast
+
+import (
+	"cmp"
+	"fmt"
+	"runtime"
+	"slices"
+)
+
+// DeliveryOrder 是同一处理器在各监听器之间的投递顺序
+type DeliveryOrder int
+
+const (
+	// OrderRegistration 默认顺序: 按监听器的 Watch 顺序依次执行, 上一个监听器返回后才执行下一个
+	// UpdateWatch 替换值时保留原位置, Unwatch 后重新 Watch 排在末尾
+	OrderRegistration DeliveryOrder = iota
+	// OrderKeySorted 按监听器 key 升序依次执行
+	// 整数、浮点数与字符串 key 按自然顺序比较, 其他类型按 fmt.Sprint 的结果比较
+	OrderKeySorted
+	// OrderUnordered 不保证顺序, 各监听器并发执行, 并发数由 SetParallel 限制, 默认为 GOMAXPROCS
+	OrderUnordered
+)
+
+// orderOf 返回信号的投递顺序, 信号没有单独设置时使用广播器的默认顺序
+func (s *settings[K, T]) orderOf(signal string) DeliveryOrder {
+	if o, ok := s.orders[signal]; ok {
+		return o
+	}
+	return s.order
+}
+
+// parallelism 返回无序投递时的并发上限
+func (s *settings[K, T]) parallelism() int {
+	if s.parallel > 1 {
+		return s.parallel
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+func (c *core[K, T]) setDeliveryOrder(order DeliveryOrder) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.order = order
+	})
+}
+
+// setSignalOrder 为单个信号设置投递顺序, 覆盖广播器的默认顺序
+func (c *core[K, T]) setSignalOrder(signal string, order DeliveryOrder) {
+	c.updateSettings(func(s *settings[K, T]) {
+		orders := make(map[string]DeliveryOrder, len(s.orders)+1)
+		for k, v := range s.orders {
+			orders[k] = v
+		}
+		orders[signal] = order
+		s.orders = orders
+	})
+}
+
+// sortByKey 返回按 key 升序排列的监听器副本
+func sortByKey[K comparable, T any](listeners []listener[K, T]) []listener[K, T] {
+	if len(listeners) < 2 {
+		return listeners
+	}
+	sorted := slices.Clone(listeners)
+	slices.SortStableFunc(sorted, func(a, b listener[K, T]) int {
+		return compareKeys(a.key.Value(), b.key.Value())
+	})
+	return sorted
+}
+
+// compareKeys 比较两个 key, 有序的内置类型按自然顺序, 其他类型按字符串形式
+func compareKeys[K comparable](a, b K) int {
+	switch x := any(a).(type) {
+	case string:
+		return cmp.Compare(x, any(b).(string))
+	case int:
+		return cmp.Compare(x, any(b).(int))
+	case int64:
+		return cmp.Compare(x, any(b).(int64))
+	case int32:
+		return cmp.Compare(x, any(b).(int32))
+	case uint:
+		return cmp.Compare(x, any(b).(uint))
+	case uint64:
+		return cmp.Compare(x, any(b).(uint64))
+	case uint32:
+		return cmp.Compare(x, any(b).(uint32))
+	case float64:
+		return cmp.Compare(x, any(b).(float64))
+	}
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// SetDeliveryOrder 设置所有信号默认的监听器投递顺序
+func (b *Broadcast[T]) SetDeliveryOrder(order DeliveryOrder) {
+	b.c().setDeliveryOrder(order)
+}
+
+// SetSignalOrder 为单个信号设置监听器投递顺序, 覆盖 SetDeliveryOrder 的设置
+func (b *Broadcast[T]) SetSignalOrder(signal string, order DeliveryOrder) {
+	b.c().setSignalOrder(b.sig(signal), order)
+}
+
+// SetDeliveryOrder 设置所有信号默认的监听器投递顺序
+func (b *UniqueBroadcast[K, T]) SetDeliveryOrder(order DeliveryOrder) {
+	b.core.setDeliveryOrder(order)
+}
+
+// SetSignalOrder 为单个信号设置监听器投递顺序, 覆盖 SetDeliveryOrder 的设置
+func (b *UniqueBroadcast[K, T]) SetSignalOrder(signal string, order DeliveryOrder) {
+	b.core.setSignalOrder(signal, order)
+}
@@ -0,0 +1,94 @@
+package broadcast
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"unique"
+)
+
+// numbered 以整数为 key 的监听器
+type numbered struct {
+	id   int
+	name string
+}
+
+func (n numbered) Unique() unique.Handle[int] { return unique.Make(n.id) }
+func (n numbered) Value() string              { return n.name }
+
+func TestDeliveryOrder_Registration(t *testing.T) {
+	b := NewUnique[int, string]()
+	for _, k := range []int{5, 3, 9, 1} {
+		b.Watch("s", numbered{k, fmt.Sprint(k)})
+	}
+	b.UpdateWatch("s", numbered{3, "3'"})
+	b.Unwatch("s", numbered{5, ""})
+	b.Watch("s", numbered{5, "5'"})
+
+	var got []string
+	b.Handle(func(signal string, data string, _ map[string]interface{}) error {
+		got = append(got, data)
+		return nil
+	})
+	for i := 0; i < 10; i++ {
+		got = got[:0]
+		b.Broadcast("s", nil)
+		if want := []string{"3'", "9", "1", "5'"}; !slices.Equal(got, want) {
+			t.Fatalf("expected registration order %v, got %v", want, got)
+		}
+	}
+}
+
+func TestDeliveryOrder_KeySorted(t *testing.T) {
+	b := NewUnique[int, string]()
+	b.SetSignalOrder("sorted", OrderKeySorted)
+	for _, k := range []int{10, 2, 33, 4} {
+		b.Watch("sorted", numbered{k, fmt.Sprint(k)})
+		b.Watch("plain", numbered{k, fmt.Sprint(k)})
+	}
+
+	got := map[string][]string{}
+	b.Handle(func(signal string, data string, _ map[string]interface{}) error {
+		got[signal] = append(got[signal], data)
+		return nil
+	})
+	b.Broadcast("sorted", nil)
+	b.Broadcast("plain", nil)
+
+	if want := []string{"2", "4", "10", "33"}; !slices.Equal(got["sorted"], want) {
+		t.Errorf("expected numeric key order %v, got %v", want, got["sorted"])
+	}
+	if want := []string{"10", "2", "33", "4"}; !slices.Equal(got["plain"], want) {
+		t.Errorf("expected other signals to keep registration order %v, got %v", want, got["plain"])
+	}
+}
+
+func TestDeliveryOrder_Unordered(t *testing.T) {
+	b := New[int]()
+	b.SetDeliveryOrder(OrderUnordered)
+	for i := 0; i < 50; i++ {
+		b.Watch("s", i)
+	}
+
+	var mu sync.Mutex
+	var got []int
+	b.Handle(func(signal string, data int, _ map[string]interface{}) error {
+		mu.Lock()
+		got = append(got, data)
+		mu.Unlock()
+		return nil
+	})
+	b.Broadcast("s", nil)
+	slices.Sort(got)
+	if len(got) != 50 || got[0] != 0 || got[49] != 49 {
+		t.Errorf("expected every listener exactly once, got %d deliveries", len(got))
+	}
+
+	b.SetSignalOrder("s", OrderRegistration)
+	got = got[:0]
+	b.Broadcast("s", nil)
+	if !slices.IsSorted(got) {
+		t.Error("expected the per-signal order to override the default")
+	}
+}
@@ -0,0 +1,75 @@
+package broadcast
+
+import (
+	"sync"
+)
+
+// setParallel 设置每个处理器并发执行的监听器数量上限
+// n > 1 时默认顺序切换为 OrderUnordered, n <= 1 时从 OrderUnordered 恢复为按注册顺序
+func (c *core[K, T]) setParallel(n int) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.parallel = max(n, 0)
+		if n > 1 {
+			s.order = OrderUnordered
+		} else if s.order == OrderUnordered {
+			s.order = OrderRegistration
+		}
+	})
+}
+
+// deliverParallel 在最多 parallelism 个 goroutine 中对所有监听器执行同一个处理器, 等待全部完成后返回
+// 错误按监听器顺序排列, 与顺序执行时一致
+// 超过 WithDeadline 截止时间时不再开始新的调用, 未执行的调用记入 late
+func (c *core[K, T]) deliverParallel(settings *settings[K, T], d delivery[K, T], handler handlerEntry[T], signal string, values []T, late *DeadlineError) ([]error, *DeadlineError) {
+	// 复制一份设置供 goroutine 使用, 避免顺序执行路径上的设置逃逸到堆上
+	shared := *settings
+	results := make([]error, len(d.listeners))
+	overdue := make([]bool, len(d.listeners))
+	sem := make(chan struct{}, shared.parallelism())
+	var wg sync.WaitGroup
+	for i, l := range d.listeners {
+		var data T
+		if values != nil {
+			data = values[i]
+		} else {
+			data = l.data.Value()
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if c.overdue(&d) {
+				overdue[i] = true
+				return
+			}
+			results[i] = c.invoke(&shared, &d, &handler, signal, l, data)
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for i, err := range results {
+		if overdue[i] {
+			late = skipDelivery(late, &d, handler.id, d.listeners[i].key.Value())
+		} else if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs, late
+}
+
+// SetParallel 让每个处理器在最多 n 个 goroutine 中并发处理各监听器, 等待全部完成后再执行下一个处理器
+// 监听器数量很大时可显著降低单次广播的延迟; 开启后处理器、OnError 等回调会被并发调用
+// 等同于 SetDeliveryOrder(OrderUnordered) 并设置并发上限, n <= 1 时恢复按注册顺序执行
+func (b *Broadcast[T]) SetParallel(n int) {
+	b.c().setParallel(n)
+}
+
+// SetParallel 让每个处理器在最多 n 个 goroutine 中并发处理各监听器, 与 Broadcast.SetParallel 相同
+func (b *UniqueBroadcast[K, T]) SetParallel(n int) {
+	b.core.setParallel(n)
+}
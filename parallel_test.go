@@ -0,0 +1,71 @@
+package broadcast
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetParallel_BoundedConcurrency(t *testing.T) {
+	b := New[int](WithParallel(4))
+	for i := 0; i < 32; i++ {
+		b.Watch("load", i)
+	}
+
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	seen := make(map[int]bool)
+	b.Handle(func(signal string, data int, _ map[string]interface{}) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+
+		mu.Lock()
+		seen[data] = true
+		mu.Unlock()
+		return nil
+	})
+
+	if err := b.Broadcast("load", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 32 {
+		t.Errorf("expected every listener to be handled before Broadcast returns, got %d", len(seen))
+	}
+	if p := peak.Load(); p > 4 || p < 2 {
+		t.Errorf("expected between 2 and 4 concurrent calls, got %d", p)
+	}
+}
+
+func TestSetParallel_ErrorsInListenerOrder(t *testing.T) {
+	b := NewUnique[string, string]()
+	b.SetParallel(8)
+	for i := 0; i < 8; i++ {
+		b.Watch("jobs", owner{fmt.Sprint(i), fmt.Sprint(i)})
+	}
+	b.Handle(func(signal string, data string, _ map[string]interface{}) error {
+		if data == "2" || data == "5" {
+			return errors.New("fail " + data)
+		}
+		return nil
+	})
+
+	err := b.Broadcast("jobs", nil)
+	if err == nil || err.Error() != "fail 2\nfail 5" {
+		t.Errorf("expected errors in listener order, got %v", err)
+	}
+
+	b.SetParallel(0)
+	if err := b.Broadcast("jobs", nil); err == nil || err.Error() != "fail 2\nfail 5" {
+		t.Errorf("expected the same errors sequentially, got %v", err)
+	}
+}
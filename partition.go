@@ -0,0 +1,91 @@
+package broadcast
+
+import (
+	"fmt"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+)
+
+// partitionSeed 在进程内固定, 使相同 key 始终落在同一分区
+var partitionSeed = maphash.MakeSeed()
+
+// deliveryParts 跟踪按 key 拆分的一次广播, 最后一个部分完成时统一确认持久处理器
+type deliveryParts struct {
+	remaining atomic.Int32
+
+	mu     sync.Mutex
+	failed map[HandlerID]bool
+}
+
+func (p *deliveryParts) fail(id HandlerID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failed == nil {
+		p.failed = make(map[HandlerID]bool)
+	}
+	p.failed[id] = true
+}
+
+func (p *deliveryParts) hasFailed(id HandlerID) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.failed[id]
+}
+
+// settleParts 在拆分投递的最后一个部分完成后确认持久处理器
+func (c *core[K, T]) settleParts(d delivery[K, T]) {
+	if d.parts.remaining.Add(-1) != 0 {
+		return
+	}
+	for _, handler := range d.handlers {
+		if handler.durable != nil {
+			c.settleDurable(handler, d, d.parts.hasFailed(handler.id))
+		}
+	}
+}
+
+// newPartitioned 创建按 key 分区的队列, 每个分区只有一个工作 goroutine
+func newPartitioned[K comparable, T any](config AsyncConfig, run, drop func(d delivery[K, T])) *dispatcher[K, T] {
+	n := config.Workers
+	config.Workers = 1
+	config.KeyOrdered = false
+
+	d := &dispatcher[K, T]{config: config, partitions: make([]*dispatcher[K, T], n)}
+	for i := range d.partitions {
+		d.partitions[i] = newDispatcher(config, run, drop)
+	}
+	return d
+}
+
+// partition 返回 key 所在的分区
+func (d *dispatcher[K, T]) partition(key any) *dispatcher[K, T] {
+	h := maphash.String(partitionSeed, fmt.Sprint(key))
+	return d.partitions[h%uint64(len(d.partitions))]
+}
+
+// route 将投递按监听器 key 拆分到各分区, 任一分区已关闭时返回 false
+func (d *dispatcher[K, T]) route(item delivery[K, T]) bool {
+	if len(item.listeners) == 0 {
+		return d.partitions[0].push(item)
+	}
+
+	parts := &deliveryParts{}
+	parts.remaining.Store(int32(len(item.listeners)))
+	for i, l := range item.listeners {
+		part := item
+		part.listeners = item.listeners[i : i+1 : i+1]
+		part.parts = parts
+		p := d.partition(l.key.Value())
+		if !p.push(part) {
+			if i == 0 {
+				return false
+			}
+			// 队列在拆分过程中关闭, 剩余部分同步执行
+			p.run(part)
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package broadcast
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyOrdered_PerKeyFIFO(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	for id := 1; id <= 8; id++ {
+		b.Watch("test", &TestUniquer{data: TestUniqueData{ID: id}})
+	}
+
+	var mu sync.Mutex
+	got := make(map[int][]int)
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got[data.ID] = append(got[data.ID], metadata["n"].(int))
+		return nil
+	})
+
+	b.EnableAsync(AsyncConfig{Workers: 4, QueueSize: 16, KeyOrdered: true})
+	want := make([]int, 100)
+	for n := range want {
+		want[n] = n
+		b.Broadcast("test", map[string]interface{}{"n": n})
+	}
+	b.Close()
+
+	for id := 1; id <= 8; id++ {
+		if !slices.Equal(got[id], want) {
+			t.Errorf("expected key %d to receive all events in order, got %v", id, got[id])
+		}
+	}
+}
+
+func TestKeyOrdered_ParallelAcrossKeys(t *testing.T) {
+	b := New[string]()
+	b.EnableAsync(AsyncConfig{Workers: 16, KeyOrdered: true})
+	defer b.Close()
+
+	// 找到落在不同分区的两个 key
+	d := b.c().loadSettings().async
+	slow, fast := "slow", ""
+	for i := 0; fast == ""; i++ {
+		if key := fmt.Sprint(i); d.partition(key) != d.partition(slow) {
+			fast = key
+		}
+	}
+	b.Watch("test", slow)
+	b.Watch("test", fast)
+
+	release := make(chan struct{})
+	delivered := make(chan string, 2)
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		if data == slow {
+			<-release
+		}
+		delivered <- data
+		return nil
+	})
+
+	b.Broadcast("test", nil)
+	select {
+	case data := <-delivered:
+		if data != fast {
+			t.Errorf("expected %q first, got %q", fast, data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a blocked key not to block other keys")
+	}
+	close(release)
+	<-delivered
+}
+
+func TestKeyOrdered_DurableAckAfterAllParts(t *testing.T) {
+	journal, err := OpenFileJournal(t.TempDir(), FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	b := New[string]()
+	b.EnableJournal(JournalConfig{Journal: journal})
+	for i := 0; i < 8; i++ {
+		b.Watch("test", fmt.Sprint(i))
+	}
+	cursors := NewMemoryCursorStore()
+	var mu sync.Mutex
+	calls := 0
+	b.HandleDurable(DurableConfig{Name: "h", Cursors: cursors, RetryInterval: time.Hour},
+		func(signal string, data string, metadata map[string]interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			return nil
+		})
+
+	b.EnableAsync(AsyncConfig{Workers: 4, KeyOrdered: true})
+	b.Broadcast("test", nil)
+	b.Close()
+
+	if calls != 8 {
+		t.Errorf("expected 8 calls, got %d", calls)
+	}
+	if cursor, _ := cursors.LoadCursor("h"); cursor != 1 {
+		t.Errorf("expected the event to be acked once every part finished, got %d", cursor)
+	}
+}
@@ -0,0 +1,74 @@
+package broadcast
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// setPaused 暂停或恢复信号的广播, 返回状态是否改变
+func (c *core[K, T]) setPaused(signal string, paused bool) bool {
+	changed := false
+	c.updateSettings(func(s *settings[K, T]) {
+		if _, ok := s.paused[signal]; ok == paused {
+			return
+		}
+		changed = true
+		set := maps.Clone(s.paused)
+		if paused {
+			if set == nil {
+				set = make(map[string]struct{})
+			}
+			set[signal] = struct{}{}
+		} else {
+			delete(set, signal)
+		}
+		if len(set) == 0 {
+			set = nil
+		}
+		s.paused = set
+	})
+	return changed
+}
+
+// pausedSignals 返回暂停的信号名, 按字典序排列, prefix 非空时只返回该前缀下的信号并去掉前缀
+func (c *core[K, T]) pausedSignals(prefix string) []string {
+	var signals []string
+	for signal := range c.loadSettings().paused {
+		if rest, ok := strings.CutPrefix(signal, prefix); ok {
+			signals = append(signals, rest)
+		}
+	}
+	slices.Sort(signals)
+	return signals
+}
+
+// Pause 暂停信号的广播, 之后的 Broadcast 返回 ErrSignalPaused, 监听器与处理器保持不变; 返回信号之前是否未暂停
+func (b *Broadcast[T]) Pause(signal string) bool {
+	return b.c().setPaused(b.sig(signal), true)
+}
+
+// Resume 恢复被 Pause 暂停的信号, 返回信号之前是否处于暂停状态
+func (b *Broadcast[T]) Resume(signal string) bool {
+	return b.c().setPaused(b.sig(signal), false)
+}
+
+// Paused 返回暂停的信号名, 按字典序排列
+func (b *Broadcast[T]) Paused() []string {
+	return b.c().pausedSignals(b.prefix())
+}
+
+// Pause 暂停信号的广播, 之后的 Broadcast 返回 ErrSignalPaused, 监听器与处理器保持不变; 返回信号之前是否未暂停
+func (b *UniqueBroadcast[K, T]) Pause(signal string) bool {
+	return b.core.setPaused(signal, true)
+}
+
+// Resume 恢复被 Pause 暂停的信号, 返回信号之前是否处于暂停状态
+func (b *UniqueBroadcast[K, T]) Resume(signal string) bool {
+	return b.core.setPaused(signal, false)
+}
+
+// Paused 返回暂停的信号名, 按字典序排列
+func (b *UniqueBroadcast[K, T]) Paused() []string {
+	return b.core.pausedSignals("")
+}
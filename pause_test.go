@@ -0,0 +1,56 @@
+package broadcast
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPause_RejectsBroadcastsUntilResumed(t *testing.T) {
+	b := New[string]()
+	b.Watch("orders", "projection")
+	var delivered int
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		delivered++
+		return nil
+	})
+
+	if !b.Pause("orders") || b.Pause("orders") {
+		t.Error("expected Pause to report whether the signal was newly paused")
+	}
+	if err := b.Broadcast("orders", nil); !errors.Is(err, ErrSignalPaused) {
+		t.Errorf("expected ErrSignalPaused, got %v", err)
+	}
+	if err := b.Broadcast("metrics", nil); err != nil {
+		t.Errorf("expected other signals to be unaffected, got %v", err)
+	}
+	if b.WatchCount("orders") != 1 {
+		t.Error("expected listeners to be kept while paused")
+	}
+	if got := b.State().Paused; !slices.Equal(got, []string{"orders"}) {
+		t.Errorf("expected the state to list paused signals, got %v", got)
+	}
+
+	if !b.Resume("orders") || b.Resume("orders") {
+		t.Error("expected Resume to report whether the signal was paused")
+	}
+	if err := b.Broadcast("orders", nil); err != nil || delivered != 1 {
+		t.Errorf("expected delivery after Resume, got %v (%d)", err, delivered)
+	}
+}
+
+func TestPause_Namespace(t *testing.T) {
+	root := New[string]()
+	ns := root.Namespace("tenant.")
+	ns.Pause("orders")
+
+	if err := root.Broadcast("tenant.orders", nil); !errors.Is(err, ErrSignalPaused) {
+		t.Errorf("expected the namespaced signal to be paused, got %v", err)
+	}
+	if got := ns.Paused(); !slices.Equal(got, []string{"orders"}) {
+		t.Errorf("expected the view to strip its prefix, got %v", got)
+	}
+	if got := root.Paused(); !slices.Equal(got, []string{"tenant.orders"}) {
+		t.Errorf("expected the root to see the full name, got %v", got)
+	}
+}
@@ -0,0 +1,45 @@
+package broadcast
+
+// Priority 是信号的优先级
+type Priority int
+
+const (
+	// PriorityNormal 默认优先级
+	PriorityNormal Priority = iota
+	// PriorityHigh 异步模式下进入独立通道, 不受普通与低优先级积压影响
+	PriorityHigh
+	// PriorityLow 异步队列已满时直接丢弃, 不阻塞发布者
+	PriorityLow
+)
+
+// priorityOf 返回信号的优先级, 未设置时为 PriorityNormal
+func (s *settings[K, T]) priorityOf(signal string) Priority {
+	return s.priorities[signal]
+}
+
+// setPriority 设置信号的优先级, PriorityNormal 移除设置
+func (c *core[K, T]) setPriority(signal string, p Priority) {
+	c.updateSettings(func(s *settings[K, T]) {
+		priorities := make(map[string]Priority, len(s.priorities)+1)
+		for k, v := range s.priorities {
+			priorities[k] = v
+		}
+		if p == PriorityNormal {
+			delete(priorities, signal)
+		} else {
+			priorities[signal] = p
+		}
+		s.priorities = priorities
+	})
+}
+
+// enqueue 按优先级将投递放入对应的队列, 队列已关闭时返回 false
+func (d *dispatcher[K, T]) enqueue(item delivery[K, T], p Priority) bool {
+	switch {
+	case p == PriorityHigh && d.lane != nil:
+		return d.lane.push(item)
+	case p == PriorityLow:
+		item.low = true
+	}
+	return d.push(item)
+}
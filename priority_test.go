@@ -0,0 +1,64 @@
+package broadcast
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriority_HighLaneBypassesSaturatedPool(t *testing.T) {
+	b := New[string]()
+	b.Watch("bulk", "a")
+	b.Watch("alert", "a")
+	b.SetPriority("alert", PriorityHigh)
+	b.SetPriority("bulk", PriorityLow)
+
+	release := make(chan struct{})
+	alerts := make(chan struct{}, 1)
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		if signal == "bulk" {
+			<-release
+			return nil
+		}
+		alerts <- struct{}{}
+		return nil
+	})
+
+	dropped := 0
+	b.EnableAsync(AsyncConfig{
+		Workers:         1,
+		QueueSize:       2,
+		PriorityWorkers: 1,
+		OnOverflow: func(signal string, metadata map[string]interface{}) {
+			dropped++
+		},
+	})
+	// 低优先级广播占满工作 goroutine 与队列, 之后的低优先级广播被丢弃而不是阻塞
+	for i := 0; i < 5; i++ {
+		b.Broadcast("bulk", nil)
+	}
+	if dropped == 0 {
+		t.Error("expected low-priority broadcasts to be dropped when the queue is full")
+	}
+
+	b.Broadcast("alert", nil)
+	select {
+	case <-alerts:
+	case <-time.After(time.Second):
+		t.Fatal("expected the high-priority broadcast to be delivered while the pool is saturated")
+	}
+
+	close(release)
+	b.Close()
+	if b.Pending() != 0 {
+		t.Errorf("expected both lanes to drain, got %d pending", b.Pending())
+	}
+}
+
+func TestPriority_NormalRemovesSetting(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	b.SetPriority("test", PriorityHigh)
+	b.SetPriority("test", PriorityNormal)
+	if p := b.core.loadSettings().priorityOf("test"); p != PriorityNormal {
+		t.Errorf("expected PriorityNormal, got %v", p)
+	}
+}
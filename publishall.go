@@ -0,0 +1,94 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Broadcaster 是可以参与 PublishAll 的广播器, 由 Broadcast 与 UniqueBroadcast 实现
+type Broadcaster interface {
+	authorizeBroadcast(ctx context.Context, signal string) error
+	admit(signal string, payload any) (admission, error)
+	cancel(signal string, a admission)
+	commitData(signal string, payload any, metadata map[string]interface{}) error
+	setUpgrader(u Upgrader)
+}
+
+// PublishEntry 是 PublishAll 中的一次广播
+// Data 作为广播时负载交给通过 HandleData 注册的处理器
+type PublishEntry struct {
+	B        Broadcaster
+	Signal   string
+	Data     any
+	Metadata map[string]interface{}
+}
+
+// PublishAll 在多个广播器上以全有或全无的方式广播
+// 准备阶段依次检查每个广播的授权 (身份来自 ctx)、熔断与限流, 任何一个被拒绝或 ctx 已结束时不广播任何事件;
+// 全部通过后进入提交阶段依次广播, 返回所有处理器错误的组合.
+// 准备阶段被拒绝时撤销此前已通过的广播: 结束放行的熔断器探测, 退还配额, 限流器实现 RateRefunder 时退还许可
+func PublishAll(ctx context.Context, entries []PublishEntry) error {
+	admitted := make([]admission, 0, len(entries))
+	rollback := func() {
+		for i, a := range admitted {
+			entries[i].B.cancel(entries[i].Signal, a)
+		}
+	}
+	for i, e := range entries {
+		if err := ctx.Err(); err != nil {
+			rollback()
+			return err
+		}
+		if err := e.B.authorizeBroadcast(ctx, e.Signal); err != nil {
+			rollback()
+			return fmt.Errorf("broadcast: authorize entry %d (%s): %w", i, e.Signal, err)
+		}
+		a, err := e.B.admit(e.Signal, e.Data)
+		if err != nil {
+			rollback()
+			return fmt.Errorf("broadcast: prepare entry %d (%s): %w", i, e.Signal, err)
+		}
+		admitted = append(admitted, a)
+	}
+	if err := ctx.Err(); err != nil {
+		rollback()
+		return err
+	}
+
+	var errs []error
+	for _, e := range entries {
+		if err := e.B.commitData(e.Signal, e.Data, e.Metadata); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *core[K, T]) commitData(signal string, payload any, metadata map[string]interface{}) error {
+	return c.commit(delivery[K, T]{signal: signal, metadata: metadata, payload: payload})
+}
+
+func (b *Broadcast[T]) admit(signal string, payload any) (admission, error) {
+	return b.c().admit(b.sig(signal), payload)
+}
+
+func (b *Broadcast[T]) cancel(signal string, a admission) {
+	b.c().cancel(b.sig(signal), a)
+}
+
+func (b *Broadcast[T]) commitData(signal string, payload any, metadata map[string]interface{}) error {
+	return b.c().commitData(b.sig(signal), payload, metadata)
+}
+
+func (b *UniqueBroadcast[K, T]) admit(signal string, payload any) (admission, error) {
+	return b.core.admit(signal, payload)
+}
+
+func (b *UniqueBroadcast[K, T]) cancel(signal string, a admission) {
+	b.core.cancel(signal, a)
+}
+
+func (b *UniqueBroadcast[K, T]) commitData(signal string, payload any, metadata map[string]interface{}) error {
+	return b.core.commitData(signal, payload, metadata)
+}
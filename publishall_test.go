@@ -0,0 +1,114 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPublishAll(t *testing.T) {
+	orders := New[string]()
+	billing := NewUnique[int, TestUniqueData]()
+	orders.Watch("order.created", "projection")
+	billing.Watch("invoice.created", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	var got []any
+	HandleData(orders, func(signal string, data string, payload int, metadata map[string]interface{}) error {
+		got = append(got, payload)
+		return nil
+	})
+	HandleData(billing, func(signal string, data TestUniqueData, payload string, metadata map[string]interface{}) error {
+		got = append(got, payload)
+		return nil
+	})
+
+	err := PublishAll(context.Background(), []PublishEntry{
+		{B: orders, Signal: "order.created", Data: 42},
+		{B: billing, Signal: "invoice.created", Data: "inv-42"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != 42 || got[1] != "inv-42" {
+		t.Errorf("expected both events delivered, got %v", got)
+	}
+}
+
+func TestPublishAll_AllOrNothing(t *testing.T) {
+	orders := New[string]()
+	billing := New[string]()
+	orders.Watch("order.created", "projection")
+	billing.Watch("invoice.created", "projection")
+	billing.SetRateLimiter(RateLimiterFunc(func(key string) bool { return false }))
+
+	calls := 0
+	orders.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		calls++
+		return nil
+	})
+
+	err := PublishAll(context.Background(), []PublishEntry{
+		{B: orders, Signal: "order.created"},
+		{B: billing, Signal: "invoice.created"},
+	})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no delivery when one entry is rejected, got %d", calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := PublishAll(ctx, []PublishEntry{{B: orders, Signal: "order.created"}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no delivery for a cancelled context, got %d", calls)
+	}
+}
+
+func TestPublishAll_RollsBackAdmissions(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	orders := New[string]()
+	orders.SetClock(clock)
+	orders.SetBreaker(&BreakerConfig{FailureThreshold: 1, ProbeInterval: time.Second, ProbeTimeout: time.Hour})
+	billing := New[string]()
+	billing.SetRateLimiter(RateLimiterFunc(func(key string) bool { return false }))
+
+	failing := true
+	orders.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		if failing {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	})
+	orders.Watch("order.created", "projection")
+	billing.Watch("invoice.created", "projection")
+
+	orders.Broadcast("order.created", nil)
+	if orders.Healthy("order.created") {
+		t.Fatal("breaker should open after a failure")
+	}
+	// 配额与令牌都只够一次广播
+	orders.SetQuota("order.created", &QuotaConfig{Rate: 0.001})
+	orders.SetRateLimiter(NewTokenBucket(0.001, 1))
+
+	// 第一个广播占用了半开熔断器的探测, 第二个被拒绝后探测、配额与令牌都应撤销
+	failing = false
+	clock.now = clock.now.Add(time.Second)
+	err := PublishAll(context.Background(), []PublishEntry{
+		{B: orders, Signal: "order.created"},
+		{B: billing, Signal: "invoice.created"},
+	})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if err := orders.Broadcast("order.created", nil); err != nil {
+		t.Fatalf("expected the probe, quota and token to be released, got %v", err)
+	}
+	if !orders.Healthy("order.created") {
+		t.Error("breaker should close after a successful probe")
+	}
+}
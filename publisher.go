@@ -0,0 +1,170 @@
+package broadcast
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// BroadcastTarget 是 Publisher 的广播目标, 由 Broadcast 与 UniqueBroadcast 实现
+type BroadcastTarget interface {
+	Broadcast(signal string, metadata map[string]interface{}, opts ...BroadcastOption) error
+}
+
+// PublisherConfig 是 Publisher 的配置
+type PublisherConfig struct {
+	// MaxBatch 缓冲的广播达到该数量时自动刷新, 默认为 100
+	MaxBatch int
+	// FlushInterval 大于 0 时按该间隔定期刷新
+	FlushInterval time.Duration
+	// PerSignal 为 true 时按信号分别计数, 某个信号达到 MaxBatch 时只刷新该信号
+	PerSignal bool
+	// OnError 接收定期刷新时的广播错误, 显式调用的 Flush 直接返回错误
+	OnError func(err error)
+	// Clock 为定期刷新使用的时间源, 默认为 SystemClock
+	Clock Clock
+}
+
+type pendingPublish struct {
+	signal   string
+	metadata map[string]interface{}
+	opts     []BroadcastOption
+}
+
+// Publisher 累积广播并按批次刷新到目标广播器, 适合高频发布者
+// 同一 Publisher 内的广播按 Publish 的顺序投递
+type Publisher struct {
+	target BroadcastTarget
+	config PublisherConfig
+
+	mu      sync.Mutex
+	pending []pendingPublish
+	counts  map[string]int
+	timer   Timer
+	closed  bool
+	// flushMu 保证批次按顺序投递
+	flushMu sync.Mutex
+}
+
+// NewPublisher 创建向 target 发布的缓冲发布者
+func NewPublisher(target BroadcastTarget, config PublisherConfig) *Publisher {
+	if config.MaxBatch <= 0 {
+		config.MaxBatch = 100
+	}
+	if config.Clock == nil {
+		config.Clock = SystemClock
+	}
+	p := &Publisher{target: target, config: config, counts: make(map[string]int)}
+	if config.FlushInterval > 0 {
+		p.mu.Lock()
+		p.schedule()
+		p.mu.Unlock()
+	}
+	return p
+}
+
+// schedule 在锁内启动下一次定期刷新
+func (p *Publisher) schedule() {
+	p.timer = p.config.Clock.AfterFunc(p.config.FlushInterval, func() {
+		if err := p.Flush(); err != nil && p.config.OnError != nil {
+			p.config.OnError(err)
+		}
+		p.mu.Lock()
+		if !p.closed {
+			p.schedule()
+		}
+		p.mu.Unlock()
+	})
+}
+
+// Publish 缓冲一次广播, 达到 MaxBatch 时在当前 goroutine 中刷新并返回刷新的错误
+func (p *Publisher) Publish(signal string, metadata map[string]interface{}, opts ...BroadcastOption) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPublisherClosed
+	}
+	p.pending = append(p.pending, pendingPublish{signal: signal, metadata: metadata, opts: opts})
+	p.counts[signal]++
+	full := len(p.pending) >= p.config.MaxBatch
+	if p.config.PerSignal {
+		full = p.counts[signal] >= p.config.MaxBatch
+	}
+	p.mu.Unlock()
+
+	if !full {
+		return nil
+	}
+	if p.config.PerSignal {
+		return p.FlushSignal(signal)
+	}
+	return p.Flush()
+}
+
+// Flush 依次广播所有缓冲的事件, 返回所有广播错误的组合
+func (p *Publisher) Flush() error {
+	p.flushMu.Lock()
+	defer p.flushMu.Unlock()
+
+	p.mu.Lock()
+	batch := p.pending
+	p.pending = nil
+	clear(p.counts)
+	p.mu.Unlock()
+	return p.send(batch)
+}
+
+// FlushSignal 只广播 signal 上缓冲的事件, 其他信号的事件保持缓冲
+func (p *Publisher) FlushSignal(signal string) error {
+	p.flushMu.Lock()
+	defer p.flushMu.Unlock()
+
+	p.mu.Lock()
+	var batch []pendingPublish
+	kept := p.pending[:0]
+	for _, e := range p.pending {
+		if e.signal == signal {
+			batch = append(batch, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	clear(p.pending[len(kept):])
+	p.pending = kept
+	delete(p.counts, signal)
+	p.mu.Unlock()
+	return p.send(batch)
+}
+
+func (p *Publisher) send(batch []pendingPublish) error {
+	var errs []error
+	for _, e := range batch {
+		if err := p.target.Broadcast(e.signal, e.metadata, e.opts...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Pending 返回缓冲中等待刷新的广播数量
+func (p *Publisher) Pending() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.pending)
+}
+
+// Close 停止定期刷新并刷新剩余的广播, 之后的 Publish 返回 ErrPublisherClosed
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	p.mu.Unlock()
+	return p.Flush()
+}
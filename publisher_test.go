@@ -0,0 +1,92 @@
+package broadcast
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPublisher_FlushOnSizeAndExplicit(t *testing.T) {
+	b := New[string]()
+	b.Watch("a", "x")
+	b.Watch("b", "x")
+	var got []string
+	b.Handle(func(signal string, data string, md map[string]interface{}) error {
+		got = append(got, signal+md["n"].(string))
+		return nil
+	})
+
+	p := NewPublisher(b, PublisherConfig{MaxBatch: 3})
+	p.Publish("a", map[string]interface{}{"n": "1"})
+	p.Publish("b", map[string]interface{}{"n": "2"})
+	if len(got) != 0 || p.Pending() != 2 {
+		t.Fatalf("expected broadcasts to be buffered, got %v", got)
+	}
+	p.Publish("a", map[string]interface{}{"n": "3"})
+	if want := []string{"a1", "b2", "a3"}; !slices.Equal(got, want) || p.Pending() != 0 {
+		t.Fatalf("expected a size-triggered flush %v, got %v", want, got)
+	}
+
+	p.Publish("b", map[string]interface{}{"n": "4"})
+	if err := p.Close(); err != nil || len(got) != 4 {
+		t.Errorf("expected Close to flush the rest, got %v %v", got, err)
+	}
+	if err := p.Publish("a", nil); !errors.Is(err, ErrPublisherClosed) {
+		t.Errorf("expected ErrPublisherClosed, got %v", err)
+	}
+}
+
+func TestPublisher_PerSignal(t *testing.T) {
+	b := NewUnique[string, string]()
+	b.Watch("hot", owner{"k", "v"})
+	b.Watch("cold", owner{"k", "v"})
+	counts := map[string]int{}
+	b.Handle(func(signal string, data string, _ map[string]interface{}) error {
+		counts[signal]++
+		return errors.New(signal)
+	})
+
+	p := NewPublisher(b, PublisherConfig{MaxBatch: 2, PerSignal: true})
+	p.Publish("cold", nil)
+	p.Publish("hot", nil)
+	err := p.Publish("hot", nil)
+	if counts["hot"] != 2 || counts["cold"] != 0 || p.Pending() != 1 {
+		t.Errorf("expected only the full signal to flush, got %v", counts)
+	}
+	if err == nil {
+		t.Error("expected the flush to return handler errors")
+	}
+}
+
+func TestPublisher_FlushInterval(t *testing.T) {
+	b := New[string]()
+	b.Watch("tick", "x")
+	var mu sync.Mutex
+	delivered := 0
+	b.Handle(func(signal string, data string, _ map[string]interface{}) error {
+		mu.Lock()
+		delivered++
+		mu.Unlock()
+		return nil
+	})
+
+	p := NewPublisher(b, PublisherConfig{MaxBatch: 1000, FlushInterval: 10 * time.Millisecond})
+	defer p.Close()
+	p.Publish("tick", nil)
+	p.Publish("tick", nil)
+
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return delivered
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for count() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := count(); n != 2 {
+		t.Errorf("expected the timer to flush both broadcasts, got %d", n)
+	}
+}
@@ -0,0 +1,163 @@
+package broadcast
+
+import (
+	"slices"
+	"sync"
+	"unique"
+)
+
+// QuarantineConfig 隔离反复失败的监听器的配置
+// 同一信号上某个 key 对任一处理器连续失败 After 次后, 该 key 在该信号上被隔离:
+// 之后的投递跳过它, 直到调用 Unquarantine
+type QuarantineConfig[K comparable] struct {
+	// After 连续失败达到该次数时隔离, 默认为 3
+	After int
+	// OnQuarantine 在 key 被隔离时调用, err 为最后一次失败的错误
+	OnQuarantine func(signal string, key K, err error)
+}
+
+// signalKey 标识某个信号上的一个 key
+type signalKey[K comparable] struct {
+	signal string
+	key    unique.Handle[K]
+}
+
+type quarantineState struct {
+	// failures 记录每个处理器的连续失败次数
+	failures    map[HandlerID]int
+	quarantined bool
+}
+
+// quarantine 维护每个 (信号, key) 的失败计数与隔离状态
+type quarantine[K comparable] struct {
+	mu     sync.Mutex
+	config QuarantineConfig[K]
+	states map[signalKey[K]]*quarantineState
+}
+
+func newQuarantine[K comparable](config QuarantineConfig[K]) *quarantine[K] {
+	if config.After <= 0 {
+		config.After = 3
+	}
+	return &quarantine[K]{config: config, states: make(map[signalKey[K]]*quarantineState)}
+}
+
+// blocked 报告 key 是否已在信号上被隔离
+func (q *quarantine[K]) blocked(signal string, key unique.Handle[K]) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	s, ok := q.states[signalKey[K]{signal, key}]
+	return ok && s.quarantined
+}
+
+// record 记录一次调用的结果, 连续失败达到阈值时隔离 key
+func (q *quarantine[K]) record(signal string, key unique.Handle[K], id HandlerID, err error) {
+	q.mu.Lock()
+	k := signalKey[K]{signal, key}
+	s, ok := q.states[k]
+	if err == nil {
+		if ok && !s.quarantined {
+			delete(s.failures, id)
+			if len(s.failures) == 0 {
+				delete(q.states, k)
+			}
+		}
+		q.mu.Unlock()
+		return
+	}
+	if !ok {
+		s = &quarantineState{failures: make(map[HandlerID]int)}
+		q.states[k] = s
+	}
+	s.failures[id]++
+	isolate := !s.quarantined && s.failures[id] >= q.config.After
+	if isolate {
+		s.quarantined = true
+	}
+	q.mu.Unlock()
+
+	if isolate && q.config.OnQuarantine != nil {
+		q.config.OnQuarantine(signal, key.Value(), err)
+	}
+}
+
+// list 返回信号上被隔离的 key
+func (q *quarantine[K]) list(signal string) []K {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var keys []K
+	for k, s := range q.states {
+		if k.signal == signal && s.quarantined {
+			keys = append(keys, k.key.Value())
+		}
+	}
+	slices.SortFunc(keys, compareKeys[K])
+	return keys
+}
+
+// release 解除隔离并清空失败计数, 返回 key 之前是否被隔离
+func (q *quarantine[K]) release(signal string, key unique.Handle[K]) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	k := signalKey[K]{signal, key}
+	s, ok := q.states[k]
+	delete(q.states, k)
+	return ok && s.quarantined
+}
+
+func (c *core[K, T]) setQuarantine(config *QuarantineConfig[K]) {
+	c.updateSettings(func(s *settings[K, T]) {
+		if config != nil {
+			s.quarantine = newQuarantine(*config)
+		} else {
+			s.quarantine = nil
+		}
+	})
+}
+
+func (c *core[K, T]) quarantined(signal string) []K {
+	if q := c.loadSettings().quarantine; q != nil {
+		return q.list(signal)
+	}
+	return nil
+}
+
+func (c *core[K, T]) unquarantine(signal string, key unique.Handle[K]) bool {
+	if q := c.loadSettings().quarantine; q != nil {
+		return q.release(signal, key)
+	}
+	return false
+}
+
+// SetQuarantine 开启对反复失败的监听器的隔离, 传入 nil 关闭并清除所有隔离
+func (b *Broadcast[T]) SetQuarantine(config *QuarantineConfig[T]) {
+	b.c().setQuarantine(config)
+}
+
+// Quarantined 返回信号上被隔离的监听器
+func (b *Broadcast[T]) Quarantined(signal string) []T {
+	return b.c().quarantined(b.sig(signal))
+}
+
+// Unquarantine 解除 data 在信号上的隔离, 返回 data 之前是否被隔离
+func (b *Broadcast[T]) Unquarantine(signal string, data T) bool {
+	return b.c().unquarantine(b.sig(signal), unique.Make(data))
+}
+
+// SetQuarantine 开启对反复失败的监听器的隔离, 传入 nil 关闭并清除所有隔离
+func (b *UniqueBroadcast[K, T]) SetQuarantine(config *QuarantineConfig[K]) {
+	b.core.setQuarantine(config)
+}
+
+// Quarantined 返回信号上被隔离的 key
+func (b *UniqueBroadcast[K, T]) Quarantined(signal string) []K {
+	return b.core.quarantined(signal)
+}
+
+// Unquarantine 解除 key 在信号上的隔离, 返回 key 之前是否被隔离
+func (b *UniqueBroadcast[K, T]) Unquarantine(signal string, key K) bool {
+	return b.core.unquarantine(signal, unique.Make(key))
+}
@@ -0,0 +1,73 @@
+package broadcast
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestQuarantine(t *testing.T) {
+	b := NewUnique[string, string]()
+	var isolated []string
+	b.SetQuarantine(&QuarantineConfig[string]{
+		After: 2,
+		OnQuarantine: func(signal string, key string, err error) {
+			isolated = append(isolated, signal+"/"+key+": "+err.Error())
+		},
+	})
+	b.Watch("hooks", owner{"good", "ok"})
+	b.Watch("hooks", owner{"bad", "broken"})
+
+	calls := map[string]int{}
+	b.Handle(func(signal string, data string, _ map[string]interface{}) error {
+		calls[data]++
+		if data == "broken" {
+			return errors.New("endpoint gone")
+		}
+		return nil
+	})
+
+	for i := 0; i < 5; i++ {
+		b.Broadcast("hooks", nil)
+	}
+	if calls["broken"] != 2 || calls["ok"] != 5 {
+		t.Errorf("expected the failing key to be skipped after 2 failures, got %v", calls)
+	}
+	if got := b.Quarantined("hooks"); !slices.Equal(got, []string{"bad"}) {
+		t.Errorf("expected bad to be quarantined, got %v", got)
+	}
+	if len(isolated) != 1 || isolated[0] != "hooks/bad: endpoint gone" {
+		t.Errorf("expected one notification, got %v", isolated)
+	}
+
+	if !b.Unquarantine("hooks", "bad") || b.Unquarantine("hooks", "bad") {
+		t.Error("expected Unquarantine to release the key once")
+	}
+	if err := b.Broadcast("hooks", nil); err == nil || calls["broken"] != 3 {
+		t.Errorf("expected the released key to be retried, got %v", err)
+	}
+}
+
+func TestQuarantine_SuccessResetsFailures(t *testing.T) {
+	b := New[string]()
+	b.SetQuarantine(&QuarantineConfig[string]{After: 2})
+	b.Watch("s", "flaky")
+
+	fail := true
+	b.Handle(func(signal string, data string, _ map[string]interface{}) error {
+		if fail {
+			return errors.New("flaky")
+		}
+		return nil
+	})
+
+	for i := 0; i < 3; i++ {
+		fail = true
+		b.Broadcast("s", nil)
+		fail = false
+		b.Broadcast("s", nil)
+	}
+	if got := b.Quarantined("s"); len(got) != 0 {
+		t.Errorf("expected intermittent failures not to quarantine, got %v", got)
+	}
+}
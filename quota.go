@@ -0,0 +1,126 @@
+package broadcast
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// QuotaPolicy 决定广播超过信号配额时的行为
+type QuotaPolicy int
+
+const (
+	// QuotaReject 超过配额的广播返回 ErrQuotaExceeded (默认)
+	QuotaReject QuotaPolicy = iota
+	// QuotaQueue 超过配额的广播排队等待, Broadcast 阻塞到配额允许时再投递
+	QuotaQueue
+)
+
+// QuotaConfig 是单个信号的吞吐配额, 用于在共享基础设施中限制各信号的使用量
+type QuotaConfig struct {
+	// Rate 为每秒允许的广播数量
+	Rate float64
+	// Burst 为允许的突发数量, 默认为 1
+	Burst int
+	// Policy 为超过配额时的策略
+	Policy QuotaPolicy
+	// MaxWait 大于 0 时, QuotaQueue 需要等待更久的广播返回 ErrQuotaExceeded
+	MaxWait time.Duration
+	// OnExceeded 在广播超过配额 (被拒绝或排队) 时调用, 用于计量与告警
+	OnExceeded func(signal string, policy QuotaPolicy)
+}
+
+// quota 是单个信号的令牌桶, QuotaQueue 时令牌可以为负, 表示已预留给排队的广播
+type quota struct {
+	config QuotaConfig
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newQuota(config QuotaConfig) *quota {
+	config.Burst = max(config.Burst, 1)
+	return &quota{config: config, tokens: float64(config.Burst)}
+}
+
+// reserve 为一次广播预留配额, 返回需要等待的时间; 无法预留时 ok 为 false
+func (q *quota) reserve(now time.Time) (wait time.Duration, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.last.IsZero() {
+		q.tokens = min(q.tokens+now.Sub(q.last).Seconds()*q.config.Rate, float64(q.config.Burst))
+	}
+	q.last = now
+	if q.tokens >= 1 {
+		q.tokens--
+		return 0, true
+	}
+	if q.config.Policy != QuotaQueue {
+		return 0, false
+	}
+	wait = time.Duration((1 - q.tokens) / q.config.Rate * float64(time.Second))
+	if q.config.MaxWait > 0 && wait > q.config.MaxWait {
+		return 0, false
+	}
+	q.tokens--
+	return wait, true
+}
+
+// refund 退还一次预留的配额
+func (q *quota) refund() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.tokens = min(q.tokens+1, float64(q.config.Burst))
+}
+
+// checkQuota 按信号配额放行广播, QuotaQueue 时阻塞到预留的时间
+func (c *core[K, T]) checkQuota(q *quota, signal string) error {
+	clock := c.clock()
+	wait, ok := q.reserve(clock.Now())
+	if (!ok || wait > 0) && q.config.OnExceeded != nil {
+		q.config.OnExceeded(signal, q.config.Policy)
+	}
+	if !ok {
+		return ErrQuotaExceeded
+	}
+	if wait > 0 {
+		done := make(chan struct{})
+		clock.AfterFunc(wait, func() { close(done) })
+		<-done
+	}
+	return nil
+}
+
+// setQuota 设置信号的配额, config 为 nil 时移除
+func (c *core[K, T]) setQuota(signal string, config *QuotaConfig) {
+	c.updateSettings(func(s *settings[K, T]) {
+		quotas := maps.Clone(s.quotas)
+		if config == nil {
+			delete(quotas, signal)
+		} else {
+			if quotas == nil {
+				quotas = make(map[string]*quota)
+			}
+			quotas[signal] = newQuota(*config)
+		}
+		if len(quotas) == 0 {
+			quotas = nil
+		}
+		s.quotas = quotas
+	})
+}
+
+// SetQuota 设置信号每秒的广播配额, 传入 nil 移除; 重新设置时配额从满额开始
+// 超过配额的广播按 Policy 返回 ErrQuotaExceeded 或排队等待, 并调用 OnExceeded
+func (b *Broadcast[T]) SetQuota(signal string, config *QuotaConfig) {
+	b.c().setQuota(b.sig(signal), config)
+}
+
+// SetQuota 设置信号每秒的广播配额, 传入 nil 移除; 重新设置时配额从满额开始
+// 超过配额的广播按 Policy 返回 ErrQuotaExceeded 或排队等待, 并调用 OnExceeded
+func (b *UniqueBroadcast[K, T]) SetQuota(signal string, config *QuotaConfig) {
+	b.core.setQuota(signal, config)
+}
@@ -0,0 +1,91 @@
+package broadcast
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuota_RejectsAndRefills(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b := New[string](WithClock(clock))
+	b.Watch("uploads", "a")
+
+	var exceeded []string
+	b.SetQuota("uploads", &QuotaConfig{
+		Rate:  2,
+		Burst: 2,
+		OnExceeded: func(signal string, policy QuotaPolicy) {
+			exceeded = append(exceeded, signal)
+		},
+	})
+
+	for range 2 {
+		if err := b.Broadcast("uploads", nil); err != nil {
+			t.Fatalf("expected the burst to pass, got %v", err)
+		}
+	}
+	if err := b.Broadcast("uploads", nil); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if len(exceeded) != 1 || exceeded[0] != "uploads" {
+		t.Errorf("expected the accounting hook to fire once, got %v", exceeded)
+	}
+	if err := b.Broadcast("other", nil); err != nil {
+		t.Errorf("expected other signals to be unaffected, got %v", err)
+	}
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	if err := b.Broadcast("uploads", nil); err != nil {
+		t.Errorf("expected the quota to refill, got %v", err)
+	}
+
+	b.SetQuota("uploads", nil)
+	for range 5 {
+		if err := b.Broadcast("uploads", nil); err != nil {
+			t.Fatalf("expected no quota after removal, got %v", err)
+		}
+	}
+}
+
+func TestQuota_QueuePolicyWaits(t *testing.T) {
+	b := NewUnique[string, string]()
+	b.Watch("jobs", owner{"w", "worker"})
+
+	queued := 0
+	b.SetQuota("jobs", &QuotaConfig{
+		Rate:    20,
+		Policy:  QuotaQueue,
+		MaxWait: 75 * time.Millisecond,
+		OnExceeded: func(signal string, policy QuotaPolicy) {
+			if policy == QuotaQueue {
+				queued++
+			}
+		},
+	})
+
+	start := time.Now()
+	for range 2 {
+		if err := b.Broadcast("jobs", nil); err != nil {
+			t.Fatalf("expected queued broadcasts to succeed, got %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected the second broadcast to wait for the quota, took %s", elapsed)
+	}
+	if queued != 1 {
+		t.Errorf("expected the hook to fire for the queued broadcast, got %d", queued)
+	}
+
+	// 排队的广播预留配额, 预留到超过 MaxWait 后拒绝
+	q := newQuota(QuotaConfig{Rate: 1, Policy: QuotaQueue, MaxWait: 1500 * time.Millisecond})
+	now := time.Unix(0, 0)
+	for i, want := range []time.Duration{0, time.Second} {
+		if wait, ok := q.reserve(now); !ok || wait != want {
+			t.Errorf("reservation %d: expected wait %s, got %s (ok=%v)", i, want, wait, ok)
+		}
+	}
+	if _, ok := q.reserve(now); ok {
+		t.Error("expected a reservation beyond MaxWait to be rejected")
+	}
+}
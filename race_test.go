@@ -249,3 +249,73 @@ func TestRaceBroadcast_ListenerModification(t *testing.T) {
 
 	wg.Wait()
 }
+
+// TestRaceBroadcast_HandleUnhandle tests handler registration overlapping with broadcasts
+func TestRaceBroadcast_HandleUnhandle(t *testing.T) {
+	b := &UniqueBroadcast[int, concurrentTestData]{}
+	b.Watch("test", &concurrentUniquer{data: concurrentTestData{ID: 1}})
+
+	var wg sync.WaitGroup
+	const numOperations = 1000
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numOperations; i++ {
+			id := b.Handle(func(signal string, data concurrentTestData, metadata map[string]interface{}) error {
+				return nil
+			})
+			b.Unhandle(id)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numOperations; i++ {
+			b.Broadcast("test", nil)
+		}
+	}()
+
+	wg.Wait()
+}
+
+// TestRaceBroadcast_CleanDuringWatch tests Clean/CleanAll racing with per-signal writers
+func TestRaceBroadcast_CleanDuringWatch(t *testing.T) {
+	b := &UniqueBroadcast[int, concurrentTestData]{}
+	var wg sync.WaitGroup
+	const numOperations = 1000
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numOperations; i++ {
+			b.Watch(fmt.Sprintf("signal-%d", i%4), &concurrentUniquer{data: concurrentTestData{ID: i}})
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numOperations; i++ {
+			b.Clean(fmt.Sprintf("signal-%d", i%4))
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numOperations/10; i++ {
+			b.CleanAll()
+			b.Range(func(signal string, count int) bool {
+				return true
+			})
+		}
+	}()
+
+	wg.Wait()
+
+	// 并发结束后注册表仍然可用
+	b.CleanAll()
+	b.Watch("signal-0", &concurrentUniquer{data: concurrentTestData{ID: 1}})
+	if count := b.WatchCount("signal-0"); count != 1 {
+		t.Errorf("expected 1 watcher after concurrent cleaning, got %d", count)
+	}
+}
@@ -0,0 +1,219 @@
+package broadcast
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter 限流器接口, key 为被限流的对象 (例如信号名)
+// 实现必须是并发安全的, 可以基于 Redis 等外部存储实现多实例间的公平限流
+type RateLimiter interface {
+	Allow(key string) bool
+}
+
+// RateLimiterFunc 允许将普通函数用作 RateLimiter
+type RateLimiterFunc func(key string) bool
+
+// Allow 实现 RateLimiter 接口
+func (f RateLimiterFunc) Allow(key string) bool {
+	return f(key)
+}
+
+// RateRefunder 是可以退还许可的 RateLimiter
+// PublishAll 的准备阶段被拒绝时, 对此前已放行的广播调用 Refund
+type RateRefunder interface {
+	Refund(key string)
+}
+
+// TokenBucket 令牌桶限流器, 每个 key 拥有独立的令牌桶
+// 空闲到令牌补满的桶与新建的桶相同, 在之后的 Allow 中被移除, 因此 key 很多时内存不会无限增长
+type TokenBucket struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+	clock   Clock
+	swept   time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket 创建令牌桶限流器, rate 为每秒补充的令牌数, burst 为桶容量
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return &TokenBucket{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		clock:   SystemClock,
+	}
+}
+
+// Allow 尝试从 key 对应的令牌桶中取出一个令牌
+func (tb *TokenBucket) Allow(key string) bool {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	now := tb.clock.Now()
+	tb.sweep(now)
+	b, ok := tb.buckets[key]
+	if !ok {
+		b = &bucket{tokens: tb.burst, last: now}
+		tb.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * tb.rate
+	if b.tokens > tb.burst {
+		b.tokens = tb.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Refund 向 key 的令牌桶退还一个令牌, 不超过桶容量
+func (tb *TokenBucket) Refund(key string) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	if b, ok := tb.buckets[key]; ok {
+		b.tokens = min(b.tokens+1, tb.burst)
+	}
+}
+
+// Len 返回当前保存的令牌桶数量
+func (tb *TokenBucket) Len() int {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	return len(tb.buckets)
+}
+
+// sweep 每经过一次补满的时间移除已补满的桶, 调用时持有 tb.mu
+func (tb *TokenBucket) sweep(now time.Time) {
+	if tb.rate <= 0 {
+		return
+	}
+	idle := time.Duration(tb.burst / tb.rate * float64(time.Second))
+	if now.Sub(tb.swept) < idle {
+		return
+	}
+	tb.swept = now
+	for key, b := range tb.buckets {
+		if now.Sub(b.last) >= idle {
+			delete(tb.buckets, key)
+		}
+	}
+}
+
+// SetClock 设置时间源, 主要用于测试
+func (tb *TokenBucket) SetClock(clock Clock) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.clock = clock
+}
+
+// SlidingWindow 滑动窗口限流器, 使用前后两个固定窗口加权估算窗口内的请求数
+// 两个窗口内没有请求的 key 在之后的 Allow 中被移除
+type SlidingWindow struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	windows map[string]*slidingCounter
+	clock   Clock
+	swept   time.Time
+}
+
+type slidingCounter struct {
+	start    time.Time
+	current  int
+	previous int
+}
+
+// NewSlidingWindow 创建滑动窗口限流器, 每个 key 在任意 window 时间内最多允许 limit 次
+func NewSlidingWindow(limit int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{
+		limit:   limit,
+		window:  window,
+		windows: make(map[string]*slidingCounter),
+		clock:   SystemClock,
+	}
+}
+
+// Allow 判断 key 在当前滑动窗口内是否还有余量
+func (sw *SlidingWindow) Allow(key string) bool {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	now := sw.clock.Now()
+	sw.sweep(now)
+	c, ok := sw.windows[key]
+	if !ok {
+		c = &slidingCounter{start: now.Truncate(sw.window)}
+		sw.windows[key] = c
+	}
+
+	if elapsed := now.Sub(c.start); elapsed >= sw.window {
+		if elapsed < 2*sw.window {
+			c.previous = c.current
+		} else {
+			c.previous = 0
+		}
+		c.current = 0
+		c.start = now.Truncate(sw.window)
+	}
+
+	weight := 1 - float64(now.Sub(c.start))/float64(sw.window)
+	if float64(c.previous)*weight+float64(c.current) >= float64(sw.limit) {
+		return false
+	}
+	c.current++
+	return true
+}
+
+// Refund 撤销 key 在当前窗口中的一次计数
+func (sw *SlidingWindow) Refund(key string) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if c, ok := sw.windows[key]; ok && c.current > 0 {
+		c.current--
+	}
+}
+
+// Len 返回当前保存的窗口数量
+func (sw *SlidingWindow) Len() int {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	return len(sw.windows)
+}
+
+// sweep 每经过两个窗口移除两个窗口内没有请求的计数器, 调用时持有 sw.mu
+func (sw *SlidingWindow) sweep(now time.Time) {
+	idle := 2 * sw.window
+	if idle <= 0 || now.Sub(sw.swept) < idle {
+		return
+	}
+	sw.swept = now
+	for key, c := range sw.windows {
+		if now.Sub(c.start) >= idle {
+			delete(sw.windows, key)
+		}
+	}
+}
+
+// SetClock 设置时间源, 主要用于测试
+func (sw *SlidingWindow) SetClock(clock Clock) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	sw.clock = clock
+}
@@ -0,0 +1,148 @@
+package broadcast_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+	"pkg.blksails.net/x/broadcast/broadcasttest"
+)
+
+func TestTokenBucket_Allow(t *testing.T) {
+	clock := broadcasttest.NewFakeClock(time.Unix(0, 0))
+	tb := broadcast.NewTokenBucket(10, 2)
+	tb.SetClock(clock)
+
+	if !tb.Allow("a") || !tb.Allow("a") {
+		t.Fatal("burst of 2 should be allowed")
+	}
+	if tb.Allow("a") {
+		t.Error("third call should be limited")
+	}
+	if !tb.Allow("b") {
+		t.Error("keys should have independent buckets")
+	}
+
+	clock.Advance(100 * time.Millisecond)
+	if !tb.Allow("a") {
+		t.Error("one token should be refilled after 100ms")
+	}
+	if tb.Allow("a") {
+		t.Error("only one token should be refilled")
+	}
+}
+
+func TestSlidingWindow_Allow(t *testing.T) {
+	clock := broadcasttest.NewFakeClock(time.Unix(0, 0))
+	sw := broadcast.NewSlidingWindow(2, time.Second)
+	sw.SetClock(clock)
+
+	if !sw.Allow("a") || !sw.Allow("a") {
+		t.Fatal("two calls should be allowed")
+	}
+	if sw.Allow("a") {
+		t.Error("third call in the same window should be limited")
+	}
+
+	// 下一个窗口的前半段仍受上一个窗口影响
+	clock.Advance(1500 * time.Millisecond)
+	if !sw.Allow("a") {
+		t.Error("one call should be allowed half way into the next window")
+	}
+	if sw.Allow("a") {
+		t.Error("weighted previous window should still limit")
+	}
+
+	clock.Advance(3 * time.Second)
+	if !sw.Allow("a") || !sw.Allow("a") {
+		t.Error("limit should reset after idle windows")
+	}
+}
+
+func TestRateLimiter_Refund(t *testing.T) {
+	clock := broadcasttest.NewFakeClock(time.Unix(0, 0))
+	tb := broadcast.NewTokenBucket(10, 1)
+	tb.SetClock(clock)
+	sw := broadcast.NewSlidingWindow(1, time.Second)
+	sw.SetClock(clock)
+
+	for _, l := range []interface {
+		broadcast.RateLimiter
+		broadcast.RateRefunder
+	}{tb, sw} {
+		if !l.Allow("a") || l.Allow("a") {
+			t.Fatalf("%T: expected a single call to be allowed", l)
+		}
+		l.Refund("a")
+		if !l.Allow("a") {
+			t.Errorf("%T: expected a refunded call to be allowed again", l)
+		}
+		// 退还不会超过容量
+		l.Refund("a")
+		l.Refund("a")
+		if !l.Allow("a") || l.Allow("a") {
+			t.Errorf("%T: expected refunds to be capped", l)
+		}
+	}
+}
+
+func TestRateLimiter_EvictsIdleKeys(t *testing.T) {
+	clock := broadcasttest.NewFakeClock(time.Unix(0, 0))
+	tb := broadcast.NewTokenBucket(10, 2)
+	tb.SetClock(clock)
+	sw := broadcast.NewSlidingWindow(2, time.Second)
+	sw.SetClock(clock)
+
+	for i := range 100 {
+		key := fmt.Sprint("conn-", i)
+		tb.Allow(key)
+		sw.Allow(key)
+	}
+	if tb.Len() != 100 || sw.Len() != 100 {
+		t.Fatalf("expected every key to be tracked, got %d buckets and %d windows", tb.Len(), sw.Len())
+	}
+
+	// 令牌桶 200ms 补满, 之后的 Allow 移除空闲的桶; 仍在限流中的 key 保留
+	clock.Advance(200 * time.Millisecond)
+	tb.Allow("hot")
+	tb.Allow("hot")
+	if tb.Len() != 1 {
+		t.Errorf("expected idle buckets to be evicted, got %d", tb.Len())
+	}
+	if tb.Allow("hot") {
+		t.Error("expected a drained bucket to be kept and limited")
+	}
+
+	clock.Advance(2 * time.Second)
+	sw.Allow("hot")
+	if sw.Len() != 1 {
+		t.Errorf("expected idle windows to be evicted, got %d", sw.Len())
+	}
+}
+
+func TestBroadcast_SetRateLimiter(t *testing.T) {
+	b := broadcast.New[string]()
+	calls := 0
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		calls++
+		return nil
+	})
+	b.Watch("test", "data")
+
+	allowed := map[string]bool{"test": false}
+	b.SetRateLimiter(broadcast.RateLimiterFunc(func(key string) bool {
+		return allowed[key]
+	}))
+
+	b.Broadcast("test", nil)
+	if calls != 0 {
+		t.Errorf("limited broadcast should not call handlers, got %d calls", calls)
+	}
+
+	b.SetRateLimiter(nil)
+	b.Broadcast("test", nil)
+	if calls != 1 {
+		t.Errorf("expected 1 call after removing limiter, got %d", calls)
+	}
+}
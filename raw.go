@@ -0,0 +1,64 @@
+package broadcast
+
+import (
+	"sync"
+)
+
+// RawPayload 是保持编码状态的广播负载
+// 只有处理器调用 Decode 时才会解码, 只转发字节的处理器可以直接读取 Bytes
+type RawPayload struct {
+	data  []byte
+	codec Codec
+
+	mu      sync.Mutex
+	decoded any
+	err     error
+}
+
+// NewRawPayload 创建一个使用 codec 解码的负载, codec 为 nil 时使用 JSONCodec
+func NewRawPayload(data []byte, codec Codec) *RawPayload {
+	if codec == nil {
+		codec = JSONCodec
+	}
+	return &RawPayload{data: data, codec: codec}
+}
+
+// Bytes 返回编码后的数据, 调用方不应修改
+func (p *RawPayload) Bytes() []byte {
+	return p.data
+}
+
+// Codec 返回负载使用的 Codec
+func (p *RawPayload) Codec() Codec {
+	return p.codec
+}
+
+// Decode 将负载解码为 P
+// 同一负载以相同类型多次解码时只解码一次, 所有处理器共享结果, 不应原地修改
+func Decode[P any](p *RawPayload) (P, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if v, ok := p.decoded.(P); ok {
+		return v, p.err
+	}
+
+	var v P
+	err := p.codec.Unmarshal(p.data, &v)
+	p.decoded, p.err = v, err
+	return v, err
+}
+
+// RawHandler 是接收编码负载的处理器
+type RawHandler[T any] func(signal string, data T, payload *RawPayload, metadata map[string]interface{}) error
+
+// BroadcastRaw 广播一个信号, 并将编码后的 data 传递给通过 HandleRaw 注册的处理器
+func BroadcastRaw(b DataPublisher, signal string, data []byte, codec Codec, metadata map[string]interface{}) error {
+	return BroadcastData(b, signal, NewRawPayload(data, codec), metadata)
+}
+
+// HandleRaw 注册一个接收编码负载的处理器
+// 由普通 Broadcast 触发时 payload 为 nil
+func HandleRaw[T any](b DataSubscriber[T], handler RawHandler[T]) HandlerID {
+	return HandleData(b, DataHandler[T, *RawPayload](handler))
+}
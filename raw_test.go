@@ -0,0 +1,74 @@
+package broadcast
+
+import (
+	"testing"
+)
+
+type countingCodec struct {
+	unmarshals int
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	return JSONCodec.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshals++
+	return JSONCodec.Unmarshal(data, v)
+}
+
+type tick struct {
+	Symbol string  `json:"symbol"`
+	Price  float64 `json:"price"`
+}
+
+func TestRawPayload_LazyDecode(t *testing.T) {
+	b := New[string]()
+	b.Watch("tick", "consumer")
+
+	var forwarded []byte
+	HandleRaw(b, func(signal string, data string, payload *RawPayload, metadata map[string]interface{}) error {
+		forwarded = payload.Bytes()
+		return nil
+	})
+
+	codec := &countingCodec{}
+	BroadcastRaw(b, "tick", []byte(`{"symbol":"ACME","price":12.5}`), codec, nil)
+	if codec.unmarshals != 0 {
+		t.Errorf("expected no decode for forwarding handler, got %d", codec.unmarshals)
+	}
+	if string(forwarded) != `{"symbol":"ACME","price":12.5}` {
+		t.Errorf("unexpected forwarded bytes %q", forwarded)
+	}
+
+	var decoded []tick
+	for i := 0; i < 2; i++ {
+		HandleRaw(b, func(signal string, data string, payload *RawPayload, metadata map[string]interface{}) error {
+			v, err := Decode[tick](payload)
+			decoded = append(decoded, v)
+			return err
+		})
+	}
+	if err := BroadcastRaw(b, "tick", []byte(`{"symbol":"ACME","price":12.5}`), codec, nil); err != nil {
+		t.Fatal(err)
+	}
+	if codec.unmarshals != 1 {
+		t.Errorf("expected a single shared decode, got %d", codec.unmarshals)
+	}
+	if len(decoded) != 2 || decoded[1] != (tick{Symbol: "ACME", Price: 12.5}) {
+		t.Errorf("unexpected decoded values %v", decoded)
+	}
+}
+
+func TestRawPayload_DecodeError(t *testing.T) {
+	codec := &countingCodec{}
+	p := NewRawPayload([]byte("not json"), codec)
+	for i := 0; i < 2; i++ {
+		if _, err := Decode[tick](p); err == nil {
+			t.Error("expected decode error")
+		}
+	}
+	if codec.unmarshals != 1 {
+		t.Errorf("expected the decode error to be cached, got %d decodes", codec.unmarshals)
+	}
+}
@@ -0,0 +1,99 @@
+package broadcast
+
+import (
+	"sync"
+	"time"
+)
+
+// Receipt 是一次投递的回执: 哪个处理器在何时将哪个事件投递给了哪个监听器
+type Receipt[K comparable] struct {
+	// Seq 事件序号, 同一次广播产生的回执序号相同
+	Seq uint64
+	// Signal 信号名
+	Signal string
+	// Key 监听器的唯一标识
+	Key K
+	// HandlerID 执行投递的处理器
+	HandlerID HandlerID
+	// Time 投递完成的时间
+	Time time.Time
+	// Err 处理器返回的错误, nil 表示投递成功
+	Err error
+}
+
+// ReceiptQuery 回执查询条件, 零值字段表示不过滤
+type ReceiptQuery[K comparable] struct {
+	Signal string
+	Key    *K
+	Since  time.Time
+	Until  time.Time
+	// Limit 最多返回的回执数量, 0 表示不限制
+	Limit int
+}
+
+// Match 判断回执是否满足查询条件
+func (q ReceiptQuery[K]) Match(r Receipt[K]) bool {
+	if q.Signal != "" && r.Signal != q.Signal {
+		return false
+	}
+	if q.Key != nil && r.Key != *q.Key {
+		return false
+	}
+	if !q.Since.IsZero() && r.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !r.Time.Before(q.Until) {
+		return false
+	}
+	return true
+}
+
+// ReceiptStore 持久化投递回执, 用于审计时证明某个通知已送达指定订阅者
+type ReceiptStore[K comparable] interface {
+	Record(r Receipt[K]) error
+	Query(q ReceiptQuery[K]) ([]Receipt[K], error)
+}
+
+// MemoryReceiptStore 是基于内存的 ReceiptStore
+type MemoryReceiptStore[K comparable] struct {
+	mu       sync.RWMutex
+	capacity int
+	receipts []Receipt[K]
+}
+
+// NewMemoryReceiptStore 创建内存回执存储, capacity 大于 0 时只保留最新的 capacity 条
+func NewMemoryReceiptStore[K comparable](capacity int) *MemoryReceiptStore[K] {
+	return &MemoryReceiptStore[K]{capacity: capacity}
+}
+
+// Record 记录一条回执
+func (s *MemoryReceiptStore[K]) Record(r Receipt[K]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.capacity > 0 && len(s.receipts) >= s.capacity {
+		// 丢弃最早的回执
+		copy(s.receipts, s.receipts[1:])
+		s.receipts = s.receipts[:len(s.receipts)-1]
+	}
+	s.receipts = append(s.receipts, r)
+	return nil
+}
+
+// Query 按记录顺序返回满足条件的回执
+func (s *MemoryReceiptStore[K]) Query(q ReceiptQuery[K]) ([]Receipt[K], error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []Receipt[K]
+	for _, r := range s.receipts {
+		if !q.Match(r) {
+			continue
+		}
+		result = append(result, r)
+		if q.Limit > 0 && len(result) >= q.Limit {
+			break
+		}
+	}
+	return result, nil
+}
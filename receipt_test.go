@@ -0,0 +1,98 @@
+package broadcast
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReceipts_RecordAndQuery(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	store := NewMemoryReceiptStore[int](0)
+	b.SetReceiptStore(store)
+
+	failing := errors.New("mailbox full")
+	okID := b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		return nil
+	})
+	failID := b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		if data.ID == 2 {
+			return failing
+		}
+		return nil
+	})
+
+	b.Watch("mail", &TestUniquer{data: TestUniqueData{ID: 1}})
+	b.Watch("mail", &TestUniquer{data: TestUniqueData{ID: 2}})
+	b.Watch("sms", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	before := time.Now()
+	b.Broadcast("mail", nil)
+	b.Broadcast("sms", nil)
+
+	all, _ := store.Query(ReceiptQuery[int]{})
+	if len(all) != 6 {
+		t.Fatalf("expected 6 receipts, got %d", len(all))
+	}
+	if all[0].Seq == all[4].Seq {
+		t.Error("receipts of different broadcasts should have different sequence numbers")
+	}
+
+	key := 2
+	got, _ := store.Query(ReceiptQuery[int]{Signal: "mail", Key: &key})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 receipts for key 2, got %d", len(got))
+	}
+	for _, r := range got {
+		if r.HandlerID == okID && r.Err != nil {
+			t.Errorf("handler %d should have succeeded", okID)
+		}
+		if r.HandlerID == failID && !errors.Is(r.Err, failing) {
+			t.Errorf("handler %d should have recorded the failure", failID)
+		}
+		if r.Time.Before(before) {
+			t.Error("receipt time should be set at delivery")
+		}
+	}
+
+	limited, _ := store.Query(ReceiptQuery[int]{Limit: 1, Until: time.Now().Add(time.Second)})
+	if len(limited) != 1 {
+		t.Errorf("expected Limit to cap results, got %d", len(limited))
+	}
+
+	b.SetReceiptStore(nil)
+	b.Broadcast("mail", nil)
+	if all, _ := store.Query(ReceiptQuery[int]{}); len(all) != 6 {
+		t.Errorf("no receipts should be recorded after removing the store, got %d", len(all))
+	}
+}
+
+func TestMemoryReceiptStore_Capacity(t *testing.T) {
+	store := NewMemoryReceiptStore[string](2)
+	for i := uint64(1); i <= 3; i++ {
+		store.Record(Receipt[string]{Seq: i})
+	}
+
+	got, _ := store.Query(ReceiptQuery[string]{})
+	if len(got) != 2 || got[0].Seq != 2 || got[1].Seq != 3 {
+		t.Errorf("expected the latest 2 receipts, got %+v", got)
+	}
+}
+
+func TestReceipts_UseClock(t *testing.T) {
+	b := New[string]()
+	store := NewMemoryReceiptStore[string](0)
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.SetClock(&manualClock{now: at})
+	b.SetReceiptStore(store)
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		return nil
+	})
+	b.Watch("test", "data")
+	b.Broadcast("test", nil)
+
+	got, _ := store.Query(ReceiptQuery[string]{})
+	if len(got) != 1 || !got[0].Time.Equal(at) {
+		t.Errorf("expected receipt time from injected clock, got %+v", got)
+	}
+}
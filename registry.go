@@ -0,0 +1,125 @@
+package broadcast
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// SignalSpec 是注册表中声明的信号
+type SignalSpec struct {
+	Signal      string
+	Description string
+	// Payload 为 BroadcastData 期望的负载类型, nil 表示不检查
+	Payload reflect.Type
+}
+
+// Registry 是预先声明的信号目录, 可被多个广播器共享
+type Registry struct {
+	mu    sync.RWMutex
+	specs map[string]SignalSpec
+}
+
+// NewRegistry 创建空的信号注册表
+func NewRegistry() *Registry {
+	return &Registry{specs: make(map[string]SignalSpec)}
+}
+
+// Declare 声明一个信号, payload 为期望负载类型的示例值, 传入 nil 表示不检查负载类型
+// 重复声明会覆盖原有声明
+func (r *Registry) Declare(signal, description string, payload any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.specs[signal] = SignalSpec{Signal: signal, Description: description, Payload: reflect.TypeOf(payload)}
+}
+
+// Lookup 返回信号的声明
+func (r *Registry) Lookup(signal string) (SignalSpec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	spec, ok := r.specs[signal]
+	return spec, ok
+}
+
+// Specs 返回按信号名排序的所有声明
+func (r *Registry) Specs() []SignalSpec {
+	r.mu.RLock()
+	specs := make([]SignalSpec, 0, len(r.specs))
+	for _, spec := range r.specs {
+		specs = append(specs, spec)
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(specs, func(a, b SignalSpec) int {
+		return strings.Compare(a.Signal, b.Signal)
+	})
+	return specs
+}
+
+// check 检查信号是否已声明以及负载类型是否匹配, payload 为 nil 或 *RawPayload 时不检查类型
+func (r *Registry) check(signal string, payload any) error {
+	spec, ok := r.Lookup(signal)
+	if !ok {
+		return undeclared(signal)
+	}
+	if spec.Payload == nil || payload == nil {
+		return nil
+	}
+	if _, raw := payload.(*RawPayload); raw {
+		return nil
+	}
+	if t := reflect.TypeOf(payload); t != spec.Payload {
+		return fmt.Errorf("%w: %s expects %s, got %s", ErrPayloadType, signal, spec.Payload, t)
+	}
+	return nil
+}
+
+func undeclared(signal string) error {
+	return fmt.Errorf("%w: %s", ErrUndeclaredSignal, signal)
+}
+
+// RegistryConfig 信号注册表配置
+type RegistryConfig struct {
+	Registry *Registry
+	// Strict 为 true 时拒绝未声明信号上的广播与监听, 以及负载类型不匹配的广播
+	Strict bool
+	// OnViolation 在每次违反声明时调用, 非严格模式下可用于记录拼写错误的信号名
+	OnViolation func(signal string, err error)
+}
+
+// violation 检查一次广播或监听, 返回严格模式下需要拒绝的错误
+func (r *RegistryConfig) violation(signal string, payload any) error {
+	err := r.Registry.check(signal, payload)
+	if err == nil {
+		return nil
+	}
+	if r.OnViolation != nil {
+		r.OnViolation(signal, err)
+	}
+	if r.Strict {
+		return err
+	}
+	return nil
+}
+
+// setRegistry 设置信号注册表, 传入 nil 关闭检查
+func (c *core[K, T]) setRegistry(config *RegistryConfig) {
+	c.updateSettings(func(s *settings[K, T]) {
+		if config == nil {
+			s.registry = nil
+			return
+		}
+		r := *config
+		s.registry = &r
+	})
+}
+
+// allowWatch 报告是否允许在信号上添加监听器
+func (c *core[K, T]) allowWatch(signal string) bool {
+	r := c.loadSettings().registry
+	return r == nil || r.violation(signal, nil) == nil
+}
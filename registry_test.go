@@ -0,0 +1,84 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRegistry_Strict(t *testing.T) {
+	r := NewRegistry()
+	r.Declare("user.login", "用户登录", "")
+	r.Declare("user.logout", "用户登出", nil)
+
+	b := New[string]()
+	var violations []string
+	b.SetRegistry(&RegistryConfig{Registry: r, Strict: true, OnViolation: func(signal string, err error) {
+		violations = append(violations, signal)
+	}})
+
+	calls := 0
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		calls++
+		return nil
+	})
+	b.Watch("user.login", "a")
+	b.Watch("user.lgoin", "a")
+	if b.HasWatch("user.lgoin") {
+		t.Error("expected Watch on an undeclared signal to be rejected")
+	}
+
+	if err := b.Broadcast("user.lgoin", nil); !errors.Is(err, ErrUndeclaredSignal) {
+		t.Errorf("expected ErrUndeclaredSignal, got %v", err)
+	}
+	if err := BroadcastData(b, "user.login", 42, nil); !errors.Is(err, ErrPayloadType) {
+		t.Errorf("expected ErrPayloadType, got %v", err)
+	}
+	if err := PublishAll(context.Background(), []PublishEntry{{B: b, Signal: "user.login", Data: 42}}); !errors.Is(err, ErrPayloadType) {
+		t.Errorf("expected PublishAll to check payload types, got %v", err)
+	}
+	if err := BroadcastData(b, "user.login", "alice", nil); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("expected only the valid broadcast to be delivered, got %d", calls)
+	}
+	if !slices.Equal(violations, []string{"user.lgoin", "user.lgoin", "user.login", "user.login"}) {
+		t.Errorf("unexpected violations %v", violations)
+	}
+}
+
+func TestRegistry_NonStrictReports(t *testing.T) {
+	r := NewRegistry()
+	b := NewUnique[int, TestUniqueData]()
+	var violations []error
+	b.SetRegistry(&RegistryConfig{Registry: r, OnViolation: func(signal string, err error) {
+		violations = append(violations, err)
+	}})
+
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+	if err := b.Broadcast("test", nil); err != nil {
+		t.Errorf("expected non-strict mode to deliver, got %v", err)
+	}
+	if !b.HasWatch("test") || len(violations) != 2 {
+		t.Errorf("expected the watch to be added and 2 violations, got %v", violations)
+	}
+
+	b.SetRegistry(nil)
+	b.Broadcast("test", nil)
+	if len(violations) != 2 {
+		t.Error("expected no checks after the registry is removed")
+	}
+}
+
+func TestRegistry_Specs(t *testing.T) {
+	r := NewRegistry()
+	r.Declare("b", "second", 0)
+	r.Declare("a", "first", nil)
+
+	specs := r.Specs()
+	if len(specs) != 2 || specs[0].Signal != "a" || specs[1].Payload.Kind().String() != "int" {
+		t.Errorf("unexpected specs %+v", specs)
+	}
+}
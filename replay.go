@@ -0,0 +1,70 @@
+package broadcast
+
+import (
+	"sync"
+)
+
+// ReplayGate 控制通过 HandleAfterReplay 注册的处理器何时开始接收实时事件
+// 在 Done 之前到达的实时事件会被缓存, Done 时按到达顺序投递, 之后直接投递,
+// 从而保证历史事件 (回放/回填) 与实时事件不会交错
+type ReplayGate struct {
+	id   HandlerID
+	gate interface{ open() }
+}
+
+// HandlerID 返回被控制的处理器 ID
+func (g *ReplayGate) HandlerID() HandlerID {
+	return g.id
+}
+
+// Done 标记回放已完成, 投递缓存的实时事件并放行后续事件
+func (g *ReplayGate) Done() {
+	g.gate.open()
+}
+
+type bufferedEvent[T any] struct {
+	signal   string
+	data     T
+	metadata map[string]interface{}
+}
+
+// replayGate 包装处理器, 在打开前缓存所有事件
+type replayGate[T any] struct {
+	mu      sync.Mutex
+	opened  bool
+	buffer  []bufferedEvent[T]
+	handler handlerFunc[T]
+}
+
+func (g *replayGate[T]) handle(signal string, data T, metadata map[string]interface{}) error {
+	g.mu.Lock()
+	if !g.opened {
+		g.buffer = append(g.buffer, bufferedEvent[T]{signal: signal, data: data, metadata: metadata})
+		g.mu.Unlock()
+		return nil
+	}
+	g.mu.Unlock()
+
+	return g.handler(signal, data, metadata)
+}
+
+func (g *replayGate[T]) open() {
+	g.mu.Lock()
+	for len(g.buffer) > 0 && !g.opened {
+		buffer := g.buffer
+		g.buffer = nil
+		g.mu.Unlock()
+
+		for _, e := range buffer {
+			_ = g.handler(e.signal, e.data, e.metadata)
+		}
+		g.mu.Lock()
+	}
+	g.opened = true
+	g.mu.Unlock()
+}
+
+func (c *core[K, T]) handleAfterReplay(prefix string, handler handlerFunc[T]) *ReplayGate {
+	g := &replayGate[T]{handler: handler}
+	return &ReplayGate{id: c.handle(prefix, g.handle), gate: g}
+}
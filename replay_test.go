@@ -0,0 +1,36 @@
+package broadcast
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHandleAfterReplay(t *testing.T) {
+	b := New[string]()
+	b.Watch("price", "AAPL")
+
+	var seen []int
+	handler := func(signal string, data string, metadata map[string]interface{}) error {
+		seen = append(seen, metadata["v"].(int))
+		return nil
+	}
+	gate := b.HandleAfterReplay(handler)
+
+	// 回放进行中, 实时事件到达
+	handler("price", "AAPL", map[string]interface{}{"v": 1})
+	b.Broadcast("price", map[string]interface{}{"v": 3})
+	handler("price", "AAPL", map[string]interface{}{"v": 2})
+	b.Broadcast("price", map[string]interface{}{"v": 4})
+
+	gate.Done()
+	b.Broadcast("price", map[string]interface{}{"v": 5})
+
+	if want := []int{1, 2, 3, 4, 5}; !slices.Equal(seen, want) {
+		t.Errorf("expected history before live events %v, got %v", want, seen)
+	}
+
+	gate.Done() // 重复调用无副作用
+	if !b.Unhandle(gate.HandlerID()) {
+		t.Error("gate handler should be removable by ID")
+	}
+}
@@ -0,0 +1,115 @@
+package broadcast
+
+import (
+	"sync"
+	"time"
+)
+
+// SamplingConfig 过载时的自适应采样配置
+// 异步队列积压或广播延迟超过阈值时, 逐步降低低优先级信号的采样率, 其他信号始终全量投递;
+// 负载恢复后采样率逐步回升到 1. 被采样丢弃的广播返回 ErrSampled
+type SamplingConfig struct {
+	// Signals 可以被采样的低优先级信号
+	Signals []string
+	// QueueThreshold 异步队列积压超过该值视为过载, 0 表示不检查队列
+	QueueThreshold int
+	// LatencyThreshold 广播投递延迟的滑动平均超过该值视为过载, 0 表示不检查延迟
+	LatencyThreshold time.Duration
+	// MinRate 最低采样率, 默认为 0.1
+	MinRate float64
+	// Interval 调整采样率的最小间隔, 默认为 100ms
+	Interval time.Duration
+}
+
+// sampler 维护当前采样率, 每次过载时采样率减半, 恢复时加倍
+type sampler struct {
+	config SamplingConfig
+	low    map[string]struct{}
+
+	mu       sync.Mutex
+	rate     float64
+	latency  time.Duration
+	adjusted time.Time
+	count    uint64
+}
+
+func newSampler(config SamplingConfig) *sampler {
+	if config.MinRate <= 0 || config.MinRate > 1 {
+		config.MinRate = 0.1
+	}
+	if config.Interval <= 0 {
+		config.Interval = 100 * time.Millisecond
+	}
+
+	low := make(map[string]struct{}, len(config.Signals))
+	for _, signal := range config.Signals {
+		low[signal] = struct{}{}
+	}
+	return &sampler{config: config, low: low, rate: 1}
+}
+
+// allow 判断本次广播是否被采样, pending 为异步队列积压数量
+// 采样按计数均匀分布, 结果是确定的: 采样率为 r 时每 1/r 次广播放行一次
+func (s *sampler) allow(signal string, now time.Time, pending int) bool {
+	if _, ok := s.low[signal]; !ok {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.adjust(now, pending)
+	if s.rate >= 1 {
+		return true
+	}
+	s.count++
+	return uint64(float64(s.count)*s.rate) != uint64(float64(s.count-1)*s.rate)
+}
+
+func (s *sampler) adjust(now time.Time, pending int) {
+	if !s.adjusted.IsZero() && now.Sub(s.adjusted) < s.config.Interval {
+		return
+	}
+	s.adjusted = now
+
+	overloaded := (s.config.QueueThreshold > 0 && pending > s.config.QueueThreshold) ||
+		(s.config.LatencyThreshold > 0 && s.latency > s.config.LatencyThreshold)
+	if overloaded {
+		s.rate = max(s.config.MinRate, s.rate/2)
+	} else {
+		s.rate = min(1, s.rate*2)
+	}
+}
+
+// observe 记录一次投递的耗时, 使用 1/8 权重的指数滑动平均
+func (s *sampler) observe(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency += (latency - s.latency) / 8
+}
+
+// effectiveRate 返回当前采样率
+func (s *sampler) effectiveRate() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rate
+}
+
+func (c *core[K, T]) setSampling(config *SamplingConfig) {
+	c.updateSettings(func(s *settings[K, T]) {
+		if config != nil {
+			s.sampler = newSampler(*config)
+		} else {
+			s.sampler = nil
+		}
+	})
+}
+
+func (c *core[K, T]) samplingRate() float64 {
+	if s := c.loadSettings().sampler; s != nil {
+		return s.effectiveRate()
+	}
+	return 1
+}
@@ -0,0 +1,72 @@
+package broadcast
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSampling_DegradesLowPrioritySignals(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b := New[string]()
+	b.SetClock(clock)
+	b.SetSampling(&SamplingConfig{
+		Signals:          []string{"metrics"},
+		LatencyThreshold: 10 * time.Millisecond,
+		MinRate:          0.25,
+	})
+
+	work := 100 * time.Millisecond
+	delivered := map[string]int{}
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		delivered[signal]++
+		clock.now = clock.now.Add(work)
+		return nil
+	})
+	b.Watch("metrics", "collector")
+	b.Watch("orders", "collector")
+
+	sampled := 0
+	for i := 0; i < 40; i++ {
+		if err := b.Broadcast("metrics", nil); errors.Is(err, ErrSampled) {
+			sampled++
+			clock.now = clock.now.Add(work)
+		}
+		if err := b.Broadcast("orders", nil); err != nil {
+			t.Fatalf("high priority signal must not be sampled: %v", err)
+		}
+	}
+
+	if rate := b.SamplingRate(); rate != 0.25 {
+		t.Errorf("expected rate to settle at MinRate 0.25, got %v", rate)
+	}
+	if sampled == 0 || delivered["metrics"]+sampled != 40 {
+		t.Errorf("expected some metrics to be sampled, delivered=%d sampled=%d", delivered["metrics"], sampled)
+	}
+	if delivered["orders"] != 40 {
+		t.Errorf("expected every order delivered, got %d", delivered["orders"])
+	}
+
+	// 负载恢复后采样率逐步回升
+	work = 0
+	for i := 0; i < 40; i++ {
+		clock.now = clock.now.Add(100 * time.Millisecond)
+		b.Broadcast("orders", nil)
+		b.Broadcast("metrics", nil)
+	}
+	if rate := b.SamplingRate(); rate != 1 {
+		t.Errorf("expected rate to recover to 1, got %v", rate)
+	}
+}
+
+func TestSampling_Disabled(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	if b.SamplingRate() != 1 {
+		t.Errorf("expected rate 1 without sampling, got %v", b.SamplingRate())
+	}
+	b.SetSampling(&SamplingConfig{Signals: []string{"test"}})
+	b.SetSampling(nil)
+	if err := b.Broadcast("test", nil); err != nil {
+		t.Errorf("expected no sampling after disabling, got %v", err)
+	}
+}
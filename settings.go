@@ -0,0 +1,95 @@
+package broadcast
+
+import (
+	"time"
+)
+
+// settings 保存广播器的可选配置
+// 配置是不可变的, 修改时复制并原子替换, 使 Broadcast 读取配置时无需加锁
+type settings[K comparable, T any] struct {
+	limiter RateLimiter
+	breaker *breaker
+	sampler *sampler
+	clock   Clock
+	async   *dispatcher[K, T]
+	// receipts 非 nil 时为每次投递记录回执
+	receipts ReceiptStore[K]
+	// dlq 非 nil 时失败的投递进入死信队列
+	dlq *deadLetterQueue[K, T]
+	// deterministic 非 nil 时使用确定性投递
+	deterministic *deterministicMode
+	// transforms 在处理器之前依次改写监听器的值
+	transforms []Transform[T]
+	// store 非 nil 时监听器变化写穿到 Store
+	store *storeBinding[K, T]
+	// journal 非 nil 时选定信号的广播在投递前写入日志
+	journal *journalBinding
+	// buffer 非 nil 时暂存没有监听器或处理器的广播
+	buffer *pendingBuffer[K, T]
+	// priorities 保存非默认优先级的信号
+	priorities map[string]Priority
+	// onExpired 在投递因超过 TTL 被丢弃时调用
+	onExpired func(signal string, metadata map[string]interface{})
+	// onError 在处理器返回错误时调用
+	onError func(signal string, data T, handlerID HandlerID, err error)
+	// registry 非 nil 时检查信号是否已声明
+	registry *RegistryConfig
+	// slow 非 nil 时对超过阈值的处理器调用告警
+	slow *slowHandler
+	// ttl 为没有设置存活时间的广播提供默认值
+	ttl time.Duration
+	// extractors 由 BroadcastCtx 依次执行, 将 ctx 中的值写入 metadata
+	extractors []ContextExtractor
+	// undoLog 为每个信号保留的历史版本数量, 0 表示默认值, 负数表示不保留
+	undoLog int
+	// parallel 为无序投递时的并发上限
+	parallel int
+	// order 为默认的监听器投递顺序, orders 保存单独设置了顺序的信号
+	order  DeliveryOrder
+	orders map[string]DeliveryOrder
+	// health 非 nil 时根据处理器结果跟踪信号的健康状态
+	health *healthTracker
+	// quarantine 非 nil 时隔离反复失败的监听器
+	quarantine *quarantine[K]
+	// onLeaseExpired 在监听器因租约到期被移除时调用
+	onLeaseExpired func(signal string, data T)
+	// onGroupLeaseExpired 在分组因租约到期被释放时调用
+	onGroupLeaseExpired func(group string, removed int)
+	// autoUnwatch 非 nil 时自动移除持续失败的监听器
+	autoUnwatch *autoUnwatch[K]
+	// history 非 nil 时按信号保留最近的广播
+	history *history[K, T]
+	// quotas 保存设置了吞吐配额的信号
+	quotas map[string]*quota
+	// paused 保存被 Pause 暂停广播的信号
+	paused map[string]struct{}
+	// taps 保存 Tap 注册的单个信号的观察者, tapAll 保存观察所有信号的观察者
+	taps   map[string][]*tap
+	tapAll []*tap
+	// authorizer 非 nil 时在 Watch、Unwatch 与 Broadcast 之前检查权限
+	authorizer Authorizer
+	// validator 非 nil 时校验 Watch 的数据, payloadValidator 非 nil 时校验广播时负载
+	validator        func(signal string, data T) error
+	payloadValidator func(signal string, payload any) error
+	// upgrader 非 nil 时迁移回放的旧版本日志负载
+	upgrader Upgrader
+	// metrics 非 nil 时接收广播与投递的计量事件
+	metrics Metrics
+}
+
+func (c *core[K, T]) loadSettings() *settings[K, T] {
+	if s := c.settings.Load(); s != nil {
+		return s
+	}
+	return &settings[K, T]{}
+}
+
+// updateSettings 复制当前配置, 应用 fn 后替换
+func (c *core[K, T]) updateSettings(fn func(s *settings[K, T])) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+
+	s := *c.loadSettings()
+	fn(&s)
+	c.settings.Store(&s)
+}
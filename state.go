@@ -0,0 +1,41 @@
+package broadcast
+
+import (
+	"io"
+)
+
+// StateHandler 是携带处理器私有状态的处理器
+type StateHandler[T any, S any] func(state S, signal string, data T, metadata map[string]interface{}) error
+
+// StateSubscriber 是可以注册 StateHandler 的广播器, 由 Broadcast 与 UniqueBroadcast 实现
+type StateSubscriber[T any] interface {
+	handleState(handler handlerFunc[T], close func()) HandlerID
+}
+
+// HandleState 注册一个带私有状态的处理器
+// 注册时调用 factory 创建状态, 之后每次调用都传入该状态; Unhandle 时如果状态实现了 io.Closer 则调用 Close.
+// 异步投递的 Workers 大于 1 时处理器可能被并发调用, 状态需要自行同步
+func HandleState[T any, S any](b StateSubscriber[T], factory func() S, handler StateHandler[T, S]) HandlerID {
+	state := factory()
+	var close func()
+	if closer, ok := any(state).(io.Closer); ok {
+		close = func() {
+			_ = closer.Close()
+		}
+	}
+	return b.handleState(func(signal string, data T, metadata map[string]interface{}) error {
+		return handler(state, signal, data, metadata)
+	}, close)
+}
+
+func (c *core[K, T]) handleState(prefix string, handler handlerFunc[T], close func()) HandlerID {
+	return c.addHandler(handlerEntry[T]{fn: handler, close: close, prefix: prefix})
+}
+
+func (b *Broadcast[T]) handleState(handler handlerFunc[T], close func()) HandlerID {
+	return b.c().handleState(b.prefix(), handler, close)
+}
+
+func (b *UniqueBroadcast[K, T]) handleState(handler handlerFunc[T], close func()) HandlerID {
+	return b.core.handleState("", handler, close)
+}
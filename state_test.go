@@ -0,0 +1,60 @@
+package broadcast
+
+import (
+	"testing"
+)
+
+type counterState struct {
+	counts map[string]int
+	closed bool
+}
+
+func (s *counterState) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestHandleState_Lifecycle(t *testing.T) {
+	b := New[string]()
+	b.Watch("click", "button")
+
+	var state *counterState
+	id := HandleState(b, func() *counterState {
+		state = &counterState{counts: make(map[string]int)}
+		return state
+	}, func(s *counterState, signal string, data string, metadata map[string]interface{}) error {
+		s.counts[data]++
+		return nil
+	})
+
+	b.Broadcast("click", nil)
+	b.Broadcast("click", nil)
+	if state.counts["button"] != 2 {
+		t.Errorf("expected state to accumulate 2 clicks, got %d", state.counts["button"])
+	}
+	if state.closed {
+		t.Error("expected state to stay open while registered")
+	}
+
+	if !b.Unhandle(id) {
+		t.Fatal("expected Unhandle to succeed")
+	}
+	if !state.closed {
+		t.Error("expected Close on removal")
+	}
+}
+
+func TestHandleState_NonCloser(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	total := 0
+	id := HandleState(b, func() *int { return &total }, func(sum *int, signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		*sum += data.ID
+		return nil
+	})
+	b.Broadcast("test", nil)
+	if total != 1 || !b.Unhandle(id) {
+		t.Errorf("expected state to be updated and the handler removed, got %d", total)
+	}
+}
@@ -0,0 +1,90 @@
+package broadcast
+
+import (
+	"sort"
+	"strings"
+)
+
+// counts 返回有监听器的信号数量和监听器总数, prefix 非空时只统计该前缀下的信号
+func (c *core[K, T]) counts(prefix string) (signals int, listeners int) {
+	c.rangeSignals(func(signal string, count int) bool {
+		if count > 0 && (prefix == "" || strings.HasPrefix(signal, prefix)) {
+			signals++
+			listeners += count
+		}
+		return true
+	})
+	return signals, listeners
+}
+
+// signals 返回有监听器的信号名, 按字典序排列, prefix 非空时只返回该前缀下的信号并去掉前缀
+func (c *core[K, T]) signals(prefix string) []string {
+	var signals []string
+	c.rangeSignals(func(signal string, count int) bool {
+		if rest, ok := strings.CutPrefix(signal, prefix); ok && count > 0 {
+			signals = append(signals, rest)
+		}
+		return true
+	})
+	sort.Strings(signals)
+	return signals
+}
+
+// handlerCount 返回已注册的处理器数量, prefix 非空时只统计通过该命名空间注册的处理器
+func (c *core[K, T]) handlerCount(prefix string) int {
+	if prefix == "" {
+		return len(c.loadHandlers())
+	}
+
+	n := 0
+	for _, h := range c.loadHandlers() {
+		if h.prefix == prefix {
+			n++
+		}
+	}
+	return n
+}
+
+// SignalCount 返回有监听器的信号数量
+func (b *Broadcast[T]) SignalCount() int {
+	signals, _ := b.c().counts(b.prefix())
+	return signals
+}
+
+// TotalWatchCount 返回所有信号上的监听器总数
+func (b *Broadcast[T]) TotalWatchCount() int {
+	_, listeners := b.c().counts(b.prefix())
+	return listeners
+}
+
+// HandlerCount 返回已注册的处理器数量
+func (b *Broadcast[T]) HandlerCount() int {
+	return b.c().handlerCount(b.prefix())
+}
+
+// Signals 返回有监听器的信号名, 按字典序排列
+func (b *Broadcast[T]) Signals() []string {
+	return b.c().signals(b.prefix())
+}
+
+// SignalCount 返回有监听器的信号数量
+func (b *UniqueBroadcast[K, T]) SignalCount() int {
+	signals, _ := b.core.counts("")
+	return signals
+}
+
+// TotalWatchCount 返回所有信号上的监听器总数
+func (b *UniqueBroadcast[K, T]) TotalWatchCount() int {
+	_, listeners := b.core.counts("")
+	return listeners
+}
+
+// HandlerCount 返回已注册的处理器数量
+func (b *UniqueBroadcast[K, T]) HandlerCount() int {
+	return b.core.handlerCount("")
+}
+
+// Signals 返回有监听器的信号名, 按字典序排列
+func (b *UniqueBroadcast[K, T]) Signals() []string {
+	return b.core.signals("")
+}
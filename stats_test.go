@@ -0,0 +1,65 @@
+package broadcast
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCounts(t *testing.T) {
+	b := New[string]()
+	b.Watch("a", "1")
+	b.Watch("a", "2")
+	b.Watch("b", "1")
+	b.Watch("c", "1")
+	b.Unwatch("c", "1")
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error { return nil })
+
+	ns := b.Namespace("ns.")
+	ns.Watch("a", "1")
+	ns.Handle(func(signal string, data string, metadata map[string]interface{}) error { return nil })
+
+	if got := b.SignalCount(); got != 3 {
+		t.Errorf("expected 3 signals with listeners, got %d", got)
+	}
+	if got := b.TotalWatchCount(); got != 4 {
+		t.Errorf("expected 4 listeners, got %d", got)
+	}
+	if got := b.HandlerCount(); got != 2 {
+		t.Errorf("expected 2 handlers, got %d", got)
+	}
+	if ns.SignalCount() != 1 || ns.TotalWatchCount() != 1 || ns.HandlerCount() != 1 {
+		t.Errorf("expected namespace counts 1/1/1, got %d/%d/%d", ns.SignalCount(), ns.TotalWatchCount(), ns.HandlerCount())
+	}
+}
+
+func TestUniqueCounts(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	if b.SignalCount() != 0 || b.TotalWatchCount() != 0 || b.HandlerCount() != 0 {
+		t.Error("expected zero counts for an empty broadcaster")
+	}
+	b.Watch("a", &TestUniquer{data: TestUniqueData{ID: 1}})
+	b.Watch("b", &TestUniquer{data: TestUniqueData{ID: 1}})
+	if b.SignalCount() != 2 || b.TotalWatchCount() != 2 {
+		t.Errorf("expected 2/2, got %d/%d", b.SignalCount(), b.TotalWatchCount())
+	}
+}
+
+func TestSignals(t *testing.T) {
+	b := New[string]()
+	for _, signal := range []string{"c", "a", "b"} {
+		b.Watch(signal, "1")
+	}
+	b.Watch("empty", "1")
+	b.Unwatch("empty", "1")
+	b.Namespace("ns.").Watch("z", "1")
+
+	if got := b.Signals(); !slices.Equal(got, []string{"a", "b", "c", "ns.z"}) {
+		t.Errorf("expected sorted signals, got %v", got)
+	}
+	if got := b.Namespace("ns.").Signals(); !slices.Equal(got, []string{"z"}) {
+		t.Errorf("expected namespace signals without prefix, got %v", got)
+	}
+	if got := NewUnique[int, TestUniqueData]().Signals(); len(got) != 0 {
+		t.Errorf("expected no signals, got %v", got)
+	}
+}
@@ -0,0 +1,214 @@
+package broadcast
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"unique"
+)
+
+// StoreRecord 是 Store 中的一个监听器, Key 与 Value 为编码后的 key 和值
+type StoreRecord struct {
+	Key   []byte
+	Value []byte
+}
+
+// Store 是监听器状态的持久化后端
+// 开启写穿模式后, 每次 Watch/Unwatch/Clean 都会同步写入 Store, 重启后可以恢复监听器
+type Store interface {
+	// Put 保存信号上的一个监听器, 相同 key 已存在时覆盖
+	Put(signal string, key []byte, value []byte) error
+	// Delete 删除信号上的一个监听器
+	Delete(signal string, key []byte) error
+	// DeleteSignal 删除信号上的所有监听器
+	DeleteSignal(signal string) error
+	// Load 返回信号上的所有监听器
+	Load(signal string) ([]StoreRecord, error)
+	// Signals 返回有监听器的信号
+	Signals() ([]string, error)
+}
+
+// StoreConfig 写穿模式配置
+type StoreConfig struct {
+	Store Store
+	// Codec 编码监听器的 key 和值, 默认为 JSONCodec
+	Codec Codec
+	// OnError 在写入 Store 失败时调用, 内存中的状态不受影响
+	OnError func(signal string, err error)
+}
+
+// storeBinding 将 core 的监听器变化写入 Store
+type storeBinding[K comparable, T any] struct {
+	config StoreConfig
+}
+
+func (b *storeBinding[K, T]) fail(signal string, err error) {
+	if err != nil && b.config.OnError != nil {
+		b.config.OnError(signal, err)
+	}
+}
+
+func (b *storeBinding[K, T]) put(signal string, l listener[K, T]) {
+	key, err := b.config.Codec.Marshal(l.key.Value())
+	if err != nil {
+		b.fail(signal, err)
+		return
+	}
+	value, err := b.config.Codec.Marshal(l.data.Value())
+	if err != nil {
+		b.fail(signal, err)
+		return
+	}
+	b.fail(signal, b.config.Store.Put(signal, key, value))
+}
+
+func (b *storeBinding[K, T]) delete(signal string, key K) {
+	data, err := b.config.Codec.Marshal(key)
+	if err != nil {
+		b.fail(signal, err)
+		return
+	}
+	b.fail(signal, b.config.Store.Delete(signal, data))
+}
+
+func (b *storeBinding[K, T]) clear() {
+	signals, err := b.config.Store.Signals()
+	if err != nil {
+		b.fail("", err)
+		return
+	}
+	for _, signal := range signals {
+		b.fail(signal, b.config.Store.DeleteSignal(signal))
+	}
+}
+
+// track 在信号锁内记录新增或替换的监听器: 更新反向索引并写入 Store
+func (c *core[K, T]) track(signal string, l listener[K, T]) {
+	c.index.add(l.key, signal)
+	if s := c.loadSettings().store; s != nil {
+		s.put(signal, l)
+	}
+}
+
+// untrack 在信号锁内记录被移除的监听器
+func (c *core[K, T]) untrack(signal string, key unique.Handle[K]) {
+	c.index.remove(key, signal)
+	if c.leased.Load() {
+		c.endLease(signalKey[K]{signal, key})
+	}
+	if s := c.loadSettings().store; s != nil {
+		s.delete(signal, key.Value())
+	}
+}
+
+// enableStore 从 Store 恢复监听器, 然后开启写穿模式
+// restore 根据解码后的 key 和值重建监听器
+func (c *core[K, T]) enableStore(config StoreConfig, restore func(key K, value T) Uniquer[K, T]) error {
+	if config.Codec == nil {
+		config.Codec = JSONCodec
+	}
+
+	signals, err := config.Store.Signals()
+	if err != nil {
+		return err
+	}
+	for _, signal := range signals {
+		records, err := config.Store.Load(signal)
+		if err != nil {
+			return err
+		}
+		for _, r := range records {
+			var (
+				key   K
+				value T
+			)
+			if err := config.Codec.Unmarshal(r.Key, &key); err != nil {
+				return err
+			}
+			if err := config.Codec.Unmarshal(r.Value, &value); err != nil {
+				return err
+			}
+			// 恢复的是已授权添加的监听器, 不再经过授权
+			c.tryWatch(signal, newListener(restore(key, value)))
+		}
+	}
+
+	c.updateSettings(func(s *settings[K, T]) {
+		s.store = &storeBinding[K, T]{config: config}
+	})
+	return nil
+}
+
+// MemoryStore 是基于内存的 Store, 用于测试
+type MemoryStore struct {
+	mu      sync.RWMutex
+	signals map[string]map[string][]byte
+}
+
+// NewMemoryStore 创建内存 Store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{signals: make(map[string]map[string][]byte)}
+}
+
+// Put 保存信号上的一个监听器
+func (s *MemoryStore) Put(signal string, key []byte, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, ok := s.signals[signal]
+	if !ok {
+		records = make(map[string][]byte)
+		s.signals[signal] = records
+	}
+	records[string(key)] = bytes.Clone(value)
+	return nil
+}
+
+// Delete 删除信号上的一个监听器
+func (s *MemoryStore) Delete(signal string, key []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.signals[signal], string(key))
+	if len(s.signals[signal]) == 0 {
+		delete(s.signals, signal)
+	}
+	return nil
+}
+
+// DeleteSignal 删除信号上的所有监听器
+func (s *MemoryStore) DeleteSignal(signal string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.signals, signal)
+	return nil
+}
+
+// Load 返回信号上的所有监听器, 按 key 排序
+func (s *MemoryStore) Load(signal string) ([]StoreRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]StoreRecord, 0, len(s.signals[signal]))
+	for key, value := range s.signals[signal] {
+		records = append(records, StoreRecord{Key: []byte(key), Value: bytes.Clone(value)})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return bytes.Compare(records[i].Key, records[j].Key) < 0
+	})
+	return records, nil
+}
+
+// Signals 返回有监听器的信号, 按字典序排列
+func (s *MemoryStore) Signals() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	signals := make([]string, 0, len(s.signals))
+	for signal := range s.signals {
+		signals = append(signals, signal)
+	}
+	sort.Strings(signals)
+	return signals, nil
+}
@@ -0,0 +1,86 @@
+package broadcast
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestStore_WriteThroughAndRestore(t *testing.T) {
+	store := NewMemoryStore()
+	b := New[string]()
+	if err := b.EnableStore(StoreConfig{Store: store}); err != nil {
+		t.Fatal(err)
+	}
+	b.Watch("a", "1")
+	b.Watch("a", "2")
+	b.Watch("b", "1")
+	b.Watch("c", "1")
+	b.Unwatch("a", "1")
+	b.Clean("c")
+
+	restored := New[string]()
+	if err := restored.EnableStore(StoreConfig{Store: store}); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.Signals(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("expected [a b] after restore, got %v", got)
+	}
+	if restored.WatchCount("a") != 1 {
+		t.Errorf("expected 1 listener on a, got %d", restored.WatchCount("a"))
+	}
+
+	restored.CleanAll()
+	if signals, _ := store.Signals(); len(signals) != 0 {
+		t.Errorf("expected CleanAll to clear the store, got %v", signals)
+	}
+}
+
+func TestStore_Unique(t *testing.T) {
+	store := NewMemoryStore()
+	restore := func(key int, value TestUniqueData) Uniquer[int, TestUniqueData] {
+		return &TestUniquer{data: value}
+	}
+
+	b := NewUnique[int, TestUniqueData]()
+	if err := b.EnableStore(StoreConfig{Store: store}, restore); err != nil {
+		t.Fatal(err)
+	}
+	b.Watch("a", &TestUniquer{data: TestUniqueData{ID: 1, Name: "old"}})
+	b.UpdateWatch("a", &TestUniquer{data: TestUniqueData{ID: 1, Name: "new"}})
+	b.Watch("a", &TestUniquer{data: TestUniqueData{ID: 2, Name: "two"}})
+	b.CleanKeys(func(key int) bool { return key == 2 })
+
+	restored := NewUnique[int, TestUniqueData]()
+	if err := restored.EnableStore(StoreConfig{Store: store}, restore); err != nil {
+		t.Fatal(err)
+	}
+	if data, ok := restored.Get("a", 1); !ok || data.Name != "new" {
+		t.Errorf("expected the updated value to be restored, got %v, %v", data, ok)
+	}
+	if restored.Has("a", 2) {
+		t.Error("expected CleanKeys to be persisted")
+	}
+}
+
+type failingStore struct {
+	*MemoryStore
+}
+
+func (failingStore) Put(signal string, key []byte, value []byte) error {
+	return errors.New("disk full")
+}
+
+func TestStore_OnError(t *testing.T) {
+	var failed []string
+	b := New[string]()
+	b.EnableStore(StoreConfig{
+		Store:   failingStore{NewMemoryStore()},
+		OnError: func(signal string, err error) { failed = append(failed, signal) },
+	})
+	b.Watch("a", "1")
+
+	if !b.HasWatch("a") || !slices.Equal(failed, []string{"a"}) {
+		t.Errorf("expected the in-memory watch to succeed and the error to be reported, got %v", failed)
+	}
+}
@@ -0,0 +1,103 @@
+// Package boltstore 是基于 BoltDB 的 broadcast.Store 实现
+// 每个信号对应一个 bucket, 监听器的 key 与值直接作为 bucket 中的键值对
+package boltstore
+
+import (
+	"bytes"
+
+	bolt "go.etcd.io/bbolt"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+// Store 是基于 BoltDB 的 broadcast.Store
+type Store struct {
+	db *bolt.DB
+}
+
+var _ broadcast.Store = (*Store)(nil)
+
+// New 使用已打开的 BoltDB 创建 Store, 调用方负责关闭 db
+func New(db *bolt.DB) *Store {
+	return &Store{db: db}
+}
+
+// Open 打开 path 处的 BoltDB 并创建 Store
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, err
+	}
+	return New(db), nil
+}
+
+// Close 关闭底层数据库
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put 保存信号上的一个监听器
+func (s *Store) Put(signal string, key []byte, value []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(signal))
+		if err != nil {
+			return err
+		}
+		return b.Put(key, value)
+	})
+}
+
+// Delete 删除信号上的一个监听器, 信号没有监听器后删除其 bucket
+func (s *Store) Delete(signal string, key []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(signal))
+		if b == nil {
+			return nil
+		}
+		if err := b.Delete(key); err != nil {
+			return err
+		}
+		if k, _ := b.Cursor().First(); k == nil {
+			return tx.DeleteBucket([]byte(signal))
+		}
+		return nil
+	})
+}
+
+// DeleteSignal 删除信号上的所有监听器
+func (s *Store) DeleteSignal(signal string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(signal)) == nil {
+			return nil
+		}
+		return tx.DeleteBucket([]byte(signal))
+	})
+}
+
+// Load 返回信号上的所有监听器, 按 key 排序
+func (s *Store) Load(signal string) ([]broadcast.StoreRecord, error) {
+	var records []broadcast.StoreRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(signal))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			records = append(records, broadcast.StoreRecord{Key: bytes.Clone(k), Value: bytes.Clone(v)})
+			return nil
+		})
+	})
+	return records, err
+}
+
+// Signals 返回有监听器的信号, 按字典序排列
+func (s *Store) Signals() ([]string, error) {
+	var signals []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			signals = append(signals, string(name))
+			return nil
+		})
+	})
+	return signals, err
+}
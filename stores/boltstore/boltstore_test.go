@@ -0,0 +1,48 @@
+package boltstore
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+func TestStore_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "listeners.db")
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := broadcast.New[string]()
+	if err := b.EnableStore(broadcast.StoreConfig{Store: store}); err != nil {
+		t.Fatal(err)
+	}
+	b.Watch("a", "1")
+	b.Watch("a", "2")
+	b.Watch("b", "1")
+	b.Unwatch("a", "1")
+	b.Watch("c", "1")
+	b.Clean("c")
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	restored := broadcast.New[string]()
+	if err := restored.EnableStore(broadcast.StoreConfig{Store: store}); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.Signals(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("expected [a b], got %v", got)
+	}
+	if restored.WatchCount("a") != 1 {
+		t.Errorf("expected 1 listener on a, got %d", restored.WatchCount("a"))
+	}
+}
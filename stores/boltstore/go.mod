@@ -0,0 +1,12 @@
+module pkg.blksails.net/x/broadcast/stores/boltstore
+
+go 1.24
+
+require (
+	go.etcd.io/bbolt v1.4.3
+	pkg.blksails.net/x/broadcast v0.0.0
+)
+
+require golang.org/x/sys v0.29.0 // indirect
+
+replace pkg.blksails.net/x/broadcast => ../..
@@ -0,0 +1,98 @@
+// Package sqlitestore 是基于 SQLite 的 broadcast.Store 实现
+// 使用 database/sql, 不绑定具体驱动, 调用方导入 SQLite 驱动 (例如 modernc.org/sqlite 或
+// github.com/mattn/go-sqlite3) 并传入打开的 *sql.DB
+package sqlitestore
+
+import (
+	"database/sql"
+	"fmt"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+// DefaultTable 默认的表名
+const DefaultTable = "broadcast_listeners"
+
+// Store 是基于 SQLite 的 broadcast.Store
+type Store struct {
+	db    *sql.DB
+	table string
+}
+
+var _ broadcast.Store = (*Store)(nil)
+
+// New 使用 db 创建 Store, 并在表不存在时创建, table 为空时使用 DefaultTable
+// 调用方负责关闭 db
+func New(db *sql.DB, table string) (*Store, error) {
+	if table == "" {
+		table = DefaultTable
+	}
+	s := &Store{db: db, table: table}
+	_, err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %q (
+		signal TEXT NOT NULL,
+		key BLOB NOT NULL,
+		value BLOB NOT NULL,
+		PRIMARY KEY (signal, key)
+	)`, table))
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Put 保存信号上的一个监听器
+func (s *Store) Put(signal string, key []byte, value []byte) error {
+	_, err := s.db.Exec(fmt.Sprintf(`INSERT INTO %q (signal, key, value) VALUES (?, ?, ?)
+		ON CONFLICT (signal, key) DO UPDATE SET value = excluded.value`, s.table), signal, key, value)
+	return err
+}
+
+// Delete 删除信号上的一个监听器
+func (s *Store) Delete(signal string, key []byte) error {
+	_, err := s.db.Exec(fmt.Sprintf(`DELETE FROM %q WHERE signal = ? AND key = ?`, s.table), signal, key)
+	return err
+}
+
+// DeleteSignal 删除信号上的所有监听器
+func (s *Store) DeleteSignal(signal string) error {
+	_, err := s.db.Exec(fmt.Sprintf(`DELETE FROM %q WHERE signal = ?`, s.table), signal)
+	return err
+}
+
+// Load 返回信号上的所有监听器, 按 key 排序
+func (s *Store) Load(signal string) ([]broadcast.StoreRecord, error) {
+	rows, err := s.db.Query(fmt.Sprintf(`SELECT key, value FROM %q WHERE signal = ? ORDER BY key`, s.table), signal)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []broadcast.StoreRecord
+	for rows.Next() {
+		var r broadcast.StoreRecord
+		if err := rows.Scan(&r.Key, &r.Value); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// Signals 返回有监听器的信号, 按字典序排列
+func (s *Store) Signals() ([]string, error) {
+	rows, err := s.db.Query(fmt.Sprintf(`SELECT DISTINCT signal FROM %q ORDER BY signal`, s.table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var signals []string
+	for rows.Next() {
+		var signal string
+		if err := rows.Scan(&signal); err != nil {
+			return nil, err
+		}
+		signals = append(signals, signal)
+	}
+	return signals, rows.Err()
+}
@@ -0,0 +1,72 @@
+package sqlitestore
+
+import (
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+	"unique"
+
+	_ "modernc.org/sqlite"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+type user struct {
+	id   string
+	name string
+}
+
+func (u user) Unique() unique.Handle[string] {
+	return unique.Make(u.id)
+}
+
+func (u user) Value() string {
+	return u.name
+}
+
+func open(t *testing.T, path string) (*sql.DB, *Store) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := New(db, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, store
+}
+
+func TestStore_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "listeners.db")
+
+	db, store := open(t, path)
+	b := broadcast.NewUnique[string, string]()
+	restore := func(key string, value string) broadcast.Uniquer[string, string] {
+		return user{id: key, name: value}
+	}
+	if err := b.EnableStore(broadcast.StoreConfig{Store: store}, restore); err != nil {
+		t.Fatal(err)
+	}
+	b.Watch("room", user{id: "u1", name: "alice"})
+	b.Watch("room", user{id: "u2", name: "bob"})
+	b.UpdateWatch("room", user{id: "u1", name: "alice v2"})
+	b.UnwatchKey("room", "u2")
+	db.Close()
+
+	db, store = open(t, path)
+	defer db.Close()
+
+	restored := broadcast.NewUnique[string, string]()
+	if err := restored.EnableStore(broadcast.StoreConfig{Store: store}, restore); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.Signals(); !slices.Equal(got, []string{"room"}) {
+		t.Errorf("expected [room], got %v", got)
+	}
+	if name, _ := restored.Get("room", "u1"); name != "alice v2" || restored.Has("room", "u2") {
+		t.Errorf("unexpected restored state: u1=%q u2=%v", name, restored.Has("room", "u2"))
+	}
+}
@@ -0,0 +1,189 @@
+package stream
+
+import (
+	"maps"
+	"sync"
+	"time"
+	"unique"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+// DefaultJoinWindow 是 JoinConfig.Window 的默认值
+const DefaultJoinWindow = time.Minute
+
+// JoinConfig 配置 Join 的关联方式
+type JoinConfig[A any, B any, K comparable] struct {
+	// LeftKey 与 RightKey 提取两侧元素的关联键, 元素实现了 Uniquer 时可以使用 UniqueKey
+	LeftKey  func(A) K
+	RightKey func(B) K
+	// Window 为一侧元素等待另一侧的最长时间, 默认为 DefaultJoinWindow
+	Window time.Duration
+	// Clock 为判断超时使用的时间源, 默认为 broadcast.SystemClock
+	Clock broadcast.Clock
+}
+
+// Joined 是 Join 发出的组合事件
+type Joined[K comparable, A any, B any] struct {
+	Key   K
+	Left  A
+	Right B
+}
+
+// UniqueKey 返回 Uniquer 的键, 用作 JoinConfig 的 LeftKey 或 RightKey
+func UniqueKey[K comparable, T interface{ Unique() unique.Handle[K] }](v T) K {
+	return v.Unique().Value()
+}
+
+// Join 按键关联 left 与 right 两个流: 一侧的元素在 Window 内遇到另一侧键相同的元素时,
+// 两者配对为一个 Joined 发出; 同一个键有多个等待的元素时按到达顺序一一配对, 超时未配对的元素被丢弃.
+//
+// 发出的元素的 Signal 为后到达一侧的信号, Metadata 为两侧元素的合并 (右侧覆盖左侧),
+// 通常应通过 IntoSignal 将组合事件广播到第三个信号上.
+// 对返回的流调用 Close 会同时注销两侧的源处理器
+func Join[A any, B any, K comparable](left *Stream[A], right *Stream[B], config JoinConfig[A, B, K]) *Stream[Joined[K, A, B]] {
+	if config.Window <= 0 {
+		config.Window = DefaultJoinWindow
+	}
+	if config.Clock == nil {
+		config.Clock = broadcast.SystemClock
+	}
+
+	j := &joiner[A, B, K]{
+		config: config,
+		lefts:  make(map[K][]*pending[A]),
+		rights: make(map[K][]*pending[B]),
+	}
+	j.next = derive[Joined[K, A, B]](&root{cancel: func() {
+		left.Close()
+		right.Close()
+	}})
+	left.connect(j.left)
+	right.connect(j.right)
+	return j.next
+}
+
+// pending 是一个等待配对的元素
+type pending[T any] struct {
+	item    Item[T]
+	matched bool
+}
+
+// expiry 按到达顺序记录等待的元素, 用于清理超时元素
+type expiry[K comparable] struct {
+	key  K
+	at   time.Time
+	left bool
+	done *bool
+}
+
+type joiner[A any, B any, K comparable] struct {
+	config JoinConfig[A, B, K]
+	next   *Stream[Joined[K, A, B]]
+
+	mu     sync.Mutex
+	lefts  map[K][]*pending[A]
+	rights map[K][]*pending[B]
+	queue  []expiry[K]
+}
+
+func (j *joiner[A, B, K]) left(item Item[A]) error {
+	key := j.config.LeftKey(item.Data)
+	now := j.config.Clock.Now()
+
+	j.mu.Lock()
+	j.expire(now)
+	other, ok := take(j.rights, key)
+	if !ok {
+		p := &pending[A]{item: item}
+		j.lefts[key] = append(j.lefts[key], p)
+		j.queue = append(j.queue, expiry[K]{key: key, at: now, left: true, done: &p.matched})
+	}
+	j.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return j.next.emit(Item[Joined[K, A, B]]{
+		Signal:   item.Signal,
+		Data:     Joined[K, A, B]{Key: key, Left: item.Data, Right: other.Data},
+		Metadata: merge(item.Metadata, other.Metadata),
+	})
+}
+
+func (j *joiner[A, B, K]) right(item Item[B]) error {
+	key := j.config.RightKey(item.Data)
+	now := j.config.Clock.Now()
+
+	j.mu.Lock()
+	j.expire(now)
+	other, ok := take(j.lefts, key)
+	if !ok {
+		p := &pending[B]{item: item}
+		j.rights[key] = append(j.rights[key], p)
+		j.queue = append(j.queue, expiry[K]{key: key, at: now, done: &p.matched})
+	}
+	j.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return j.next.emit(Item[Joined[K, A, B]]{
+		Signal:   item.Signal,
+		Data:     Joined[K, A, B]{Key: key, Left: other.Data, Right: item.Data},
+		Metadata: merge(other.Metadata, item.Metadata),
+	})
+}
+
+// expire 丢弃超过 Window 仍未配对的元素, 调用方持有 j.mu
+func (j *joiner[A, B, K]) expire(now time.Time) {
+	cutoff := now.Add(-j.config.Window)
+	n := 0
+	for _, e := range j.queue {
+		if e.at.After(cutoff) {
+			break
+		}
+		n++
+		if *e.done {
+			continue
+		}
+		// 同一个键上的元素按到达顺序超时, 最早等待的即为超时的元素
+		if e.left {
+			take(j.lefts, e.key)
+		} else {
+			take(j.rights, e.key)
+		}
+	}
+	if n > 0 {
+		j.queue = append(j.queue[:0], j.queue[n:]...)
+	}
+}
+
+// take 取出 key 上最早等待的元素
+func take[K comparable, T any](waiting map[K][]*pending[T], key K) (Item[T], bool) {
+	list := waiting[key]
+	if len(list) == 0 {
+		return Item[T]{}, false
+	}
+	p := list[0]
+	p.matched = true
+	if len(list) == 1 {
+		delete(waiting, key)
+	} else {
+		waiting[key] = list[1:]
+	}
+	return p.item, true
+}
+
+// merge 合并两侧的元数据, 两侧都为空时返回 nil
+func merge(left, right map[string]interface{}) map[string]interface{} {
+	if len(left) == 0 {
+		return right
+	}
+	if len(right) == 0 {
+		return left
+	}
+	md := maps.Clone(left)
+	maps.Copy(md, right)
+	return md
+}
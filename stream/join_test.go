@@ -0,0 +1,109 @@
+package stream
+
+import (
+	"testing"
+	"time"
+	"unique"
+
+	"pkg.blksails.net/x/broadcast"
+	"pkg.blksails.net/x/broadcast/broadcasttest"
+)
+
+type payment struct {
+	order  string
+	amount int
+}
+
+type shipment struct {
+	order   string
+	carrier string
+}
+
+func (s shipment) Unique() unique.Handle[string] { return unique.Make(s.order) }
+
+func TestJoin_PairsByKeyIntoThirdSignal(t *testing.T) {
+	payments := broadcast.New[payment]()
+	shipments := broadcast.New[shipment]()
+	fulfilled := broadcast.New[string]()
+	fulfilled.Watch("fulfilled", "audit")
+
+	var got []Joined[string, payment, shipment]
+	var mds []map[string]interface{}
+	broadcast.HandleData(fulfilled, func(signal string, _ string, j Joined[string, payment, shipment], md map[string]interface{}) error {
+		got = append(got, j)
+		mds = append(mds, md)
+		return nil
+	})
+
+	joined := Join(From(payments, "payment"), From(shipments, "shipment"), JoinConfig[payment, shipment, string]{
+		LeftKey:  func(p payment) string { return p.order },
+		RightKey: UniqueKey[string, shipment],
+	})
+	joined.IntoSignal(fulfilled, "fulfilled")
+
+	payments.Watch("payment", payment{"o1", 10})
+	payments.Watch("payment", payment{"o2", 20})
+	payments.Broadcast("payment", map[string]interface{}{"from": "payment", "left": true})
+	if len(got) != 0 {
+		t.Fatalf("expected no pairs before shipments, got %v", got)
+	}
+
+	shipments.Watch("shipment", shipment{"o2", "ups"})
+	shipments.Watch("shipment", shipment{"o3", "dhl"})
+	shipments.Broadcast("shipment", map[string]interface{}{"from": "shipment"})
+
+	if len(got) != 1 || got[0].Key != "o2" || got[0].Left.amount != 20 || got[0].Right.carrier != "ups" {
+		t.Fatalf("expected o2 paired, got %v", got)
+	}
+	if mds[0]["from"] != "shipment" || mds[0]["left"] != true {
+		t.Errorf("expected merged metadata with right side winning, got %v", mds[0])
+	}
+
+	joined.Close()
+	payments.Broadcast("payment", nil)
+	shipments.Broadcast("shipment", nil)
+	if len(got) != 1 {
+		t.Errorf("expected Close to unregister both sources, got %v", got)
+	}
+}
+
+func TestJoin_WindowExpiresUnmatched(t *testing.T) {
+	clock := broadcasttest.NewFakeClock(time.Unix(0, 0))
+	left := broadcast.New[int]()
+	right := broadcast.New[string]()
+	left.Watch("l", 1)
+	right.Watch("r", "1")
+
+	var pairs int
+	Join(From(left, "l"), From(right, "r"), JoinConfig[int, string, int]{
+		LeftKey:  func(n int) int { return n },
+		RightKey: func(s string) int { return int(s[0] - '0') },
+		Window:   time.Second,
+		Clock:    clock,
+	}).Each(func(Item[Joined[int, int, string]]) error {
+		pairs++
+		return nil
+	})
+
+	left.Broadcast("l", nil)
+	clock.Advance(2 * time.Second)
+	right.Broadcast("r", nil)
+	if pairs != 0 {
+		t.Fatalf("expected expired left element not to pair, got %d pairs", pairs)
+	}
+
+	left.Broadcast("l", nil)
+	if pairs != 1 {
+		t.Errorf("expected waiting right element to pair within the window, got %d pairs", pairs)
+	}
+
+	// 同一个键上多个等待的元素按到达顺序一一配对
+	left.Broadcast("l", nil)
+	left.Broadcast("l", nil)
+	right.Broadcast("r", nil)
+	right.Broadcast("r", nil)
+	right.Broadcast("r", nil)
+	if pairs != 3 {
+		t.Errorf("expected one pair per waiting element, got %d pairs", pairs)
+	}
+}
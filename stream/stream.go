@@ -0,0 +1,149 @@
+// Package stream 在 broadcast 之上提供轻量的响应式管道, 将信号上投递的值经过
+// Filter、Map、Reduce 等算子处理后转发到其他广播器或回调:
+//
+//	stream.From(b, "orders").Filter(paid).Map(normalize).Into(other)
+//
+// 管道是推模式的: 源广播器每次调用处理器时, 监听器的值依次流过各级算子.
+// 算子在广播的 goroutine 中同步执行, 下游返回的错误作为处理器错误返回给源广播器
+package stream
+
+import (
+	"errors"
+	"sync"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+// Source 是可以作为管道源的广播器, 由 Broadcast 与 UniqueBroadcast 实现
+type Source[T any] interface {
+	broadcast.DataSubscriber[T]
+	Unhandle(id broadcast.HandlerID) bool
+}
+
+// Item 是流过管道的一个元素, Data 为经过各级算子处理后的值
+type Item[T any] struct {
+	Signal   string
+	Data     T
+	Metadata map[string]interface{}
+}
+
+// Stream 是管道中的一级, 由 From 或算子创建
+// 一级可以连接多个下游, 每个元素会依次交给所有下游
+type Stream[T any] struct {
+	root *root
+
+	mu    sync.RWMutex
+	sinks []func(Item[T]) error
+}
+
+// root 是一条管道共享的源处理器
+type root struct {
+	once   sync.Once
+	cancel func()
+}
+
+// From 在 source 上注册一个处理器, 返回 signal 上投递的监听器值组成的流
+// 处理器在 From 返回时即开始工作, 尚未连接下游时到达的值被丢弃
+func From[T any](source Source[T], signal string) *Stream[T] {
+	s := &Stream[T]{root: &root{}}
+	id := broadcast.HandleData(source, func(sig string, data T, _ any, metadata map[string]interface{}) error {
+		if sig != signal {
+			return nil
+		}
+		return s.emit(Item[T]{Signal: sig, Data: data, Metadata: metadata})
+	})
+	s.root.cancel = func() { source.Unhandle(id) }
+	return s
+}
+
+// Close 从源广播器注销管道的处理器, 对管道中任意一级调用效果相同, 重复调用不做任何事
+func (s *Stream[T]) Close() {
+	s.root.once.Do(s.root.cancel)
+}
+
+// Filter 返回只包含 f 返回 true 的元素的流
+func (s *Stream[T]) Filter(f func(T) bool) *Stream[T] {
+	next := derive[T](s.root)
+	s.connect(func(item Item[T]) error {
+		if !f(item.Data) {
+			return nil
+		}
+		return next.emit(item)
+	})
+	return next
+}
+
+// Map 返回以 f 改写每个元素后的流, 需要改变元素类型时使用 MapTo
+func (s *Stream[T]) Map(f func(T) T) *Stream[T] {
+	return MapTo(s, f)
+}
+
+// Each 对每个元素调用 fn, fn 返回的错误作为处理器错误返回给源广播器
+func (s *Stream[T]) Each(fn func(Item[T]) error) {
+	s.connect(fn)
+}
+
+// Into 将每个元素以原信号广播到 target, 元素作为负载交给 target 上通过 HandleData 注册的处理器
+func (s *Stream[T]) Into(target broadcast.DataPublisher) {
+	s.connect(func(item Item[T]) error {
+		return broadcast.BroadcastData(target, item.Signal, item.Data, item.Metadata)
+	})
+}
+
+// IntoSignal 与 Into 相同, 但广播到 target 的 signal 上
+func (s *Stream[T]) IntoSignal(target broadcast.DataPublisher, signal string) {
+	s.connect(func(item Item[T]) error {
+		return broadcast.BroadcastData(target, signal, item.Data, item.Metadata)
+	})
+}
+
+// MapTo 返回以 f 将每个元素转换为 U 后的流
+func MapTo[T any, U any](s *Stream[T], f func(T) U) *Stream[U] {
+	next := derive[U](s.root)
+	s.connect(func(item Item[T]) error {
+		return next.emit(Item[U]{Signal: item.Signal, Data: f(item.Data), Metadata: item.Metadata})
+	})
+	return next
+}
+
+// Reduce 返回累积值组成的流: 每个元素到达时以 f 将其并入累积值, 并向下游发出新的累积值
+// 累积值在管道的所有元素之间共享, f 在锁内调用, 不应阻塞
+func Reduce[T any, A any](s *Stream[T], initial A, f func(acc A, data T) A) *Stream[A] {
+	next := derive[A](s.root)
+	var (
+		mu  sync.Mutex
+		acc = initial
+	)
+	s.connect(func(item Item[T]) error {
+		mu.Lock()
+		acc = f(acc, item.Data)
+		value := acc
+		mu.Unlock()
+		return next.emit(Item[A]{Signal: item.Signal, Data: value, Metadata: item.Metadata})
+	})
+	return next
+}
+
+func derive[T any](r *root) *Stream[T] {
+	return &Stream[T]{root: r}
+}
+
+func (s *Stream[T]) connect(sink func(Item[T]) error) {
+	s.mu.Lock()
+	s.sinks = append(s.sinks, sink)
+	s.mu.Unlock()
+}
+
+// emit 将元素依次交给所有下游, 返回所有错误的组合
+func (s *Stream[T]) emit(item Item[T]) error {
+	s.mu.RLock()
+	sinks := s.sinks
+	s.mu.RUnlock()
+	var errs []error
+	for _, sink := range sinks {
+		if err := sink(item); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
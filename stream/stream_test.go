@@ -0,0 +1,100 @@
+package stream
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"unique"
+
+	"pkg.blksails.net/x/broadcast"
+)
+
+func TestStream_FilterMapInto(t *testing.T) {
+	src := broadcast.New[int]()
+	dst := broadcast.New[string]()
+	dst.Watch("orders", "audit")
+
+	var got []string
+	broadcast.HandleData(dst, func(signal string, data string, payload int, metadata map[string]interface{}) error {
+		got = append(got, signal+":"+strconv.Itoa(payload))
+		return nil
+	})
+
+	for i := 1; i <= 4; i++ {
+		src.Watch("orders", i)
+	}
+	src.Watch("other", 100)
+
+	s := From(src, "orders").
+		Filter(func(n int) bool { return n%2 == 0 }).
+		Map(func(n int) int { return n * 10 })
+	s.Into(dst)
+
+	if err := src.Broadcast("orders", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Broadcast("other", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "orders:20" || got[1] != "orders:40" {
+		t.Errorf("expected filtered and mapped values forwarded, got %v", got)
+	}
+
+	s.Close()
+	s.Close()
+	got = nil
+	src.Broadcast("orders", nil)
+	if len(got) != 0 {
+		t.Errorf("expected no values after Close, got %v", got)
+	}
+}
+
+func TestStream_MapToAndReduce(t *testing.T) {
+	src := broadcast.New[int]()
+	for i := 1; i <= 3; i++ {
+		src.Watch("n", i)
+	}
+
+	labels := MapTo(From(src, "n"), strconv.Itoa)
+	var names []string
+	labels.Each(func(item Item[string]) error {
+		names = append(names, item.Data)
+		return nil
+	})
+
+	var sums []int
+	Reduce(From(src, "n"), 0, func(acc, n int) int { return acc + n }).Each(func(item Item[int]) error {
+		sums = append(sums, item.Data)
+		return nil
+	})
+
+	src.Broadcast("n", map[string]interface{}{"k": "v"})
+	if len(names) != 3 || names[0] != "1" || names[2] != "3" {
+		t.Errorf("expected mapped names, got %v", names)
+	}
+	if len(sums) != 3 || sums[2] != 6 {
+		t.Errorf("expected running sums, got %v", sums)
+	}
+}
+
+func TestStream_ErrorsReachSource(t *testing.T) {
+	src := broadcast.NewUnique[string, string]()
+	src.Watch("sig", owner{"r", "a"})
+
+	boom := errors.New("boom")
+	s := From(src, "sig")
+	s.Each(func(Item[string]) error { return boom })
+	s.IntoSignal(broadcast.New[int](), "forwarded")
+
+	if err := src.Broadcast("sig", nil); !errors.Is(err, boom) {
+		t.Errorf("expected sink error returned to the source, got %v", err)
+	}
+}
+
+type owner struct {
+	resource string
+	holder   string
+}
+
+func (o owner) Unique() unique.Handle[string] { return unique.Make(o.resource) }
+func (o owner) Value() string                 { return o.holder }
@@ -0,0 +1,91 @@
+package broadcast
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// tap 是 Tap 注册的观察者
+// 观察单个信号时 name 为注册时不含命名空间前缀的信号名; 观察所有信号时只接收 prefix 下的信号
+type tap struct {
+	name   string
+	prefix string
+	fn     func(e Event[any])
+}
+
+// addTap 注册观察者, signal 为空时观察 prefix 下的所有信号, 返回取消函数
+func (c *core[K, T]) addTap(prefix, signal string, fn func(e Event[any])) (cancel func()) {
+	t := &tap{name: signal, prefix: prefix, fn: fn}
+	key := prefix + signal
+	c.updateSettings(func(s *settings[K, T]) {
+		if signal == "" {
+			s.tapAll = append(slices.Clone(s.tapAll), t)
+			return
+		}
+		taps := maps.Clone(s.taps)
+		if taps == nil {
+			taps = make(map[string][]*tap)
+		}
+		taps[key] = append(slices.Clone(taps[key]), t)
+		s.taps = taps
+	})
+	return func() {
+		c.updateSettings(func(s *settings[K, T]) {
+			if signal == "" {
+				if i := slices.Index(s.tapAll, t); i >= 0 {
+					s.tapAll = slices.Delete(slices.Clone(s.tapAll), i, i+1)
+				}
+				return
+			}
+			i := slices.Index(s.taps[key], t)
+			if i < 0 {
+				return
+			}
+			taps := maps.Clone(s.taps)
+			if rest := slices.Delete(slices.Clone(taps[key]), i, i+1); len(rest) > 0 {
+				taps[key] = rest
+			} else {
+				delete(taps, key)
+			}
+			if len(taps) == 0 {
+				taps = nil
+			}
+			s.taps = taps
+		})
+	}
+}
+
+// notifyTaps 将一次广播交给观察者, Data 为广播时负载
+func notifyTaps[K comparable, T any](taps []*tap, d *delivery[K, T]) {
+	for _, t := range taps {
+		signal := t.name
+		if signal == "" {
+			rest, ok := strings.CutPrefix(d.signal, t.prefix)
+			if !ok {
+				continue
+			}
+			signal = rest
+		}
+		t.fn(Event[any]{
+			ID:        d.eventID(),
+			Timestamp: d.time,
+			Signal:    signal,
+			Source:    d.source,
+			Metadata:  d.metadata,
+			Data:      d.payload,
+		})
+	}
+}
+
+// Tap 观察信号的每一次广播, signal 为空时观察所有信号; 与监听器和处理器无关, 没有监听器的广播同样会被观察到; 返回取消函数
+// fn 收到的 Event.Data 为广播时负载, 在广播所在的 goroutine 中同步调用, 应尽快返回; 用于调试与运维工具的实时跟踪
+func (b *Broadcast[T]) Tap(signal string, fn func(e Event[any])) (cancel func()) {
+	return b.c().addTap(b.prefix(), signal, fn)
+}
+
+// Tap 观察信号的每一次广播, signal 为空时观察所有信号; 与监听器和处理器无关, 没有监听器的广播同样会被观察到; 返回取消函数
+// fn 收到的 Event.Data 为广播时负载, 在广播所在的 goroutine 中同步调用, 应尽快返回; 用于调试与运维工具的实时跟踪
+func (b *UniqueBroadcast[K, T]) Tap(signal string, fn func(e Event[any])) (cancel func()) {
+	return b.core.addTap("", signal, fn)
+}
@@ -0,0 +1,55 @@
+package broadcast
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTap_ObservesBroadcastsWithoutListeners(t *testing.T) {
+	b := New[string]()
+	ns := b.Namespace("tenant.")
+	var events []Event[any]
+	cancel := ns.Tap("orders", func(e Event[any]) {
+		events = append(events, e)
+	})
+
+	b.Broadcast("tenant.orders", map[string]interface{}{"n": 1}, WithEventID("evt-1"), WithSource("checkout"))
+	BroadcastData(b, "tenant.orders", 42, nil)
+	b.Broadcast("tenant.metrics", nil)
+	if len(events) != 2 {
+		t.Fatalf("expected two observed broadcasts, got %d", len(events))
+	}
+	if e := events[0]; e.Signal != "orders" || e.ID != "evt-1" || e.Source != "checkout" || e.Metadata["n"] != 1 {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if events[1].Data != 42 {
+		t.Errorf("expected the payload as Data, got %v", events[1].Data)
+	}
+
+	cancel()
+	cancel()
+	b.Broadcast("tenant.orders", nil)
+	if len(events) != 2 {
+		t.Error("expected no events after cancel")
+	}
+}
+
+func TestTap_AllSignals(t *testing.T) {
+	b := New[string]()
+	ns := b.Namespace("tenant.")
+	var all, scoped []string
+	cancel := b.Tap("", func(e Event[any]) { all = append(all, e.Signal) })
+	ns.Tap("", func(e Event[any]) { scoped = append(scoped, e.Signal) })
+
+	b.Broadcast("tenant.orders", nil)
+	b.Broadcast("metrics", nil)
+	cancel()
+	b.Broadcast("tenant.users", nil)
+
+	if !slices.Equal(all, []string{"tenant.orders", "metrics"}) {
+		t.Errorf("expected every signal until cancel, got %v", all)
+	}
+	if !slices.Equal(scoped, []string{"orders", "users"}) {
+		t.Errorf("expected the view to observe only its signals without the prefix, got %v", scoped)
+	}
+}
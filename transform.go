@@ -0,0 +1,33 @@
+package broadcast
+
+// Transform 在数据到达处理器之前改写监听器的值, 例如脱敏字段或补全默认值
+//
+// 复制语义: Transform 接收的是 Value() 返回值的副本, 返回值只用于本次投递,
+// 存储的监听器值永远不会被替换. 如果 T 实现了 Cloner, 会先调用 Clone 得到深拷贝;
+// 否则 T 中的指针、map、切片与存储值共享, Transform 不应原地修改它们
+type Transform[T any] func(signal string, data T, metadata map[string]interface{}) T
+
+// Cloner 由需要深拷贝的数据类型实现, Transform 执行前调用
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// transformAll 依次应用 transforms, 没有 Transform 时原样返回
+func transformAll[T any](transforms []Transform[T], signal string, data T, metadata map[string]interface{}) T {
+	if len(transforms) == 0 {
+		return data
+	}
+	if c, ok := any(data).(Cloner[T]); ok {
+		data = c.Clone()
+	}
+	for _, t := range transforms {
+		data = t(signal, data, metadata)
+	}
+	return data
+}
+
+func (c *core[K, T]) useTransform(t Transform[T]) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.transforms = append(s.transforms[:len(s.transforms):len(s.transforms)], t)
+	})
+}
@@ -0,0 +1,72 @@
+package broadcast
+
+import (
+	"maps"
+	"testing"
+	"unique"
+)
+
+type profile struct {
+	Name   string
+	Fields map[string]string
+}
+
+func (p profile) Clone() profile {
+	p.Fields = maps.Clone(p.Fields)
+	return p
+}
+
+func TestUseTransform_RedactsWithoutMutatingStoredValue(t *testing.T) {
+	b := NewUnique[string, profile]()
+	stored := profile{Name: "alice", Fields: map[string]string{"email": "alice@example.com"}}
+	b.Watch("profile", &testProfileUniquer{stored})
+
+	calls := 0
+	b.UseTransform(func(signal string, data profile, metadata map[string]interface{}) profile {
+		calls++
+		data.Fields["email"] = "<redacted>"
+		return data
+	})
+	b.UseTransform(func(signal string, data profile, metadata map[string]interface{}) profile {
+		if data.Fields["region"] == "" {
+			data.Fields["region"] = "default"
+		}
+		return data
+	})
+
+	var seen []profile
+	for i := 0; i < 2; i++ {
+		b.Handle(func(signal string, data profile, metadata map[string]interface{}) error {
+			seen = append(seen, data)
+			return nil
+		})
+	}
+	b.Broadcast("profile", nil)
+
+	if len(seen) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(seen))
+	}
+	for _, p := range seen {
+		if p.Fields["email"] != "<redacted>" || p.Fields["region"] != "default" {
+			t.Errorf("expected transformed value, got %v", p.Fields)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the transform to run once per listener, got %d", calls)
+	}
+	if stored.Fields["email"] != "alice@example.com" || len(stored.Fields) != 1 {
+		t.Errorf("stored listener value was mutated: %v", stored.Fields)
+	}
+}
+
+type testProfileUniquer struct {
+	p profile
+}
+
+func (u *testProfileUniquer) Unique() unique.Handle[string] {
+	return unique.Make(u.p.Name)
+}
+
+func (u *testProfileUniquer) Value() profile {
+	return u.p
+}
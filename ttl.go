@@ -0,0 +1,41 @@
+package broadcast
+
+import (
+	"time"
+)
+
+// WithEventTTL 设置事件的存活时间
+// 异步队列中等待超过 ttl 的事件会被丢弃并计入 Expired, 同步投递不受影响
+func WithEventTTL(ttl time.Duration) BroadcastOption {
+	return func(o *broadcastOptions) {
+		o.ttl = ttl
+	}
+}
+
+// expired 报告投递是否已超过其截止时间
+func (d *delivery[K, T]) expired(now time.Time) bool {
+	return !d.deadline.IsZero() && now.After(d.deadline)
+}
+
+// run 执行一次出队的异步投递, 已过期的投递被丢弃并计数
+func (c *core[K, T]) run(d delivery[K, T]) {
+	if d.expired(c.clock().Now()) {
+		c.expire(d)
+		return
+	}
+	_ = c.deliver(d)
+}
+
+// expire 丢弃一个已过期的投递, 计数并调用过期回调
+// 过期是有意的丢弃, 持久处理器确认该序号而不重新投递
+func (c *core[K, T]) expire(d delivery[K, T]) {
+	c.expired.Add(1)
+	c.undelivered(d, false)
+	settings := c.loadSettings()
+	if settings.metrics != nil {
+		settings.metrics.Dropped(d.signal)
+	}
+	if settings.onExpired != nil {
+		settings.onExpired(d.signal, d.metadata)
+	}
+}
@@ -0,0 +1,77 @@
+package broadcast
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestEventTTL_DropsExpiredQueuedEvents(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b, gate, received := gatedBroadcast(t, AsyncConfig{QueueSize: 4})
+	b.SetClock(clock)
+	var dropped []interface{}
+	b.OnExpired(func(signal string, metadata map[string]interface{}) {
+		dropped = append(dropped, metadata["seq"])
+	})
+
+	b.Broadcast("test", map[string]interface{}{"seq": 1}, WithEventTTL(time.Second))
+	b.Broadcast("test", map[string]interface{}{"seq": 2})
+	b.Broadcast("test", map[string]interface{}{"seq": 3}, WithEventTTL(time.Minute))
+
+	clock.now = clock.now.Add(2 * time.Second)
+	close(gate)
+	b.Close()
+
+	if got := received(); !slices.Equal(got, []int{0, 2, 3}) {
+		t.Errorf("expected [0 2 3], got %v", got)
+	}
+	if expired := b.Expired(); expired != 1 {
+		t.Errorf("expected 1 expired event, got %d", expired)
+	}
+	if !slices.Equal(dropped, []interface{}{1}) {
+		t.Errorf("expected the drop callback for seq 1, got %v", dropped)
+	}
+}
+
+func TestEventTTL_BufferedEventsExpire(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b := New[string]()
+	b.SetClock(clock)
+	b.SetPendingBuffer(4)
+	var dropped []string
+	b.OnExpired(func(signal string, metadata map[string]interface{}) {
+		dropped = append(dropped, signal)
+	})
+
+	b.Broadcast("test", nil, WithEventTTL(time.Second))
+	clock.now = clock.now.Add(2 * time.Second)
+	calls := 0
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		calls++
+		return nil
+	})
+	b.Watch("test", "a")
+
+	if calls != 0 || !slices.Equal(dropped, []string{"test"}) {
+		t.Errorf("expected the stale buffered event to be dropped, got %d calls and %v", calls, dropped)
+	}
+}
+
+func TestEventTTL_SyncDeliveryIgnoresTTL(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	b := NewUnique[int, TestUniqueData]()
+	b.SetClock(clock)
+
+	calls := 0
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		calls++
+		return nil
+	})
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}})
+
+	b.Broadcast("test", nil, WithEventTTL(time.Nanosecond))
+	if calls != 1 || b.Expired() != 0 {
+		t.Errorf("expected synchronous delivery, got %d calls and %d expired", calls, b.Expired())
+	}
+}
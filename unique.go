@@ -1,7 +1,8 @@
 package broadcast
 
 import (
-	"sync"
+	"context"
+	"time"
 	"unique"
 )
 
@@ -16,131 +17,323 @@ type UniqueHandler[K comparable, T any] func(signal string, data T, metadata map
 
 // UniqueBroadcast 实现了对 Uniquer 类型数据的广播功能
 type UniqueBroadcast[K comparable, T any] struct {
-	mu        sync.RWMutex
-	handlers  []UniqueHandler[K, T]
-	listeners map[string][]Uniquer[K, T]
+	core core[K, T]
 }
 
-// Handle 注册一个处理器
-func (b *UniqueBroadcast[K, T]) Handle(handler UniqueHandler[K, T]) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+// Handle 注册一个处理器, 返回的 HandlerID 可用于 Unhandle
+func (b *UniqueBroadcast[K, T]) Handle(handler UniqueHandler[K, T], opts ...HandleOption) HandlerID {
+	return b.core.handle("", handlerFunc[T](handler), opts...)
+}
 
-	if b.handlers == nil {
-		b.handlers = make([]UniqueHandler[K, T], 0)
-	}
-	b.handlers = append(b.handlers, handler)
+// HandleEvent 注册一个以 Event 信封接收广播的处理器, 返回的 HandlerID 可用于 Unhandle
+func (b *UniqueBroadcast[K, T]) HandleEvent(handler EventHandler[T], opts ...HandleOption) HandlerID {
+	return b.core.handleEvent("", eventHandlerFunc[T](handler), opts...)
 }
 
-// Watch 监听一个信号
-func (b *UniqueBroadcast[K, T]) Watch(signal string, data Uniquer[K, T]) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+// Unhandle 移除一个处理器, 返回处理器是否存在
+// 正在进行的广播仍会使用移除前的处理器快照
+func (b *UniqueBroadcast[K, T]) Unhandle(id HandlerID) bool {
+	return b.core.unhandle(id)
+}
 
-	if b.listeners == nil {
-		b.listeners = make(map[string][]Uniquer[K, T])
-	}
+// HandleAfterReplay 注册一个处理器, 该处理器在 ReplayGate.Done 之前不接收实时事件
+// 调用方先将历史事件直接交给处理器回放, 再调用 Done; 期间到达的实时事件在 Done 时按顺序补投
+func (b *UniqueBroadcast[K, T]) HandleAfterReplay(handler UniqueHandler[K, T]) *ReplayGate {
+	return b.core.handleAfterReplay("", handlerFunc[T](handler))
+}
 
-	listeners := b.listeners[signal]
-	handle := data.Unique()
-	for _, listener := range listeners {
-		if listener.Unique() == handle {
-			return
-		}
+// Watch 监听一个信号, 返回监听器是否被添加, 可以通过 WithRate 限制监听器接收投递的速率
+// 相同 key 已在监听该信号, 或严格模式的注册表拒绝了该信号时返回 false
+func (b *UniqueBroadcast[K, T]) Watch(signal string, data Uniquer[K, T], opts ...WatchOption) bool {
+	ok, _ := b.core.watchCtx(nil, signal, newListener(data).withOptions(opts))
+	return ok
+}
+
+// UpdateWatch 监听一个信号, 如果相同 key 已存在则替换为新的 data
+// updated 为 true 表示替换了已有的监听器, false 表示新增; 授权失败时返回授权器的错误,
+// 严格模式的注册表拒绝时返回 ErrUndeclaredSignal, 校验失败时返回包装了 ErrValidation 的错误
+func (b *UniqueBroadcast[K, T]) UpdateWatch(signal string, data Uniquer[K, T]) (updated bool, err error) {
+	return b.core.upsert(signal, newListener(data))
+}
+
+// Get 返回指定 key 在信号上的监听器值
+func (b *UniqueBroadcast[K, T]) Get(signal string, key K) (T, bool) {
+	if l, ok := b.core.lookup(signal, unique.Make(key)); ok {
+		return l.Value(), true
 	}
+	var zero T
+	return zero, false
+}
 
-	// 创建新的切片以避免共享底层数组
-	newListeners := make([]Uniquer[K, T], len(listeners)+1)
-	copy(newListeners, listeners)
-	newListeners[len(listeners)] = data
-	b.listeners[signal] = newListeners
+// Has 返回指定 key 是否正在监听信号
+func (b *UniqueBroadcast[K, T]) Has(signal string, key K) bool {
+	_, ok := b.core.lookup(signal, unique.Make(key))
+	return ok
 }
 
-// Unwatch 取消监听一个信号
-func (b *UniqueBroadcast[K, T]) Unwatch(signal string, data Uniquer[K, T]) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+// WatchContext 监听一个信号, 并在 ctx 取消时自动取消监听
+// 如果相同 key 已在监听该信号, 则不做任何事
+func (b *UniqueBroadcast[K, T]) WatchContext(ctx context.Context, signal string, data Uniquer[K, T]) {
+	b.core.watchContext(ctx, signal, newListener(data))
+}
 
-	listeners := b.listeners[signal]
-	if listeners == nil {
-		return
-	}
+// WatchGroup 监听一个信号, 并将监听器记录到 group 中, 以便通过 UnwatchGroup 一次性移除
+// 如果相同 key 已在监听该信号, 则不做任何事
+func (b *UniqueBroadcast[K, T]) WatchGroup(group string, signal string, data Uniquer[K, T]) {
+	b.core.watchGroup(group, signal, newListener(data))
+}
 
-	handle := data.Unique()
-	for i, item := range listeners {
-		if item.Unique() == handle {
-			// 创建新的切片以避免共享底层数组
-			newListeners := make([]Uniquer[K, T], 0, len(listeners)-1)
-			newListeners = append(newListeners, listeners[:i]...)
-			newListeners = append(newListeners, listeners[i+1:]...)
-			b.listeners[signal] = newListeners
-			break
-		}
-	}
+// UnwatchGroup 移除 group 中的所有监听器, 返回实际移除的数量
+func (b *UniqueBroadcast[K, T]) UnwatchGroup(group string) int {
+	return b.core.unwatchGroup(group)
+}
+
+// Unwatch 取消监听一个信号, 返回是否有监听器被移除
+func (b *UniqueBroadcast[K, T]) Unwatch(signal string, data Uniquer[K, T]) bool {
+	ok, _ := b.core.unwatchCtx(nil, signal, data.Unique())
+	return ok
+}
+
+// UnwatchKey 取消指定 key 对信号的监听, 无需构造 Uniquer, 返回是否有监听器被移除
+func (b *UniqueBroadcast[K, T]) UnwatchKey(signal string, key K) bool {
+	ok, _ := b.core.unwatchCtx(nil, signal, unique.Make(key))
+	return ok
+}
+
+// SignalsOf 返回指定 key 正在监听的信号, 按字典序排列
+func (b *UniqueBroadcast[K, T]) SignalsOf(key K) []string {
+	return b.core.signalsOf(unique.Make(key))
+}
+
+// UnwatchAll 从所有信号中移除指定 key 的监听器, 返回被移除的信号数量
+// 用于用户断开连接时一次性取消其所有订阅
+func (b *UniqueBroadcast[K, T]) UnwatchAll(key K) int {
+	return b.core.unwatchAll(unique.Make(key))
 }
 
 // Broadcast 广播一个信号
-func (b *UniqueBroadcast[K, T]) Broadcast(signal string, metadata map[string]interface{}) {
-	// 获取快照以减少锁持有时间
-	b.mu.RLock()
-	listeners := make([]Uniquer[K, T], len(b.listeners[signal]))
-	copy(listeners, b.listeners[signal])
-	handlers := make([]UniqueHandler[K, T], len(b.handlers))
-	copy(handlers, b.handlers)
-	b.mu.RUnlock()
-
-	// 使用快照数据执行回调
-	for _, handler := range handlers {
-		for _, data := range listeners {
-			// 创建数据副本以避免并发访问
-			dataCopy := data.Value()
-			_ = handler(signal, dataCopy, metadata)
-		}
-	}
+// 处理器在监听器快照上执行, 不持有任何锁
+// 同步投递时返回所有处理器错误的组合, 异步投递时入队成功即返回 nil
+func (b *UniqueBroadcast[K, T]) Broadcast(signal string, metadata map[string]interface{}, opts ...BroadcastOption) error {
+	return b.core.broadcast(signal, metadata, opts...)
+}
+
+// Expired 返回因超过 TTL 而被丢弃的异步投递数量
+func (b *UniqueBroadcast[K, T]) Expired() uint64 {
+	return b.core.expired.Load()
+}
+
+// OnError 设置错误回调, 每个处理器错误都会调用一次, 包括异步投递中的错误, 传入 nil 取消
+// 回调在投递所在的 goroutine 中同步执行
+func (b *UniqueBroadcast[K, T]) OnError(fn func(signal string, data T, handlerID HandlerID, err error)) {
+	b.core.updateSettings(func(s *settings[K, T]) {
+		s.onError = fn
+	})
+}
+
+// OnExpired 设置过期回调, 在异步队列或暂存缓冲中的事件因超过 TTL 被丢弃时调用, 传入 nil 取消
+func (b *UniqueBroadcast[K, T]) OnExpired(fn func(signal string, metadata map[string]interface{})) {
+	b.core.updateSettings(func(s *settings[K, T]) {
+		s.onExpired = fn
+	})
 }
 
 // HasWatch 检查指定信号是否有监听器
 func (b *UniqueBroadcast[K, T]) HasWatch(signal string) bool {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
-
-	listeners, exists := b.listeners[signal]
-	return exists && len(listeners) > 0
+	return b.core.hasWatch(signal)
 }
 
 // WatchCount 返回指定信号的监听器数量
 func (b *UniqueBroadcast[K, T]) WatchCount(signal string) int {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
+	return b.core.watchCount(signal)
+}
 
-	return len(b.listeners[signal])
+// LastBroadcast 返回信号最近一次广播的时间, 从未广播时为零值
+func (b *UniqueBroadcast[K, T]) LastBroadcast(signal string) time.Time {
+	return b.core.lastBroadcast(signal)
+}
+
+// LastWatchChange 返回信号最近一次添加或移除监听器的时间, 从未变化时为零值
+func (b *UniqueBroadcast[K, T]) LastWatchChange(signal string) time.Time {
+	return b.core.lastWatchChange(signal)
+}
+
+// WaitFor 阻塞直到每个信号都至少被广播过一次, 用于启动时等待配置等必要事件
+// 在调用之前已经广播过的信号视为已满足, ctx 结束时返回 ctx.Err()
+func (b *UniqueBroadcast[K, T]) WaitFor(ctx context.Context, signals ...string) error {
+	return b.core.waitFor(ctx, signals...)
+}
+
+// SetPriority 设置信号的优先级, 只影响异步投递
+// PriorityHigh 的广播在 AsyncConfig.PriorityWorkers 大于 0 时进入独立通道, PriorityLow 的广播在队列已满时被丢弃
+func (b *UniqueBroadcast[K, T]) SetPriority(signal string, p Priority) {
+	b.core.setPriority(signal, p)
+}
+
+// SetRegistry 设置信号注册表, 传入 nil 关闭检查
+// 严格模式下未声明信号上的 Broadcast 返回 ErrUndeclaredSignal, Watch 不添加监听器, 并调用 OnViolation
+func (b *UniqueBroadcast[K, T]) SetRegistry(config *RegistryConfig) {
+	b.core.setRegistry(config)
+}
+
+// SetRateLimiter 设置信号级限流器, 以信号名为 key
+// 被限流的广播不会投递并返回 ErrRateLimited, 传入 nil 取消限流
+func (b *UniqueBroadcast[K, T]) SetRateLimiter(limiter RateLimiter) {
+	b.core.updateSettings(func(s *settings[K, T]) {
+		s.limiter = limiter
+	})
+}
+
+// EnableAsync 开启异步投递, Broadcast 只将事件放入有界队列后立即返回
+// 重复调用会替换原有队列, 原队列中的事件会先投递完成
+func (b *UniqueBroadcast[K, T]) EnableAsync(config AsyncConfig) {
+	b.core.enableAsync(config)
+}
+
+// Close 关闭异步投递并等待队列中的事件投递完成, 之后的广播同步执行
+func (b *UniqueBroadcast[K, T]) Close() {
+	b.core.close()
+}
+
+// Pending 返回异步队列中等待投递的事件数量
+func (b *UniqueBroadcast[K, T]) Pending() int {
+	return b.core.pending()
+}
+
+// SetReceiptStore 设置投递回执存储, 之后每次投递都会记录一条回执
+// 传入 nil 停止记录
+func (b *UniqueBroadcast[K, T]) SetReceiptStore(store ReceiptStore[K]) {
+	b.core.updateSettings(func(s *settings[K, T]) {
+		s.receipts = store
+	})
+}
+
+// SetDeterministic 开启或关闭确定性投递模式, 用于需要断言投递顺序的测试
+// 开启后所有投递同步执行, 处理器按注册顺序执行;
+// seed 为 0 时监听器按注册顺序接收事件, 否则按种子打乱, 相同种子与相同操作序列得到相同顺序
+func (b *UniqueBroadcast[K, T]) SetDeterministic(enabled bool, seed uint64) {
+	b.core.setDeterministic(enabled, seed)
+}
+
+// UseTransform 追加一个在处理器之前改写数据的 Transform, 按添加顺序执行
+// 存储的监听器值不会被修改, 复制语义见 Transform
+func (b *UniqueBroadcast[K, T]) UseTransform(t Transform[T]) {
+	b.core.useTransform(t)
+}
+
+// SetPendingBuffer 启用暂存缓冲: 信号没有监听器或处理器时, 广播被暂存而不是丢失,
+// 在该信号出现第一个监听器或新增处理器时重新投递. 每个信号最多暂存 size 个, 超出时丢弃最早的,
+// size <= 0 时关闭缓冲并丢弃已暂存的广播
+func (b *UniqueBroadcast[K, T]) SetPendingBuffer(size int) {
+	b.core.setPendingBuffer(size)
+}
+
+// Buffered 返回暂存缓冲中的广播数量
+func (b *UniqueBroadcast[K, T]) Buffered() int {
+	return b.core.buffered()
+}
+
+// EnableStore 从 Store 恢复监听器, 然后开启写穿模式, 之后的监听器变化同步写入 Store
+// restore 根据解码后的 key 和值重建 Uniquer. 应在启动时、其他 Watch 之前调用
+func (b *UniqueBroadcast[K, T]) EnableStore(config StoreConfig, restore func(key K, value T) Uniquer[K, T]) error {
+	return b.core.enableStore(config, restore)
+}
+
+// EnableJournal 开启广播日志, 选定信号的广播在投递前写入日志, 写入失败时 Broadcast 返回错误且不投递
+// 广播序号从日志的最后一条记录之后继续
+func (b *UniqueBroadcast[K, T]) EnableJournal(config JournalConfig) error {
+	return b.core.enableJournal(config)
+}
+
+// ReplayJournal 重新投递日志中序号不小于 from 的广播, 用于崩溃后重建下游状态
+// 回放的广播不会再次写入日志, 负载以 *RawPayload 交给处理器
+func (b *UniqueBroadcast[K, T]) ReplayJournal(from uint64) error {
+	return b.core.replayJournal(from)
+}
+
+// HandleDurable 注册持久处理器, 提供至少一次投递: 日志中的广播在处理器对所有监听器成功后才确认,
+// 失败时按 RetryInterval 重新投递, 重启后从 config.Cursors 中的进度之后由日志重新投递. 需要先调用 EnableJournal
+func (b *UniqueBroadcast[K, T]) HandleDurable(config DurableConfig, handler UniqueHandler[K, T]) (HandlerID, error) {
+	return b.core.handleDurable(config, "", handlerFunc[T](handler))
+}
+
+// HandleIdempotent 注册幂等处理器, 已在 config.Store 中记录的事件不再交给处理器, 处理成功后记录事件
+// 事件 ID 由 WithEventID 设置, 未设置时使用广播序号
+func (b *UniqueBroadcast[K, T]) HandleIdempotent(config IdempotencyConfig, handler UniqueHandler[K, T]) HandlerID {
+	return b.core.handleIdempotent(config, "", handlerFunc[T](handler))
+}
+
+// SetClock 设置时间源, 默认为 SystemClock
+func (b *UniqueBroadcast[K, T]) SetClock(clock Clock) {
+	b.core.updateSettings(func(s *settings[K, T]) {
+		s.clock = clock
+	})
+}
+
+// EnableDeadLetter 开启死信队列, 处理器返回错误的投递会被放入有界队列
+// 队列满时最早的死信被挤出, 交给 Spill 并广播 SignalDLQOverflow 元事件
+func (b *UniqueBroadcast[K, T]) EnableDeadLetter(config DeadLetterConfig[K, T]) {
+	b.core.enableDeadLetter(config)
+}
+
+// DeadLetters 返回当前死信队列中的死信
+func (b *UniqueBroadcast[K, T]) DeadLetters() []DeadLetter[K, T] {
+	return b.core.deadLetters(false)
+}
+
+// DrainDeadLetters 返回并清空死信队列
+func (b *UniqueBroadcast[K, T]) DrainDeadLetters() []DeadLetter[K, T] {
+	return b.core.deadLetters(true)
+}
+
+// SetBreaker 设置生产者侧熔断器, 传入 nil 关闭熔断
+func (b *UniqueBroadcast[K, T]) SetBreaker(config *BreakerConfig) {
+	b.core.setBreaker(config)
+}
+
+// Healthy 返回信号的熔断器是否闭合, 未设置熔断器时始终为 true
+func (b *UniqueBroadcast[K, T]) Healthy(signal string) bool {
+	return b.core.healthy(signal)
+}
+
+// SetSampling 设置过载时的自适应采样, 传入 nil 关闭采样
+func (b *UniqueBroadcast[K, T]) SetSampling(config *SamplingConfig) {
+	b.core.setSampling(config)
+}
+
+// SamplingRate 返回低优先级信号当前的有效采样率, 未设置采样时为 1
+func (b *UniqueBroadcast[K, T]) SamplingRate() float64 {
+	return b.core.samplingRate()
 }
 
 // Clean 清除指定信号的所有监听器
 func (b *UniqueBroadcast[K, T]) Clean(signal string) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	delete(b.listeners, signal)
+	b.core.clean(signal)
 }
 
 // CleanAll 清除所有信号的监听器
 func (b *UniqueBroadcast[K, T]) CleanAll() {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+	b.core.cleanAll()
+}
 
-	b.listeners = make(map[string][]Uniquer[K, T])
+// CleanKeys 移除所有信号上 key 满足 pred 的监听器, 返回移除的监听器数量
+// 逐个信号执行, 适用于下线租户等需要批量清理的场景
+func (b *UniqueBroadcast[K, T]) CleanKeys(pred func(key K) bool) int {
+	return b.core.cleanKeys(pred)
 }
 
 // Range 遍历所有信号及其监听器数量
 // 如果 fn 返回 false，则停止遍历
 func (b *UniqueBroadcast[K, T]) Range(fn func(signal string, count int) bool) {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
+	b.core.rangeSignals(fn)
+}
 
-	for signal, listeners := range b.listeners {
-		if !fn(signal, len(listeners)) {
-			break
+// RangeEntries 遍历指定信号上的所有监听器的 key 和值
+// 遍历的是调用时的快照, 如果 fn 返回 false，则停止遍历
+func (b *UniqueBroadcast[K, T]) RangeEntries(signal string, fn func(key unique.Handle[K], value T) bool) {
+	for _, l := range b.core.snapshot(signal) {
+		if !fn(l.key, l.data.Value()) {
+			return
 		}
 	}
 }
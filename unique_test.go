@@ -1,7 +1,9 @@
 package broadcast
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"testing"
 	"unique"
@@ -58,8 +60,8 @@ func TestUniqueBroadcast_Watch(t *testing.T) {
 	b.Watch("test", data2) // Should not duplicate due to same ID
 	b.Watch("test", data3)
 
-	if len(b.listeners["test"]) != 2 {
-		t.Errorf("expected 2 listeners, got %d", len(b.listeners["test"]))
+	if b.WatchCount("test") != 2 {
+		t.Errorf("expected 2 listeners, got %d", b.WatchCount("test"))
 	}
 }
 
@@ -73,8 +75,8 @@ func TestUniqueBroadcast_Unwatch(t *testing.T) {
 	b.Watch("test", data2)
 	b.Unwatch("test", data1)
 
-	if len(b.listeners["test"]) != 1 {
-		t.Errorf("expected 1 listener after unwatch, got %d", len(b.listeners["test"]))
+	if b.WatchCount("test") != 1 {
+		t.Errorf("expected 1 listener after unwatch, got %d", b.WatchCount("test"))
 	}
 }
 
@@ -137,6 +139,172 @@ func TestUniqueBroadcast_MultipleHandlers(t *testing.T) {
 	}
 }
 
+func TestUniqueBroadcast_Metadata(t *testing.T) {
+	b := &UniqueBroadcast[int, TestUniqueData]{}
+	var got map[string]interface{}
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		got = metadata
+		return nil
+	})
+
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1, Name: "test"}})
+	if err := b.Broadcast("test", map[string]interface{}{"source": "race"}); err != nil {
+		t.Fatal(err)
+	}
+	if got["source"] != "race" {
+		t.Errorf("expected metadata to reach the handler, got %v", got)
+	}
+}
+
+func TestUniqueBroadcast_UpdateWatch(t *testing.T) {
+	b := &UniqueBroadcast[int, TestUniqueData]{}
+	var names []string
+	b.Handle(func(signal string, data TestUniqueData, metadata map[string]interface{}) error {
+		names = append(names, data.Name)
+		return nil
+	})
+
+	if updated, err := b.UpdateWatch("test", &TestUniquer{data: TestUniqueData{ID: 1, Name: "old"}}); updated || err != nil {
+		t.Errorf("expected first UpdateWatch to insert, got %v, %v", updated, err)
+	}
+	if updated, err := b.UpdateWatch("test", &TestUniquer{data: TestUniqueData{ID: 1, Name: "new"}}); !updated || err != nil {
+		t.Errorf("expected second UpdateWatch to update, got %v, %v", updated, err)
+	}
+	b.Broadcast("test", nil)
+
+	if b.WatchCount("test") != 1 || len(names) != 1 || names[0] != "new" {
+		t.Errorf("expected a single listener with the new value, got %v", names)
+	}
+}
+
+func TestUniqueBroadcast_UpdateWatchRejected(t *testing.T) {
+	r := NewRegistry()
+	r.Declare("test", "", nil)
+	b := NewUnique[int, TestUniqueData](WithRegistry(RegistryConfig{Registry: r, Strict: true}))
+
+	if _, err := b.UpdateWatch("tset", &TestUniquer{data: TestUniqueData{ID: 1}}); !errors.Is(err, ErrUndeclaredSignal) {
+		t.Errorf("expected ErrUndeclaredSignal, got %v", err)
+	}
+
+	b.SetAuthorizer(denyAll)
+	if _, err := b.UpdateWatch("test", &TestUniquer{data: TestUniqueData{ID: 1}}); !errors.Is(err, errForbidden) {
+		t.Errorf("expected the authorizer error, got %v", err)
+	}
+	if b.WatchCount("test") != 0 || b.WatchCount("tset") != 0 {
+		t.Error("expected rejected UpdateWatch calls not to add listeners")
+	}
+}
+
+func TestUniqueBroadcast_GetHas(t *testing.T) {
+	b := &UniqueBroadcast[int, TestUniqueData]{}
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1, Name: "one"}})
+
+	if data, ok := b.Get("test", 1); !ok || data.Name != "one" {
+		t.Errorf("expected key 1 to be found, got %v, %v", data, ok)
+	}
+	if _, ok := b.Get("test", 2); ok {
+		t.Error("expected key 2 to be missing")
+	}
+	if !b.Has("test", 1) || b.Has("other", 1) {
+		t.Error("expected Has to match only the watched signal")
+	}
+}
+
+func TestUniqueBroadcast_RangeEntries(t *testing.T) {
+	b := &UniqueBroadcast[int, TestUniqueData]{}
+	for i := 1; i <= 3; i++ {
+		b.Watch("test", &TestUniquer{data: TestUniqueData{ID: i, Name: fmt.Sprintf("test%d", i)}})
+	}
+
+	entries := map[int]string{}
+	b.RangeEntries("test", func(key unique.Handle[int], value TestUniqueData) bool {
+		entries[key.Value()] = value.Name
+		return true
+	})
+	if len(entries) != 3 || entries[2] != "test2" {
+		t.Errorf("expected all 3 entries, got %v", entries)
+	}
+
+	visited := 0
+	b.RangeEntries("test", func(key unique.Handle[int], value TestUniqueData) bool {
+		visited++
+		return false
+	})
+	if visited != 1 {
+		t.Errorf("expected range to stop after the first entry, got %d", visited)
+	}
+}
+
+func TestUniqueBroadcast_UnwatchKey(t *testing.T) {
+	b := &UniqueBroadcast[int, TestUniqueData]{}
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1, Name: "test1"}})
+	b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 2, Name: "test2"}})
+
+	if !b.UnwatchKey("test", 1) {
+		t.Error("expected key 1 to be removed")
+	}
+	if b.UnwatchKey("test", 1) {
+		t.Error("expected a second UnwatchKey to report nothing removed")
+	}
+	if b.Has("test", 1) || !b.Has("test", 2) {
+		t.Error("expected only key 1 to be removed")
+	}
+}
+
+func TestUniqueBroadcast_UnwatchAll(t *testing.T) {
+	b := &UniqueBroadcast[int, TestUniqueData]{}
+	for _, signal := range []string{"a", "b", "c"} {
+		b.Watch(signal, &TestUniquer{data: TestUniqueData{ID: 1, Name: "test1"}})
+	}
+	b.Watch("a", &TestUniquer{data: TestUniqueData{ID: 2, Name: "test2"}})
+
+	if n := b.UnwatchAll(1); n != 3 {
+		t.Errorf("expected key 1 removed from 3 signals, got %d", n)
+	}
+	if b.Has("a", 1) || b.Has("b", 1) || b.Has("c", 1) {
+		t.Error("expected key 1 to be gone from every signal")
+	}
+	if !b.Has("a", 2) {
+		t.Error("expected other keys to remain")
+	}
+	if n := b.UnwatchAll(1); n != 0 {
+		t.Errorf("expected nothing left to remove, got %d", n)
+	}
+}
+
+func TestUniqueBroadcast_SignalsOf(t *testing.T) {
+	b := &UniqueBroadcast[int, TestUniqueData]{}
+	data := &TestUniquer{data: TestUniqueData{ID: 1, Name: "test1"}}
+	for _, signal := range []string{"c", "a", "b", "d"} {
+		b.Watch(signal, data)
+	}
+	b.Watch("e", &TestUniquer{data: TestUniqueData{ID: 2, Name: "test2"}})
+
+	if got := b.SignalsOf(1); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("expected [a b c d], got %v", got)
+	}
+
+	b.Unwatch("a", data)
+	b.Clean("b")
+	if got := b.SignalsOf(1); !slices.Equal(got, []string{"c", "d"}) {
+		t.Errorf("expected [c d] after Unwatch and Clean, got %v", got)
+	}
+
+	b.CleanKeys(func(key int) bool { return key == 1 })
+	if got := b.SignalsOf(1); len(got) != 0 {
+		t.Errorf("expected no signals after CleanKeys, got %v", got)
+	}
+
+	b.CleanAll()
+	b.Watch("f", data)
+	if got := b.SignalsOf(2); len(got) != 0 {
+		t.Errorf("expected CleanAll to clear the index, got %v", got)
+	}
+	if got := b.SignalsOf(1); !slices.Equal(got, []string{"f"}) {
+		t.Errorf("expected [f] after re-watch, got %v", got)
+	}
+}
+
 func TestUniqueBroadcast_HasWatch(t *testing.T) {
 	b := &UniqueBroadcast[int, TestUniqueData]{}
 
@@ -207,30 +375,35 @@ func TestUniqueBroadcast_Clean(t *testing.T) {
 
 	// 验证初始状态
 	for _, signal := range signals {
-		if len(b.listeners[signal]) != 3 {
-			t.Errorf("expected 3 listeners for signal %s, got %d", signal, len(b.listeners[signal]))
+		if b.WatchCount(signal) != 3 {
+			t.Errorf("expected 3 listeners for signal %s, got %d", signal, b.WatchCount(signal))
 		}
 	}
 
 	// 测试清除单个信号
 	b.Clean("test1")
-	if _, exists := b.listeners["test1"]; exists {
+	if b.HasWatch("test1") {
 		t.Error("listeners for test1 should be removed")
 	}
-	if len(b.listeners["test2"]) != 3 {
+	if b.WatchCount("test2") != 3 {
 		t.Error("listeners for test2 should remain unchanged")
 	}
 
 	// 测试 CleanAll
 	b.CleanAll()
-	if len(b.listeners) != 0 {
+	remaining := 0
+	b.Range(func(signal string, count int) bool {
+		remaining++
+		return true
+	})
+	if remaining != 0 {
 		t.Error("all listeners should be removed after CleanAll")
 	}
 
 	// 测试清除后重新添加
 	data := &TestUniquer{data: TestUniqueData{ID: 1, Name: "test"}}
 	b.Watch("test", data)
-	if len(b.listeners["test"]) != 1 {
+	if b.WatchCount("test") != 1 {
 		t.Error("should be able to add listeners after cleaning")
 	}
 }
@@ -431,19 +604,71 @@ func BenchmarkUniqueBroadcast_Range(b *testing.B) {
 func TestNewUnique(t *testing.T) {
 	b := NewUnique[int, string]()
 
-	if b.handlers == nil {
-		t.Error("handlers should be initialized")
+	if b.HasWatch("test") {
+		t.Error("new broadcast should have no watchers")
 	}
 
-	if b.listeners == nil {
-		t.Error("listeners should be initialized")
+	signals := 0
+	b.Range(func(signal string, count int) bool {
+		signals++
+		return true
+	})
+	if signals != 0 {
+		t.Error("listeners should be empty")
 	}
 
-	if len(b.handlers) != 0 {
-		t.Error("handlers should be empty")
+	// 没有处理器时广播不应 panic
+	b.Broadcast("test", nil)
+}
+
+func TestUniqueBroadcast_CleanKeys(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+
+	// ID 以 100 为界划分两个租户
+	for _, signal := range []string{"a", "b", "c"} {
+		for _, id := range []int{1, 2, 101, 102} {
+			b.Watch(signal, &TestUniquer{data: TestUniqueData{ID: id}})
+		}
 	}
 
-	if len(b.listeners) != 0 {
-		t.Error("listeners should be empty")
+	removed := b.CleanKeys(func(key int) bool { return key >= 100 })
+	if removed != 6 {
+		t.Errorf("expected 6 listeners removed, got %d", removed)
+	}
+
+	for _, signal := range []string{"a", "b", "c"} {
+		if count := b.WatchCount(signal); count != 2 {
+			t.Errorf("signal %s: expected 2 remaining watchers, got %d", signal, count)
+		}
+	}
+
+	if removed := b.CleanKeys(func(key int) bool { return key >= 100 }); removed != 0 {
+		t.Errorf("second CleanKeys should remove nothing, got %d", removed)
+	}
+}
+
+func TestUniqueBroadcast_WatchUnwatchResult(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	if !b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1, Name: "a"}}) {
+		t.Error("expected the first Watch to add the listener")
+	}
+	if b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1, Name: "b"}}) {
+		t.Error("expected a Watch with the same key to be deduplicated")
+	}
+	if !b.Unwatch("test", &TestUniquer{data: TestUniqueData{ID: 1}}) || b.Unwatch("test", &TestUniquer{data: TestUniqueData{ID: 1}}) {
+		t.Error("expected Unwatch to report true once")
+	}
+
+	b.SetRegistry(&RegistryConfig{Registry: NewRegistry(), Strict: true})
+	if b.Watch("test", &TestUniquer{data: TestUniqueData{ID: 2}}) {
+		t.Error("expected strict mode to reject an undeclared signal")
+	}
+
+	f := NewFanout[int, TestUniqueData](2)
+	if !f.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}}) || f.Watch("test", &TestUniquer{data: TestUniqueData{ID: 1}}) {
+		t.Error("expected Fanout.Watch to report additions")
+	}
+	if !f.Unwatch("test", &TestUniquer{data: TestUniqueData{ID: 1}}) || f.Unwatch("test", &TestUniquer{data: TestUniqueData{ID: 1}}) {
+		t.Error("expected Fanout.Unwatch to report removals")
 	}
 }
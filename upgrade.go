@@ -0,0 +1,60 @@
+package broadcast
+
+import (
+	"fmt"
+)
+
+// Upgrader 将旧版本的日志负载迁移为当前的负载类型, raw 为日志中记录的经过 Codec 编码的字节
+type Upgrader func(version int, raw []byte) (any, error)
+
+// WithUpgrader 设置回放日志时的负载迁移, 等同于构造后调用 SetUpgrader
+// 回放 Version 低于 JournalConfig.Version 的记录时调用 fn, 返回的 P 直接作为广播时负载交给 HandleData 注册的处理器,
+// 使负载结构演进后旧格式的事件仍然可以回放; 当前版本的记录仍以 *RawPayload 交给处理器
+func WithUpgrader[P any](fn func(version int, raw []byte) (P, error)) Option {
+	return func(o *options) {
+		o.upgrader = upgraderOf(fn)
+	}
+}
+
+func upgraderOf[P any](fn func(version int, raw []byte) (P, error)) Upgrader {
+	if fn == nil {
+		return nil
+	}
+	return func(version int, raw []byte) (any, error) {
+		return fn(version, raw)
+	}
+}
+
+func (c *core[K, T]) setUpgrader(u Upgrader) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.upgrader = u
+	})
+}
+
+// journalPayload 返回回放记录的负载, 旧版本的记录经过迁移
+func (c *core[K, T]) journalPayload(b *journalBinding, e JournalEntry) (any, error) {
+	if e.Payload == nil {
+		return nil, nil
+	}
+	if up := c.loadSettings().upgrader; up != nil && e.Version < b.config.Version {
+		payload, err := up(e.Version, e.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("broadcast: upgrade entry %d from version %d: %w", e.Seq, e.Version, err)
+		}
+		return payload, nil
+	}
+	return NewRawPayload(e.Payload, b.config.Codec), nil
+}
+
+// SetUpgrader 设置回放日志时的负载迁移, 传入 nil 关闭; 与 WithUpgrader 相同
+func SetUpgrader[P any](b Broadcaster, fn func(version int, raw []byte) (P, error)) {
+	b.setUpgrader(upgraderOf(fn))
+}
+
+func (b *Broadcast[T]) setUpgrader(u Upgrader) {
+	b.c().setUpgrader(u)
+}
+
+func (b *UniqueBroadcast[K, T]) setUpgrader(u Upgrader) {
+	b.core.setUpgrader(u)
+}
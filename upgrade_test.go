@@ -0,0 +1,104 @@
+package broadcast
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+type orderV1 struct {
+	Amount int `json:"amount"`
+}
+
+type orderV2 struct {
+	Cents    int    `json:"cents"`
+	Currency string `json:"currency"`
+}
+
+func TestUpgrader_ReplayOldVersions(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := New[string]()
+	b.EnableJournal(JournalConfig{Journal: journal})
+	BroadcastData(b, "orders", orderV1{Amount: 3}, nil)
+	journal.Close()
+
+	journal, err = OpenFileJournal(dir, FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	var versions []int
+	restarted := New[string](WithUpgrader(func(version int, raw []byte) (orderV2, error) {
+		versions = append(versions, version)
+		var v1 orderV1
+		if err := json.Unmarshal(raw, &v1); err != nil {
+			return orderV2{}, err
+		}
+		return orderV2{Cents: v1.Amount * 100, Currency: "USD"}, nil
+	}))
+	restarted.Watch("orders", "projection")
+	var got []orderV2
+	HandleRaw(restarted, func(signal string, data string, payload *RawPayload, metadata map[string]interface{}) error {
+		if payload == nil {
+			return nil
+		}
+		v, err := Decode[orderV2](payload)
+		got = append(got, v)
+		return err
+	})
+	HandleData(restarted, func(signal string, data string, payload orderV2, metadata map[string]interface{}) error {
+		if payload != (orderV2{}) {
+			got = append(got, payload)
+		}
+		return nil
+	})
+	if err := restarted.EnableJournal(JournalConfig{Journal: journal, Version: 2}); err != nil {
+		t.Fatal(err)
+	}
+	BroadcastData(restarted, "orders", orderV2{Cents: 250, Currency: "EUR"}, nil)
+	got = nil
+
+	if err := restarted.ReplayJournal(0); err != nil {
+		t.Fatal(err)
+	}
+	// 旧记录迁移后交给 HandleData, 当前版本的记录仍以 *RawPayload 交给 HandleRaw
+	want := []orderV2{{Cents: 300, Currency: "USD"}, {Cents: 250, Currency: "EUR"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if !slices.Equal(versions, []int{0}) {
+		t.Errorf("expected only the version 0 entry to be upgraded, got %v", versions)
+	}
+}
+
+func TestUpgrader_Error(t *testing.T) {
+	journal, err := OpenFileJournal(t.TempDir(), FileJournalConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	errBroken := errors.New("broken")
+	b := New[string]()
+	b.EnableJournal(JournalConfig{Journal: journal})
+	BroadcastData(b, "orders", orderV1{Amount: 1}, nil)
+
+	b.EnableJournal(JournalConfig{Journal: journal, Version: 1})
+	SetUpgrader(b, func(version int, raw []byte) (orderV2, error) {
+		return orderV2{}, errBroken
+	})
+	if err := b.ReplayJournal(0); !errors.Is(err, errBroken) {
+		t.Errorf("expected the upgrade error to be returned, got %v", err)
+	}
+
+	SetUpgrader[orderV2](b, nil)
+	if err := b.ReplayJournal(0); err != nil {
+		t.Errorf("expected raw replay after SetUpgrader(nil), got %v", err)
+	}
+}
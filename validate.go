@@ -0,0 +1,74 @@
+package broadcast
+
+import (
+	"fmt"
+)
+
+// validate 使用 Watch 校验器检查监听器的数据
+func (c *core[K, T]) validate(signal string, data T) error {
+	v := c.loadSettings().validator
+	if v == nil {
+		return nil
+	}
+	if err := v(signal, data); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	return nil
+}
+
+func validatePayload(v func(signal string, payload any) error, signal string, payload any) error {
+	if err := v(signal, payload); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	return nil
+}
+
+func (c *core[K, T]) setValidator(v func(signal string, data T) error) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.validator = v
+	})
+}
+
+func (c *core[K, T]) setPayloadValidator(v func(signal string, payload any) error) {
+	c.updateSettings(func(s *settings[K, T]) {
+		s.payloadValidator = v
+	})
+}
+
+// WithValidator 设置 Watch 的校验器, 等同于构造后调用 SetValidator
+// T 必须与广播器的数据类型一致 (UniqueBroadcast 为 Value 的类型), 否则构造时 panic
+func WithValidator[T any](v func(signal string, data T) error) Option {
+	return func(o *options) {
+		o.validator = v
+	}
+}
+
+// WithPayloadValidator 设置广播时负载的校验器, 等同于构造后调用 SetPayloadValidator
+func WithPayloadValidator(v func(signal string, payload any) error) Option {
+	return func(o *options) {
+		o.payloadValidator = v
+	}
+}
+
+// SetValidator 设置 Watch 的校验器, 传入 nil 关闭
+// 校验失败的 Watch、UpdateWatch、WatchGroup、WatchIfAbsent 等添加操作不生效, WatchCtx 与 UpdateWatch 返回包装了 ErrValidation 的错误,
+// 使格式错误的数据在边界处被拒绝, 而不是在每个处理器中出错
+func (b *Broadcast[T]) SetValidator(v func(signal string, data T) error) {
+	b.c().setValidator(v)
+}
+
+// SetPayloadValidator 设置广播时负载的校验器, 传入 nil 关闭
+// 携带负载 (BroadcastData、PublishAll 等) 的广播校验失败时返回包装了 ErrValidation 的错误且不投递
+func (b *Broadcast[T]) SetPayloadValidator(v func(signal string, payload any) error) {
+	b.c().setPayloadValidator(v)
+}
+
+// SetValidator 设置 Watch 的校验器, data 为监听器的 Value, 与 Broadcast.SetValidator 相同
+func (b *UniqueBroadcast[K, T]) SetValidator(v func(signal string, data T) error) {
+	b.core.setValidator(v)
+}
+
+// SetPayloadValidator 设置广播时负载的校验器, 与 Broadcast.SetPayloadValidator 相同
+func (b *UniqueBroadcast[K, T]) SetPayloadValidator(v func(signal string, payload any) error) {
+	b.core.setPayloadValidator(v)
+}
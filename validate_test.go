@@ -0,0 +1,131 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var errMalformed = errors.New("malformed")
+
+func emailValidator(signal string, data string) error {
+	if !strings.Contains(data, "@") {
+		return errMalformed
+	}
+	return nil
+}
+
+func TestValidator_RejectsMalformedWatch(t *testing.T) {
+	b := New[string](WithValidator(emailValidator))
+
+	if b.Watch("signup", "nobody") {
+		t.Error("expected malformed data to be rejected")
+	}
+	if !b.Watch("signup", "a@example.com") {
+		t.Error("expected valid data to be accepted")
+	}
+	_, err := b.WatchCtx(context.Background(), "signup", "still-nobody")
+	if !errors.Is(err, ErrValidation) || !errors.Is(err, errMalformed) {
+		t.Errorf("expected the validator error wrapped in ErrValidation, got %v", err)
+	}
+	b.WatchGroup("g", "signup", "bad")
+	if err := b.Batch().Watch("signup", "also-bad").Commit(); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected the batch to be rejected, got %v", err)
+	}
+	if n := b.WatchCount("signup"); n != 1 {
+		t.Errorf("expected only the valid listener, got %d", n)
+	}
+
+	b.SetValidator(nil)
+	if !b.Watch("signup", "nobody") {
+		t.Error("expected no validation after SetValidator(nil)")
+	}
+}
+
+func TestValidator_UniqueAndPayload(t *testing.T) {
+	b := NewUnique[string, string](WithPayloadValidator(func(signal string, payload any) error {
+		if n, ok := payload.(int); ok && n < 0 {
+			return errMalformed
+		}
+		return nil
+	}))
+	b.SetValidator(emailValidator)
+
+	if _, err := b.UpdateWatch("users", owner{"u1", "bad"}); b.Watch("users", owner{"u1", "bad"}) || err == nil {
+		t.Error("expected the Value of a Uniquer to be validated")
+	}
+	if !b.Watch("users", owner{"u1", "u1@example.com"}) {
+		t.Fatal("expected a valid listener")
+	}
+
+	var got []int
+	HandleData(b, func(signal string, data string, payload int, metadata map[string]interface{}) error {
+		got = append(got, payload)
+		return nil
+	})
+	if err := BroadcastData(b, "users", -1, nil); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected an invalid payload to be rejected, got %v", err)
+	}
+	if err := BroadcastData(b, "users", 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Broadcast("users", nil); err != nil {
+		t.Errorf("expected broadcasts without payload to skip payload validation, got %v", err)
+	}
+	if len(got) != 2 || got[0] != 1 {
+		t.Errorf("expected only valid payloads delivered, got %v", got)
+	}
+}
+
+func TestWithValidator_TypeMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a mismatched validator type to panic")
+		}
+	}()
+	New[int](WithValidator(emailValidator))
+}
+
+func TestValidator_UpdateWatchRejected(t *testing.T) {
+	b := NewUnique[string, string](WithValidator(emailValidator))
+	if !b.Watch("users", owner{"u1", "u1@example.com"}) {
+		t.Fatal("expected a valid listener")
+	}
+
+	updated, err := b.UpdateWatch("users", owner{"u1", "bad"})
+	if updated || !errors.Is(err, ErrValidation) || !errors.Is(err, errMalformed) {
+		t.Errorf("expected the validator error wrapped in ErrValidation, got %v, %v", updated, err)
+	}
+	if v, _ := b.Get("users", "u1"); v != "u1@example.com" {
+		t.Errorf("expected the existing listener to be kept, got %q", v)
+	}
+	if _, err := b.UpdateWatch("users", owner{"u2", "bad"}); !errors.Is(err, ErrValidation) || b.Has("users", "u2") {
+		t.Errorf("expected an invalid insert to be rejected, got %v", err)
+	}
+
+	if updated, err := b.UpdateWatch("users", owner{"u1", "new@example.com"}); !updated || err != nil {
+		t.Errorf("expected a valid update, got %v, %v", updated, err)
+	}
+}
+
+func TestValidator_CompareAndSwap(t *testing.T) {
+	b := NewUnique[string, string](WithValidator(emailValidator))
+
+	if _, added := b.WatchIfAbsent("users", owner{"u1", "bad"}); added || b.Has("users", "u1") {
+		t.Error("expected WatchIfAbsent to validate data")
+	}
+	if _, added := b.WatchIfAbsent("users", owner{"u1", "u1@example.com"}); !added {
+		t.Fatal("expected a valid listener")
+	}
+	always := func(string) bool { return true }
+	if b.CompareAndSwapWatch("users", owner{"u1", "bad"}, always) {
+		t.Error("expected CompareAndSwapWatch to validate data")
+	}
+	if v, _ := b.Get("users", "u1"); v != "u1@example.com" {
+		t.Errorf("expected the existing listener to be kept, got %q", v)
+	}
+	if !b.CompareAndSwapWatch("users", owner{"u1", "new@example.com"}, always) {
+		t.Error("expected a valid swap")
+	}
+}
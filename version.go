@@ -0,0 +1,147 @@
+package broadcast
+
+import (
+	"slices"
+)
+
+// defaultUndoLog 每个信号默认保留的历史版本数量
+const defaultUndoLog = 16
+
+// listenerVersion 是监听器集合的一个历史版本
+// 监听器切片不可变, 保存切片本身即可
+type listenerVersion[K comparable, T any] struct {
+	version   uint64
+	listeners []listener[K, T]
+}
+
+// undoLimit 返回每个信号保留的历史版本数量
+func (c *core[K, T]) undoLimit() int {
+	switch n := c.loadSettings().undoLog; {
+	case n < 0:
+		return 0
+	case n == 0:
+		return defaultUndoLog
+	default:
+		return n
+	}
+}
+
+// setUndoLog 设置每个信号保留的历史版本数量, n <= 0 时不保留历史
+func (c *core[K, T]) setUndoLog(n int) {
+	if n <= 0 {
+		n = -1
+	}
+	c.updateSettings(func(s *settings[K, T]) {
+		s.undoLog = n
+	})
+}
+
+// commitEntry 在信号锁内替换监听器切片, 并把替换前的版本记入有界的撤销日志
+func (c *core[K, T]) commitEntry(e *signalEntry[K, T], listeners []listener[K, T]) {
+	if limit := c.undoLimit(); limit > 0 {
+		if len(e.undo) >= limit {
+			n := copy(e.undo, e.undo[len(e.undo)-limit+1:])
+			clear(e.undo[n:])
+			e.undo = e.undo[:n]
+		}
+		e.undo = append(e.undo, listenerVersion[K, T]{version: e.version, listeners: e.load()})
+	} else {
+		e.undo = nil
+	}
+	e.version = c.version.Add(1)
+	e.listeners.Store(&listeners)
+}
+
+// retrack 在信号锁内根据前后两个监听器集合更新反向索引与 Store, 返回是否有新的 key 加入
+func (c *core[K, T]) retrack(signal string, before, after []listener[K, T]) bool {
+	for _, l := range before {
+		if !slices.ContainsFunc(after, func(item listener[K, T]) bool { return item.key == l.key }) {
+			c.untrack(signal, l.key)
+		}
+	}
+	grew := false
+	for _, l := range after {
+		if !slices.ContainsFunc(before, func(item listener[K, T]) bool { return item.key == l.key }) {
+			grew = true
+		}
+		c.track(signal, l)
+	}
+	return grew
+}
+
+// signalVersion 返回信号监听器集合的当前版本, 信号不存在时返回 0
+func (c *core[K, T]) signalVersion(signal string) uint64 {
+	e := c.entry(signal, false)
+	if e == nil {
+		return 0
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.version
+}
+
+// rollback 将信号的监听器集合恢复到撤销日志中的 version
+// 回滚本身也是一次变化, 会产生新的版本, 因此可以再次回滚
+func (c *core[K, T]) rollback(signal string, version uint64) error {
+	// 回滚可能同时添加与移除监听器
+	for _, op := range []Operation{OpWatch, OpUnwatch} {
+		if err := c.authorize(nil, op, signal); err != nil {
+			return err
+		}
+	}
+	err := ErrVersionNotFound
+	grew := false
+	c.mutateEntry(signal, false, func(e *signalEntry[K, T]) ([]listener[K, T], bool) {
+		if e.version == version {
+			err = nil
+			return nil, false
+		}
+		i := slices.IndexFunc(e.undo, func(v listenerVersion[K, T]) bool { return v.version == version })
+		if i < 0 {
+			return nil, false
+		}
+		err = nil
+		target := e.undo[i].listeners
+		// 历史版本与之后的版本可能共享底层数组, 不能再原地追加
+		e.owned = nil
+		grew = c.retrack(signal, e.load(), target)
+		return target, true
+	})
+	if grew {
+		c.flushBuffer(signal)
+	}
+	return err
+}
+
+// Version 返回信号监听器集合的当前版本, 每次 Watch/Unwatch 等变化都会产生更大的版本
+// 信号没有监听器记录时返回 0, Clean 会丢弃信号的版本历史
+func (b *Broadcast[T]) Version(signal string) uint64 {
+	return b.c().signalVersion(b.sig(signal))
+}
+
+// RollbackTo 将信号的监听器集合恢复到之前的 version, version 不在撤销日志中时返回 ErrVersionNotFound
+// 例如批量导入监听器出错后恢复到导入前的版本
+func (b *Broadcast[T]) RollbackTo(signal string, version uint64) error {
+	return b.c().rollback(b.sig(signal), version)
+}
+
+// SetUndoLog 设置每个信号保留的历史版本数量, 默认为 16, n <= 0 时不保留历史
+func (b *Broadcast[T]) SetUndoLog(n int) {
+	b.c().setUndoLog(n)
+}
+
+// Version 返回信号监听器集合的当前版本, 每次 Watch/Unwatch 等变化都会产生更大的版本
+// 信号没有监听器记录时返回 0, Clean 会丢弃信号的版本历史
+func (b *UniqueBroadcast[K, T]) Version(signal string) uint64 {
+	return b.core.signalVersion(signal)
+}
+
+// RollbackTo 将信号的监听器集合恢复到之前的 version, version 不在撤销日志中时返回 ErrVersionNotFound
+func (b *UniqueBroadcast[K, T]) RollbackTo(signal string, version uint64) error {
+	return b.core.rollback(signal, version)
+}
+
+// SetUndoLog 设置每个信号保留的历史版本数量, 默认为 16, n <= 0 时不保留历史
+func (b *UniqueBroadcast[K, T]) SetUndoLog(n int) {
+	b.core.setUndoLog(n)
+}
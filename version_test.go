@@ -0,0 +1,76 @@
+package broadcast
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestVersion_RollbackBulkImport(t *testing.T) {
+	b := New[string]()
+	if b.Version("users") != 0 {
+		t.Fatalf("expected version 0 before any watch, got %d", b.Version("users"))
+	}
+	b.Watch("users", "alice")
+	before := b.Version("users")
+	if before == 0 {
+		t.Fatal("expected Watch to bump the version")
+	}
+
+	for i := 0; i < 5; i++ {
+		b.Watch("users", fmt.Sprintf("imported-%d", i))
+	}
+	if b.Version("users") <= before {
+		t.Fatal("expected versions to increase")
+	}
+
+	if err := b.RollbackTo("users", before); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.WatchCount("users") != 1 || !b.HasWatch("users") {
+		t.Fatalf("expected only alice after rollback, got %d listeners", b.WatchCount("users"))
+	}
+
+	// 回滚本身也是一次变化, 可以再次撤销
+	rolled := b.Version("users")
+	if rolled <= before {
+		t.Fatal("expected the rollback to produce a new version")
+	}
+	if err := b.RollbackTo("users", rolled-1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.WatchCount("users") != 6 {
+		t.Errorf("expected the import to be restored, got %d listeners", b.WatchCount("users"))
+	}
+}
+
+func TestVersion_UndoLogBounded(t *testing.T) {
+	b := NewUnique[string, string]()
+	b.SetUndoLog(2)
+
+	b.Watch("locks", owner{"a", "1"})
+	first := b.Version("locks")
+	b.Watch("locks", owner{"b", "1"})
+	b.Watch("locks", owner{"c", "1"})
+	b.Watch("locks", owner{"d", "1"})
+
+	if err := b.RollbackTo("locks", first); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("expected an evicted version to be unavailable, got %v", err)
+	}
+	if err := b.RollbackTo("locks", b.Version("locks")-2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Has("locks", "c") || b.Has("locks", "d") || len(b.SignalsOf("d")) != 0 {
+		t.Error("expected rolled back keys to be removed from listeners and the index")
+	}
+	if err := b.RollbackTo("missing", 1); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound for an unknown signal, got %v", err)
+	}
+
+	b.SetUndoLog(0)
+	current := b.Version("locks")
+	b.Unwatch("locks", owner{"a", "1"})
+	if err := b.RollbackTo("locks", current); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("expected no history when the undo log is disabled, got %v", err)
+	}
+}
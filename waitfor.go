@@ -0,0 +1,82 @@
+package broadcast
+
+import (
+	"context"
+)
+
+// hasSeen 报告信号是否至少被广播过一次
+func (s *shard[K, T]) hasSeen(signal string) bool {
+	if p := s.seen.Load(); p != nil {
+		_, ok := (*p)[signal]
+		return ok
+	}
+	return false
+}
+
+// markSeen 记录信号已被广播, 首次记录时唤醒 WaitFor
+// 已记录的信号只需一次无锁查找, 不影响广播热路径
+func (c *core[K, T]) markSeen(signal string) {
+	s := c.shard(signal)
+	if s.hasSeen(signal) {
+		return
+	}
+
+	s.mu.Lock()
+	if s.hasSeen(signal) {
+		s.mu.Unlock()
+		return
+	}
+	var seen map[string]struct{}
+	if p := s.seen.Load(); p != nil {
+		seen = make(map[string]struct{}, len(*p)+1)
+		for k := range *p {
+			seen[k] = struct{}{}
+		}
+	} else {
+		seen = make(map[string]struct{}, 1)
+	}
+	seen[signal] = struct{}{}
+	s.seen.Store(&seen)
+	s.mu.Unlock()
+
+	c.seenMu.Lock()
+	if c.seenCh != nil {
+		close(c.seenCh)
+		c.seenCh = nil
+	}
+	c.seenMu.Unlock()
+}
+
+// seenChanged 返回在下一个信号首次被广播时关闭的 channel
+func (c *core[K, T]) seenChanged() <-chan struct{} {
+	c.seenMu.Lock()
+	defer c.seenMu.Unlock()
+
+	if c.seenCh == nil {
+		c.seenCh = make(chan struct{})
+	}
+	return c.seenCh
+}
+
+// waitFor 阻塞直到每个信号都至少被广播过一次, 或 ctx 结束
+func (c *core[K, T]) waitFor(ctx context.Context, signals ...string) error {
+	for {
+		changed := c.seenChanged()
+		ready := true
+		for _, signal := range signals {
+			if !c.shard(signal).hasSeen(signal) {
+				ready = false
+				break
+			}
+		}
+		if ready {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
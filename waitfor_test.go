@@ -0,0 +1,48 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitFor(t *testing.T) {
+	b := New[string]()
+	b.Broadcast("config", nil)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.WaitFor(context.Background(), "config", "schema", "flags")
+	}()
+
+	b.Broadcast("schema", nil)
+	select {
+	case <-done:
+		t.Fatal("WaitFor returned before every signal was broadcast")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	b.Broadcast("flags", nil)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected nil, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitFor did not return after every signal was broadcast")
+	}
+}
+
+func TestWaitFor_ContextDone(t *testing.T) {
+	b := NewUnique[int, TestUniqueData]()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := b.WaitFor(ctx, "never"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+	if err := b.WaitFor(context.Background()); err != nil {
+		t.Errorf("expected no signals to be ready immediately, got %v", err)
+	}
+}
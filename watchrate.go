@@ -0,0 +1,162 @@
+package broadcast
+
+import (
+	"sync"
+	"time"
+)
+
+// RatePolicy 决定监听器超过速率限制时如何处理多余的投递
+type RatePolicy int
+
+const (
+	// RateCoalesce 合并多余的投递, 只保留最新的一次, 在速率允许时补投 (默认)
+	RateCoalesce RatePolicy = iota
+	// RateDrop 直接丢弃多余的投递
+	RateDrop
+)
+
+// WatchOption 是 Watch 的选项
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	rate   float64
+	burst  int
+	policy RatePolicy
+}
+
+// WithRate 限制单个监听器每秒最多接收 perSecond 次投递, 避免频繁的信号压垮慢消费者
+// 多余的投递按 WithRatePolicy 设置的策略合并或丢弃
+func WithRate(perSecond float64) WatchOption {
+	return func(o *watchOptions) {
+		o.rate = perSecond
+	}
+}
+
+// WithRateBurst 设置 WithRate 允许的突发数量, 默认为 1
+func WithRateBurst(n int) WatchOption {
+	return func(o *watchOptions) {
+		o.burst = n
+	}
+}
+
+// WithRatePolicy 设置监听器超过速率限制时的策略, 默认为 RateCoalesce
+func WithRatePolicy(policy RatePolicy) WatchOption {
+	return func(o *watchOptions) {
+		o.policy = policy
+	}
+}
+
+// listenerRate 是单个监听器的令牌桶, 合并模式下保存等待补投的最新投递
+type listenerRate[K comparable, T any] struct {
+	rate   float64
+	burst  float64
+	policy RatePolicy
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	pending *delivery[K, T]
+}
+
+// withOptions 按选项为监听器设置速率限制
+func (l listener[K, T]) withOptions(opts []WatchOption) listener[K, T] {
+	if len(opts) == 0 {
+		return l
+	}
+	var o watchOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.rate > 0 {
+		burst := float64(max(o.burst, 1))
+		l.rate = &listenerRate[K, T]{rate: o.rate, burst: burst, policy: o.policy, tokens: burst}
+	}
+	return l
+}
+
+// watchRated 添加监听器, 监听器有速率限制时标记投递需要检查速率
+func (c *core[K, T]) watchRated(signal string, l listener[K, T]) (bool, error) {
+	if l.rate != nil {
+		c.rated.Store(true)
+	}
+	return c.tryWatch(signal, l)
+}
+
+// admitListeners 过滤超过速率限制的监听器, 没有被限制的监听器时原样返回不分配
+func (c *core[K, T]) admitListeners(d *delivery[K, T]) []listener[K, T] {
+	var admitted []listener[K, T]
+	for i, l := range d.listeners {
+		if l.rate == nil || c.allowListener(d, l) {
+			if admitted != nil {
+				admitted = append(admitted, l)
+			}
+			continue
+		}
+		if admitted == nil {
+			admitted = make([]listener[K, T], i, len(d.listeners))
+			copy(admitted, d.listeners[:i])
+		}
+	}
+	if admitted == nil {
+		return d.listeners
+	}
+	return admitted
+}
+
+// allowListener 从监听器的令牌桶取出一个令牌
+// 合并模式下有等待补投的投递时, 新的投递替换它而不是直接投递, 保证监听器按顺序收到事件
+func (c *core[K, T]) allowListener(d *delivery[K, T], l listener[K, T]) bool {
+	r := l.rate
+	now := c.clock().Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.last.IsZero() {
+		r.tokens = min(r.tokens+now.Sub(r.last).Seconds()*r.rate, r.burst)
+	}
+	r.last = now
+	if r.pending == nil && r.tokens >= 1 {
+		r.tokens--
+		return true
+	}
+	if r.policy == RateDrop {
+		return false
+	}
+
+	scheduled := r.pending != nil
+	p := *d
+	p.listeners = []listener[K, T]{l}
+	r.pending = &p
+	if !scheduled {
+		wait := time.Duration((1 - r.tokens) / r.rate * float64(time.Second))
+		c.clock().AfterFunc(max(wait, 0), func() {
+			c.flushListener(l)
+		})
+	}
+	return false
+}
+
+// flushListener 补投合并后的最新投递, 监听器已被移除时丢弃
+func (c *core[K, T]) flushListener(l listener[K, T]) {
+	r := l.rate
+	r.mu.Lock()
+	p := r.pending
+	r.pending = nil
+	if p != nil {
+		// 定时器在令牌补足时触发, 补投消耗这个令牌
+		now := c.clock().Now()
+		r.tokens = max(min(r.tokens+now.Sub(r.last).Seconds()*r.rate, r.burst)-1, 0)
+		r.last = now
+	}
+	r.mu.Unlock()
+
+	if p == nil {
+		return
+	}
+	if _, ok := c.lookup(p.signal, l.key); !ok {
+		return
+	}
+	p.handlers = c.loadHandlers()
+	_ = c.deliver(*p)
+}
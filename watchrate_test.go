@@ -0,0 +1,69 @@
+package broadcast_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"pkg.blksails.net/x/broadcast"
+	"pkg.blksails.net/x/broadcast/broadcasttest"
+)
+
+func TestWatchRate_CoalescesToLatest(t *testing.T) {
+	clock := broadcasttest.NewFakeClock(time.Unix(0, 0))
+	b := broadcast.New[string](broadcast.WithClock(clock))
+	seen := make(map[string][]int)
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		seen[data] = append(seen[data], metadata["v"].(int))
+		return nil
+	})
+
+	b.Watch("ticks", "fast")
+	b.Watch("ticks", "slow", broadcast.WithRate(20))
+	b.Watch("ticks", "dropping", broadcast.WithRate(20), broadcast.WithRatePolicy(broadcast.RateDrop))
+	for v := 1; v <= 5; v++ {
+		b.Broadcast("ticks", map[string]interface{}{"v": v})
+	}
+	if want := []int{1}; !slices.Equal(seen["slow"], want) {
+		t.Errorf("expected excess events held until a token is available, got %v", seen["slow"])
+	}
+
+	// 每秒 20 次, 50ms 后补足一个令牌并补投合并后的最新事件
+	clock.Advance(50 * time.Millisecond)
+	clock.Advance(time.Second)
+
+	if want := []int{1, 2, 3, 4, 5}; !slices.Equal(seen["fast"], want) {
+		t.Errorf("expected the unlimited listener to see every event, got %v", seen["fast"])
+	}
+	if want := []int{1, 5}; !slices.Equal(seen["slow"], want) {
+		t.Errorf("expected excess events coalesced to the latest, got %v", seen["slow"])
+	}
+	if want := []int{1}; !slices.Equal(seen["dropping"], want) {
+		t.Errorf("expected excess events dropped, got %v", seen["dropping"])
+	}
+}
+
+func TestWatchRate_BurstAndUnwatch(t *testing.T) {
+	clock := broadcasttest.NewFakeClock(time.Unix(0, 0))
+	b := broadcast.NewUnique[string, string](broadcast.WithClock(clock))
+	var got []string
+	b.Handle(func(signal string, data string, metadata map[string]interface{}) error {
+		got = append(got, data)
+		return nil
+	})
+
+	b.Watch("chat", presence{"alice", "a"}, broadcast.WithRate(10), broadcast.WithRateBurst(3))
+	for range 4 {
+		b.Broadcast("chat", nil)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected the burst to pass, got %v", got)
+	}
+
+	// 等待补投的监听器被移除后不再收到合并的投递
+	b.UnwatchKey("chat", "alice")
+	clock.Advance(200 * time.Millisecond)
+	if len(got) != 3 {
+		t.Errorf("expected the coalesced event to be dropped after Unwatch, got %v", got)
+	}
+}
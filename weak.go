@@ -0,0 +1,34 @@
+package broadcast
+
+import (
+	"runtime"
+	"unique"
+	"weak"
+)
+
+// weakListener 通过弱指针持有监听对象, 不会阻止对象被垃圾回收
+type weakListener[K comparable, E any] struct {
+	key unique.Handle[K]
+	ptr weak.Pointer[E]
+}
+
+func (w *weakListener[K, E]) Unique() unique.Handle[K] { return w.key }
+func (w *weakListener[K, E]) Value() *E                { return w.ptr.Value() }
+
+// WatchWeak 以弱引用方式监听一个信号, 返回监听器是否被添加
+// 监听器不会阻止 obj 被垃圾回收, obj 不可达后监听器自动从信号上移除, 无需调用 Unwatch
+// obj 被回收之后、自动移除之前的短暂窗口内, 处理器可能收到 nil
+func WatchWeak[K comparable, E any](b *UniqueBroadcast[K, *E], signal string, key K, obj *E) bool {
+	w := &weakListener[K, E]{key: unique.Make(key), ptr: weak.Make(obj)}
+	if !b.core.watch(signal, newListener[K, *E](w)) {
+		return false
+	}
+
+	// 只移除已失效的监听器, 相同 key 之后被其他对象重新监听时不受影响
+	runtime.AddCleanup(obj, func(c *core[K, *E]) {
+		c.unwatchIf(signal, w.key, func(current *E) bool {
+			return current == nil
+		})
+	}, &b.core)
+	return true
+}
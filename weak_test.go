@@ -0,0 +1,54 @@
+package broadcast
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+type session struct {
+	name string
+	buf  [64]byte
+}
+
+func TestWatchWeak_UnwatchedWhenUnreachable(t *testing.T) {
+	b := NewUnique[string, *session]()
+
+	kept := &session{name: "kept"}
+	if !WatchWeak(b, "chat", "kept", kept) {
+		t.Fatal("expected the weak listener to be added")
+	}
+	func() {
+		if !WatchWeak(b, "chat", "dropped", &session{name: "dropped"}) {
+			t.Fatal("expected the weak listener to be added")
+		}
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for b.Has("chat", "dropped") && time.Now().Before(deadline) {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if b.Has("chat", "dropped") {
+		t.Fatal("expected the unreachable listener to be unwatched")
+	}
+
+	var got []string
+	b.Handle(func(signal string, data *session, _ map[string]interface{}) error {
+		got = append(got, data.name)
+		return nil
+	})
+	b.Broadcast("chat", nil)
+	if len(got) != 1 || got[0] != "kept" {
+		t.Errorf("expected only the reachable listener, got %v", got)
+	}
+	runtime.KeepAlive(kept)
+}
+
+func TestWatchWeak_Authorized(t *testing.T) {
+	b := NewUnique[string, *session](WithAuthorizer(denyAll))
+	s := &session{name: "kept"}
+	if WatchWeak(b, "chat", "kept", s) || b.WatchCount("chat") != 0 {
+		t.Error("expected WatchWeak to be denied")
+	}
+}